@@ -11,10 +11,21 @@ of this functionality is to improve efficiency: manually freeing memory
 before a garbage collection delays that cycle. Less frequent cycles means
 the CPU cost of the garbage collector is incurred less frequently.
 
+This package is experimental. It is only available when building with
+GOEXPERIMENT=arenas, and its API is not covered by the Go 1 compatibility
+promise.
+
 This functionality in this package is mostly captured in the Arena type.
 Arenas allocate large chunks of memory for Go values, so they're likely to
 be inefficient for allocating only small amounts of small Go values. They're
 best used in bulk, on the order of MiB of memory allocated on each use.
+Values of any type may be allocated in an arena with New and MakeSlice,
+strings with NewString, and maps with MakeMap.
+
+Memory allocated in arenas is managed by the garbage collector like the
+rest of the heap: pointers from arena values to heap values keep the heap
+values alive, and the runtime/metrics package reports arena memory under
+/gc/arenas.
 
 Note that by allowing for this limited form of manual memory allocation
 that use-after-free bugs are possible with regular Go values. This package
@@ -25,6 +36,13 @@ error message, but this package reserves the right to not force a fault on
 freed memory. That means a valid implementation of this package is to just
 allocate all memory the way the runtime normally would, and in fact, it
 reserves the right to occasionally do so for some Go values.
+
+Programs built with the race detector or with the address or memory
+sanitizer check for use-after-free bugs more strictly: freed arena memory
+is never reused, so accessing it always faults once the current garbage
+collection cycle, if any, has finished. The sanitizers report such
+accesses immediately. The address sanitizer also reports accesses to the
+parts of an arena that were not allocated.
 */
 package arena
 
@@ -75,6 +93,27 @@ func MakeSlice[T any](a *Arena, len, cap int) []T {
 	return sl[:len]
 }
 
+// MakeMap creates a new map[K]V with room for approximately hint
+// elements, like make(map[K]V, hint). The map header is allocated in the
+// arena, as is the initial storage of a map with a small hint; storage
+// added as the map grows is allocated on the heap. The map must not be
+// used after the arena is freed. To keep the contents of the map beyond
+// the lifetime of the arena, copy it with [maps.Clone].
+func MakeMap[K comparable, V any](a *Arena, hint int) map[K]V {
+	return runtime_arena_arena_Map(a.a, reflectlite.TypeOf((map[K]V)(nil)), hint).(map[K]V)
+}
+
+// NewString returns a copy of s whose bytes are allocated in the provided
+// arena. The string must not be used after the arena is freed.
+func NewString(a *Arena, s string) string {
+	if len(s) == 0 {
+		return ""
+	}
+	b := MakeSlice[byte](a, len(s), len(s))
+	copy(b, s)
+	return unsafe.String(&b[0], len(b))
+}
+
 // Clone makes a shallow copy of the input value that is no longer bound to any
 // arena it may have been allocated from, returning the copy. If it was not
 // allocated from an arena, it is returned untouched. This function is useful
@@ -101,6 +140,9 @@ func runtime_arena_arena_New(arena unsafe.Pointer, typ any) any
 //go:linkname runtime_arena_arena_Slice
 func runtime_arena_arena_Slice(arena unsafe.Pointer, slice any, cap int)
 
+//go:linkname runtime_arena_arena_Map
+func runtime_arena_arena_Map(arena unsafe.Pointer, typ any, hint int) any
+
 //go:linkname runtime_arena_arena_Free
 func runtime_arena_arena_Free(arena unsafe.Pointer)
 
@@ -8,6 +8,9 @@ package arena_test
 
 import (
 	"arena"
+	"runtime"
+	"runtime/metrics"
+	"strings"
 	"testing"
 )
 
@@ -40,3 +43,74 @@ func TestSmokeLarge(t *testing.T) {
 		_ = arena.New[T2](a)
 	}
 }
+
+func TestMakeMap(t *testing.T) {
+	a := arena.NewArena()
+	defer a.Free()
+
+	for _, hint := range []int{0, 1, 8, 9, 1000} {
+		m := arena.MakeMap[string, *T1](a, hint)
+		for i := range 2000 {
+			m[strings.Repeat("x", i/26)+string(rune('a'+i%26))] = &T1{i}
+		}
+		runtime.GC()
+		if len(m) != 2000 {
+			t.Errorf("MakeMap(%d): len = %d, want 2000", hint, len(m))
+		}
+		for k, v := range m {
+			if len(k) != v.n/26+1 || k[len(k)-1] != byte('a'+v.n%26) {
+				t.Errorf("MakeMap(%d): m[%q] = %d", hint, k, v.n)
+			}
+		}
+	}
+}
+
+func TestNewString(t *testing.T) {
+	a := arena.NewArena()
+	defer a.Free()
+
+	for _, s := range []string{"", "x", strings.Repeat("hello", 1000)} {
+		if got := arena.NewString(a, s); got != s {
+			t.Errorf("NewString(%q) = %q", s, got)
+		}
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	samples := []metrics.Sample{
+		{Name: "/gc/arenas/allocs:bytes"},
+		{Name: "/gc/arenas/chunks:bytes"},
+		{Name: "/gc/arenas/frees:bytes"},
+	}
+	read := func() (allocs, chunks, frees uint64) {
+		metrics.Read(samples)
+		return samples[0].Value.Uint64(), samples[1].Value.Uint64(), samples[2].Value.Uint64()
+	}
+
+	allocs0, chunks0, frees0 := read()
+	a := arena.NewArena()
+	arena.MakeSlice[byte](a, 1000, 1000)
+	arena.New[T1](a)
+	allocs1, chunks1, frees1 := read()
+	a.Free()
+	allocs2, chunks2, frees2 := read()
+
+	if allocs1-allocs0 < 1008 {
+		t.Errorf("allocs grew by %d bytes, want at least 1008", allocs1-allocs0)
+	}
+	if chunks1 <= chunks0 {
+		t.Errorf("chunks = %d after NewArena, want more than %d", chunks1, chunks0)
+	}
+	if frees1 != frees0 {
+		t.Errorf("frees = %d before Free, want %d", frees1, frees0)
+	}
+	if allocs2 != allocs1 {
+		t.Errorf("allocs = %d after Free, want %d", allocs2, allocs1)
+	}
+	if chunks2 != chunks0 {
+		t.Errorf("chunks = %d after Free, want %d", chunks2, chunks0)
+	}
+	if frees2-frees1 != allocs1-allocs0 {
+		t.Errorf("frees grew by %d bytes, want %d", frees2-frees1, allocs1-allocs0)
+	}
+}
@@ -66,6 +66,7 @@ func TestASAN(t *testing.T) {
 		{src: "asan_global4_fail.go", memoryAccessError: "global-buffer-overflow", errorLocation: "asan_global4_fail.go:21"},
 		{src: "asan_global5.go"},
 		{src: "arena_fail.go", memoryAccessError: "use-after-poison", errorLocation: "arena_fail.go:26", experiments: []string{"arenas"}},
+		{src: "arena2_fail.go", memoryAccessError: "use-after-poison", errorLocation: "arena2_fail.go:24", experiments: []string{"arenas"}},
 	}
 	for _, tc := range cases {
 		tc := tc
@@ -0,0 +1,25 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build goexperiment.arenas
+
+package main
+
+import (
+	"arena"
+	"unsafe"
+)
+
+func main() {
+	a := arena.NewArena()
+	defer a.Free()
+	x := arena.New[[200]byte](a)
+	x[0] = 9
+	// Access to arena memory that was never allocated.
+	//
+	// ASAN should detect this deterministically, as the parts
+	// of the arena that are not allocated stay poisoned.
+	p := (*byte)(unsafe.Add(unsafe.Pointer(x), -8))
+	println(*p)
+}
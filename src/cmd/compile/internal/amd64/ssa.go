@@ -46,7 +46,7 @@ func ssaMarkMoves(s *ssagen.State, b *ssa.Block) {
 // loadByType returns the load instruction of the given type.
 func loadByType(t *types.Type) obj.As {
 	// Avoid partial register write
-	if !t.IsFloat() {
+	if !t.IsFloat() && !t.IsSIMD() {
 		switch t.Size() {
 		case 1:
 			return x86.AMOVBLZX
@@ -61,7 +61,11 @@ func loadByType(t *types.Type) obj.As {
 // storeByType returns the store instruction of the given type.
 func storeByType(t *types.Type) obj.As {
 	width := t.Size()
-	if t.IsFloat() {
+	if t.IsSIMD() {
+		if width == 32 {
+			return x86.AVMOVDQU
+		}
+	} else if t.IsFloat() {
 		switch width {
 		case 4:
 			return x86.AMOVSS
@@ -87,7 +91,9 @@ func storeByType(t *types.Type) obj.As {
 
 // moveByType returns the reg->reg move instruction of the given type.
 func moveByType(t *types.Type) obj.As {
-	if t.IsFloat() {
+	if t.IsSIMD() {
+		return x86.AVMOVDQU
+	} else if t.IsFloat() {
 		// Moving the whole sse2 register is faster
 		// than moving just the correct low portion of it.
 		// There is no xmm->xmm move with 1 byte opcode,
@@ -127,6 +133,28 @@ func opregreg(s *ssagen.State, op obj.As, dest, src int16) *obj.Prog {
 	return p
 }
 
+// simdReg returns the 256-bit Y register that extends the X
+// register r, which is what the register allocator hands out for
+// vector values.
+func simdReg(r int16) int16 {
+	return r - x86.REG_X0 + x86.REG_Y0
+}
+
+// simdV21 emits a three-operand VEX instruction for v, computing
+//
+//	v := arg0 op arg1
+//
+// on 256-bit vectors, and returns the created obj.Prog.
+func simdV21(s *ssagen.State, v *ssa.Value) *obj.Prog {
+	p := s.Prog(v.Op.Asm())
+	p.From.Type = obj.TYPE_REG
+	p.From.Reg = simdReg(v.Args[1].Reg())
+	p.AddRestSourceReg(simdReg(v.Args[0].Reg()))
+	p.To.Type = obj.TYPE_REG
+	p.To.Reg = simdReg(v.Reg())
+	return p
+}
+
 // memIdx fills out a as an indexed memory reference for v.
 // It assumes that the base register and the index register
 // are v.Args[0].Reg() and v.Args[1].Reg(), respectively.
@@ -253,7 +281,7 @@ func ssaGenValue(s *ssagen.State, v *ssa.Value) {
 		ssa.OpAMD64ADDSS, ssa.OpAMD64ADDSD, ssa.OpAMD64SUBSS, ssa.OpAMD64SUBSD,
 		ssa.OpAMD64MULSS, ssa.OpAMD64MULSD, ssa.OpAMD64DIVSS, ssa.OpAMD64DIVSD,
 		ssa.OpAMD64MINSS, ssa.OpAMD64MINSD,
-		ssa.OpAMD64POR, ssa.OpAMD64PXOR,
+		ssa.OpAMD64POR, ssa.OpAMD64PXOR, ssa.OpAMD64PCMPEQB,
 		ssa.OpAMD64BTSL, ssa.OpAMD64BTSQ,
 		ssa.OpAMD64BTCL, ssa.OpAMD64BTCQ,
 		ssa.OpAMD64BTRL, ssa.OpAMD64BTRQ:
@@ -915,7 +943,7 @@ func ssaGenValue(s *ssagen.State, v *ssa.Value) {
 		ssagen.AddAux2(&p.To, v, sc.Off64())
 	case ssa.OpAMD64MOVLQSX, ssa.OpAMD64MOVWQSX, ssa.OpAMD64MOVBQSX, ssa.OpAMD64MOVLQZX, ssa.OpAMD64MOVWQZX, ssa.OpAMD64MOVBQZX,
 		ssa.OpAMD64CVTTSS2SL, ssa.OpAMD64CVTTSD2SL, ssa.OpAMD64CVTTSS2SQ, ssa.OpAMD64CVTTSD2SQ,
-		ssa.OpAMD64CVTSS2SD, ssa.OpAMD64CVTSD2SS:
+		ssa.OpAMD64CVTSS2SD, ssa.OpAMD64CVTSD2SS, ssa.OpAMD64PMOVMSKB:
 		opregreg(s, v.Op.Asm(), v.Reg(), v.Args[0].Reg())
 	case ssa.OpAMD64CVTSL2SD, ssa.OpAMD64CVTSQ2SD, ssa.OpAMD64CVTSQ2SS, ssa.OpAMD64CVTSL2SS:
 		r := v.Reg()
@@ -1010,6 +1038,9 @@ func ssaGenValue(s *ssagen.State, v *ssa.Value) {
 		x := v.Args[0].Reg()
 		y := v.Reg()
 		if x != y {
+			if v.Type.IsSIMD() {
+				x, y = simdReg(x), simdReg(y)
+			}
 			opregreg(s, moveByType(v.Type), y, x)
 		}
 	case ssa.OpLoadReg:
@@ -1021,6 +1052,9 @@ func ssaGenValue(s *ssagen.State, v *ssa.Value) {
 		ssagen.AddrAuto(&p.From, v.Args[0])
 		p.To.Type = obj.TYPE_REG
 		p.To.Reg = v.Reg()
+		if v.Type.IsSIMD() {
+			p.To.Reg = simdReg(p.To.Reg)
+		}
 
 	case ssa.OpStoreReg:
 		if v.Type.IsFlags() {
@@ -1030,6 +1064,9 @@ func ssaGenValue(s *ssagen.State, v *ssa.Value) {
 		p := s.Prog(storeByType(v.Type))
 		p.From.Type = obj.TYPE_REG
 		p.From.Reg = v.Args[0].Reg()
+		if v.Type.IsSIMD() {
+			p.From.Reg = simdReg(p.From.Reg)
+		}
 		ssagen.AddrAuto(&p.To, v)
 	case ssa.OpAMD64LoweredHasCPUFeature:
 		p := s.Prog(x86.AMOVBLZX)
@@ -1058,6 +1095,7 @@ func ssaGenValue(s *ssagen.State, v *ssa.Value) {
 		r := v.Reg()
 		getgFromTLS(s, r)
 	case ssa.OpAMD64CALLstatic, ssa.OpAMD64CALLtail:
+		zeroUpper(s)
 		if s.ABI == obj.ABI0 && v.Aux.(*ssa.AuxCall).Fn.ABI() == obj.ABIInternal {
 			// zeroing X15 when entering ABIInternal from ABI0
 			if buildcfg.GOOS != "plan9" { // do not use SSE on Plan 9
@@ -1080,6 +1118,7 @@ func ssaGenValue(s *ssagen.State, v *ssa.Value) {
 			getgFromTLS(s, x86.REG_R14)
 		}
 	case ssa.OpAMD64CALLclosure, ssa.OpAMD64CALLinter:
+		zeroUpper(s)
 		s.Call(v)
 
 	case ssa.OpAMD64LoweredGetCallerPC:
@@ -1349,6 +1388,75 @@ func ssaGenValue(s *ssagen.State, v *ssa.Value) {
 		p6 := s.Prog(x86.AJNE)
 		p6.To.Type = obj.TYPE_BRANCH
 		p6.To.SetTarget(p1)
+	case ssa.OpAMD64VMOVDQUload256:
+		p := s.Prog(v.Op.Asm())
+		p.From.Type = obj.TYPE_MEM
+		p.From.Reg = v.Args[0].Reg()
+		ssagen.AddAux(&p.From, v)
+		p.To.Type = obj.TYPE_REG
+		p.To.Reg = simdReg(v.Reg())
+	case ssa.OpAMD64VMOVDQUstore256:
+		p := s.Prog(v.Op.Asm())
+		p.From.Type = obj.TYPE_REG
+		p.From.Reg = simdReg(v.Args[1].Reg())
+		p.To.Type = obj.TYPE_MEM
+		p.To.Reg = v.Args[0].Reg()
+		ssagen.AddAux(&p.To, v)
+	case ssa.OpAMD64Zero256:
+		r := simdReg(v.Reg())
+		p := s.Prog(v.Op.Asm())
+		p.From.Type = obj.TYPE_REG
+		p.From.Reg = r
+		p.AddRestSourceReg(r)
+		p.To.Type = obj.TYPE_REG
+		p.To.Reg = r
+	case ssa.OpAMD64VPBROADCASTB256, ssa.OpAMD64VPBROADCASTW256, ssa.OpAMD64VPBROADCASTD256, ssa.OpAMD64VPBROADCASTQ256,
+		ssa.OpAMD64VBROADCASTSS256, ssa.OpAMD64VBROADCASTSD256:
+		// The source is a scalar in the low lane of an X register.
+		opregreg(s, v.Op.Asm(), simdReg(v.Reg()), v.Args[0].Reg())
+	case ssa.OpAMD64VPMOVMSKB256, ssa.OpAMD64VMOVMSKPS256, ssa.OpAMD64VMOVMSKPD256:
+		opregreg(s, v.Op.Asm(), v.Reg(), simdReg(v.Args[0].Reg()))
+	case ssa.OpAMD64VPABSB256, ssa.OpAMD64VPABSW256, ssa.OpAMD64VPABSD256,
+		ssa.OpAMD64VSQRTPS256, ssa.OpAMD64VSQRTPD256:
+		opregreg(s, v.Op.Asm(), simdReg(v.Reg()), simdReg(v.Args[0].Reg()))
+	case ssa.OpAMD64VPBLENDVB256:
+		p := s.Prog(v.Op.Asm())
+		p.From.Type = obj.TYPE_REG
+		p.From.Reg = simdReg(v.Args[2].Reg())
+		p.AddRestSourceArgs([]obj.Addr{
+			{Type: obj.TYPE_REG, Reg: simdReg(v.Args[1].Reg())},
+			{Type: obj.TYPE_REG, Reg: simdReg(v.Args[0].Reg())},
+		})
+		p.To.Type = obj.TYPE_REG
+		p.To.Reg = simdReg(v.Reg())
+	case ssa.OpAMD64VCMPPS256, ssa.OpAMD64VCMPPD256:
+		p := s.Prog(v.Op.Asm())
+		p.From.Type = obj.TYPE_CONST
+		p.From.Offset = v.AuxInt
+		p.AddRestSourceArgs([]obj.Addr{
+			{Type: obj.TYPE_REG, Reg: simdReg(v.Args[1].Reg())},
+			{Type: obj.TYPE_REG, Reg: simdReg(v.Args[0].Reg())},
+		})
+		p.To.Type = obj.TYPE_REG
+		p.To.Reg = simdReg(v.Reg())
+	case ssa.OpAMD64VPAND256, ssa.OpAMD64VPOR256, ssa.OpAMD64VPXOR256, ssa.OpAMD64VPANDN256,
+		ssa.OpAMD64VPADDB256, ssa.OpAMD64VPADDW256, ssa.OpAMD64VPADDD256, ssa.OpAMD64VPADDQ256,
+		ssa.OpAMD64VPSUBB256, ssa.OpAMD64VPSUBW256, ssa.OpAMD64VPSUBD256, ssa.OpAMD64VPSUBQ256,
+		ssa.OpAMD64VPADDSB256, ssa.OpAMD64VPADDSW256, ssa.OpAMD64VPADDUSB256, ssa.OpAMD64VPADDUSW256,
+		ssa.OpAMD64VPSUBSB256, ssa.OpAMD64VPSUBSW256, ssa.OpAMD64VPSUBUSB256, ssa.OpAMD64VPSUBUSW256,
+		ssa.OpAMD64VPMULLW256, ssa.OpAMD64VPMULLD256,
+		ssa.OpAMD64VPMINSB256, ssa.OpAMD64VPMINSW256, ssa.OpAMD64VPMINSD256,
+		ssa.OpAMD64VPMINUB256, ssa.OpAMD64VPMINUW256, ssa.OpAMD64VPMINUD256,
+		ssa.OpAMD64VPMAXSB256, ssa.OpAMD64VPMAXSW256, ssa.OpAMD64VPMAXSD256,
+		ssa.OpAMD64VPMAXUB256, ssa.OpAMD64VPMAXUW256, ssa.OpAMD64VPMAXUD256,
+		ssa.OpAMD64VPAVGB256, ssa.OpAMD64VPAVGW256,
+		ssa.OpAMD64VPSLLVD256, ssa.OpAMD64VPSLLVQ256, ssa.OpAMD64VPSRLVD256, ssa.OpAMD64VPSRLVQ256, ssa.OpAMD64VPSRAVD256,
+		ssa.OpAMD64VPCMPEQB256, ssa.OpAMD64VPCMPEQW256, ssa.OpAMD64VPCMPEQD256, ssa.OpAMD64VPCMPEQQ256,
+		ssa.OpAMD64VPCMPGTB256, ssa.OpAMD64VPCMPGTW256, ssa.OpAMD64VPCMPGTD256, ssa.OpAMD64VPCMPGTQ256,
+		ssa.OpAMD64VADDPS256, ssa.OpAMD64VADDPD256, ssa.OpAMD64VSUBPS256, ssa.OpAMD64VSUBPD256,
+		ssa.OpAMD64VMULPS256, ssa.OpAMD64VMULPD256, ssa.OpAMD64VDIVPS256, ssa.OpAMD64VDIVPD256,
+		ssa.OpAMD64VMINPS256, ssa.OpAMD64VMINPD256, ssa.OpAMD64VMAXPS256, ssa.OpAMD64VMAXPD256:
+		simdV21(s, v)
 	case ssa.OpAMD64PrefetchT0, ssa.OpAMD64PrefetchNTA:
 		p := s.Prog(v.Op.Asm())
 		p.From.Type = obj.TYPE_MEM
@@ -1407,6 +1515,16 @@ var nefJumps = [2][2]ssagen.IndexJump{
 	{{Jump: x86.AJNE, Index: 0}, {Jump: x86.AJPS, Index: 0}}, // next == b.Succs[1]
 }
 
+// zeroUpper clears the upper halves of the vector registers with
+// VZEROUPPER if the function uses 256-bit vectors, so that the SSE code
+// it calls or returns to does not pay for the transition. Calls clobber
+// all vector registers, so no live value is lost.
+func zeroUpper(s *ssagen.State) {
+	if s.UsesSIMD {
+		s.Prog(x86.AVZEROUPPER)
+	}
+}
+
 func ssaGenBlock(s *ssagen.State, b, next *ssa.Block) {
 	switch b.Kind {
 	case ssa.BlockPlain:
@@ -1434,6 +1552,7 @@ func ssaGenBlock(s *ssagen.State, b, next *ssa.Block) {
 		}
 	case ssa.BlockExit, ssa.BlockRetJmp:
 	case ssa.BlockRet:
+		zeroUpper(s)
 		s.Prog(obj.ARET)
 
 	case ssa.BlockAMD64EQF:
@@ -184,6 +184,7 @@ func ParseFlags() {
 	Debug.PGOInline = 1
 	Debug.PGODevirtualize = 2
 	Debug.SyncFrames = -1 // disable sync markers by default
+	Debug.VariableMakeThreshold = 32
 	Debug.ZeroCopy = 1
 	Debug.RangeFuncCheck = 1
 	Debug.MergeLocals = 1
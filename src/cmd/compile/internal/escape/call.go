@@ -122,6 +122,15 @@ func (e *escape) call(ks []hole, call ir.Node) {
 		}
 		argument(appendeeK, args[0])
 
+		// The result may also point to a new backing store, which
+		// is allocated on the stack if it does not escape. Each
+		// append uses its stack buffer at most once per call, so
+		// the backing store does not need to outlive the loops the
+		// append is in.
+		loc := e.newLoc(call, false)
+		loc.loopDepth = 0
+		e.flow(ks[0].addr(call, "spill"), loc)
+
 		if call.IsDDD {
 			appendedK := e.discardHole()
 			if args[1].Type().IsSlice() && args[1].Type().Elem().HasPointers() {
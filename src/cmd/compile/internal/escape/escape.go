@@ -296,6 +296,10 @@ func (b *batch) finish(fns []*ir.Func) {
 		// TODO(mdempsky): Update tests to expect this.
 		goDeferWrapper := n.Op() == ir.OCLOSURE && n.(*ir.ClosureExpr).Func.Wrapper()
 
+		// Likewise for the backing stores of appends, which are only
+		// allocated if the slice needs to grow.
+		quiet := goDeferWrapper || isAppend(n)
+
 		if loc.hasAttr(attrEscapes) {
 			if n.Op() == ir.ONAME {
 				if base.Flag.CompilingRuntime {
@@ -305,19 +309,22 @@ func (b *batch) finish(fns []*ir.Func) {
 					base.WarnfAt(n.Pos(), "moved to heap: %v", n)
 				}
 			} else {
-				if base.Flag.LowerM != 0 && !goDeferWrapper {
+				if base.Flag.LowerM != 0 && !quiet {
 					base.WarnfAt(n.Pos(), "%v escapes to heap", n)
 				}
-				if logopt.Enabled() {
+				if logopt.Enabled() && !quiet {
 					var e_curfn *ir.Func // TODO(mdempsky): Fix.
 					logopt.LogOpt(n.Pos(), "escape", "escape", ir.FuncName(e_curfn))
 				}
 			}
 			n.SetEsc(ir.EscHeap)
 		} else {
-			if base.Flag.LowerM != 0 && n.Op() != ir.ONAME && !goDeferWrapper {
+			if base.Flag.LowerM != 0 && n.Op() != ir.ONAME && !quiet {
 				base.WarnfAt(n.Pos(), "%v does not escape", n)
 			}
+			if logopt.Enabled() && n.Op() != ir.ONAME && !quiet {
+				logopt.LogOpt(n.Pos(), "doesNotEscape", "escape", ir.FuncName(loc.curfn), fmt.Sprintf("%v does not escape", n))
+			}
 			n.SetEsc(ir.EscNone)
 			if !loc.hasAttr(attrPersists) {
 				switch n.Op() {
@@ -464,6 +471,10 @@ func (b *batch) paramTag(fn *ir.Func, narg int, f *types.Field) string {
 	if diagnose && !loc.hasAttr(attrEscapes) {
 		b.reportLeaks(f.Pos, name(), esc, fn.Type())
 	}
+	// Leaks are logged with their explanation as they are found.
+	if logopt.Enabled() && !(fn.Wrapper() || fn.Dupok()) && !loc.hasAttr(attrEscapes) && !esc.Escapes() {
+		logopt.LogOpt(f.Pos, "doesNotEscape", "escape", ir.FuncName(fn), fmt.Sprintf("parameter %v does not escape", name()))
+	}
 
 	return esc.Encode()
 }
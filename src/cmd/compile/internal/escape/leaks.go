@@ -43,6 +43,20 @@ func (l leaks) Callee() int { return l.get(leakCallee) }
 // Result returns -1.
 func (l leaks) Result(i int) int { return l.get(leakResult0 + i) }
 
+// Escapes reports whether l has any assignment flow to the heap or
+// to a result parameter.
+func (l leaks) Escapes() bool {
+	if l.Heap() >= 0 {
+		return true
+	}
+	for i := 0; i < numEscResults; i++ {
+		if l.Result(i) >= 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // AddHeap adds an assignment flow from l to the heap.
 func (l *leaks) AddHeap(derefs int) { l.add(leakHeap, derefs) }
 
@@ -98,7 +98,7 @@ func (b *batch) walkOne(root *location, walkgen uint32, enqueue func(*location))
 			// outlives it, then l needs to be heap
 			// allocated.
 			if b.outlives(root, l) {
-				if !l.hasAttr(attrEscapes) && (logopt.Enabled() || base.Flag.LowerM >= 2) {
+				if !l.hasAttr(attrEscapes) && !isAppend(l.n) && (logopt.Enabled() || base.Flag.LowerM >= 2) {
 					if base.Flag.LowerM >= 2 {
 						fmt.Printf("%s: %v escapes to heap:\n", base.FmtPos(l.n.Pos()), l.n)
 					}
@@ -5,11 +5,18 @@
 package escape
 
 import (
+	"cmd/compile/internal/base"
 	"cmd/compile/internal/ir"
 	"cmd/compile/internal/typecheck"
 	"cmd/compile/internal/types"
 )
 
+// isAppend reports whether n is an append call, whose location
+// represents the backing store it may allocate.
+func isAppend(n ir.Node) bool {
+	return n != nil && n.Op() == ir.OAPPEND
+}
+
 func isSliceSelfAssign(dst, src ir.Node) bool {
 	// Detect the following special case.
 	//
@@ -168,6 +175,12 @@ func mayAffectMemory(n ir.Node) bool {
 	}
 }
 
+// fitsStackBuffer reports whether at least one element of type elem
+// fits in the stack buffer used for variable-sized allocations.
+func fitsStackBuffer(elem *types.Type) bool {
+	return elem.Size() != 0 && elem.Size() <= int64(base.Debug.VariableMakeThreshold)
+}
+
 // HeapAllocReason returns the reason the given Node must be heap
 // allocated, or the empty string if it doesn't.
 func HeapAllocReason(n ir.Node) string {
@@ -211,12 +224,28 @@ func HeapAllocReason(n ir.Node) string {
 			r = n.Len
 		}
 		if !ir.IsSmallIntConst(r) {
-			return "non-constant size"
+			// A make of non-constant size uses a small stack
+			// buffer if the size turns out to fit in it, which a
+			// constant length must do too. See walkMakeSlice.
+			elem := n.Type().Elem()
+			if !fitsStackBuffer(elem) {
+				return "non-constant size"
+			}
+			if ir.IsSmallIntConst(n.Len) && ir.Int64Val(n.Len) > int64(base.Debug.VariableMakeThreshold)/elem.Size() {
+				return "non-constant size"
+			}
+			return ""
 		}
 		if t := n.Type(); t.Elem().Size() != 0 && ir.Int64Val(r) > ir.MaxImplicitStackVarSize/t.Elem().Size() {
 			return "too large for stack"
 		}
 	}
 
+	// Likewise for the backing store allocated by append. See
+	// walk.appendStackBuffer.
+	if n.Op() == ir.OAPPEND && !fitsStackBuffer(n.Type().Elem()) {
+		return "too large for stack"
+	}
+
 	return ""
 }
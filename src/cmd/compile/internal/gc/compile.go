@@ -47,7 +47,10 @@ func enqueueFunc(fn *ir.Func) {
 		return
 	}
 
-	if len(fn.Body) == 0 {
+	if len(fn.Body) == 0 && ssagen.GenIntrinsicBody(fn) {
+		// fn is a bodyless simd intrinsic, and now has a body
+		// that calls the intrinsic.
+	} else if len(fn.Body) == 0 {
 		// Initialize ABI wrappers if necessary.
 		ir.InitLSym(fn, false)
 		types.CalcSize(fn.Type())
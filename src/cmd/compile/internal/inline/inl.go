@@ -1070,6 +1070,9 @@ func mkinlcall(callerfn *ir.Func, n *ir.CallExpr, fn *ir.Func, bigCaller bool) *
 			fmt.Printf("%v: inlining call to %v\n", ir.Line(n), fn)
 		}
 	}
+	if logopt.Enabled() {
+		logopt.LogOpt(n.Pos(), "inlineCall", "inline", ir.FuncName(callerfn), ir.PkgFuncName(fn))
+	}
 	if base.Flag.LowerM > 2 {
 		fmt.Printf("%v: Before inlining: %+v\n", ir.Line(n), n)
 	}
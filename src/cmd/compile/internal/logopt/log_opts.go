@@ -57,7 +57,8 @@ import (
 // Range: the outermost source position, for now begin and end are equal.
 // Severity: (always) SeverityInformation (3)
 // Source: (always) "go compiler"
-// Code: a string describing the missed optimization, e.g., "nilcheck", "cannotInline", "isInBounds", "escape"
+// Code: a string describing the missed optimization, e.g., "nilcheck", "cannotInline", "isInBounds", "escape",
+//    or the optimization that was done, e.g., "inlineCall", "doesNotEscape"
 // Message: depending on code, additional information, e.g., the reason a function cannot be inlined.
 // RelatedInformation: if the missed optimization actually occurred at a function inlined at Range,
 //    then the sequence of inlined locations appears here, from (second) outermost to innermost,
@@ -1080,7 +1080,8 @@ func (w *writer) funcExt(obj *types2.Func) {
 			// Linknamed functions are allowed to have no body. Hopefully
 			// the linkname target has a body. See issue 23311.
 			// Wasmimport functions are also allowed to have no body.
-			if _, ok := w.p.linknames[obj]; !ok && wi == nil {
+			// The functions of package simd are compiler intrinsics.
+			if _, ok := w.p.linknames[obj]; !ok && wi == nil && base.Ctxt.Pkgpath != "simd" {
 				w.p.errorf(decl, "missing function body")
 			}
 		}
@@ -782,6 +782,10 @@ func init() {
 		{name: "PXOR", argLength: 2, reg: fp21, asm: "PXOR", commutative: true, resultInArg0: true}, // exclusive or, applied to X regs (for float negation).
 		{name: "POR", argLength: 2, reg: fp21, asm: "POR", commutative: true, resultInArg0: true},   // inclusive or, applied to X regs (for float min/max).
 
+		// Bytewise SIMD operations, used to match map group control words (see runtime/map_swiss_group_amd64.go).
+		{name: "PCMPEQB", argLength: 2, reg: fp21, asm: "PCMPEQB", commutative: true, resultInArg0: true, typ: "Int128"}, // byte i of result is 0xff if byte i of arg0 and arg1 are equal, else 0
+		{name: "PMOVMSKB", argLength: 1, reg: fpgp, asm: "PMOVMSKB", typ: "UInt16"},                                      // bit i of result is the high bit of byte i of arg0
+
 		{name: "LEAQ", argLength: 1, reg: gp11sb, asm: "LEAQ", aux: "SymOff", rematerializeable: true, symEffect: "Addr"}, // arg0 + auxint + offset encoded in aux
 		{name: "LEAL", argLength: 1, reg: gp11sb, asm: "LEAL", aux: "SymOff", rematerializeable: true, symEffect: "Addr"}, // arg0 + auxint + offset encoded in aux
 		{name: "LEAW", argLength: 1, reg: gp11sb, asm: "LEAW", aux: "SymOff", rematerializeable: true, symEffect: "Addr"}, // arg0 + auxint + offset encoded in aux
@@ -1161,6 +1165,8 @@ func init() {
 		{name: "JUMPTABLE", controls: 2, aux: "Sym"},
 	}
 
+	AMD64ops = append(AMD64ops, simdAMD64Ops(fp01, fp11, fp21, fp31, fpgp, fpload, fpstore)...)
+
 	archs = append(archs, arch{
 		name:               "AMD64",
 		pkg:                "cmd/internal/obj/x86",
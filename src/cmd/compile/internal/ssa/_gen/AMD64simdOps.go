@@ -0,0 +1,113 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+// simdAMD64Ops returns the 256-bit vector ops used to implement
+// package simd. Vector values live in the same registers as floats;
+// the code generator uses the Y form of the X register it is given.
+//
+// Unless noted otherwise, binary ops compute arg0 OP arg1.
+func simdAMD64Ops(fp01, fp11, fp21, fp31, fpgp, fpload, fpstore regInfo) []opData {
+	return []opData{
+		{name: "VMOVDQUload256", argLength: 2, reg: fpload, asm: "VMOVDQU", aux: "SymOff", faultOnNilArg0: true, symEffect: "Read"},    // load 32 bytes from arg0+auxint+aux. arg1=mem
+		{name: "VMOVDQUstore256", argLength: 3, reg: fpstore, asm: "VMOVDQU", aux: "SymOff", faultOnNilArg0: true, symEffect: "Write"}, // store 32 bytes of arg1 to arg0+auxint+aux. arg2=mem
+		{name: "Zero256", reg: fp01, asm: "VPXOR", rematerializeable: true},                                                            // all-zero vector
+
+		// Broadcast the low lane of arg0 to every lane.
+		{name: "VPBROADCASTB256", argLength: 1, reg: fp11, asm: "VPBROADCASTB"},
+		{name: "VPBROADCASTW256", argLength: 1, reg: fp11, asm: "VPBROADCASTW"},
+		{name: "VPBROADCASTD256", argLength: 1, reg: fp11, asm: "VPBROADCASTD"},
+		{name: "VPBROADCASTQ256", argLength: 1, reg: fp11, asm: "VPBROADCASTQ"},
+		{name: "VBROADCASTSS256", argLength: 1, reg: fp11, asm: "VBROADCASTSS"},
+		{name: "VBROADCASTSD256", argLength: 1, reg: fp11, asm: "VBROADCASTSD"},
+
+		// Collect the sign bit of every lane of arg0 into a general purpose register.
+		{name: "VPMOVMSKB256", argLength: 1, reg: fpgp, asm: "VPMOVMSKB"},
+		{name: "VMOVMSKPS256", argLength: 1, reg: fpgp, asm: "VMOVMSKPS"},
+		{name: "VMOVMSKPD256", argLength: 1, reg: fpgp, asm: "VMOVMSKPD"},
+
+		{name: "VPBLENDVB256", argLength: 3, reg: fp31, asm: "VPBLENDVB"}, // bytewise arg2 < 0 ? arg1 : arg0
+
+		// Bitwise ops.
+		{name: "VPAND256", argLength: 2, reg: fp21, asm: "VPAND", commutative: true},
+		{name: "VPOR256", argLength: 2, reg: fp21, asm: "VPOR", commutative: true},
+		{name: "VPXOR256", argLength: 2, reg: fp21, asm: "VPXOR", commutative: true},
+		{name: "VPANDN256", argLength: 2, reg: fp21, asm: "VPANDN"}, // ^arg0 & arg1
+
+		// Integer arithmetic.
+		{name: "VPADDB256", argLength: 2, reg: fp21, asm: "VPADDB", commutative: true},
+		{name: "VPADDW256", argLength: 2, reg: fp21, asm: "VPADDW", commutative: true},
+		{name: "VPADDD256", argLength: 2, reg: fp21, asm: "VPADDD", commutative: true},
+		{name: "VPADDQ256", argLength: 2, reg: fp21, asm: "VPADDQ", commutative: true},
+		{name: "VPSUBB256", argLength: 2, reg: fp21, asm: "VPSUBB"},
+		{name: "VPSUBW256", argLength: 2, reg: fp21, asm: "VPSUBW"},
+		{name: "VPSUBD256", argLength: 2, reg: fp21, asm: "VPSUBD"},
+		{name: "VPSUBQ256", argLength: 2, reg: fp21, asm: "VPSUBQ"},
+		{name: "VPADDSB256", argLength: 2, reg: fp21, asm: "VPADDSB", commutative: true},
+		{name: "VPADDSW256", argLength: 2, reg: fp21, asm: "VPADDSW", commutative: true},
+		{name: "VPADDUSB256", argLength: 2, reg: fp21, asm: "VPADDUSB", commutative: true},
+		{name: "VPADDUSW256", argLength: 2, reg: fp21, asm: "VPADDUSW", commutative: true},
+		{name: "VPSUBSB256", argLength: 2, reg: fp21, asm: "VPSUBSB"},
+		{name: "VPSUBSW256", argLength: 2, reg: fp21, asm: "VPSUBSW"},
+		{name: "VPSUBUSB256", argLength: 2, reg: fp21, asm: "VPSUBUSB"},
+		{name: "VPSUBUSW256", argLength: 2, reg: fp21, asm: "VPSUBUSW"},
+		{name: "VPMULLW256", argLength: 2, reg: fp21, asm: "VPMULLW", commutative: true},
+		{name: "VPMULLD256", argLength: 2, reg: fp21, asm: "VPMULLD", commutative: true},
+		{name: "VPMINSB256", argLength: 2, reg: fp21, asm: "VPMINSB", commutative: true},
+		{name: "VPMINSW256", argLength: 2, reg: fp21, asm: "VPMINSW", commutative: true},
+		{name: "VPMINSD256", argLength: 2, reg: fp21, asm: "VPMINSD", commutative: true},
+		{name: "VPMINUB256", argLength: 2, reg: fp21, asm: "VPMINUB", commutative: true},
+		{name: "VPMINUW256", argLength: 2, reg: fp21, asm: "VPMINUW", commutative: true},
+		{name: "VPMINUD256", argLength: 2, reg: fp21, asm: "VPMINUD", commutative: true},
+		{name: "VPMAXSB256", argLength: 2, reg: fp21, asm: "VPMAXSB", commutative: true},
+		{name: "VPMAXSW256", argLength: 2, reg: fp21, asm: "VPMAXSW", commutative: true},
+		{name: "VPMAXSD256", argLength: 2, reg: fp21, asm: "VPMAXSD", commutative: true},
+		{name: "VPMAXUB256", argLength: 2, reg: fp21, asm: "VPMAXUB", commutative: true},
+		{name: "VPMAXUW256", argLength: 2, reg: fp21, asm: "VPMAXUW", commutative: true},
+		{name: "VPMAXUD256", argLength: 2, reg: fp21, asm: "VPMAXUD", commutative: true},
+		{name: "VPAVGB256", argLength: 2, reg: fp21, asm: "VPAVGB", commutative: true},
+		{name: "VPAVGW256", argLength: 2, reg: fp21, asm: "VPAVGW", commutative: true},
+		{name: "VPABSB256", argLength: 1, reg: fp11, asm: "VPABSB"},
+		{name: "VPABSW256", argLength: 1, reg: fp11, asm: "VPABSW"},
+		{name: "VPABSD256", argLength: 1, reg: fp11, asm: "VPABSD"},
+
+		// Per-lane shifts of arg0 by the corresponding lane of arg1.
+		{name: "VPSLLVD256", argLength: 2, reg: fp21, asm: "VPSLLVD"},
+		{name: "VPSLLVQ256", argLength: 2, reg: fp21, asm: "VPSLLVQ"},
+		{name: "VPSRLVD256", argLength: 2, reg: fp21, asm: "VPSRLVD"},
+		{name: "VPSRLVQ256", argLength: 2, reg: fp21, asm: "VPSRLVQ"},
+		{name: "VPSRAVD256", argLength: 2, reg: fp21, asm: "VPSRAVD"},
+
+		// Integer comparisons. Each lane of the result is all ones or all zeros.
+		{name: "VPCMPEQB256", argLength: 2, reg: fp21, asm: "VPCMPEQB", commutative: true},
+		{name: "VPCMPEQW256", argLength: 2, reg: fp21, asm: "VPCMPEQW", commutative: true},
+		{name: "VPCMPEQD256", argLength: 2, reg: fp21, asm: "VPCMPEQD", commutative: true},
+		{name: "VPCMPEQQ256", argLength: 2, reg: fp21, asm: "VPCMPEQQ", commutative: true},
+		{name: "VPCMPGTB256", argLength: 2, reg: fp21, asm: "VPCMPGTB"}, // signed arg0 > arg1
+		{name: "VPCMPGTW256", argLength: 2, reg: fp21, asm: "VPCMPGTW"},
+		{name: "VPCMPGTD256", argLength: 2, reg: fp21, asm: "VPCMPGTD"},
+		{name: "VPCMPGTQ256", argLength: 2, reg: fp21, asm: "VPCMPGTQ"},
+
+		// Floating point arithmetic.
+		{name: "VADDPS256", argLength: 2, reg: fp21, asm: "VADDPS", commutative: true},
+		{name: "VADDPD256", argLength: 2, reg: fp21, asm: "VADDPD", commutative: true},
+		{name: "VSUBPS256", argLength: 2, reg: fp21, asm: "VSUBPS"},
+		{name: "VSUBPD256", argLength: 2, reg: fp21, asm: "VSUBPD"},
+		{name: "VMULPS256", argLength: 2, reg: fp21, asm: "VMULPS", commutative: true},
+		{name: "VMULPD256", argLength: 2, reg: fp21, asm: "VMULPD", commutative: true},
+		{name: "VDIVPS256", argLength: 2, reg: fp21, asm: "VDIVPS"},
+		{name: "VDIVPD256", argLength: 2, reg: fp21, asm: "VDIVPD"},
+		{name: "VMINPS256", argLength: 2, reg: fp21, asm: "VMINPS"}, // arg0 < arg1 ? arg0 : arg1; not commutative for NaNs and zeros
+		{name: "VMINPD256", argLength: 2, reg: fp21, asm: "VMINPD"},
+		{name: "VMAXPS256", argLength: 2, reg: fp21, asm: "VMAXPS"}, // arg0 > arg1 ? arg0 : arg1
+		{name: "VMAXPD256", argLength: 2, reg: fp21, asm: "VMAXPD"},
+		{name: "VSQRTPS256", argLength: 1, reg: fp11, asm: "VSQRTPS"},
+		{name: "VSQRTPD256", argLength: 1, reg: fp11, asm: "VSQRTPD"},
+
+		// Floating point comparisons; auxint is the VCMPPS/VCMPPD predicate.
+		{name: "VCMPPS256", argLength: 2, reg: fp21, asm: "VCMPPS", aux: "Int8"},
+		{name: "VCMPPD256", argLength: 2, reg: fp21, asm: "VCMPPD", aux: "Int8"},
+	}
+}
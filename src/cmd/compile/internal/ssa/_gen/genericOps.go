@@ -338,6 +338,7 @@ var genericOps = []opData{
 	{name: "Const64F", aux: "Float64"}, // value is math.Float64frombits(uint64(auxint))
 	{name: "ConstInterface"},           // nil interface
 	{name: "ConstSlice"},               // nil slice
+	{name: "ZeroSIMD"},                 // all-zero vector
 
 	// Constant-like things
 	{name: "InitMem", zeroWidth: true},                               // memory input to the function.
@@ -99,6 +99,8 @@ func decomposeBuiltIn(f *Func) {
 			}
 		case t.IsFloat():
 			// floats are never decomposed, even ones bigger than RegSize
+		case t.IsSIMD():
+			// vectors are never decomposed
 		case t.Size() > f.Config.RegSize:
 			f.Fatalf("undecomposed named type %s %v", name, t)
 		}
@@ -134,6 +136,8 @@ func decomposeBuiltInPhi(v *Value) {
 		decomposeInterfacePhi(v)
 	case v.Type.IsFloat():
 		// floats are never decomposed, even ones bigger than RegSize
+	case v.Type.IsSIMD():
+		// vectors are never decomposed
 	case v.Type.Size() > v.Block.Func.Config.RegSize:
 		v.Fatalf("%v undecomposed type %v", v, v.Type)
 	}
@@ -247,7 +251,7 @@ func decomposeUser(f *Func) {
 	for _, name := range f.Names {
 		t := name.Type
 		switch {
-		case t.IsStruct():
+		case t.IsStruct() && !t.IsSIMD():
 			newNames = decomposeUserStructInto(f, name, newNames)
 		case t.IsArray():
 			newNames = decomposeUserArrayInto(f, name, newNames)
@@ -292,7 +296,7 @@ func decomposeUserArrayInto(f *Func, name *LocalSlot, slots []*LocalSlot) []*Loc
 
 	if t.Elem().IsArray() {
 		return decomposeUserArrayInto(f, elemName, slots)
-	} else if t.Elem().IsStruct() {
+	} else if t.Elem().IsStruct() && !t.Elem().IsSIMD() {
 		return decomposeUserStructInto(f, elemName, slots)
 	}
 
@@ -312,7 +316,7 @@ func decomposeUserStructInto(f *Func, name *LocalSlot, slots []*LocalSlot) []*Lo
 		fnames = append(fnames, fs)
 		// arrays and structs will be decomposed further, so
 		// there's no need to record a name
-		if !fs.Type.IsArray() && !fs.Type.IsStruct() {
+		if !fs.Type.IsArray() && (!fs.Type.IsStruct() || fs.Type.IsSIMD()) {
 			slots = maybeAppend(f, slots, fs)
 		}
 	}
@@ -339,7 +343,7 @@ func decomposeUserStructInto(f *Func, name *LocalSlot, slots []*LocalSlot) []*Lo
 	// now that this f.NamedValues contains values for the struct
 	// fields, recurse into nested structs
 	for i := 0; i < n; i++ {
-		if name.Type.FieldType(i).IsStruct() {
+		if ft := name.Type.FieldType(i); ft.IsStruct() && !ft.IsSIMD() {
 			slots = decomposeUserStructInto(f, fnames[i], slots)
 			delete(f.NamedValues, *fnames[i])
 		} else if name.Type.FieldType(i).IsArray() {
@@ -351,6 +355,8 @@ func decomposeUserStructInto(f *Func, name *LocalSlot, slots []*LocalSlot) []*Lo
 }
 func decomposeUserPhi(v *Value) {
 	switch {
+	case v.Type.IsSIMD():
+		// vectors are never decomposed
 	case v.Type.IsStruct():
 		decomposeStructPhi(v)
 	case v.Type.IsArray():
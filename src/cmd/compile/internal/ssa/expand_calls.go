@@ -399,6 +399,9 @@ func (x *expandState) decomposeAsNecessary(pos src.XPos, b *Block, a, m0 *Value,
 		return mem
 
 	case types.TSTRUCT:
+		if at.IsSIMD() {
+			break // vectors are atomic
+		}
 		for i := 0; i < at.NumFields(); i++ {
 			et := at.Field(i).Type // might need to read offsets from the fields
 			e := b.NewValue1I(pos, OpStructSelect, et, int64(i), a)
@@ -546,6 +549,9 @@ func (x *expandState) rewriteSelectOrArg(pos src.XPos, b *Block, container, a, m
 		return a
 
 	case types.TSTRUCT:
+		if at.IsSIMD() {
+			break // vectors are atomic
+		}
 		// Assume ssagen/ssa.go (in buildssa) spills large aggregates so they won't appear here.
 		for i := 0; i < at.NumFields(); i++ {
 			et := at.Field(i).Type
@@ -712,6 +718,9 @@ func (x *expandState) rewriteWideSelectToStores(pos src.XPos, b *Block, containe
 		return m0
 
 	case types.TSTRUCT:
+		if at.IsSIMD() {
+			break // vectors are atomic
+		}
 		// Assume ssagen/ssa.go (in buildssa) spills large aggregates so they won't appear here.
 		for i := 0; i < at.NumFields(); i++ {
 			et := at.Field(i).Type
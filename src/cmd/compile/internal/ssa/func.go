@@ -47,6 +47,10 @@ type Func struct {
 	dumpFileSeq uint8 // the sequence numbers of dump file. (%s_%02d__%s.dump", funcname, dumpFileSeq, phaseName)
 	IsPgoHot    bool
 
+	// PgoCallLines holds the lines of the calls outside of inlined
+	// bodies that were sampled in the PGO profile, if any.
+	PgoCallLines map[uint]bool
+
 	// when register allocation is done, maps value ids to locations
 	RegAlloc []Location
 
@@ -857,6 +861,7 @@ func IsMergeCandidate(n *ir.Name) bool {
 		base.Flag.N != 0 ||
 		n.Class != ir.PAUTO ||
 		n.Type().Size() <= int64(3*types.PtrSize) ||
+		n.Type().IsSIMD() || // SSA-able despite its size
 		n.Addrtaken() ||
 		n.NonMergeable() ||
 		n.OpenDeferSlot() {
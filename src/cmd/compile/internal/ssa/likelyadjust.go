@@ -88,14 +88,15 @@ func max8(a, b int8) int8 {
 }
 
 const (
+	blHOTCALL = -1 // a call that the PGO profile shows is made often
+	blMin     = blHOTCALL
 	blDEFAULT = 0
-	blMin     = blDEFAULT
 	blCALL    = 1
 	blRET     = 2
 	blEXIT    = 3
 )
 
-var bllikelies = [4]string{"default", "call", "ret", "exit"}
+var bllikelies = [5]string{"hot call", "default", "call", "ret", "exit"}
 
 func describePredictionAgrees(b *Block, prediction BranchPrediction) string {
 	s := ""
@@ -115,8 +116,8 @@ func describeBranchPrediction(f *Func, b *Block, likely, not int8, prediction Br
 func likelyadjust(f *Func) {
 	// The values assigned to certain and local only matter
 	// in their rank order.  0 is default, more positive
-	// is less likely. It's possible to assign a negative
-	// unlikeliness (though not currently the case).
+	// is less likely. Calls that the PGO profile shows are
+	// made often have a negative unlikeliness.
 	certain := f.Cache.allocInt8Slice(f.NumBlocks()) // In the long run, all outcomes are at least this bad. Mainly for Exit
 	defer f.Cache.freeInt8Slice(certain)
 	local := f.Cache.allocInt8Slice(f.NumBlocks()) // for our immediate predecessors.
@@ -219,14 +220,25 @@ func likelyadjust(f *Func) {
 					}
 				}
 			}
-			// Look for calls in the block.  If there is one, make this block unlikely.
+			// Look for calls in the block.  If there is one, make this block unlikely,
+			// unless the PGO profile sampled one of the calls, which makes it likely.
+			call, hot := false, false
 			for _, v := range b.Values {
 				if opcodeTable[v.Op].call {
-					local[b.ID] = blCALL
-					certain[b.ID] = max8(blCALL, certain[b.Succs[0].b.ID])
-					break
+					call = true
+					if f.pgoSampledCall(v) {
+						hot = true
+						break
+					}
 				}
 			}
+			switch {
+			case hot:
+				local[b.ID] = blHOTCALL
+			case call:
+				local[b.ID] = blCALL
+				certain[b.ID] = max8(blCALL, certain[b.Succs[0].b.ID])
+			}
 		}
 		if f.pass.debug > 2 {
 			f.Warnl(b.Pos, "BP: Block %s, local=%s, certain=%s", b, bllikelies[local[b.ID]-blMin], bllikelies[certain[b.ID]-blMin])
@@ -235,6 +247,15 @@ func likelyadjust(f *Func) {
 	}
 }
 
+// pgoSampledCall reports whether the PGO profile sampled call v.
+func (f *Func) pgoSampledCall(v *Value) bool {
+	if f.PgoCallLines == nil {
+		return false
+	}
+	pos := f.Config.ctxt.InnermostPos(v.Pos)
+	return pos.Base().InliningIndex() < 0 && f.PgoCallLines[pos.RelLine()]
+}
+
 func (l *loop) String() string {
 	return fmt.Sprintf("hdr:%s", l.header)
 }
@@ -13868,13 +13868,13 @@ func rewriteValuegeneric_OpLoad(v *Value) bool {
 		return true
 	}
 	// match: (Load <t> ptr mem)
-	// cond: t.IsStruct() && t.NumFields() == 2 && CanSSA(t)
+	// cond: t.IsStruct() && t.NumFields() == 2 && CanSSA(t) && !t.IsSIMD()
 	// result: (StructMake2 (Load <t.FieldType(0)> (OffPtr <t.FieldType(0).PtrTo()> [0] ptr) mem) (Load <t.FieldType(1)> (OffPtr <t.FieldType(1).PtrTo()> [t.FieldOff(1)] ptr) mem))
 	for {
 		t := v.Type
 		ptr := v_0
 		mem := v_1
-		if !(t.IsStruct() && t.NumFields() == 2 && CanSSA(t)) {
+		if !(t.IsStruct() && t.NumFields() == 2 && CanSSA(t) && !t.IsSIMD()) {
 			break
 		}
 		v.reset(OpStructMake2)
@@ -294,12 +294,36 @@ func (s *ssafn) AllocFrame(f *ssa.Func) {
 
 const maxStackSize = 1 << 30
 
+// pgoCallLines returns the lines of the calls in fn that were sampled
+// in the PGO profile, or nil if there are none.
+func pgoCallLines(fn *ir.Func, profile *pgoir.Profile) map[uint]bool {
+	if profile == nil {
+		return nil
+	}
+	n, ok := profile.WeightedCG.IRNodes[ir.LinkFuncName(fn)]
+	if !ok {
+		return nil
+	}
+	var lines map[uint]bool
+	start := int(base.Ctxt.InnermostPos(fn.Pos()).RelLine())
+	for _, e := range n.OutEdges {
+		if e.Weight == 0 {
+			continue
+		}
+		if lines == nil {
+			lines = make(map[uint]bool)
+		}
+		lines[uint(start+e.CallSiteOffset)] = true
+	}
+	return lines
+}
+
 // Compile builds an SSA backend function,
 // uses it to generate a plist,
 // and flushes that plist to machine code.
 // worker indicates which of the backend workers is doing the processing.
 func Compile(fn *ir.Func, worker int, profile *pgoir.Profile) {
-	f := buildssa(fn, worker, inline.IsPgoHotFunc(fn, profile) || inline.HasPgoHotInline(fn))
+	f := buildssa(fn, worker, inline.IsPgoHotFunc(fn, profile) || inline.HasPgoHotInline(fn), pgoCallLines(fn, profile))
 	// Note: check arg size to fix issue 25507.
 	if f.Frontend().(*ssafn).stksize >= maxStackSize || f.OwnAux.ArgWidth() >= maxStackSize {
 		largeStackFramesMu.Lock()
@@ -0,0 +1,175 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssagen
+
+import (
+	"cmd/compile/internal/base"
+	"cmd/compile/internal/ir"
+	"cmd/compile/internal/ssa"
+	"cmd/compile/internal/typecheck"
+	"cmd/compile/internal/types"
+	"internal/buildcfg"
+)
+
+// This file contains the intrinsic builders for package simd. The
+// table that maps each function and method of package simd to its
+// builder is generated by src/simd/mkstubs.go into simdintrinsics.go.
+
+// simdLoad builds LoadT(p *[N]E) T.
+func simdLoad() intrinsicBuilder {
+	return func(s *state, n *ir.CallExpr, args []*ssa.Value) *ssa.Value {
+		p := s.nilCheck(args[0])
+		s.instrument(n.Type(), p, instrumentRead)
+		return s.rawLoad(n.Type(), p)
+	}
+}
+
+// simdStore builds (x T) Store(p *[N]E).
+func simdStore() intrinsicBuilder {
+	return func(s *state, n *ir.CallExpr, args []*ssa.Value) *ssa.Value {
+		p := s.nilCheck(args[1])
+		s.instrument(args[0].Type, p, instrumentWrite)
+		s.store(args[0].Type, p, args[0])
+		return nil
+	}
+}
+
+// simdBroadcast builds BroadcastT(x E) T. The broadcast instructions
+// read their operand from a vector register, so unless move is
+// ssa.OpInvalid, x is first moved there from a general purpose
+// register using move.
+func simdBroadcast(move, op ssa.Op) intrinsicBuilder {
+	return func(s *state, n *ir.CallExpr, args []*ssa.Value) *ssa.Value {
+		x := args[0]
+		switch move {
+		case ssa.OpInvalid:
+		case ssa.OpAMD64MOVQi2f:
+			x = s.newValue1(move, types.Types[types.TFLOAT64], x)
+		default:
+			x = s.newValue1(move, types.Types[types.TFLOAT32], x)
+		}
+		return s.newValue1(op, n.Type(), x)
+	}
+}
+
+// simdUnary builds a method computing op(x).
+func simdUnary(op ssa.Op) intrinsicBuilder {
+	return func(s *state, n *ir.CallExpr, args []*ssa.Value) *ssa.Value {
+		return s.newValue1(op, n.Type(), args[0])
+	}
+}
+
+// simdBinary builds a method computing op(x, y).
+func simdBinary(op ssa.Op) intrinsicBuilder {
+	return func(s *state, n *ir.CallExpr, args []*ssa.Value) *ssa.Value {
+		return s.newValue2(op, n.Type(), args[0], args[1])
+	}
+}
+
+// simdBinarySwapped builds a method computing op(y, x).
+func simdBinarySwapped(op ssa.Op) intrinsicBuilder {
+	return func(s *state, n *ir.CallExpr, args []*ssa.Value) *ssa.Value {
+		return s.newValue2(op, n.Type(), args[1], args[0])
+	}
+}
+
+// simdCompare builds a floating point comparison method using the
+// given VCMPPS or VCMPPD predicate.
+func simdCompare(op ssa.Op, pred int64) intrinsicBuilder {
+	return func(s *state, n *ir.CallExpr, args []*ssa.Value) *ssa.Value {
+		return s.newValue2I(op, n.Type(), pred, args[0], args[1])
+	}
+}
+
+// simdBlend builds (x T) Blend(y T, m Mask) T.
+func simdBlend(op ssa.Op) intrinsicBuilder {
+	return func(s *state, n *ir.CallExpr, args []*ssa.Value) *ssa.Value {
+		return s.newValue3(op, n.Type(), args[0], args[1], args[2])
+	}
+}
+
+// simdNot builds (m Mask) Not() Mask as m XOR all ones, where the all
+// ones vector is produced by comparing m with itself using eq.
+func simdNot(eq, xor ssa.Op) intrinsicBuilder {
+	return func(s *state, n *ir.CallExpr, args []*ssa.Value) *ssa.Value {
+		ones := s.newValue2(eq, n.Type(), args[0], args[0])
+		return s.newValue2(xor, n.Type(), args[0], ones)
+	}
+}
+
+// simdReinterpret builds a conversion between vector types of the
+// same size that leaves the bits unchanged.
+func simdReinterpret() intrinsicBuilder {
+	return func(s *state, n *ir.CallExpr, args []*ssa.Value) *ssa.Value {
+		return s.newValue1(ssa.OpCopy, n.Type(), args[0])
+	}
+}
+
+// GenIntrinsicBody gives fn, a function or method of package simd
+// declared without a body, a body that calls the intrinsic that
+// implements it. Calls are normally replaced by the intrinsic at the
+// call site, but the function still needs a body when it is called
+// indirectly, for instance through a method value or an interface.
+//
+// GenIntrinsicBody reports whether it generated a body.
+func GenIntrinsicBody(fn *ir.Func) bool {
+	if len(fn.Body) != 0 || fn.Sym().Pkg.Path != "simd" || findIntrinsic(fn.Sym()) == nil {
+		return false
+	}
+
+	savepos := base.Pos
+	savedcurfn := ir.CurFunc
+
+	pos := fn.Pos()
+	base.Pos = pos
+	ir.CurFunc = fn
+
+	if fn.Dcl == nil {
+		fn.DeclareParams(true)
+	}
+
+	// The callee has fn's symbol, so the call is replaced by the
+	// intrinsic. Methods are called as functions with the receiver
+	// as the first argument.
+	ft := fn.Type()
+	callee := ir.NewNameAt(pos, fn.Sym(), ft)
+	if recv := ft.Recv(); recv != nil {
+		callee.SetType(typecheck.NewMethodType(ft, recv.Type))
+	}
+	callee.Class = ir.PFUNC
+	callee.Func = fn
+
+	call := ir.NewCallExpr(pos, ir.OCALL, callee, nil)
+	for _, p := range fn.Dcl[:len(ft.RecvParams())] {
+		call.Args.Append(p)
+	}
+	if ft.NumResults() > 0 {
+		ret := ir.NewReturnStmt(pos, nil)
+		ret.Results = []ir.Node{call}
+		fn.Body = []ir.Node{ret}
+	} else {
+		fn.Body = []ir.Node{call}
+	}
+	typecheck.Stmts(fn.Body)
+
+	ir.CurFunc = savedcurfn
+	base.Pos = savepos
+	return true
+}
+
+// usesSIMD reports whether f computes any value of a vector type.
+func usesSIMD(f *ssa.Func) bool {
+	if !buildcfg.Experiment.SIMD {
+		return false
+	}
+	for _, b := range f.Blocks {
+		for _, v := range b.Values {
+			if v.Type.IsSIMD() {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,220 @@
+// Code generated by mkstubs.go. DO NOT EDIT.
+
+package ssagen
+
+import (
+	"cmd/compile/internal/ssa"
+	"cmd/internal/sys"
+)
+
+func simdIntrinsics(addF func(pkg, fn string, b intrinsicBuilder, archFamilies ...sys.ArchFamily)) {
+	addF("simd", "LoadInt8x32", simdLoad(), sys.AMD64)
+	addF("simd", "Int8x32.Store", simdStore(), sys.AMD64)
+	addF("simd", "BroadcastInt8x32", simdBroadcast(ssa.OpAMD64MOVLi2f, ssa.OpAMD64VPBROADCASTB256), sys.AMD64)
+	addF("simd", "LoadUint8x32", simdLoad(), sys.AMD64)
+	addF("simd", "Uint8x32.Store", simdStore(), sys.AMD64)
+	addF("simd", "BroadcastUint8x32", simdBroadcast(ssa.OpAMD64MOVLi2f, ssa.OpAMD64VPBROADCASTB256), sys.AMD64)
+	addF("simd", "LoadInt16x16", simdLoad(), sys.AMD64)
+	addF("simd", "Int16x16.Store", simdStore(), sys.AMD64)
+	addF("simd", "BroadcastInt16x16", simdBroadcast(ssa.OpAMD64MOVLi2f, ssa.OpAMD64VPBROADCASTW256), sys.AMD64)
+	addF("simd", "LoadUint16x16", simdLoad(), sys.AMD64)
+	addF("simd", "Uint16x16.Store", simdStore(), sys.AMD64)
+	addF("simd", "BroadcastUint16x16", simdBroadcast(ssa.OpAMD64MOVLi2f, ssa.OpAMD64VPBROADCASTW256), sys.AMD64)
+	addF("simd", "LoadInt32x8", simdLoad(), sys.AMD64)
+	addF("simd", "Int32x8.Store", simdStore(), sys.AMD64)
+	addF("simd", "BroadcastInt32x8", simdBroadcast(ssa.OpAMD64MOVLi2f, ssa.OpAMD64VPBROADCASTD256), sys.AMD64)
+	addF("simd", "LoadUint32x8", simdLoad(), sys.AMD64)
+	addF("simd", "Uint32x8.Store", simdStore(), sys.AMD64)
+	addF("simd", "BroadcastUint32x8", simdBroadcast(ssa.OpAMD64MOVLi2f, ssa.OpAMD64VPBROADCASTD256), sys.AMD64)
+	addF("simd", "LoadInt64x4", simdLoad(), sys.AMD64)
+	addF("simd", "Int64x4.Store", simdStore(), sys.AMD64)
+	addF("simd", "BroadcastInt64x4", simdBroadcast(ssa.OpAMD64MOVQi2f, ssa.OpAMD64VPBROADCASTQ256), sys.AMD64)
+	addF("simd", "LoadUint64x4", simdLoad(), sys.AMD64)
+	addF("simd", "Uint64x4.Store", simdStore(), sys.AMD64)
+	addF("simd", "BroadcastUint64x4", simdBroadcast(ssa.OpAMD64MOVQi2f, ssa.OpAMD64VPBROADCASTQ256), sys.AMD64)
+	addF("simd", "LoadFloat32x8", simdLoad(), sys.AMD64)
+	addF("simd", "Float32x8.Store", simdStore(), sys.AMD64)
+	addF("simd", "BroadcastFloat32x8", simdBroadcast(ssa.OpInvalid, ssa.OpAMD64VBROADCASTSS256), sys.AMD64)
+	addF("simd", "LoadFloat64x4", simdLoad(), sys.AMD64)
+	addF("simd", "Float64x4.Store", simdStore(), sys.AMD64)
+	addF("simd", "BroadcastFloat64x4", simdBroadcast(ssa.OpInvalid, ssa.OpAMD64VBROADCASTSD256), sys.AMD64)
+	addF("simd", "Int8x32.Add", simdBinary(ssa.OpAMD64VPADDB256), sys.AMD64)
+	addF("simd", "Int8x32.Sub", simdBinary(ssa.OpAMD64VPSUBB256), sys.AMD64)
+	addF("simd", "Int8x32.AddSaturated", simdBinary(ssa.OpAMD64VPADDSB256), sys.AMD64)
+	addF("simd", "Int8x32.SubSaturated", simdBinary(ssa.OpAMD64VPSUBSB256), sys.AMD64)
+	addF("simd", "Int8x32.Min", simdBinary(ssa.OpAMD64VPMINSB256), sys.AMD64)
+	addF("simd", "Int8x32.Max", simdBinary(ssa.OpAMD64VPMAXSB256), sys.AMD64)
+	addF("simd", "Int8x32.Abs", simdUnary(ssa.OpAMD64VPABSB256), sys.AMD64)
+	addF("simd", "Int8x32.And", simdBinary(ssa.OpAMD64VPAND256), sys.AMD64)
+	addF("simd", "Int8x32.Or", simdBinary(ssa.OpAMD64VPOR256), sys.AMD64)
+	addF("simd", "Int8x32.Xor", simdBinary(ssa.OpAMD64VPXOR256), sys.AMD64)
+	addF("simd", "Int8x32.AndNot", simdBinarySwapped(ssa.OpAMD64VPANDN256), sys.AMD64)
+	addF("simd", "Int8x32.Equal", simdBinary(ssa.OpAMD64VPCMPEQB256), sys.AMD64)
+	addF("simd", "Int8x32.Greater", simdBinary(ssa.OpAMD64VPCMPGTB256), sys.AMD64)
+	addF("simd", "Int8x32.Less", simdBinarySwapped(ssa.OpAMD64VPCMPGTB256), sys.AMD64)
+	addF("simd", "Uint8x32.Add", simdBinary(ssa.OpAMD64VPADDB256), sys.AMD64)
+	addF("simd", "Uint8x32.Sub", simdBinary(ssa.OpAMD64VPSUBB256), sys.AMD64)
+	addF("simd", "Uint8x32.AddSaturated", simdBinary(ssa.OpAMD64VPADDUSB256), sys.AMD64)
+	addF("simd", "Uint8x32.SubSaturated", simdBinary(ssa.OpAMD64VPSUBUSB256), sys.AMD64)
+	addF("simd", "Uint8x32.Min", simdBinary(ssa.OpAMD64VPMINUB256), sys.AMD64)
+	addF("simd", "Uint8x32.Max", simdBinary(ssa.OpAMD64VPMAXUB256), sys.AMD64)
+	addF("simd", "Uint8x32.Average", simdBinary(ssa.OpAMD64VPAVGB256), sys.AMD64)
+	addF("simd", "Uint8x32.And", simdBinary(ssa.OpAMD64VPAND256), sys.AMD64)
+	addF("simd", "Uint8x32.Or", simdBinary(ssa.OpAMD64VPOR256), sys.AMD64)
+	addF("simd", "Uint8x32.Xor", simdBinary(ssa.OpAMD64VPXOR256), sys.AMD64)
+	addF("simd", "Uint8x32.AndNot", simdBinarySwapped(ssa.OpAMD64VPANDN256), sys.AMD64)
+	addF("simd", "Uint8x32.Equal", simdBinary(ssa.OpAMD64VPCMPEQB256), sys.AMD64)
+	addF("simd", "Int16x16.Add", simdBinary(ssa.OpAMD64VPADDW256), sys.AMD64)
+	addF("simd", "Int16x16.Sub", simdBinary(ssa.OpAMD64VPSUBW256), sys.AMD64)
+	addF("simd", "Int16x16.MulLow", simdBinary(ssa.OpAMD64VPMULLW256), sys.AMD64)
+	addF("simd", "Int16x16.AddSaturated", simdBinary(ssa.OpAMD64VPADDSW256), sys.AMD64)
+	addF("simd", "Int16x16.SubSaturated", simdBinary(ssa.OpAMD64VPSUBSW256), sys.AMD64)
+	addF("simd", "Int16x16.Min", simdBinary(ssa.OpAMD64VPMINSW256), sys.AMD64)
+	addF("simd", "Int16x16.Max", simdBinary(ssa.OpAMD64VPMAXSW256), sys.AMD64)
+	addF("simd", "Int16x16.Abs", simdUnary(ssa.OpAMD64VPABSW256), sys.AMD64)
+	addF("simd", "Int16x16.And", simdBinary(ssa.OpAMD64VPAND256), sys.AMD64)
+	addF("simd", "Int16x16.Or", simdBinary(ssa.OpAMD64VPOR256), sys.AMD64)
+	addF("simd", "Int16x16.Xor", simdBinary(ssa.OpAMD64VPXOR256), sys.AMD64)
+	addF("simd", "Int16x16.AndNot", simdBinarySwapped(ssa.OpAMD64VPANDN256), sys.AMD64)
+	addF("simd", "Int16x16.Equal", simdBinary(ssa.OpAMD64VPCMPEQW256), sys.AMD64)
+	addF("simd", "Int16x16.Greater", simdBinary(ssa.OpAMD64VPCMPGTW256), sys.AMD64)
+	addF("simd", "Int16x16.Less", simdBinarySwapped(ssa.OpAMD64VPCMPGTW256), sys.AMD64)
+	addF("simd", "Uint16x16.Add", simdBinary(ssa.OpAMD64VPADDW256), sys.AMD64)
+	addF("simd", "Uint16x16.Sub", simdBinary(ssa.OpAMD64VPSUBW256), sys.AMD64)
+	addF("simd", "Uint16x16.MulLow", simdBinary(ssa.OpAMD64VPMULLW256), sys.AMD64)
+	addF("simd", "Uint16x16.AddSaturated", simdBinary(ssa.OpAMD64VPADDUSW256), sys.AMD64)
+	addF("simd", "Uint16x16.SubSaturated", simdBinary(ssa.OpAMD64VPSUBUSW256), sys.AMD64)
+	addF("simd", "Uint16x16.Min", simdBinary(ssa.OpAMD64VPMINUW256), sys.AMD64)
+	addF("simd", "Uint16x16.Max", simdBinary(ssa.OpAMD64VPMAXUW256), sys.AMD64)
+	addF("simd", "Uint16x16.Average", simdBinary(ssa.OpAMD64VPAVGW256), sys.AMD64)
+	addF("simd", "Uint16x16.And", simdBinary(ssa.OpAMD64VPAND256), sys.AMD64)
+	addF("simd", "Uint16x16.Or", simdBinary(ssa.OpAMD64VPOR256), sys.AMD64)
+	addF("simd", "Uint16x16.Xor", simdBinary(ssa.OpAMD64VPXOR256), sys.AMD64)
+	addF("simd", "Uint16x16.AndNot", simdBinarySwapped(ssa.OpAMD64VPANDN256), sys.AMD64)
+	addF("simd", "Uint16x16.Equal", simdBinary(ssa.OpAMD64VPCMPEQW256), sys.AMD64)
+	addF("simd", "Int32x8.Add", simdBinary(ssa.OpAMD64VPADDD256), sys.AMD64)
+	addF("simd", "Int32x8.Sub", simdBinary(ssa.OpAMD64VPSUBD256), sys.AMD64)
+	addF("simd", "Int32x8.MulLow", simdBinary(ssa.OpAMD64VPMULLD256), sys.AMD64)
+	addF("simd", "Int32x8.Min", simdBinary(ssa.OpAMD64VPMINSD256), sys.AMD64)
+	addF("simd", "Int32x8.Max", simdBinary(ssa.OpAMD64VPMAXSD256), sys.AMD64)
+	addF("simd", "Int32x8.Abs", simdUnary(ssa.OpAMD64VPABSD256), sys.AMD64)
+	addF("simd", "Int32x8.ShiftLeft", simdBinary(ssa.OpAMD64VPSLLVD256), sys.AMD64)
+	addF("simd", "Int32x8.ShiftRight", simdBinary(ssa.OpAMD64VPSRAVD256), sys.AMD64)
+	addF("simd", "Int32x8.And", simdBinary(ssa.OpAMD64VPAND256), sys.AMD64)
+	addF("simd", "Int32x8.Or", simdBinary(ssa.OpAMD64VPOR256), sys.AMD64)
+	addF("simd", "Int32x8.Xor", simdBinary(ssa.OpAMD64VPXOR256), sys.AMD64)
+	addF("simd", "Int32x8.AndNot", simdBinarySwapped(ssa.OpAMD64VPANDN256), sys.AMD64)
+	addF("simd", "Int32x8.Equal", simdBinary(ssa.OpAMD64VPCMPEQD256), sys.AMD64)
+	addF("simd", "Int32x8.Greater", simdBinary(ssa.OpAMD64VPCMPGTD256), sys.AMD64)
+	addF("simd", "Int32x8.Less", simdBinarySwapped(ssa.OpAMD64VPCMPGTD256), sys.AMD64)
+	addF("simd", "Uint32x8.Add", simdBinary(ssa.OpAMD64VPADDD256), sys.AMD64)
+	addF("simd", "Uint32x8.Sub", simdBinary(ssa.OpAMD64VPSUBD256), sys.AMD64)
+	addF("simd", "Uint32x8.MulLow", simdBinary(ssa.OpAMD64VPMULLD256), sys.AMD64)
+	addF("simd", "Uint32x8.Min", simdBinary(ssa.OpAMD64VPMINUD256), sys.AMD64)
+	addF("simd", "Uint32x8.Max", simdBinary(ssa.OpAMD64VPMAXUD256), sys.AMD64)
+	addF("simd", "Uint32x8.ShiftLeft", simdBinary(ssa.OpAMD64VPSLLVD256), sys.AMD64)
+	addF("simd", "Uint32x8.ShiftRight", simdBinary(ssa.OpAMD64VPSRLVD256), sys.AMD64)
+	addF("simd", "Uint32x8.And", simdBinary(ssa.OpAMD64VPAND256), sys.AMD64)
+	addF("simd", "Uint32x8.Or", simdBinary(ssa.OpAMD64VPOR256), sys.AMD64)
+	addF("simd", "Uint32x8.Xor", simdBinary(ssa.OpAMD64VPXOR256), sys.AMD64)
+	addF("simd", "Uint32x8.AndNot", simdBinarySwapped(ssa.OpAMD64VPANDN256), sys.AMD64)
+	addF("simd", "Uint32x8.Equal", simdBinary(ssa.OpAMD64VPCMPEQD256), sys.AMD64)
+	addF("simd", "Int64x4.Add", simdBinary(ssa.OpAMD64VPADDQ256), sys.AMD64)
+	addF("simd", "Int64x4.Sub", simdBinary(ssa.OpAMD64VPSUBQ256), sys.AMD64)
+	addF("simd", "Int64x4.ShiftLeft", simdBinary(ssa.OpAMD64VPSLLVQ256), sys.AMD64)
+	addF("simd", "Int64x4.And", simdBinary(ssa.OpAMD64VPAND256), sys.AMD64)
+	addF("simd", "Int64x4.Or", simdBinary(ssa.OpAMD64VPOR256), sys.AMD64)
+	addF("simd", "Int64x4.Xor", simdBinary(ssa.OpAMD64VPXOR256), sys.AMD64)
+	addF("simd", "Int64x4.AndNot", simdBinarySwapped(ssa.OpAMD64VPANDN256), sys.AMD64)
+	addF("simd", "Int64x4.Equal", simdBinary(ssa.OpAMD64VPCMPEQQ256), sys.AMD64)
+	addF("simd", "Int64x4.Greater", simdBinary(ssa.OpAMD64VPCMPGTQ256), sys.AMD64)
+	addF("simd", "Int64x4.Less", simdBinarySwapped(ssa.OpAMD64VPCMPGTQ256), sys.AMD64)
+	addF("simd", "Uint64x4.Add", simdBinary(ssa.OpAMD64VPADDQ256), sys.AMD64)
+	addF("simd", "Uint64x4.Sub", simdBinary(ssa.OpAMD64VPSUBQ256), sys.AMD64)
+	addF("simd", "Uint64x4.ShiftLeft", simdBinary(ssa.OpAMD64VPSLLVQ256), sys.AMD64)
+	addF("simd", "Uint64x4.ShiftRight", simdBinary(ssa.OpAMD64VPSRLVQ256), sys.AMD64)
+	addF("simd", "Uint64x4.And", simdBinary(ssa.OpAMD64VPAND256), sys.AMD64)
+	addF("simd", "Uint64x4.Or", simdBinary(ssa.OpAMD64VPOR256), sys.AMD64)
+	addF("simd", "Uint64x4.Xor", simdBinary(ssa.OpAMD64VPXOR256), sys.AMD64)
+	addF("simd", "Uint64x4.AndNot", simdBinarySwapped(ssa.OpAMD64VPANDN256), sys.AMD64)
+	addF("simd", "Uint64x4.Equal", simdBinary(ssa.OpAMD64VPCMPEQQ256), sys.AMD64)
+	addF("simd", "Float32x8.Add", simdBinary(ssa.OpAMD64VADDPS256), sys.AMD64)
+	addF("simd", "Float32x8.Sub", simdBinary(ssa.OpAMD64VSUBPS256), sys.AMD64)
+	addF("simd", "Float32x8.Mul", simdBinary(ssa.OpAMD64VMULPS256), sys.AMD64)
+	addF("simd", "Float32x8.Div", simdBinary(ssa.OpAMD64VDIVPS256), sys.AMD64)
+	addF("simd", "Float32x8.Min", simdBinary(ssa.OpAMD64VMINPS256), sys.AMD64)
+	addF("simd", "Float32x8.Max", simdBinary(ssa.OpAMD64VMAXPS256), sys.AMD64)
+	addF("simd", "Float32x8.Sqrt", simdUnary(ssa.OpAMD64VSQRTPS256), sys.AMD64)
+	addF("simd", "Float32x8.Equal", simdCompare(ssa.OpAMD64VCMPPS256, 0x00), sys.AMD64)
+	addF("simd", "Float32x8.NotEqual", simdCompare(ssa.OpAMD64VCMPPS256, 0x04), sys.AMD64)
+	addF("simd", "Float32x8.Less", simdCompare(ssa.OpAMD64VCMPPS256, 0x01), sys.AMD64)
+	addF("simd", "Float32x8.LessEqual", simdCompare(ssa.OpAMD64VCMPPS256, 0x02), sys.AMD64)
+	addF("simd", "Float32x8.Greater", simdCompare(ssa.OpAMD64VCMPPS256, 0x0e), sys.AMD64)
+	addF("simd", "Float32x8.GreaterEqual", simdCompare(ssa.OpAMD64VCMPPS256, 0x0d), sys.AMD64)
+	addF("simd", "Float64x4.Add", simdBinary(ssa.OpAMD64VADDPD256), sys.AMD64)
+	addF("simd", "Float64x4.Sub", simdBinary(ssa.OpAMD64VSUBPD256), sys.AMD64)
+	addF("simd", "Float64x4.Mul", simdBinary(ssa.OpAMD64VMULPD256), sys.AMD64)
+	addF("simd", "Float64x4.Div", simdBinary(ssa.OpAMD64VDIVPD256), sys.AMD64)
+	addF("simd", "Float64x4.Min", simdBinary(ssa.OpAMD64VMINPD256), sys.AMD64)
+	addF("simd", "Float64x4.Max", simdBinary(ssa.OpAMD64VMAXPD256), sys.AMD64)
+	addF("simd", "Float64x4.Sqrt", simdUnary(ssa.OpAMD64VSQRTPD256), sys.AMD64)
+	addF("simd", "Float64x4.Equal", simdCompare(ssa.OpAMD64VCMPPD256, 0x00), sys.AMD64)
+	addF("simd", "Float64x4.NotEqual", simdCompare(ssa.OpAMD64VCMPPD256, 0x04), sys.AMD64)
+	addF("simd", "Float64x4.Less", simdCompare(ssa.OpAMD64VCMPPD256, 0x01), sys.AMD64)
+	addF("simd", "Float64x4.LessEqual", simdCompare(ssa.OpAMD64VCMPPD256, 0x02), sys.AMD64)
+	addF("simd", "Float64x4.Greater", simdCompare(ssa.OpAMD64VCMPPD256, 0x0e), sys.AMD64)
+	addF("simd", "Float64x4.GreaterEqual", simdCompare(ssa.OpAMD64VCMPPD256, 0x0d), sys.AMD64)
+	addF("simd", "Int8x32.Blend", simdBlend(ssa.OpAMD64VPBLENDVB256), sys.AMD64)
+	addF("simd", "Uint8x32.Blend", simdBlend(ssa.OpAMD64VPBLENDVB256), sys.AMD64)
+	addF("simd", "Int16x16.Blend", simdBlend(ssa.OpAMD64VPBLENDVB256), sys.AMD64)
+	addF("simd", "Uint16x16.Blend", simdBlend(ssa.OpAMD64VPBLENDVB256), sys.AMD64)
+	addF("simd", "Int32x8.Blend", simdBlend(ssa.OpAMD64VPBLENDVB256), sys.AMD64)
+	addF("simd", "Uint32x8.Blend", simdBlend(ssa.OpAMD64VPBLENDVB256), sys.AMD64)
+	addF("simd", "Int64x4.Blend", simdBlend(ssa.OpAMD64VPBLENDVB256), sys.AMD64)
+	addF("simd", "Uint64x4.Blend", simdBlend(ssa.OpAMD64VPBLENDVB256), sys.AMD64)
+	addF("simd", "Float32x8.Blend", simdBlend(ssa.OpAMD64VPBLENDVB256), sys.AMD64)
+	addF("simd", "Float64x4.Blend", simdBlend(ssa.OpAMD64VPBLENDVB256), sys.AMD64)
+	addF("simd", "Int8x32.AsUint8x32", simdReinterpret(), sys.AMD64)
+	addF("simd", "Uint8x32.AsInt8x32", simdReinterpret(), sys.AMD64)
+	addF("simd", "Int16x16.AsUint16x16", simdReinterpret(), sys.AMD64)
+	addF("simd", "Uint16x16.AsInt16x16", simdReinterpret(), sys.AMD64)
+	addF("simd", "Int32x8.AsUint32x8", simdReinterpret(), sys.AMD64)
+	addF("simd", "Int32x8.AsFloat32x8", simdReinterpret(), sys.AMD64)
+	addF("simd", "Uint32x8.AsInt32x8", simdReinterpret(), sys.AMD64)
+	addF("simd", "Uint32x8.AsFloat32x8", simdReinterpret(), sys.AMD64)
+	addF("simd", "Int64x4.AsUint64x4", simdReinterpret(), sys.AMD64)
+	addF("simd", "Int64x4.AsFloat64x4", simdReinterpret(), sys.AMD64)
+	addF("simd", "Uint64x4.AsInt64x4", simdReinterpret(), sys.AMD64)
+	addF("simd", "Uint64x4.AsFloat64x4", simdReinterpret(), sys.AMD64)
+	addF("simd", "Float32x8.AsInt32x8", simdReinterpret(), sys.AMD64)
+	addF("simd", "Float32x8.AsUint32x8", simdReinterpret(), sys.AMD64)
+	addF("simd", "Float64x4.AsInt64x4", simdReinterpret(), sys.AMD64)
+	addF("simd", "Float64x4.AsUint64x4", simdReinterpret(), sys.AMD64)
+	addF("simd", "Mask8x32.And", simdBinary(ssa.OpAMD64VPAND256), sys.AMD64)
+	addF("simd", "Mask8x32.Or", simdBinary(ssa.OpAMD64VPOR256), sys.AMD64)
+	addF("simd", "Mask8x32.Xor", simdBinary(ssa.OpAMD64VPXOR256), sys.AMD64)
+	addF("simd", "Mask8x32.AndNot", simdBinarySwapped(ssa.OpAMD64VPANDN256), sys.AMD64)
+	addF("simd", "Mask8x32.Not", simdNot(ssa.OpAMD64VPCMPEQB256, ssa.OpAMD64VPXOR256), sys.AMD64)
+	addF("simd", "Mask8x32.AsInt8x32", simdReinterpret(), sys.AMD64)
+	addF("simd", "Mask8x32.ToBits", simdUnary(ssa.OpAMD64VPMOVMSKB256), sys.AMD64)
+	addF("simd", "Mask16x16.And", simdBinary(ssa.OpAMD64VPAND256), sys.AMD64)
+	addF("simd", "Mask16x16.Or", simdBinary(ssa.OpAMD64VPOR256), sys.AMD64)
+	addF("simd", "Mask16x16.Xor", simdBinary(ssa.OpAMD64VPXOR256), sys.AMD64)
+	addF("simd", "Mask16x16.AndNot", simdBinarySwapped(ssa.OpAMD64VPANDN256), sys.AMD64)
+	addF("simd", "Mask16x16.Not", simdNot(ssa.OpAMD64VPCMPEQB256, ssa.OpAMD64VPXOR256), sys.AMD64)
+	addF("simd", "Mask16x16.AsInt16x16", simdReinterpret(), sys.AMD64)
+	addF("simd", "Mask32x8.And", simdBinary(ssa.OpAMD64VPAND256), sys.AMD64)
+	addF("simd", "Mask32x8.Or", simdBinary(ssa.OpAMD64VPOR256), sys.AMD64)
+	addF("simd", "Mask32x8.Xor", simdBinary(ssa.OpAMD64VPXOR256), sys.AMD64)
+	addF("simd", "Mask32x8.AndNot", simdBinarySwapped(ssa.OpAMD64VPANDN256), sys.AMD64)
+	addF("simd", "Mask32x8.Not", simdNot(ssa.OpAMD64VPCMPEQB256, ssa.OpAMD64VPXOR256), sys.AMD64)
+	addF("simd", "Mask32x8.AsInt32x8", simdReinterpret(), sys.AMD64)
+	addF("simd", "Mask32x8.ToBits", simdUnary(ssa.OpAMD64VMOVMSKPS256), sys.AMD64)
+	addF("simd", "Mask64x4.And", simdBinary(ssa.OpAMD64VPAND256), sys.AMD64)
+	addF("simd", "Mask64x4.Or", simdBinary(ssa.OpAMD64VPOR256), sys.AMD64)
+	addF("simd", "Mask64x4.Xor", simdBinary(ssa.OpAMD64VPXOR256), sys.AMD64)
+	addF("simd", "Mask64x4.AndNot", simdBinarySwapped(ssa.OpAMD64VPANDN256), sys.AMD64)
+	addF("simd", "Mask64x4.Not", simdNot(ssa.OpAMD64VPCMPEQB256, ssa.OpAMD64VPXOR256), sys.AMD64)
+	addF("simd", "Mask64x4.AsInt64x4", simdReinterpret(), sys.AMD64)
+	addF("simd", "Mask64x4.ToBits", simdUnary(ssa.OpAMD64VMOVMSKPD256), sys.AMD64)
+}
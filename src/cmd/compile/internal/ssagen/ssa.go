@@ -295,7 +295,9 @@ func (s *state) emitOpenDeferInfo() {
 
 // buildssa builds an SSA function for fn.
 // worker indicates which of the backend workers is doing the processing.
-func buildssa(fn *ir.Func, worker int, isPgoHot bool) *ssa.Func {
+// pgoCallLines holds the lines of the calls in fn sampled in the PGO
+// profile, if any.
+func buildssa(fn *ir.Func, worker int, isPgoHot bool, pgoCallLines map[uint]bool) *ssa.Func {
 	name := ir.FuncName(fn)
 
 	abiSelf := abiForFunc(fn, ssaConfig.ABI0, ssaConfig.ABI1)
@@ -378,6 +380,7 @@ func buildssa(fn *ir.Func, worker int, isPgoHot bool) *ssa.Func {
 	s.f.Entry = s.f.NewBlock(ssa.BlockPlain)
 	s.f.Entry.Pos = fn.Pos()
 	s.f.IsPgoHot = isPgoHot
+	s.f.PgoCallLines = pgoCallLines
 
 	if printssa {
 		ssaDF := ssaDumpFile
@@ -4054,6 +4057,8 @@ func (s *state) zeroVal(t *types.Type) *ssa.Value {
 		return s.constInterface(t)
 	case t.IsSlice():
 		return s.constSlice(t)
+	case t.IsSIMD():
+		return s.entryNewValue0(ssa.OpZeroSIMD, t)
 	case t.IsStruct():
 		n := t.NumFields()
 		v := s.entryNewValue0(ssa.StructMakeOp(t.NumFields()), t)
@@ -4298,6 +4303,44 @@ func InitTables() {
 		},
 		sys.ARM64, sys.PPC64, sys.RISCV64)
 
+	// Swiss map group matching. On amd64 the eight control bytes of a
+	// group are compared in parallel with SSE2, producing a packed
+	// bitset. See runtime/map_swiss_group_amd64.go.
+	addF("runtime", "ctrlGroupMatchH2",
+		func(s *state, n *ir.CallExpr, args []*ssa.Value) *ssa.Value {
+			// Broadcast the H2 hash to every byte.
+			lsb := s.constInt64(types.Types[types.TUINT64], 0x0101010101010101)
+			h := s.newValue2(ssa.OpMul64, types.Types[types.TUINT64], args[1], lsb)
+			return ctrlGroupMatchAMD64(s, args[0], h)
+		},
+		sys.AMD64)
+	addF("runtime", "ctrlGroupMatchEmpty",
+		func(s *state, n *ir.CallExpr, args []*ssa.Value) *ssa.Value {
+			// 0x8080808080808080: ctrlEmpty in every byte.
+			empty := s.constInt64(types.Types[types.TUINT64], ^0x7f7f7f7f7f7f7f7f)
+			return ctrlGroupMatchAMD64(s, args[0], empty)
+		},
+		sys.AMD64)
+	addF("runtime", "ctrlGroupMatchEmptyOrDeleted",
+		func(s *state, n *ir.CallExpr, args []*ssa.Value) *ssa.Value {
+			// Empty and deleted slots are exactly those with the
+			// high bit set.
+			x := s.newValue1(ssa.OpAMD64MOVQi2f, types.Types[types.TFLOAT64], args[0])
+			mask := s.newValue1(ssa.OpAMD64PMOVMSKB, types.Types[types.TUINT16], x)
+			return s.newValue1(ssa.OpZeroExt16to64, types.Types[types.TUINT64], mask)
+		},
+		sys.AMD64)
+	addF("runtime", "ctrlGroupMatchFull",
+		func(s *state, n *ir.CallExpr, args []*ssa.Value) *ssa.Value {
+			// Full slots are exactly those with the high bit clear.
+			x := s.newValue1(ssa.OpAMD64MOVQi2f, types.Types[types.TFLOAT64], args[0])
+			mask := s.newValue1(ssa.OpAMD64PMOVMSKB, types.Types[types.TUINT16], x)
+			mask = s.newValue1(ssa.OpTrunc16to8, types.Types[types.TUINT8], mask)
+			mask = s.newValue1(ssa.OpCom8, types.Types[types.TUINT8], mask)
+			return s.newValue1(ssa.OpZeroExt8to64, types.Types[types.TUINT64], mask)
+		},
+		sys.AMD64)
+
 	brev_arch := []sys.ArchFamily{sys.AMD64, sys.I386, sys.ARM64, sys.ARM, sys.S390X}
 	if buildcfg.GOPPC64 >= 10 {
 		// Use only on Power10 as the new byte reverse instructions that Power10 provide
@@ -5175,6 +5218,24 @@ func InitTables() {
 
 	/******** math/big ********/
 	alias("math/big", "mulWW", "math/bits", "Mul64", p8...)
+
+	/******** simd ********/
+	if buildcfg.Experiment.SIMD {
+		simdIntrinsics(addF)
+	}
+}
+
+// ctrlGroupMatchAMD64 returns a packed bitset with bit i set if byte i
+// of the map group control word g equals byte i of v.
+func ctrlGroupMatchAMD64(s *state, g, v *ssa.Value) *ssa.Value {
+	x := s.newValue1(ssa.OpAMD64MOVQi2f, types.Types[types.TFLOAT64], g)
+	y := s.newValue1(ssa.OpAMD64MOVQi2f, types.Types[types.TFLOAT64], v)
+	eq := s.newValue2(ssa.OpAMD64PCMPEQB, types.TypeInt128, x, y)
+	mask := s.newValue1(ssa.OpAMD64PMOVMSKB, types.Types[types.TUINT16], eq)
+	// MOVQ zeroes the upper eight bytes of both operands, which then
+	// compare equal. Only the low eight bits of the mask are meaningful.
+	mask = s.newValue1(ssa.OpTrunc16to8, types.Types[types.TUINT8], mask)
+	return s.newValue1(ssa.OpZeroExt8to64, types.Types[types.TUINT64], mask)
 }
 
 // findIntrinsic returns a function which builds the SSA equivalent of the
@@ -5202,6 +5263,8 @@ func findIntrinsic(sym *types.Sym) intrinsicBuilder {
 	if ssa.IntrinsicsDisable {
 		if pkg == "runtime" && (fn == "getcallerpc" || fn == "getcallersp" || fn == "getclosureptr") {
 			// These runtime functions don't have definitions, must be intrinsics.
+		} else if pkg == "simd" {
+			// Neither do the vector operations.
 		} else {
 			return nil
 		}
@@ -5213,6 +5276,16 @@ func IsIntrinsicCall(n *ir.CallExpr) bool {
 	if n == nil {
 		return false
 	}
+	if n.Fun.Op() == ir.OMETHEXPR {
+		// Method calls are only rewritten to call the method's
+		// function name during walk, but the inliner needs to know
+		// about intrinsic methods, such as those of package simd,
+		// earlier.
+		if meth := ir.MethodExprName(n.Fun); meth != nil {
+			return findIntrinsic(meth.Sym()) != nil
+		}
+		return false
+	}
 	name, ok := n.Fun.(*ir.Name)
 	if !ok {
 		return false
@@ -7100,6 +7173,12 @@ type State struct {
 
 	// wasm: The number of values on the WebAssembly stack. This is only used as a safeguard.
 	OnWasmStackSkipped int
+
+	// UsesSIMD reports whether the function computes values of a
+	// vector type from package simd. On amd64, such a function clears
+	// the upper halves of the vector registers before calls and
+	// returns, to avoid the penalty for mixing AVX and SSE code.
+	UsesSIMD bool
 }
 
 func (s *State) FuncInfo() *obj.FuncInfo {
@@ -7366,6 +7445,7 @@ func genssa(f *ssa.Func, pp *objw.Progs) {
 	// Remember where each block starts.
 	s.bstart = make([]*obj.Prog, f.NumBlocks())
 	s.pp = pp
+	s.UsesSIMD = usesSIMD(f)
 	var progToValue map[*obj.Prog]*ssa.Value
 	var progToBlock map[*obj.Prog]*ssa.Block
 	var valueToProgAfter []*obj.Prog // The first Prog following computation of a value v; v is visible at this point.
@@ -39,10 +39,7 @@ func TestIntendedInlining(t *testing.T) {
 			"adjustpointer",
 			"alignDown",
 			"alignUp",
-			"bucketMask",
-			"bucketShift",
 			"chanbuf",
-			"evacuated",
 			"fastlog2",
 			"float64bits",
 			"funcspdelta",
@@ -62,9 +59,6 @@ func TestIntendedInlining(t *testing.T) {
 			"stringStructOf",
 			"subtract1",
 			"subtractb",
-			"tophash",
-			"(*bmap).keys",
-			"(*bmap).overflow",
 			"(*waitq).enqueue",
 			"funcInfo.entry",
 
@@ -261,6 +255,31 @@ func TestIntendedInlining(t *testing.T) {
 		want["sync/atomic"] = append(want["sync/atomic"], "(*Bool).CompareAndSwap")
 	}
 
+	if goexperiment.SwissMap {
+		want["runtime"] = append(want["runtime"],
+			"h1",
+			"h2",
+			"makeProbeSeq",
+			"probeSeq.next",
+			"bitset.removeFirst",
+			"(*ctrlGroup).set",
+			"(*groupReference).key",
+			"(*groupReference).elem",
+			"(*groupsReference).group",
+			"(*hmap).directoryAt",
+			"(*hmap).directoryIndex",
+		)
+	} else {
+		want["runtime"] = append(want["runtime"],
+			"bucketMask",
+			"bucketShift",
+			"evacuated",
+			"tophash",
+			"(*bmap).keys",
+			"(*bmap).overflow",
+		)
+	}
+
 	switch runtime.GOARCH {
 	case "386", "wasm", "arm":
 	default:
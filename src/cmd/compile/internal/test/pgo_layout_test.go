@@ -0,0 +1,68 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"internal/testenv"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const pgoLayoutSrc = `package p
+
+//go:noinline
+func hot() int { return 1 }
+
+//go:noinline
+func cold() int { return 2 }
+
+func F(x bool) int {
+	r := 0
+	if x {
+		r = cold()
+	} else {
+		r = hot()
+	}
+	return r + 1
+}
+`
+
+// TestPGOBranchPrediction tests that a branch to a call that the
+// profile sampled is predicted to be taken over a branch to a call that
+// it did not, so that the block with the unsampled call is laid out
+// out of the way.
+func TestPGOBranchPrediction(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+	t.Parallel()
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "p.go")
+	if err := os.WriteFile(src, []byte(pgoLayoutSrc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// The call to hot is 5 lines after the start of F.
+	prof := filepath.Join(dir, "p.preprofile")
+	if err := os.WriteFile(prof, []byte("GO PREPROFILE V1\np.F\np.hot\n5 10\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	const want = "p.go:11:2: Branch prediction rule hot call < call"
+	for _, pgo := range []bool{false, true} {
+		args := []string{"tool", "compile", "-p=p", "-d=ssa/likelyadjust/debug=1", "-o", filepath.Join(dir, "p.o")}
+		if pgo {
+			args = append(args, "-pgoprofile="+prof)
+		}
+		cmd := testenv.Command(t, testenv.GoToolPath(t), append(args, src)...)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("go tool compile: %v\n%s", err, out)
+		}
+		if got := strings.Contains(string(out), want); got != pgo {
+			t.Errorf("with PGO %v: got %q in output %v, want %v:\n%s", pgo, want, got, pgo, out)
+		}
+	}
+}
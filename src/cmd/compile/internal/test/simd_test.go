@@ -0,0 +1,101 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"internal/testenv"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// Make sure a function using package simd executes VZEROUPPER before
+// its calls and its return, and a function that does not never does.
+func TestSIMDZeroUpper(t *testing.T) {
+	if runtime.GOARCH != "amd64" {
+		t.Skip("package simd is only supported on amd64")
+	}
+	testenv.MustHaveGoBuild(t)
+	t.Parallel()
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "test.go")
+	err := os.WriteFile(src, []byte(`
+package main
+
+import "simd"
+
+//go:noinline
+func add(a, b *[8]int32) {
+	simd.LoadInt32x8(a).Add(simd.LoadInt32x8(b)).Store(a)
+	println(a[0])
+}
+
+//go:noinline
+func scalar(a *[8]int32) {
+	println(a[0])
+}
+
+func main() {
+	var a, b [8]int32
+	add(&a, &b)
+	scalar(&a)
+}
+`), 0666)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := testenv.Command(t, testenv.GoToolPath(t), "build", "-gcflags=-S", "-o", filepath.Join(dir, "test"), src)
+	cmd.Env = append(cmd.Environ(), "GOEXPERIMENT=simd")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("could not build target: %v\n%s", err, out)
+	}
+
+	// For each call and return in the output, record whether a
+	// VZEROUPPER precedes it. The frame teardown may come in between
+	// VZEROUPPER and RET.
+	funcs := make(map[string][]string)
+	var fn string
+	zeroed := false
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 || !strings.HasPrefix(fields[0], "0x") {
+			continue
+		}
+		switch op := fields[3]; op {
+		case "TEXT":
+			fn = strings.TrimSuffix(fields[4], "(SB),")
+			zeroed = false
+		case "VZEROUPPER":
+			zeroed = true
+		case "CALL", "RET":
+			if !strings.Contains(line, "morestack") {
+				if zeroed {
+					op = "VZEROUPPER " + op
+				}
+				funcs[fn] = append(funcs[fn], op)
+			}
+			zeroed = false
+		}
+	}
+
+	if len(funcs["main.add"]) == 0 {
+		t.Fatalf("no calls or returns found in main.add:\n%s", out)
+	}
+	for _, op := range funcs["main.add"] {
+		if !strings.HasPrefix(op, "VZEROUPPER ") {
+			t.Errorf("main.add: %s not preceded by VZEROUPPER", op)
+		}
+	}
+	for _, op := range funcs["main.scalar"] {
+		if strings.HasPrefix(op, "VZEROUPPER ") {
+			t.Errorf("main.scalar: unexpected %s", op)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !race
+
+package test
+
+import (
+	"internal/testenv"
+	"slices"
+	"testing"
+)
+
+//go:noinline
+func sumMake(n int) int {
+	s := make([]int, n)
+	for i := range s {
+		s[i] = i
+	}
+	t := 0
+	for _, v := range s {
+		t += v
+	}
+	return t
+}
+
+//go:noinline
+func makeLenCap(n, c int) int {
+	s := make([]byte, n, c)
+	return len(s) + cap(s)
+}
+
+//go:noinline
+func sumEvens(xs []int) int {
+	var r []int
+	for _, x := range xs {
+		if x%2 == 0 {
+			r = append(r, x)
+		}
+	}
+	t := 0
+	for _, v := range r {
+		t += v
+	}
+	return t
+}
+
+//go:noinline
+func concat(xs []string) int {
+	var b []byte
+	for _, x := range xs {
+		b = append(b, x...)
+	}
+	return len(b)
+}
+
+//go:noinline
+func appendFrom(s []int) []int {
+	t := append(s, 1)
+	return slices.Clone(t)
+}
+
+//go:noinline
+func appendReset(n int) []int {
+	// Each append starts again from a nil slice, so the slices kept in
+	// r must not share a backing store.
+	var r [][]int
+	for i := 0; i < n; i++ {
+		var s []int
+		s = append(s, i)
+		r = append(r, s)
+	}
+	var out []int
+	for _, s := range r {
+		out = append(out, s[0])
+	}
+	return slices.Clone(out)
+}
+
+func TestStackAllocVariableMake(t *testing.T) {
+	for _, n := range []int{0, 1, 3, 4, 5, 100} {
+		if got, want := sumMake(n), n*(n-1)/2; got != want {
+			t.Errorf("sumMake(%d) = %d, want %d", n, got, want)
+		}
+	}
+	if got := makeLenCap(3, 40); got != 43 {
+		t.Errorf("makeLenCap(3, 40) = %d, want 43", got)
+	}
+	for _, lc := range [][2]int{{-1, 4}, {5, 4}, {-1, 100}, {0, -1}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("makeLenCap(%d, %d) did not panic", lc[0], lc[1])
+				}
+			}()
+			makeLenCap(lc[0], lc[1])
+		}()
+	}
+}
+
+func TestStackAllocAppend(t *testing.T) {
+	if got := sumEvens([]int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}); got != 42 {
+		t.Errorf("sumEvens = %d, want 42", got)
+	}
+	if got := concat([]string{"abc", "def", "0123456789012345678901234567890123456789"}); got != 46 {
+		t.Errorf("concat = %d, want 46", got)
+	}
+	s := []int{7}
+	if got := appendFrom(s); !slices.Equal(got, []int{7, 1}) || !slices.Equal(s, []int{7}) {
+		t.Errorf("appendFrom = %v, %v; want [7 1], [7]", got, s)
+	}
+	if got := appendReset(6); !slices.Equal(got, []int{0, 1, 2, 3, 4, 5}) {
+		t.Errorf("appendReset = %v, want [0 1 2 3 4 5]", got)
+	}
+}
+
+func TestStackAllocAllocs(t *testing.T) {
+	testenv.SkipIfOptimizationOff(t)
+	xs := []int{1, 2, 3, 4}
+	tests := []struct {
+		name   string
+		f      func()
+		allocs float64
+	}{
+		{"make small", func() { sumMake(4) }, 0},
+		{"make large", func() { sumMake(5) }, 1},
+		{"append small", func() { sumEvens(xs) }, 0},
+		{"append large", func() { concat([]string{"0123456789", "0123456789", "0123456789", "0123456789"}) }, 1},
+	}
+	for _, tt := range tests {
+		if got := testing.AllocsPerRun(10, tt.f); got != tt.allocs {
+			t.Errorf("%s: got %v allocs, want %v", tt.name, got, tt.allocs)
+		}
+	}
+}
@@ -104,140 +104,141 @@ var runtimeDecls = [...]struct {
 	{"efaceeq", funcTag, 72},
 	{"panicrangestate", funcTag, 73},
 	{"deferrangefunc", funcTag, 74},
-	{"rand32", funcTag, 75},
-	{"makemap64", funcTag, 77},
-	{"makemap", funcTag, 78},
-	{"makemap_small", funcTag, 79},
-	{"mapaccess1", funcTag, 80},
-	{"mapaccess1_fast32", funcTag, 81},
-	{"mapaccess1_fast64", funcTag, 82},
-	{"mapaccess1_faststr", funcTag, 83},
-	{"mapaccess1_fat", funcTag, 84},
-	{"mapaccess2", funcTag, 85},
-	{"mapaccess2_fast32", funcTag, 86},
-	{"mapaccess2_fast64", funcTag, 87},
-	{"mapaccess2_faststr", funcTag, 88},
-	{"mapaccess2_fat", funcTag, 89},
-	{"mapassign", funcTag, 80},
-	{"mapassign_fast32", funcTag, 81},
-	{"mapassign_fast32ptr", funcTag, 90},
-	{"mapassign_fast64", funcTag, 82},
-	{"mapassign_fast64ptr", funcTag, 90},
-	{"mapassign_faststr", funcTag, 83},
-	{"mapiterinit", funcTag, 91},
-	{"mapdelete", funcTag, 91},
-	{"mapdelete_fast32", funcTag, 92},
-	{"mapdelete_fast64", funcTag, 93},
-	{"mapdelete_faststr", funcTag, 94},
-	{"mapiternext", funcTag, 95},
-	{"mapclear", funcTag, 96},
-	{"makechan64", funcTag, 98},
-	{"makechan", funcTag, 99},
-	{"chanrecv1", funcTag, 101},
-	{"chanrecv2", funcTag, 102},
-	{"chansend1", funcTag, 104},
-	{"closechan", funcTag, 105},
-	{"chanlen", funcTag, 106},
-	{"chancap", funcTag, 106},
-	{"writeBarrier", varTag, 108},
-	{"typedmemmove", funcTag, 109},
-	{"typedmemclr", funcTag, 110},
-	{"typedslicecopy", funcTag, 111},
-	{"selectnbsend", funcTag, 112},
-	{"selectnbrecv", funcTag, 113},
-	{"selectsetpc", funcTag, 114},
-	{"selectgo", funcTag, 115},
+	{"rand", funcTag, 75},
+	{"rand32", funcTag, 76},
+	{"makemap64", funcTag, 78},
+	{"makemap", funcTag, 79},
+	{"makemap_small", funcTag, 80},
+	{"mapaccess1", funcTag, 81},
+	{"mapaccess1_fast32", funcTag, 82},
+	{"mapaccess1_fast64", funcTag, 83},
+	{"mapaccess1_faststr", funcTag, 84},
+	{"mapaccess1_fat", funcTag, 85},
+	{"mapaccess2", funcTag, 86},
+	{"mapaccess2_fast32", funcTag, 87},
+	{"mapaccess2_fast64", funcTag, 88},
+	{"mapaccess2_faststr", funcTag, 89},
+	{"mapaccess2_fat", funcTag, 90},
+	{"mapassign", funcTag, 81},
+	{"mapassign_fast32", funcTag, 82},
+	{"mapassign_fast32ptr", funcTag, 91},
+	{"mapassign_fast64", funcTag, 83},
+	{"mapassign_fast64ptr", funcTag, 91},
+	{"mapassign_faststr", funcTag, 84},
+	{"mapiterinit", funcTag, 92},
+	{"mapdelete", funcTag, 92},
+	{"mapdelete_fast32", funcTag, 93},
+	{"mapdelete_fast64", funcTag, 94},
+	{"mapdelete_faststr", funcTag, 95},
+	{"mapiternext", funcTag, 96},
+	{"mapclear", funcTag, 97},
+	{"makechan64", funcTag, 99},
+	{"makechan", funcTag, 100},
+	{"chanrecv1", funcTag, 102},
+	{"chanrecv2", funcTag, 103},
+	{"chansend1", funcTag, 105},
+	{"closechan", funcTag, 106},
+	{"chanlen", funcTag, 107},
+	{"chancap", funcTag, 107},
+	{"writeBarrier", varTag, 109},
+	{"typedmemmove", funcTag, 110},
+	{"typedmemclr", funcTag, 111},
+	{"typedslicecopy", funcTag, 112},
+	{"selectnbsend", funcTag, 113},
+	{"selectnbrecv", funcTag, 114},
+	{"selectsetpc", funcTag, 115},
+	{"selectgo", funcTag, 116},
 	{"block", funcTag, 9},
-	{"makeslice", funcTag, 116},
-	{"makeslice64", funcTag, 117},
-	{"makeslicecopy", funcTag, 118},
-	{"growslice", funcTag, 120},
-	{"unsafeslicecheckptr", funcTag, 121},
+	{"makeslice", funcTag, 117},
+	{"makeslice64", funcTag, 118},
+	{"makeslicecopy", funcTag, 119},
+	{"growslice", funcTag, 121},
+	{"unsafeslicecheckptr", funcTag, 122},
 	{"panicunsafeslicelen", funcTag, 9},
 	{"panicunsafeslicenilptr", funcTag, 9},
-	{"unsafestringcheckptr", funcTag, 122},
+	{"unsafestringcheckptr", funcTag, 123},
 	{"panicunsafestringlen", funcTag, 9},
 	{"panicunsafestringnilptr", funcTag, 9},
-	{"memmove", funcTag, 123},
-	{"memclrNoHeapPointers", funcTag, 124},
-	{"memclrHasPointers", funcTag, 124},
-	{"memequal", funcTag, 125},
-	{"memequal0", funcTag, 126},
-	{"memequal8", funcTag, 126},
-	{"memequal16", funcTag, 126},
-	{"memequal32", funcTag, 126},
-	{"memequal64", funcTag, 126},
-	{"memequal128", funcTag, 126},
-	{"f32equal", funcTag, 127},
-	{"f64equal", funcTag, 127},
-	{"c64equal", funcTag, 127},
-	{"c128equal", funcTag, 127},
-	{"strequal", funcTag, 127},
-	{"interequal", funcTag, 127},
-	{"nilinterequal", funcTag, 127},
-	{"memhash", funcTag, 128},
-	{"memhash0", funcTag, 129},
-	{"memhash8", funcTag, 129},
-	{"memhash16", funcTag, 129},
-	{"memhash32", funcTag, 129},
-	{"memhash64", funcTag, 129},
-	{"memhash128", funcTag, 129},
-	{"f32hash", funcTag, 130},
-	{"f64hash", funcTag, 130},
-	{"c64hash", funcTag, 130},
-	{"c128hash", funcTag, 130},
-	{"strhash", funcTag, 130},
-	{"interhash", funcTag, 130},
-	{"nilinterhash", funcTag, 130},
-	{"int64div", funcTag, 131},
-	{"uint64div", funcTag, 132},
-	{"int64mod", funcTag, 131},
-	{"uint64mod", funcTag, 132},
-	{"float64toint64", funcTag, 133},
-	{"float64touint64", funcTag, 134},
-	{"float64touint32", funcTag, 135},
-	{"int64tofloat64", funcTag, 136},
-	{"int64tofloat32", funcTag, 138},
-	{"uint64tofloat64", funcTag, 139},
-	{"uint64tofloat32", funcTag, 140},
-	{"uint32tofloat64", funcTag, 141},
-	{"complex128div", funcTag, 142},
-	{"getcallerpc", funcTag, 143},
-	{"getcallersp", funcTag, 143},
+	{"memmove", funcTag, 124},
+	{"memclrNoHeapPointers", funcTag, 125},
+	{"memclrHasPointers", funcTag, 125},
+	{"memequal", funcTag, 126},
+	{"memequal0", funcTag, 127},
+	{"memequal8", funcTag, 127},
+	{"memequal16", funcTag, 127},
+	{"memequal32", funcTag, 127},
+	{"memequal64", funcTag, 127},
+	{"memequal128", funcTag, 127},
+	{"f32equal", funcTag, 128},
+	{"f64equal", funcTag, 128},
+	{"c64equal", funcTag, 128},
+	{"c128equal", funcTag, 128},
+	{"strequal", funcTag, 128},
+	{"interequal", funcTag, 128},
+	{"nilinterequal", funcTag, 128},
+	{"memhash", funcTag, 129},
+	{"memhash0", funcTag, 130},
+	{"memhash8", funcTag, 130},
+	{"memhash16", funcTag, 130},
+	{"memhash32", funcTag, 130},
+	{"memhash64", funcTag, 130},
+	{"memhash128", funcTag, 130},
+	{"f32hash", funcTag, 131},
+	{"f64hash", funcTag, 131},
+	{"c64hash", funcTag, 131},
+	{"c128hash", funcTag, 131},
+	{"strhash", funcTag, 131},
+	{"interhash", funcTag, 131},
+	{"nilinterhash", funcTag, 131},
+	{"int64div", funcTag, 132},
+	{"uint64div", funcTag, 133},
+	{"int64mod", funcTag, 132},
+	{"uint64mod", funcTag, 133},
+	{"float64toint64", funcTag, 134},
+	{"float64touint64", funcTag, 135},
+	{"float64touint32", funcTag, 136},
+	{"int64tofloat64", funcTag, 137},
+	{"int64tofloat32", funcTag, 139},
+	{"uint64tofloat64", funcTag, 140},
+	{"uint64tofloat32", funcTag, 141},
+	{"uint32tofloat64", funcTag, 142},
+	{"complex128div", funcTag, 143},
+	{"getcallerpc", funcTag, 144},
+	{"getcallersp", funcTag, 144},
 	{"racefuncenter", funcTag, 31},
 	{"racefuncexit", funcTag, 9},
 	{"raceread", funcTag, 31},
 	{"racewrite", funcTag, 31},
-	{"racereadrange", funcTag, 144},
-	{"racewriterange", funcTag, 144},
-	{"msanread", funcTag, 144},
-	{"msanwrite", funcTag, 144},
-	{"msanmove", funcTag, 145},
-	{"asanread", funcTag, 144},
-	{"asanwrite", funcTag, 144},
-	{"checkptrAlignment", funcTag, 146},
-	{"checkptrArithmetic", funcTag, 148},
-	{"libfuzzerTraceCmp1", funcTag, 149},
-	{"libfuzzerTraceCmp2", funcTag, 150},
-	{"libfuzzerTraceCmp4", funcTag, 151},
-	{"libfuzzerTraceCmp8", funcTag, 152},
-	{"libfuzzerTraceConstCmp1", funcTag, 149},
-	{"libfuzzerTraceConstCmp2", funcTag, 150},
-	{"libfuzzerTraceConstCmp4", funcTag, 151},
-	{"libfuzzerTraceConstCmp8", funcTag, 152},
-	{"libfuzzerHookStrCmp", funcTag, 153},
-	{"libfuzzerHookEqualFold", funcTag, 153},
-	{"addCovMeta", funcTag, 155},
+	{"racereadrange", funcTag, 145},
+	{"racewriterange", funcTag, 145},
+	{"msanread", funcTag, 145},
+	{"msanwrite", funcTag, 145},
+	{"msanmove", funcTag, 146},
+	{"asanread", funcTag, 145},
+	{"asanwrite", funcTag, 145},
+	{"checkptrAlignment", funcTag, 147},
+	{"checkptrArithmetic", funcTag, 149},
+	{"libfuzzerTraceCmp1", funcTag, 150},
+	{"libfuzzerTraceCmp2", funcTag, 151},
+	{"libfuzzerTraceCmp4", funcTag, 152},
+	{"libfuzzerTraceCmp8", funcTag, 153},
+	{"libfuzzerTraceConstCmp1", funcTag, 150},
+	{"libfuzzerTraceConstCmp2", funcTag, 151},
+	{"libfuzzerTraceConstCmp4", funcTag, 152},
+	{"libfuzzerTraceConstCmp8", funcTag, 153},
+	{"libfuzzerHookStrCmp", funcTag, 154},
+	{"libfuzzerHookEqualFold", funcTag, 154},
+	{"addCovMeta", funcTag, 156},
 	{"x86HasPOPCNT", varTag, 6},
 	{"x86HasSSE41", varTag, 6},
 	{"x86HasFMA", varTag, 6},
 	{"armHasVFPv4", varTag, 6},
 	{"arm64HasATOMICS", varTag, 6},
-	{"asanregisterglobals", funcTag, 124},
+	{"asanregisterglobals", funcTag, 125},
 }
 
 func runtimeTypes() []*types.Type {
-	var typs [156]*types.Type
+	var typs [157]*types.Type
 	typs[0] = types.ByteType
 	typs[1] = types.NewPtr(typs[0])
 	typs[2] = types.Types[types.TANY]
@@ -313,87 +314,88 @@ func runtimeTypes() []*types.Type {
 	typs[72] = newSig(params(typs[71], typs[7], typs[7]), params(typs[6]))
 	typs[73] = newSig(params(typs[15]), nil)
 	typs[74] = newSig(nil, params(typs[10]))
-	typs[75] = newSig(nil, params(typs[60]))
-	typs[76] = types.NewMap(typs[2], typs[2])
-	typs[77] = newSig(params(typs[1], typs[22], typs[3]), params(typs[76]))
-	typs[78] = newSig(params(typs[1], typs[15], typs[3]), params(typs[76]))
-	typs[79] = newSig(nil, params(typs[76]))
-	typs[80] = newSig(params(typs[1], typs[76], typs[3]), params(typs[3]))
-	typs[81] = newSig(params(typs[1], typs[76], typs[60]), params(typs[3]))
-	typs[82] = newSig(params(typs[1], typs[76], typs[24]), params(typs[3]))
-	typs[83] = newSig(params(typs[1], typs[76], typs[28]), params(typs[3]))
-	typs[84] = newSig(params(typs[1], typs[76], typs[3], typs[1]), params(typs[3]))
-	typs[85] = newSig(params(typs[1], typs[76], typs[3]), params(typs[3], typs[6]))
-	typs[86] = newSig(params(typs[1], typs[76], typs[60]), params(typs[3], typs[6]))
-	typs[87] = newSig(params(typs[1], typs[76], typs[24]), params(typs[3], typs[6]))
-	typs[88] = newSig(params(typs[1], typs[76], typs[28]), params(typs[3], typs[6]))
-	typs[89] = newSig(params(typs[1], typs[76], typs[3], typs[1]), params(typs[3], typs[6]))
-	typs[90] = newSig(params(typs[1], typs[76], typs[7]), params(typs[3]))
-	typs[91] = newSig(params(typs[1], typs[76], typs[3]), nil)
-	typs[92] = newSig(params(typs[1], typs[76], typs[60]), nil)
-	typs[93] = newSig(params(typs[1], typs[76], typs[24]), nil)
-	typs[94] = newSig(params(typs[1], typs[76], typs[28]), nil)
-	typs[95] = newSig(params(typs[3]), nil)
-	typs[96] = newSig(params(typs[1], typs[76]), nil)
-	typs[97] = types.NewChan(typs[2], types.Cboth)
-	typs[98] = newSig(params(typs[1], typs[22]), params(typs[97]))
-	typs[99] = newSig(params(typs[1], typs[15]), params(typs[97]))
-	typs[100] = types.NewChan(typs[2], types.Crecv)
-	typs[101] = newSig(params(typs[100], typs[3]), nil)
-	typs[102] = newSig(params(typs[100], typs[3]), params(typs[6]))
-	typs[103] = types.NewChan(typs[2], types.Csend)
-	typs[104] = newSig(params(typs[103], typs[3]), nil)
-	typs[105] = newSig(params(typs[103]), nil)
-	typs[106] = newSig(params(typs[2]), params(typs[15]))
-	typs[107] = types.NewArray(typs[0], 3)
-	typs[108] = types.NewStruct([]*types.Field{types.NewField(src.NoXPos, Lookup("enabled"), typs[6]), types.NewField(src.NoXPos, Lookup("pad"), typs[107]), types.NewField(src.NoXPos, Lookup("cgo"), typs[6]), types.NewField(src.NoXPos, Lookup("alignme"), typs[24])})
-	typs[109] = newSig(params(typs[1], typs[3], typs[3]), nil)
-	typs[110] = newSig(params(typs[1], typs[3]), nil)
-	typs[111] = newSig(params(typs[1], typs[3], typs[15], typs[3], typs[15]), params(typs[15]))
-	typs[112] = newSig(params(typs[103], typs[3]), params(typs[6]))
-	typs[113] = newSig(params(typs[3], typs[100]), params(typs[6], typs[6]))
-	typs[114] = newSig(params(typs[71]), nil)
-	typs[115] = newSig(params(typs[1], typs[1], typs[71], typs[15], typs[15], typs[6]), params(typs[15], typs[6]))
-	typs[116] = newSig(params(typs[1], typs[15], typs[15]), params(typs[7]))
-	typs[117] = newSig(params(typs[1], typs[22], typs[22]), params(typs[7]))
-	typs[118] = newSig(params(typs[1], typs[15], typs[15], typs[7]), params(typs[7]))
-	typs[119] = types.NewSlice(typs[2])
-	typs[120] = newSig(params(typs[3], typs[15], typs[15], typs[15], typs[1]), params(typs[119]))
-	typs[121] = newSig(params(typs[1], typs[7], typs[22]), nil)
-	typs[122] = newSig(params(typs[7], typs[22]), nil)
-	typs[123] = newSig(params(typs[3], typs[3], typs[5]), nil)
-	typs[124] = newSig(params(typs[7], typs[5]), nil)
-	typs[125] = newSig(params(typs[3], typs[3], typs[5]), params(typs[6]))
-	typs[126] = newSig(params(typs[3], typs[3]), params(typs[6]))
-	typs[127] = newSig(params(typs[7], typs[7]), params(typs[6]))
-	typs[128] = newSig(params(typs[3], typs[5], typs[5]), params(typs[5]))
-	typs[129] = newSig(params(typs[7], typs[5]), params(typs[5]))
-	typs[130] = newSig(params(typs[3], typs[5]), params(typs[5]))
-	typs[131] = newSig(params(typs[22], typs[22]), params(typs[22]))
-	typs[132] = newSig(params(typs[24], typs[24]), params(typs[24]))
-	typs[133] = newSig(params(typs[20]), params(typs[22]))
-	typs[134] = newSig(params(typs[20]), params(typs[24]))
-	typs[135] = newSig(params(typs[20]), params(typs[60]))
-	typs[136] = newSig(params(typs[22]), params(typs[20]))
-	typs[137] = types.Types[types.TFLOAT32]
-	typs[138] = newSig(params(typs[22]), params(typs[137]))
-	typs[139] = newSig(params(typs[24]), params(typs[20]))
-	typs[140] = newSig(params(typs[24]), params(typs[137]))
-	typs[141] = newSig(params(typs[60]), params(typs[20]))
-	typs[142] = newSig(params(typs[26], typs[26]), params(typs[26]))
-	typs[143] = newSig(nil, params(typs[5]))
-	typs[144] = newSig(params(typs[5], typs[5]), nil)
-	typs[145] = newSig(params(typs[5], typs[5], typs[5]), nil)
-	typs[146] = newSig(params(typs[7], typs[1], typs[5]), nil)
-	typs[147] = types.NewSlice(typs[7])
-	typs[148] = newSig(params(typs[7], typs[147]), nil)
-	typs[149] = newSig(params(typs[64], typs[64], typs[17]), nil)
-	typs[150] = newSig(params(typs[58], typs[58], typs[17]), nil)
-	typs[151] = newSig(params(typs[60], typs[60], typs[17]), nil)
-	typs[152] = newSig(params(typs[24], typs[24], typs[17]), nil)
-	typs[153] = newSig(params(typs[28], typs[28], typs[17]), nil)
-	typs[154] = types.NewArray(typs[0], 16)
-	typs[155] = newSig(params(typs[7], typs[60], typs[154], typs[28], typs[15], typs[64], typs[64]), params(typs[60]))
+	typs[75] = newSig(nil, params(typs[24]))
+	typs[76] = newSig(nil, params(typs[60]))
+	typs[77] = types.NewMap(typs[2], typs[2])
+	typs[78] = newSig(params(typs[1], typs[22], typs[3]), params(typs[77]))
+	typs[79] = newSig(params(typs[1], typs[15], typs[3]), params(typs[77]))
+	typs[80] = newSig(nil, params(typs[77]))
+	typs[81] = newSig(params(typs[1], typs[77], typs[3]), params(typs[3]))
+	typs[82] = newSig(params(typs[1], typs[77], typs[60]), params(typs[3]))
+	typs[83] = newSig(params(typs[1], typs[77], typs[24]), params(typs[3]))
+	typs[84] = newSig(params(typs[1], typs[77], typs[28]), params(typs[3]))
+	typs[85] = newSig(params(typs[1], typs[77], typs[3], typs[1]), params(typs[3]))
+	typs[86] = newSig(params(typs[1], typs[77], typs[3]), params(typs[3], typs[6]))
+	typs[87] = newSig(params(typs[1], typs[77], typs[60]), params(typs[3], typs[6]))
+	typs[88] = newSig(params(typs[1], typs[77], typs[24]), params(typs[3], typs[6]))
+	typs[89] = newSig(params(typs[1], typs[77], typs[28]), params(typs[3], typs[6]))
+	typs[90] = newSig(params(typs[1], typs[77], typs[3], typs[1]), params(typs[3], typs[6]))
+	typs[91] = newSig(params(typs[1], typs[77], typs[7]), params(typs[3]))
+	typs[92] = newSig(params(typs[1], typs[77], typs[3]), nil)
+	typs[93] = newSig(params(typs[1], typs[77], typs[60]), nil)
+	typs[94] = newSig(params(typs[1], typs[77], typs[24]), nil)
+	typs[95] = newSig(params(typs[1], typs[77], typs[28]), nil)
+	typs[96] = newSig(params(typs[3]), nil)
+	typs[97] = newSig(params(typs[1], typs[77]), nil)
+	typs[98] = types.NewChan(typs[2], types.Cboth)
+	typs[99] = newSig(params(typs[1], typs[22]), params(typs[98]))
+	typs[100] = newSig(params(typs[1], typs[15]), params(typs[98]))
+	typs[101] = types.NewChan(typs[2], types.Crecv)
+	typs[102] = newSig(params(typs[101], typs[3]), nil)
+	typs[103] = newSig(params(typs[101], typs[3]), params(typs[6]))
+	typs[104] = types.NewChan(typs[2], types.Csend)
+	typs[105] = newSig(params(typs[104], typs[3]), nil)
+	typs[106] = newSig(params(typs[104]), nil)
+	typs[107] = newSig(params(typs[2]), params(typs[15]))
+	typs[108] = types.NewArray(typs[0], 3)
+	typs[109] = types.NewStruct([]*types.Field{types.NewField(src.NoXPos, Lookup("enabled"), typs[6]), types.NewField(src.NoXPos, Lookup("pad"), typs[108]), types.NewField(src.NoXPos, Lookup("cgo"), typs[6]), types.NewField(src.NoXPos, Lookup("alignme"), typs[24])})
+	typs[110] = newSig(params(typs[1], typs[3], typs[3]), nil)
+	typs[111] = newSig(params(typs[1], typs[3]), nil)
+	typs[112] = newSig(params(typs[1], typs[3], typs[15], typs[3], typs[15]), params(typs[15]))
+	typs[113] = newSig(params(typs[104], typs[3]), params(typs[6]))
+	typs[114] = newSig(params(typs[3], typs[101]), params(typs[6], typs[6]))
+	typs[115] = newSig(params(typs[71]), nil)
+	typs[116] = newSig(params(typs[1], typs[1], typs[71], typs[15], typs[15], typs[6]), params(typs[15], typs[6]))
+	typs[117] = newSig(params(typs[1], typs[15], typs[15]), params(typs[7]))
+	typs[118] = newSig(params(typs[1], typs[22], typs[22]), params(typs[7]))
+	typs[119] = newSig(params(typs[1], typs[15], typs[15], typs[7]), params(typs[7]))
+	typs[120] = types.NewSlice(typs[2])
+	typs[121] = newSig(params(typs[3], typs[15], typs[15], typs[15], typs[1]), params(typs[120]))
+	typs[122] = newSig(params(typs[1], typs[7], typs[22]), nil)
+	typs[123] = newSig(params(typs[7], typs[22]), nil)
+	typs[124] = newSig(params(typs[3], typs[3], typs[5]), nil)
+	typs[125] = newSig(params(typs[7], typs[5]), nil)
+	typs[126] = newSig(params(typs[3], typs[3], typs[5]), params(typs[6]))
+	typs[127] = newSig(params(typs[3], typs[3]), params(typs[6]))
+	typs[128] = newSig(params(typs[7], typs[7]), params(typs[6]))
+	typs[129] = newSig(params(typs[3], typs[5], typs[5]), params(typs[5]))
+	typs[130] = newSig(params(typs[7], typs[5]), params(typs[5]))
+	typs[131] = newSig(params(typs[3], typs[5]), params(typs[5]))
+	typs[132] = newSig(params(typs[22], typs[22]), params(typs[22]))
+	typs[133] = newSig(params(typs[24], typs[24]), params(typs[24]))
+	typs[134] = newSig(params(typs[20]), params(typs[22]))
+	typs[135] = newSig(params(typs[20]), params(typs[24]))
+	typs[136] = newSig(params(typs[20]), params(typs[60]))
+	typs[137] = newSig(params(typs[22]), params(typs[20]))
+	typs[138] = types.Types[types.TFLOAT32]
+	typs[139] = newSig(params(typs[22]), params(typs[138]))
+	typs[140] = newSig(params(typs[24]), params(typs[20]))
+	typs[141] = newSig(params(typs[24]), params(typs[138]))
+	typs[142] = newSig(params(typs[60]), params(typs[20]))
+	typs[143] = newSig(params(typs[26], typs[26]), params(typs[26]))
+	typs[144] = newSig(nil, params(typs[5]))
+	typs[145] = newSig(params(typs[5], typs[5]), nil)
+	typs[146] = newSig(params(typs[5], typs[5], typs[5]), nil)
+	typs[147] = newSig(params(typs[7], typs[1], typs[5]), nil)
+	typs[148] = types.NewSlice(typs[7])
+	typs[149] = newSig(params(typs[7], typs[148]), nil)
+	typs[150] = newSig(params(typs[64], typs[64], typs[17]), nil)
+	typs[151] = newSig(params(typs[58], typs[58], typs[17]), nil)
+	typs[152] = newSig(params(typs[60], typs[60], typs[17]), nil)
+	typs[153] = newSig(params(typs[24], typs[24], typs[17]), nil)
+	typs[154] = newSig(params(typs[28], typs[28], typs[17]), nil)
+	typs[155] = types.NewArray(typs[0], 16)
+	typs[156] = newSig(params(typs[7], typs[60], typs[155], typs[28], typs[15], typs[64], typs[64]), params(typs[60]))
 	return typs[:]
 }
 
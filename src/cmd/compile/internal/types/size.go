@@ -195,6 +195,14 @@ func calcStructOffset(t *Type, fields []*Field, offset int64) int64 {
 	return offset
 }
 
+// isSIMDTag reports whether t is the zero-sized tag type that package
+// simd uses as the first field of its vector types. Structs starting
+// with it are SSA'd as a single value that lives in a vector register.
+func isSIMDTag(t *Type) bool {
+	sym := t.Sym()
+	return sym != nil && sym.Name == "v256" && sym.Pkg.Prefix == "simd"
+}
+
 func isAtomicStdPkg(p *Pkg) bool {
 	if p.Prefix == `""` {
 		panic("bad package prefix")
@@ -550,6 +558,9 @@ func CalcStructSize(t *Type) {
 	t.align = maxAlign
 	t.intRegs = uint8(intRegs)
 	t.floatRegs = uint8(floatRegs)
+	if len(fields) > 0 && isSIMDTag(fields[0].Type) {
+		t.flags.set(typeIsSIMD, true)
+	}
 
 	// Compute eq/hash algorithm type.
 	t.alg = AMEM // default
@@ -240,6 +240,7 @@ const (
 	typeRecur
 	typeIsShape  // represents a set of closely related types, for generics
 	typeHasShape // there is a shape somewhere in the type
+	typeIsSIMD   // a vector type from package simd, see isSIMDTag
 )
 
 func (t *Type) NotInHeap() bool  { return t.flags&typeNotInHeap != 0 }
@@ -248,6 +249,7 @@ func (t *Type) Deferwidth() bool { return t.flags&typeDeferwidth != 0 }
 func (t *Type) Recur() bool      { return t.flags&typeRecur != 0 }
 func (t *Type) IsShape() bool    { return t.flags&typeIsShape != 0 }
 func (t *Type) HasShape() bool   { return t.flags&typeHasShape != 0 }
+func (t *Type) IsSIMD() bool     { return t.flags&typeIsSIMD != 0 }
 
 func (t *Type) SetNotInHeap(b bool)  { t.flags.set(typeNotInHeap, b) }
 func (t *Type) SetNoalg(b bool)      { t.flags.set(typeNoalg, b) }
@@ -324,7 +326,7 @@ type Map struct {
 	OldBucket *Type // internal struct type representing a hash bucket
 
 	// GOEXPERIMENT=swissmap fields
-	SwissBucket *Type // internal struct type representing a hash bucket
+	SwissGroup *Type // internal struct type representing a slot group
 }
 
 // MapType returns t's extra map-specific fields.
@@ -1225,16 +1227,16 @@ func (t *Type) cmp(x *Type) Cmp {
 				// to the fallthrough
 			} else if x.StructType().Map == nil {
 				return CMPgt // nil > non-nil
-			} else if t.StructType().Map.MapType().SwissBucket == t {
+			} else if t.StructType().Map.MapType().SwissGroup == t {
 				// Both have non-nil Map
 				// Special case for Maps which include a recursive type where the recursion is not broken with a named type
-				if x.StructType().Map.MapType().SwissBucket != x {
-					return CMPlt // bucket maps are least
+				if x.StructType().Map.MapType().SwissGroup != x {
+					return CMPlt // group maps are least
 				}
 				return t.StructType().Map.cmp(x.StructType().Map)
-			} else if x.StructType().Map.MapType().SwissBucket == x {
-				return CMPgt // bucket maps are least
-			} // If t != t.Map.SwissBucket, fall through to general case
+			} else if x.StructType().Map.MapType().SwissGroup == x {
+				return CMPgt // group maps are least
+			} // If t != t.Map.SwissGroup, fall through to general case
 		} else {
 			if t.StructType().Map == nil {
 				if x.StructType().Map != nil {
@@ -486,6 +486,9 @@ func appendSlice(n *ir.CallExpr, init *ir.Nodes) ir.Node {
 	// var s []T
 	s := typecheck.TempAt(base.Pos, ir.CurFunc, l1.Type())
 	nodes.Append(ir.NewAssignStmt(base.Pos, s, l1)) // s = l1
+	if n.Esc() == ir.EscNone {
+		nodes.Append(appendStackBuffer(n, s, ir.NewUnaryExpr(base.Pos, ir.OLEN, l2)))
+	}
 
 	elemtype := s.Type().Elem()
 
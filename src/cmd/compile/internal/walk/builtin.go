@@ -75,6 +75,14 @@ func walkAppend(n *ir.CallExpr, init *ir.Nodes, dst ir.Node) ir.Node {
 	// General case, with no function calls left as arguments.
 	// Leave for ssagen, except that instrumentation requires the old form.
 	if !base.Flag.Cfg.Instrumenting || base.Flag.CompilingRuntime {
+		if n.Esc() == ir.EscNone {
+			if nsrc.Op() != ir.ONAME || !ir.SameSafeExpr(dst, nsrc) {
+				// Don't change the slice appended to unless it
+				// is the variable assigned the result.
+				n.Args[0] = copyExpr(nsrc, nsrc.Type(), init)
+			}
+			appendWalkStmt(init, appendStackBuffer(n, n.Args[0], ir.NewInt(base.Pos, int64(argc))))
+		}
 		return n
 	}
 
@@ -83,6 +91,9 @@ func walkAppend(n *ir.CallExpr, init *ir.Nodes, dst ir.Node) ir.Node {
 	// s = slice to append to
 	s := typecheck.TempAt(base.Pos, ir.CurFunc, nsrc.Type())
 	l = append(l, ir.NewAssignStmt(base.Pos, s, nsrc))
+	if n.Esc() == ir.EscNone {
+		l = append(l, appendStackBuffer(n, s, ir.NewInt(base.Pos, int64(argc))))
+	}
 
 	// num = number of things to append
 	num := ir.NewInt(base.Pos, int64(argc))
@@ -136,6 +147,63 @@ func walkGrowslice(slice *ir.Name, init *ir.Nodes, oldPtr, newLen, oldCap, num i
 	return mkcall1(fn, slice.Type(), init, oldPtr, newLen, oldCap, num, elemtypeptr)
 }
 
+// appendStackBufferUsed holds the flags created by appendStackBuffer
+// for the function being walked. Walk clears them on entry to the
+// function.
+var appendStackBufferUsed []*ir.Name
+
+// appendStackBuffer returns a statement that makes the append call n,
+// whose backing store does not escape, grow the slice s by num
+// elements into a stack buffer of base.Debug.VariableMakeThreshold
+// bytes instead of the heap, when the grown slice fits in it:
+//
+//	if !used {
+//		if need := uint(len(s)) + uint(num); uint(cap(s)) < need && need <= K {
+//			used = true
+//			var buf [K]T
+//			copy(buf[:], s)
+//			s = buf[:len(s)]
+//		}
+//	}
+//
+// The buffer is used at most once per call of the function, so that
+// a slice that was grown into it is never overwritten by a later
+// append at the same site, which may be in a loop.
+//
+// The statement must be evaluated after the arguments of n, so that
+// s does not change if they panic.
+func appendStackBuffer(n *ir.CallExpr, s, num ir.Node) ir.Node {
+	pos := n.Pos()
+	et := n.Type().Elem()
+	k := int64(base.Debug.VariableMakeThreshold) / et.Size()
+	uint_ := types.Types[types.TUINT]
+
+	used := typecheck.TempAt(pos, ir.CurFunc, types.Types[types.TBOOL])
+	appendStackBufferUsed = append(appendStackBufferUsed, used)
+	buf := typecheck.TempAt(pos, ir.CurFunc, types.NewArray(et, k))
+	need := typecheck.TempAt(pos, ir.CurFunc, uint_)
+
+	nifneed := ir.NewIfStmt(pos,
+		ir.NewLogicalExpr(pos, ir.OANDAND,
+			ir.NewBinaryExpr(pos, ir.OLT, typecheck.Conv(ir.NewUnaryExpr(pos, ir.OCAP, s), uint_), need),
+			ir.NewBinaryExpr(pos, ir.OLE, need, ir.NewInt(pos, k))),
+		nil, nil)
+	nifneed.Body = []ir.Node{
+		ir.NewAssignStmt(pos, used, ir.NewBool(pos, true)),
+		ir.NewAssignStmt(pos, buf, nil),
+		ir.NewBinaryExpr(pos, ir.OCOPY, ir.NewSliceExpr(pos, ir.OSLICE, buf, nil, nil, nil), s),
+		ir.NewAssignStmt(pos, s, ir.NewSliceExpr(pos, ir.OSLICE, buf, nil, ir.NewUnaryExpr(pos, ir.OLEN, s), nil)),
+	}
+
+	nif := ir.NewIfStmt(pos, ir.NewUnaryExpr(pos, ir.ONOT, used), nil, nil)
+	nif.Body = []ir.Node{
+		ir.NewAssignStmt(pos, need, ir.NewBinaryExpr(pos, ir.OADD,
+			typecheck.Conv(ir.NewUnaryExpr(pos, ir.OLEN, s), uint_), typecheck.Conv(num, uint_))),
+		nifneed,
+	}
+	return nif
+}
+
 // walkClear walks an OCLEAR node.
 func walkClear(n *ir.UnaryExpr) ir.Node {
 	typ := n.X.Type()
@@ -332,57 +400,66 @@ func walkMakeSwissMap(n *ir.MakeExpr, init *ir.Nodes) ir.Node {
 		// h = &hv
 		h = stackTempAddr(init, hmapType)
 
-		// Allocate one bucket pointed to by hmap.buckets on stack if hint
-		// is not larger than BUCKETSIZE. In case hint is larger than
-		// BUCKETSIZE runtime.makemap will allocate the buckets on the heap.
+		// Allocate one group pointed to by hmap.dirPtr on stack if hint
+		// is not larger than SwissMapGroupSlots. In case hint is larger,
+		// runtime.makemap will allocate on the heap.
 		// Maximum key and elem size is 128 bytes, larger objects
-		// are stored with an indirection. So max bucket size is 2048+eps.
+		// are stored with an indirection. So max group size is
+		// 8 + (8 * 2 * 128) = 2056 bytes.
 		if !ir.IsConst(hint, constant.Int) ||
-			constant.Compare(hint.Val(), token.LEQ, constant.MakeInt64(abi.SwissMapBucketCount)) {
+			constant.Compare(hint.Val(), token.LEQ, constant.MakeInt64(abi.SwissMapGroupSlots)) {
 
-			// In case hint is larger than BUCKETSIZE runtime.makemap
-			// will allocate the buckets on the heap, see #20184
+			// In case hint is larger than SwissMapGroupSlots
+			// runtime.makemap will allocate on the heap, see #20184
 			//
-			// if hint <= BUCKETSIZE {
-			//     var bv bmap
-			//     b = &bv
-			//     h.buckets = b
+			// if hint <= abi.SwissMapGroupSlots {
+			//     var gv group
+			//     g = &gv
+			//     g.ctrl = abi.SwissMapCtrlEmpty
+			//     h.dirPtr = g
 			// }
 
-			nif := ir.NewIfStmt(base.Pos, ir.NewBinaryExpr(base.Pos, ir.OLE, hint, ir.NewInt(base.Pos, abi.SwissMapBucketCount)), nil, nil)
+			nif := ir.NewIfStmt(base.Pos, ir.NewBinaryExpr(base.Pos, ir.OLE, hint, ir.NewInt(base.Pos, abi.SwissMapGroupSlots)), nil, nil)
 			nif.Likely = true
 
-			// var bv bmap
-			// b = &bv
-			b := stackTempAddr(&nif.Body, reflectdata.SwissMapBucketType(t))
+			groupType := reflectdata.SwissMapGroupType(t)
 
-			// h.buckets = b
-			bsym := hmapType.Field(5).Sym // hmap.buckets see reflect.go:hmap
-			na := ir.NewAssignStmt(base.Pos, ir.NewSelectorExpr(base.Pos, ir.ODOT, h, bsym), typecheck.ConvNop(b, types.Types[types.TUNSAFEPTR]))
+			// var gv group
+			// g = &gv
+			g := stackTempAddr(&nif.Body, groupType)
+
+			// g.ctrl = abi.SwissMapCtrlEmpty
+			csym := groupType.Field(0).Sym // g.ctrl see reflectdata/map_swiss.go
+			ca := ir.NewAssignStmt(base.Pos, ir.NewSelectorExpr(base.Pos, ir.ODOT, g, csym), ir.NewBasicLit(base.Pos, types.Types[types.TUINT64], constant.MakeUint64(abi.SwissMapCtrlEmpty)))
+			nif.Body.Append(ca)
+
+			// h.dirPtr = g
+			dsym := hmapType.Field(2).Sym // hmap.dirPtr see reflectdata/map_swiss.go
+			na := ir.NewAssignStmt(base.Pos, ir.NewSelectorExpr(base.Pos, ir.ODOT, h, dsym), typecheck.ConvNop(g, types.Types[types.TUNSAFEPTR]))
 			nif.Body.Append(na)
 			appendWalkStmt(init, nif)
 		}
 	}
 
-	if ir.IsConst(hint, constant.Int) && constant.Compare(hint.Val(), token.LEQ, constant.MakeInt64(abi.SwissMapBucketCount)) {
+	if ir.IsConst(hint, constant.Int) && constant.Compare(hint.Val(), token.LEQ, constant.MakeInt64(abi.SwissMapGroupSlots)) {
 		// Handling make(map[any]any) and
-		// make(map[any]any, hint) where hint <= BUCKETSIZE
-		// special allows for faster map initialization and
+		// make(map[any]any, hint) where hint <= abi.SwissMapGroupSlots
+		// specially allows for faster map initialization and
 		// improves binary size by using calls with fewer arguments.
-		// For hint <= BUCKETSIZE overLoadFactor(hint, 0) is false
-		// and no buckets will be allocated by makemap. Therefore,
-		// no buckets need to be allocated in this code path.
+		// For hint <= abi.SwissMapGroupSlots no groups will be
+		// allocated by makemap. Therefore, no groups need to be
+		// allocated in this code path.
 		if n.Esc() == ir.EscNone {
-			// Only need to initialize h.hash0 since
+			// Only need to initialize h.seed since
 			// hmap h has been allocated on the stack already.
-			// h.hash0 = rand32()
-			rand := mkcall("rand32", types.Types[types.TUINT32], init)
-			hashsym := hmapType.Field(4).Sym // hmap.hash0 see reflect.go:hmap
-			appendWalkStmt(init, ir.NewAssignStmt(base.Pos, ir.NewSelectorExpr(base.Pos, ir.ODOT, h, hashsym), rand))
+			// h.seed = uintptr(rand())
+			rand := mkcall("rand", types.Types[types.TUINT64], init)
+			seedsym := hmapType.Field(1).Sym // hmap.seed see reflectdata/map_swiss.go
+			appendWalkStmt(init, ir.NewAssignStmt(base.Pos, ir.NewSelectorExpr(base.Pos, ir.ODOT, h, seedsym), typecheck.Conv(rand, types.Types[types.TUINTPTR])))
 			return typecheck.ConvNop(h, t)
 		}
-		// Call runtime.makehmap to allocate an
-		// hmap on the heap and initialize hmap's hash0 field.
+		// Call runtime.makemap_small to allocate an
+		// hmap on the heap and initialize hmap's seed field.
 		fn := typecheck.LookupRuntime("makemap_small", t.Key(), t.Elem())
 		return mkcall1(fn, n.Type(), init)
 	}
@@ -524,6 +601,9 @@ func walkMakeSlice(n *ir.MakeExpr, init *ir.Nodes) ir.Node {
 		if why := escape.HeapAllocReason(n); why != "" {
 			base.Fatalf("%v has EscNone, but %v", n, why)
 		}
+		if !ir.IsSmallIntConst(r) {
+			return walkMakeSliceStack(n, l, r, init)
+		}
 		// var arr [r]T
 		// n = arr[:l]
 		i := typecheck.IndexConst(r)
@@ -578,6 +658,59 @@ func walkMakeSlice(n *ir.MakeExpr, init *ir.Nodes) ir.Node {
 	return walkExpr(typecheck.Expr(sh), init)
 }
 
+// walkMakeSliceStack walks an OMAKESLICE node n of non-constant size
+// that does not escape, with length l and capacity r. It uses a stack
+// buffer of base.Debug.VariableMakeThreshold bytes if the slice fits:
+//
+//	var s []T
+//	if uint64(r) <= K {
+//		if uint64(l) > uint64(r) {
+//			if l < 0 { panicmakeslicelen() }
+//			panicmakeslicecap()
+//		}
+//		var arr [K]T
+//		s = arr[:l:r]
+//	} else {
+//		s = makeslice(T, l, r)
+//	}
+func walkMakeSliceStack(n *ir.MakeExpr, l, r ir.Node, init *ir.Nodes) ir.Node {
+	t := n.Type()
+	k := int64(base.Debug.VariableMakeThreshold) / t.Elem().Size()
+	if l == r {
+		l = cheapExpr(l, init)
+		r = l
+	} else {
+		l = cheapExpr(l, init)
+		r = cheapExpr(r, init)
+	}
+	u64 := types.Types[types.TUINT64]
+
+	s := typecheck.TempAt(base.Pos, ir.CurFunc, t)
+	nif := ir.NewIfStmt(base.Pos, ir.NewBinaryExpr(base.Pos, ir.OLE, typecheck.Conv(r, u64), ir.NewInt(base.Pos, k)), nil, nil)
+
+	// The length is checked here, as makeslice would, so that the
+	// slice expression below cannot fail.
+	nifcap := ir.NewIfStmt(base.Pos, ir.NewBinaryExpr(base.Pos, ir.OGT, typecheck.Conv(l, u64), typecheck.Conv(r, u64)), nil, nil)
+	niflen := ir.NewIfStmt(base.Pos, ir.NewBinaryExpr(base.Pos, ir.OLT, l, ir.NewInt(base.Pos, 0)), nil, nil)
+	niflen.Body = []ir.Node{mkcall("panicmakeslicelen", nil, init)}
+	nifcap.Body.Append(niflen, mkcall("panicmakeslicecap", nil, init))
+
+	arr := typecheck.TempAt(base.Pos, ir.CurFunc, types.NewArray(t.Elem(), k))
+	nif.Body.Append(typecheck.Stmt(nifcap))
+	appendWalkStmt(&nif.Body, ir.NewAssignStmt(base.Pos, arr, nil)) // zero temp
+	appendWalkStmt(&nif.Body, ir.NewAssignStmt(base.Pos, s, typecheck.Conv(ir.NewSliceExpr(base.Pos, ir.OSLICE3, arr, nil, l, r), t)))
+
+	heap := ir.NewMakeExpr(n.Pos(), ir.OMAKESLICE, l, r)
+	heap.RType = n.RType
+	heap.SetType(t)
+	heap.SetEsc(ir.EscHeap)
+	heap.SetTypecheck(1)
+	appendWalkStmt(&nif.Else, ir.NewAssignStmt(base.Pos, s, heap))
+
+	init.Append(typecheck.Stmt(nif))
+	return s
+}
+
 // walkMakeSliceCopy walks an OMAKESLICECOPY node.
 func walkMakeSliceCopy(n *ir.MakeExpr, init *ir.Nodes) ir.Node {
 	if n.Esc() == ir.EscNone {
@@ -35,7 +35,16 @@ func Walk(fn *ir.Func) {
 		ir.DumpList(s, ir.CurFunc.Body)
 	}
 
+	appendStackBufferUsed = nil
 	walkStmtList(ir.CurFunc.Body)
+	if len(appendStackBufferUsed) > 0 {
+		var init ir.Nodes
+		for _, used := range appendStackBufferUsed {
+			appendWalkStmt(&init, ir.NewAssignStmt(fn.Pos(), used, ir.NewBool(fn.Pos(), false)))
+		}
+		fn.Body.Prepend(init...)
+		appendStackBufferUsed = nil
+	}
 	if base.Flag.W != 0 {
 		s := fmt.Sprintf("after walk %v", ir.CurFunc.Sym())
 		ir.DumpList(s, ir.CurFunc.Body)
@@ -192,8 +201,30 @@ func mapfast(t *types.Type) int {
 }
 
 func mapfastSwiss(t *types.Type) int {
-	// TODO(#54766): Temporarily avoid specialized variants to minimize
-	// required code.
+	if t.Elem().Size() > abi.SwissMapMaxElemBytes {
+		return mapslow
+	}
+	switch reflectdata.AlgType(t.Key()) {
+	case types.AMEM32:
+		if !t.Key().HasPointers() {
+			return mapfast32
+		}
+		if types.PtrSize == 4 {
+			return mapfast32ptr
+		}
+		base.Fatalf("small pointer %v", t.Key())
+	case types.AMEM64:
+		if !t.Key().HasPointers() {
+			return mapfast64
+		}
+		if types.PtrSize == 8 {
+			return mapfast64ptr
+		}
+		// Two-word object, at least one of which is a pointer.
+		// Use the slow path.
+	case types.ASTRING:
+		return mapfaststr
+	}
 	return mapslow
 }
 
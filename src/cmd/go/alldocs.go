@@ -38,6 +38,7 @@
 // Additional help topics:
 //
 //	buildconstraint build constraints
+//	buildjson       build -json encoding
 //	buildmode       build modes
 //	c               calling between Go and C
 //	cache           build and test caching
@@ -70,7 +71,7 @@
 //
 // Usage:
 //
-//	go build [-o output] [build flags] [packages]
+//	go build [-o output] [-json] [build flags] [packages]
 //
 // Build compiles the packages named by the import paths,
 // along with their dependencies, but it does not install the results.
@@ -101,6 +102,11 @@
 // ends with a slash or backslash, then any resulting executables
 // will be written to that directory.
 //
+// The -json flag causes build to report its progress and the output of the
+// compiler, linker and other tools as a stream of JSON events on standard
+// output, instead of printing the output as text on standard error.
+// See 'go help buildjson' for the format.
+//
 // The build flags are shared by the build, clean, get, install, list, run,
 // and test commands:
 //
@@ -161,6 +167,12 @@
 //		arguments to pass on each go tool asm invocation.
 //	-buildmode mode
 //		build mode to use. See 'go help buildmode' for more.
+//	-buildpkgs
+//		stamp binaries with the import paths of the packages built into
+//		them, other than those of the standard library, so that
+//		'go version -m -format' can list them in a software bill of
+//		materials. This adds one line per package, typically a few
+//		kilobytes, to the build information of the binary.
 //	-buildvcs
 //		Whether to stamp binaries with version control information
 //		("true", "false", or "auto"). By default ("auto"), version control
@@ -203,6 +215,15 @@
 //		directory, but it is not accessed. When -modfile is specified, an
 //		alternate go.sum file is also used: its path is derived from the
 //		-modfile flag by trimming the ".mod" extension and appending ".sum".
+//	-optdetails dir
+//		write the compiler's optimization decisions for the packages named on
+//		the command line to dir, as LSP diagnostics in JSON. The diagnostics
+//		for each source file are written to a file in a subdirectory named
+//		for the package's URL-escaped import path. They cover inlining,
+//		escape analysis (with the chain of assignments that makes a value
+//		escape), and the bounds and nil checks that remain after optimization.
+//		Packages built with this flag are always recompiled, so that their
+//		diagnostics are written.
 //	-overlay file
 //		read a JSON config file that provides an overlay for build operations.
 //		The file is a JSON struct with a single field, named 'Replace', that
@@ -220,6 +241,7 @@
 //		build, the go command selects a file named "default.pgo" in the package's
 //		directory if that file exists, and applies it to the (transitive)
 //		dependencies of the main package (other packages are not affected).
+//		The linker also uses the profile to place hot functions together.
 //		Special name "off" turns off PGO. The default is "auto".
 //	-pkgdir dir
 //		install and load all packages from dir instead of the usual locations.
@@ -663,7 +685,7 @@
 //
 // Usage:
 //
-//	go get [-t] [-u] [-v] [build flags] [packages]
+//	go get [-t] [-u] [-tool] [-v] [build flags] [packages]
 //
 // Get resolves its command-line arguments to packages at specific module versions,
 // updates go.mod to require those versions, and downloads source code into the
@@ -689,6 +711,18 @@
 //
 //	go get toolchain@patch
 //
+// To add a tool to the main module, so that it can be run with 'go tool':
+//
+//	go get -tool example.com/cmd/tool
+//
+// To upgrade the tools of the main module to their latest versions:
+//
+//	go get tool
+//
+// To remove a tool from the main module:
+//
+//	go get -tool example.com/cmd/tool@none
+//
 // See https://golang.org/ref/mod#go-get for details.
 //
 // In earlier versions of Go, 'go get' was used to build and install packages.
@@ -717,6 +751,13 @@
 // When the -t and -u flags are used together, get will update
 // test dependencies as well.
 //
+// The -tool flag instructs get to add a tool directive to go.mod for each
+// main package named on the command line, in addition to requiring the
+// module that provides it. With the version suffix @none, it removes the
+// matching tool directives instead, leaving the module requirements in
+// place. The 'tool' pattern matches the tools declared in go.mod; see
+// 'go help packages'.
+//
 // The -x flag prints commands as they are executed. This is useful for
 // debugging version control commands when a module is downloaded directly
 // from a repository.
@@ -741,7 +782,7 @@
 //
 // Usage:
 //
-//	go install [build flags] [packages]
+//	go install [-json] [build flags] [packages]
 //
 // Install compiles and installs the packages named by the import paths.
 //
@@ -794,6 +835,9 @@
 // Setting GODEBUG=installgoroot=all restores the use of
 // $GOROOT/pkg/$GOOS_$GOARCH.
 //
+// The -json flag reports the progress and output of the build as a stream
+// of JSON events on standard output, as for 'go build -json'.
+//
 // For more about build flags, see 'go help build'.
 //
 // For more about specifying packages, see 'go help packages'.
@@ -1122,8 +1166,10 @@
 //
 // The commands are:
 //
+//	audit       report known vulnerabilities and licenses of dependencies
 //	download    download modules to local cache
 //	edit        edit go.mod from tools or scripts
+//	explain     explain why modules are selected at their versions
 //	graph       print module requirement graph
 //	init        initialize new module in current directory
 //	tidy        add missing and remove unused modules
@@ -1133,6 +1179,67 @@
 //
 // Use "go help mod <command>" for more information about a command.
 //
+// # Report known vulnerabilities and licenses of dependencies
+//
+// Usage:
+//
+//	go mod audit [-json] [-db url] [-allow licenses] [-deny licenses]
+//
+// Audit reports, for each module in the build list of the main module,
+// the known vulnerabilities that affect the selected version of the module
+// and the license found in the module's zip file.
+//
+// Vulnerabilities are read from a database in the OSV format, laid out as
+// described at https://go.dev/security/vuln/database: an index of affected
+// modules in index/modules.json, and each entry in ID/<id>.json. The -db flag
+// sets the location of the database, which may be a local directory or a URL,
+// including a file:// URL. It defaults to the value of the GOVULNDB environment
+// variable, or https://vuln.go.dev if that is not set. A vulnerability is
+// reported when the selected version of a module falls in one of the affected
+// ranges of an entry, whether or not the main module uses the vulnerable
+// packages. The standard library is checked against the version of the go
+// command, if it is a release.
+//
+// The license of a module is detected from the LICENSE, LICENCE, COPYING and
+// UNLICENSE files at the root of its zip file, which is downloaded if needed,
+// and is reported as an SPDX identifier, such as BSD-3-Clause, or "unknown".
+// A module with several license files reports their identifiers joined by
+// " AND ".
+//
+// The -allow and -deny flags define a license policy, as comma-separated
+// lists of SPDX identifiers. With -allow, each module must have only allowed
+// licenses; a module whose license is unknown violates that policy. With
+// -deny, no module may have a denied license.
+//
+// Audit exits with a non-zero status if any module is affected by a
+// vulnerability, violates the license policy, or could not be checked.
+//
+// By default, audit prints one line for each module, giving its path, version
+// and license, followed by an indented line for each vulnerability and policy
+// violation. The -json flag causes audit to print instead a sequence of JSON
+// objects, one for each module, corresponding to this Go struct:
+//
+//	type Module struct {
+//	    Path         string   // module path
+//	    Version      string   // module version
+//	    License      string   // detected license
+//	    LicenseFiles []string // license files at the root of the module
+//	    Vulns        []Vuln   // vulnerabilities affecting the module
+//	    Violations   []string // license policy violations
+//	    Error        string   // error checking the module
+//	}
+//
+//	type Vuln struct {
+//	    ID      string   // vulnerability database ID, such as GO-2024-0001
+//	    Aliases []string // other IDs, such as CVE and GHSA IDs
+//	    Summary string   // short description
+//	    Fixed   string   // earliest fixed version, if any
+//	}
+//
+// Audit only uses the network to read a remote vulnerability database and to
+// download the zip files missing from the module cache. With a local database
+// and GOFLAGS=-mod=mod GOPROXY=off, it runs entirely offline.
+//
 // # Download modules to local cache
 //
 // Usage:
@@ -1315,6 +1422,74 @@
 //
 // See https://golang.org/ref/mod#go-mod-edit for more about 'go mod edit'.
 //
+// # Explain why modules are selected at their versions
+//
+// Usage:
+//
+//	go mod explain [-json] modules...
+//
+// Explain shows, for each of the listed modules, why minimal version
+// selection selected the version of the module in the build list: the
+// requirement paths through the module graph, from the main module, that
+// require the module.
+//
+// The output is a sequence of stanzas, one for each module on the command
+// line, separated by blank lines. Each stanza begins with a comment line
+// "# module version" giving the selected version, followed by a comment
+// line for the replacement of that version, if any. Each following line
+// gives a requirement path, as a sequence of modules separated by " -> ",
+// ending with a requirement on the module. Paths requiring the selected
+// version come first; those are the paths that raised the module to that
+// version. They are followed by paths requiring a lower version, marked
+// "(lower than selected)", and by requirements dropped from the module
+// graph because the main module's go.mod file excludes the required
+// version, marked "(excluded)". A final comment line notes how many
+// modules in the graph had their requirements pruned out, because the main
+// module and those modules declare go 1.17 or higher: such requirements
+// cannot affect the selected version. If the module is not in the module
+// graph, the stanza displays a single parenthesized note indicating that
+// fact.
+//
+// For example:
+//
+//	$ go mod explain example.com/c
+//	# example.com/c v1.2.0
+//	example.com/m -> example.com/c@v1.2.0
+//	example.com/m -> example.com/a@v1.0.0 -> example.com/c@v1.2.0
+//	example.com/m -> example.com/b@v1.0.0 -> example.com/c@v1.1.0 (lower than selected)
+//	example.com/m -> example.com/d@v1.0.0 -> example.com/c@v1.3.0 (excluded)
+//	# requirements of 2 modules pruned out of the module graph
+//	$
+//
+// The -json flag causes explain to print instead a sequence of JSON
+// objects, one for each module, corresponding to this Go struct:
+//
+//	type Explanation struct {
+//	    Path         string         // module path
+//	    Version      string         // selected version
+//	    Replace      *Module        // replacement of the selected version, if any
+//	    Excluded     []string       // versions excluded by the main module
+//	    Requirements []Requirement  // requirements on the module
+//	    Pruned       []string       // modules whose requirements are pruned out
+//	    Error        string         // error explaining the module
+//	}
+//
+//	type Requirement struct {
+//	    Version string   // required version
+//	    Status  string   // "selected", "lower", or "excluded"
+//	    Path    []string // requirement path, starting at the main module
+//	}
+//
+//	type Module struct {
+//	    Path    string
+//	    Version string
+//	}
+//
+// Modules in requirement paths are identified as strings of the form
+// path@version, except for the main module, which has no @version suffix.
+//
+// See also 'go mod graph' and 'go mod why'.
+//
 // # Print module requirement graph
 //
 // Usage:
@@ -1530,7 +1705,9 @@
 //
 // The commands are:
 //
+//	check       check that workspace modules build on their own
 //	edit        edit go.work from tools or scripts
+//	graph       print requirement graph of workspace modules
 //	init        initialize workspace file
 //	sync        sync workspace build list to modules
 //	use         add modules to workspace file
@@ -1538,6 +1715,32 @@
 //
 // Use "go help work <command>" for more information about a command.
 //
+// # Check that workspace modules build on their own
+//
+// Usage:
+//
+//	go work check
+//
+// Check reports the requirements of the workspace's modules that
+// the workspace overrides, so that a module that builds and passes its
+// tests in the workspace might not on its own, once released.
+//
+// For each workspace module, check loads the packages in "all", as 'go work
+// sync' does, and reports each module providing one of those packages for
+// which the workspace module's go.mod file disagrees with the workspace:
+//
+//   - another workspace module that the go.mod file does not require;
+//   - a module that the go.mod file requires at a lower version than the
+//     version selected in the workspace, or, if the go.mod file declares
+//     go 1.17 or higher, does not require at all;
+//   - a module replaced by the go.work file but not by the go.mod file.
+//
+// Check exits with a non-zero status if it reports any problem. Running
+// 'go work sync' fixes the problems of the second kind.
+//
+// See the workspaces reference at https://go.dev/ref/mod#workspaces
+// for more information.
+//
 // # Edit go.work from tools or scripts
 //
 // Usage:
@@ -1622,6 +1825,27 @@
 // See the workspaces reference at https://go.dev/ref/mod#workspaces
 // for more information.
 //
+// # Print requirement graph of workspace modules
+//
+// Usage:
+//
+//	go work graph
+//
+// Graph prints the requirements among the modules of the workspace,
+// as listed in their go.mod files, in text form. Each line in the output
+// has two space-separated fields: a workspace module and a workspace module
+// that it requires, identified as a string of the form path@version, with
+// the version required by the go.mod file. A workspace module that requires
+// no other workspace module is printed on a line by itself.
+//
+// In the workspace, the requirements printed by graph are satisfied by the
+// workspace modules themselves, whatever their required versions. Use
+// 'go work check' to find the requirements of workspace modules that the
+// workspace overrides.
+//
+// See the workspaces reference at https://go.dev/ref/mod#workspaces
+// for more information.
+//
 // # Initialize workspace file
 //
 // Usage:
@@ -1885,6 +2109,13 @@
 // at all, so a successful package test result will be cached and
 // reused regardless of -timeout setting.
 //
+// Files are normally considered unchanged if their size and modification
+// time are unchanged. When the -trimpath build flag is set, go test instead
+// compares the content of the files, and records their names relative to
+// the root of the package's module, so that a cached result can be reused
+// by a copy of the module in a different directory or on a different
+// machine, for example through a build cache shared using GOCACHEPROG.
+//
 // In addition to the build flags, the flags handled by 'go test' itself are:
 //
 //	-args
@@ -1912,6 +2143,37 @@
 //	    If file ends in a slash or names an existing directory,
 //	    the test is written to pkg.test in that directory.
 //
+//	-shard i/n
+//	    Split the packages to test into n shards and test only those
+//	    in shard i, counting from zero, so that n workers running
+//	    'go test -shard=0/n', ..., 'go test -shard=n-1/n' together
+//	    test every package exactly once. Packages are assigned by a
+//	    hash of their import path, or balanced by the times given by
+//	    the -shardtimes flag. The assignment depends only on the
+//	    packages and those times, so every worker computes the same one.
+//
+//	-shardtimes file
+//	    With -shard, balance the shards by the times that the tests
+//	    took in an earlier run, read from file. The file holds the
+//	    output of 'go test -json', for example that of every shard of
+//	    an earlier run joined together. Packages and tests not listed
+//	    in the file are assigned by a hash of their name. Every worker
+//	    must be given the same file.
+//	    The special value "cache" balances the shards by the times
+//	    recorded in the test results in the build cache instead: those
+//	    of the latest cached run of each package and, if that run used
+//	    -v, of its top-level tests. The workers agree on the assignment
+//	    only if they see the same cached results, for example through a
+//	    build cache shared by GOCACHEPROG that is not written to while
+//	    the workers start; otherwise, use a file.
+//
+//	-shardtests
+//	    With -shard, split the top-level tests, examples and fuzz tests
+//	    of each package matching -run, instead of the packages, and
+//	    run only those in shard i. Tests are balanced by their times
+//	    given by the -shardtimes flag. Test results are not cached when
+//	    -shardtests is set, and -run may not select subtests.
+//
 // The test binary also accepts flags that control execution of the test; these
 // flags are also accessible by 'go test'. See 'go help testflag' for details.
 //
@@ -1929,6 +2191,13 @@
 // Tool runs the go tool command identified by the arguments.
 // With no arguments it prints the list of known tools.
 //
+// The command may also name a tool declared by a tool directive in the
+// go.mod file of the main module, either by its package path or by the
+// last element of that path (ignoring a major version suffix such as /v2).
+// Such a tool is built in the context of the main module, at the version
+// selected by its requirements, and then run. Tools are added to go.mod
+// with 'go get -tool'.
+//
 // The -n flag causes tool to print the command that would be
 // executed but not execute it.
 //
@@ -1938,7 +2207,7 @@
 //
 // Usage:
 //
-//	go version [-m] [-v] [file ...]
+//	go version [-m [-format=format]] [-v] [-verify] [file ...]
 //
 // Version prints the build information for Go binary files.
 //
@@ -1957,6 +2226,35 @@
 // information consists of multiple lines following the version line, each
 // indented by a leading tab character.
 //
+// The -format flag, which requires -m, causes go version to print the
+// module information of each file as a software bill of materials (SBOM)
+// instead, in the given format: "spdx-json" for an SPDX 2.3 document or
+// "cyclonedx-json" for a CycloneDX 1.5 document, both in JSON. The default
+// format, "text", is the one described above. The SBOM lists the main
+// module, the dependency modules, and the standard library, and, for files
+// built by Go 1.24 or later with the -buildpkgs build flag, the packages
+// of each module built into the file, as recorded by the go command from
+// the import graph of the main package. See 'go doc runtime/debug.BuildInfo.WriteSBOM' for details.
+//
+// The -verify flag causes go version to check that each file can be
+// reproduced from the information embedded in it: go version runs
+// 'go install path@version' for the main package and module version
+// recorded in the file, in a temporary directory, with the recorded build
+// flags and environment settings such as GOOS, GOARCH and CGO_ENABLED,
+// and with the recorded Go toolchain, which is downloaded if needed (see
+// 'go help toolchain'). It then compares the rebuilt binary with the file
+// and, if they differ, reports the names of the object file sections that
+// differ and the differences in build information. Files built from a
+// module without a version, from replaced modules, or with settings that
+// cannot be reproduced, such as a -pgo profile other than default.pgo,
+// cannot be verified. Neither can files whose recorded settings could make
+// the rebuild run other programs or use a different toolchain, such as
+// -ldflags=-extld=prog or a CGO_CFLAGS value that is not allowed in #cgo
+// directives. Note that build information does not record all the
+// inputs of a build: in particular, a build using cgo depends on the C
+// toolchain and libraries, and -ldflags is omitted when -trimpath is set.
+// go version exits with a non-zero status if any file cannot be verified.
+//
 // See also: go doc runtime/debug.BuildInfo.
 //
 // # Report likely mistakes in packages
@@ -1979,6 +2277,11 @@
 //	go install golang.org/x/tools/go/analysis/passes/shadow/cmd/shadow@latest
 //	go vet -vettool=$(which shadow)
 //
+// The -buildjson flag causes vet to report its progress and diagnostics as
+// a stream of JSON events on standard output, as for 'go build -json'.
+// See 'go help buildjson' for the format. It is separate from the -json flag,
+// which is passed to the analysis tool and leaves the vet output unchanged.
+//
 // The build flags supported by go vet are those that control package resolution
 // and execution, such as -C, -n, -x, -v, -tags, and -toolexec.
 // For more about these flags, see 'go help build'.
@@ -2122,6 +2425,64 @@
 // has a term for a Go major release, the language version used when compiling
 // the file will be the minimum version implied by the build constraint.
 //
+// # Build -json encoding
+//
+// The 'go build' and 'go install' commands take a -json flag, and the
+// 'go vet' command a -buildjson flag, that reports the progress of the build,
+// and the output of the compiler, linker and other tools it runs, as a stream
+// of JSON objects on standard output, one per line, instead of printing that
+// output on standard error. Each object is a BuildEvent:
+//
+//	type BuildEvent struct {
+//		Time       time.Time
+//		Action     string
+//		ID         int
+//		Mode       string
+//		ImportPath string  // omitted if the action has no package
+//		ActionID   string  // omitted if not yet known
+//		Cached     bool    // omitted if false
+//		Elapsed    float64 // seconds; omitted if zero
+//		Output     string  // omitted if empty
+//		File       string  // omitted if empty
+//		Line       int     // omitted if zero
+//		Col        int     // omitted if zero
+//		Message    string  // omitted if empty
+//		Analyzer   string  // omitted if empty
+//	}
+//
+// Each event describes one action of the build, such as compiling or
+// linking a package. ID is the number of the action, and ActionID its
+// build cache key: these are the same as the ID and ActionID fields of the
+// action graph written by the -debug-actiongraph flag. Mode is the kind of
+// action, such as "build", "link" or "vet", and ImportPath is the package
+// it works on.
+//
+// The Action field is one of a fixed set of action descriptions:
+//
+//	start      - the action started
+//	output     - the action printed output (Output)
+//	diagnostic - the output contained a diagnostic
+//	pass       - the action succeeded
+//	fail       - the action failed
+//
+// Output events carry the text printed by the action, or the error that
+// made it fail, such as a link failure, in the same form as without -json.
+// Each output event is followed by a diagnostic event for each diagnostic
+// of the form file:line:col: message that it contains, with the position in
+// File, Line and (if printed) Col, and the text in Message. For
+// 'go vet -buildjson -json', where the analysis tool itself reports in JSON,
+// the diagnostics are those in its report, and Analyzer names the analyzer
+// that reported each one.
+//
+// The pass and fail events end an action. Cached reports whether its result
+// was taken from the build cache rather than computed, and Elapsed how long
+// the action ran. Actions that do not run because a dependency failed report
+// no events.
+//
+// Commands printed by the -n and -x flags, and errors that prevent the build
+// from starting, such as errors loading packages, are still printed on
+// standard error.
+//
 // # Build modes
 //
 // The 'go build' and 'go install' commands take a -buildmode argument which
@@ -2263,6 +2624,11 @@
 //	GOCACHE
 //		The directory where the go command will store cached
 //		information for reuse in future builds.
+//	GOCACHEPROG
+//		A command (with optional space-separated flags) that implements an
+//		external go command build cache, such as one shared by several
+//		machines. See 'go doc cmd/go/internal/cacheprog' for the protocol,
+//		and $GOROOT/src/cmd/go/testdata/cacheprog for an example.
 //	GOMODCACHE
 //		The directory where the go command will store downloaded modules.
 //	GODEBUG
@@ -2912,7 +3278,7 @@
 // If no import paths are given, the action applies to the
 // package in the current directory.
 //
-// There are four reserved names for paths that should not be used
+// There are five reserved names for paths that should not be used
 // for packages to be built with the go tool:
 //
 // - "main" denotes the top-level package in a stand-alone executable.
@@ -2920,8 +3286,8 @@
 // - "all" expands to all packages found in all the GOPATH
 // trees. For example, 'go list all' lists all the packages on the local
 // system. When using modules, "all" expands to all packages in
-// the main module and their dependencies, including dependencies
-// needed by tests of any of those.
+// the main module, the tools it declares, and their dependencies,
+// including dependencies needed by tests of any of those.
 //
 // - "std" is like all but expands to just the packages in the standard
 // Go library.
@@ -2929,6 +3295,10 @@
 // - "cmd" expands to the Go repository's commands and their
 // internal libraries.
 //
+// - "tool" expands to the tools declared by tool directives in the go.mod
+// file of the main module, which 'go get -tool' adds. It requires module
+// mode.
+//
 // Import paths beginning with "cmd/" only match source code in
 // the Go repository.
 //
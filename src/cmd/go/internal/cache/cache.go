@@ -533,7 +533,33 @@ func PutBytes(c Cache, id ActionID, data []byte) error {
 // copyFile copies file into the cache, expecting it to have the given
 // output ID and size, if that file is not present already.
 func (c *DiskCache) copyFile(file io.ReadSeeker, out OutputID, size int64) error {
-	name := c.fileName(out, "d")
+	name, mode, ok := c.checkOutputFile(out, size)
+	if ok {
+		return nil
+	}
+	if _, err := file.Seek(0, 0); err != nil {
+		return err
+	}
+	return c.writeOutputFile(name, mode, file, out, size)
+}
+
+// putOutput stores the size bytes read from r in the cache as the output
+// with ID out, writing them directly to the cache directory.
+// It consumes the size bytes from r even if the output is already present.
+func (c *DiskCache) putOutput(r io.Reader, out OutputID, size int64) error {
+	name, mode, ok := c.checkOutputFile(out, size)
+	if ok {
+		_, err := io.CopyN(io.Discard, r, size)
+		return err
+	}
+	return c.writeOutputFile(name, mode, r, out, size)
+}
+
+// checkOutputFile reports whether the cache already holds the output
+// with ID out and the given size. If not, it returns the name of the
+// output file and the mode with which to open it for writing.
+func (c *DiskCache) checkOutputFile(out OutputID, size int64) (name string, mode int, ok bool) {
+	name = c.fileName(out, "d")
 	info, err := os.Stat(name)
 	if err == nil && info.Size() == size {
 		// Check hash.
@@ -544,17 +570,22 @@ func (c *DiskCache) copyFile(file io.ReadSeeker, out OutputID, size int64) error
 			var out2 OutputID
 			h.Sum(out2[:0])
 			if out == out2 {
-				return nil
+				return name, 0, true
 			}
 		}
 		// Hash did not match. Fall through and rewrite file.
 	}
 
-	// Copy file to cache directory.
-	mode := os.O_RDWR | os.O_CREATE
+	mode = os.O_RDWR | os.O_CREATE
 	if err == nil && info.Size() > size { // shouldn't happen but fix in case
 		mode |= os.O_TRUNC
 	}
+	return name, mode, false
+}
+
+// writeOutputFile writes the size bytes read from r to the output file name,
+// opened with mode, expecting them to have the given output ID.
+func (c *DiskCache) writeOutputFile(name string, mode int, r io.Reader, out OutputID, size int64) error {
 	f, err := os.OpenFile(name, mode, 0666)
 	if err != nil {
 		return err
@@ -571,14 +602,10 @@ func (c *DiskCache) copyFile(file io.ReadSeeker, out OutputID, size int64) error
 	// we make a best-effort attempt to truncate the file f
 	// before returning, to avoid leaving bad bytes in the file.
 
-	// Copy file to f, but also into h to double-check hash.
-	if _, err := file.Seek(0, 0); err != nil {
-		f.Truncate(0)
-		return err
-	}
+	// Copy r to f, but also into h to double-check hash.
 	h := sha256.New()
 	w := io.MultiWriter(f, h)
-	if _, err := io.CopyN(w, file, size-1); err != nil {
+	if _, err := io.CopyN(w, r, size-1); err != nil {
 		f.Truncate(0)
 		return err
 	}
@@ -586,7 +613,7 @@ func (c *DiskCache) copyFile(file io.ReadSeeker, out OutputID, size int64) error
 	// what other processes expect to find and might cause them to start
 	// using the file.
 	buf := make([]byte, 1)
-	if _, err := file.Read(buf); err != nil {
+	if _, err := io.ReadFull(r, buf); err != nil {
 		f.Truncate(0)
 		return err
 	}
@@ -12,7 +12,6 @@ import (
 
 	"cmd/go/internal/base"
 	"cmd/go/internal/cfg"
-	"internal/goexperiment"
 )
 
 // Default returns the default cache to use.
@@ -59,7 +58,7 @@ func initDefaultCache() {
 		base.Fatalf("failed to initialize build cache at %s: %s\n", dir, err)
 	}
 
-	if v := cfg.Getenv("GOCACHEPROG"); v != "" && goexperiment.CacheProg {
+	if v := cfg.GOCACHEPROG; v != "" {
 		defaultCache = startCacheProg(v, diskCache)
 	} else {
 		defaultCache = diskCache
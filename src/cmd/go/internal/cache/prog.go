@@ -6,7 +6,9 @@ package cache
 
 import (
 	"bufio"
+	"bytes"
 	"cmd/go/internal/base"
+	"cmd/go/internal/cacheprog"
 	"cmd/internal/quoted"
 	"context"
 	"crypto/sha256"
@@ -27,7 +29,7 @@ import (
 // helper process which can then implement whatever caching policy/mechanism it
 // wants.
 //
-// See https://github.com/golang/go/issues/59719
+// See the cmd/go/internal/cacheprog package for the protocol.
 type ProgCache struct {
 	cmd    *exec.Cmd
 	stdout io.ReadCloser  // from the child process
@@ -37,117 +39,47 @@ type ProgCache struct {
 
 	// can are the commands that the child process declared that it supports.
 	// This is effectively the versioning mechanism.
-	can map[ProgCmd]bool
+	can map[cacheprog.Cmd]bool
 
-	// fuzzDirCache is another Cache implementation to use for the FuzzDir
-	// method. In practice this is the default GOCACHE disk-based
-	// implementation.
+	// disk is the default GOCACHE disk-based implementation.
+	// It is used for the FuzzDir method and to store the objects
+	// that the child process streams in its responses to gets.
 	//
-	// TODO(bradfitz): maybe this isn't ideal. But we'd need to extend the Cache
-	// interface and the fuzzing callers to be less disk-y to do more here.
-	fuzzDirCache Cache
+	// TODO(bradfitz): maybe this isn't ideal for FuzzDir. But we'd need to
+	// extend the Cache interface and the fuzzing callers to be less disk-y
+	// to do more here.
+	disk *DiskCache
 
 	closing      atomic.Bool
+	streamed     atomic.Bool        // whether any object was stored in disk
 	ctx          context.Context    // valid until Close via ctxClose
 	ctxCancel    context.CancelFunc // called on Close
 	readLoopDone chan struct{}      // closed when readLoop returns
 
 	mu         sync.Mutex // guards following fields
 	nextID     int64
-	inFlight   map[int64]chan<- *ProgResponse
-	outputFile map[OutputID]string // object => abs path on disk
-
-	// writeMu serializes writing to the child process.
-	// It must never be held at the same time as mu.
-	writeMu sync.Mutex
+	inFlight   map[int64]chan<- *cacheprog.Response // nil for batch requests
+	batches    map[int64][]*cacheprog.Request       // requests in each batch request in flight
+	outputFile map[OutputID]string                  // object => abs path on disk
+
+	// queue holds the requests waiting to be written to the child process,
+	// and writing reports whether a goroutine is writing them.
+	// Only that goroutine writes to the child process.
+	queue   []*cacheprog.Request
+	writing bool
 }
 
-// ProgCmd is a command that can be issued to a child process.
-//
-// If the interface needs to grow, we can add new commands or new versioned
-// commands like "get2".
-type ProgCmd string
-
-const (
-	cmdGet   = ProgCmd("get")
-	cmdPut   = ProgCmd("put")
-	cmdClose = ProgCmd("close")
-)
-
-// ProgRequest is the JSON-encoded message that's sent from cmd/go to
-// the GOCACHEPROG child process over stdin. Each JSON object is on its
-// own line. A ProgRequest of Type "put" with BodySize > 0 will be followed
-// by a line containing a base64-encoded JSON string literal of the body.
-type ProgRequest struct {
-	// ID is a unique number per process across all requests.
-	// It must be echoed in the ProgResponse from the child.
-	ID int64
-
-	// Command is the type of request.
-	// The cmd/go tool will only send commands that were declared
-	// as supported by the child.
-	Command ProgCmd
-
-	// ActionID is non-nil for get and puts.
-	ActionID []byte `json:",omitempty"` // or nil if not used
-
-	// ObjectID is set for Type "put" and "output-file".
-	ObjectID []byte `json:",omitempty"` // or nil if not used
-
-	// Body is the body for "put" requests. It's sent after the JSON object
-	// as a base64-encoded JSON string when BodySize is non-zero.
-	// It's sent as a separate JSON value instead of being a struct field
-	// send in this JSON object so large values can be streamed in both directions.
-	// The base64 string body of a ProgRequest will always be written
-	// immediately after the JSON object and a newline.
-	Body io.Reader `json:"-"`
-
-	// BodySize is the number of bytes of Body. If zero, the body isn't written.
-	BodySize int64 `json:",omitempty"`
-}
-
-// ProgResponse is the JSON response from the child process to cmd/go.
-//
-// With the exception of the first protocol message that the child writes to its
-// stdout with ID==0 and KnownCommands populated, these are only sent in
-// response to a ProgRequest from cmd/go.
-//
-// ProgResponses can be sent in any order. The ID must match the request they're
-// replying to.
-type ProgResponse struct {
-	ID  int64  // that corresponds to ProgRequest; they can be answered out of order
-	Err string `json:",omitempty"` // if non-empty, the error
-
-	// KnownCommands is included in the first message that cache helper program
-	// writes to stdout on startup (with ID==0). It includes the
-	// ProgRequest.Command types that are supported by the program.
-	//
-	// This lets us extend the protocol gracefully over time (adding "get2",
-	// etc), or fail gracefully when needed. It also lets us verify the program
-	// wants to be a cache helper.
-	KnownCommands []ProgCmd `json:",omitempty"`
-
-	// For Get requests.
-
-	Miss     bool       `json:",omitempty"` // cache miss
-	OutputID []byte     `json:",omitempty"`
-	Size     int64      `json:",omitempty"` // in bytes
-	Time     *time.Time `json:",omitempty"` // an Entry.Time; when the object was added to the docs
-
-	// DiskPath is the absolute path on disk of the ObjectID corresponding
-	// a "get" request's ActionID (on cache hit) or a "put" request's
-	// provided ObjectID.
-	DiskPath string `json:",omitempty"`
-}
+// maxBatch is the maximum number of requests in a batch request.
+const maxBatch = 64
 
 // startCacheProg starts the prog binary (with optional space-separated flags)
 // and returns a Cache implementation that talks to it.
 //
 // It blocks a few seconds to wait for the child process to successfully start
 // and advertise its capabilities.
-func startCacheProg(progAndArgs string, fuzzDirCache Cache) Cache {
-	if fuzzDirCache == nil {
-		panic("missing fuzzDirCache")
+func startCacheProg(progAndArgs string, disk *DiskCache) Cache {
+	if disk == nil {
+		panic("missing disk cache")
 	}
 	args, err := quoted.Split(progAndArgs)
 	if err != nil {
@@ -180,19 +112,20 @@ func startCacheProg(progAndArgs string, fuzzDirCache Cache) Cache {
 	pc := &ProgCache{
 		ctx:          ctx,
 		ctxCancel:    ctxCancel,
-		fuzzDirCache: fuzzDirCache,
+		disk:         disk,
 		cmd:          cmd,
 		stdout:       out,
 		stdin:        in,
 		bw:           bufio.NewWriter(in),
-		inFlight:     make(map[int64]chan<- *ProgResponse),
+		inFlight:     make(map[int64]chan<- *cacheprog.Response),
+		batches:      make(map[int64][]*cacheprog.Request),
 		outputFile:   make(map[OutputID]string),
 		readLoopDone: make(chan struct{}),
 	}
 
 	// Register our interest in the initial protocol message from the child to
 	// us, saying what it can do.
-	capResc := make(chan *ProgResponse, 1)
+	capResc := make(chan *cacheprog.Response, 1)
 	pc.inFlight[0] = capResc
 
 	pc.jenc = json.NewEncoder(pc.bw)
@@ -207,7 +140,7 @@ func startCacheProg(progAndArgs string, fuzzDirCache Cache) Cache {
 		case <-timer.C:
 			log.Printf("# still waiting for GOCACHEPROG %v ...", prog)
 		case capRes := <-capResc:
-			can := map[ProgCmd]bool{}
+			can := map[cacheprog.Cmd]bool{}
 			for _, cmd := range capRes.KnownCommands {
 				can[cmd] = true
 			}
@@ -222,9 +155,10 @@ func startCacheProg(progAndArgs string, fuzzDirCache Cache) Cache {
 
 func (c *ProgCache) readLoop(readLoopDone chan<- struct{}) {
 	defer close(readLoopDone)
-	jd := json.NewDecoder(c.stdout)
+	var r io.Reader = c.stdout
+	jd := json.NewDecoder(r)
 	for {
-		res := new(ProgResponse)
+		res := new(cacheprog.Response)
 		if err := jd.Decode(res); err != nil {
 			if c.closing.Load() {
 				return // quietly
@@ -237,23 +171,179 @@ func (c *ProgCache) readLoop(readLoopDone chan<- struct{}) {
 			}
 			base.Fatalf("error reading JSON from GOCACHEPROG: %v", err)
 		}
+
+		// Find the requests the response answers:
+		// res itself, or each response in its batch.
+		var (
+			responses []*cacheprog.Response
+			chans     []chan<- *cacheprog.Response
+		)
 		c.mu.Lock()
 		ch, ok := c.inFlight[res.ID]
 		delete(c.inFlight, res.ID)
+		batch := c.batches[res.ID]
+		delete(c.batches, res.ID)
+		if ok && ch != nil {
+			responses = append(responses, res)
+			chans = append(chans, ch)
+		} else if ok {
+			for _, bres := range res.Batch {
+				ch, ok := c.inFlight[bres.ID]
+				delete(c.inFlight, bres.ID)
+				if !ok || ch == nil {
+					c.mu.Unlock()
+					base.Fatalf("GOCACHEPROG sent response for unknown request ID %v in batch %v", bres.ID, res.ID)
+				}
+				responses = append(responses, bres)
+				chans = append(chans, ch)
+			}
+			// Every request in the batch must be answered,
+			// or its caller would wait forever.
+			for _, req := range batch {
+				if _, pending := c.inFlight[req.ID]; pending {
+					c.mu.Unlock()
+					base.Fatalf("GOCACHEPROG did not answer request ID %v in batch %v", req.ID, res.ID)
+				}
+			}
+		}
 		c.mu.Unlock()
-		if ok {
-			ch <- res
-		} else {
+		if !ok {
 			base.Fatalf("GOCACHEPROG sent response for unknown request ID %v", res.ID)
 		}
+
+		// Read the bodies that follow the responses.
+		// The decoder may have buffered the start of them.
+		var br *bufio.Reader
+		for _, res := range responses {
+			if res.BodySize == 0 {
+				continue
+			}
+			if br == nil {
+				r = io.MultiReader(jd.Buffered(), r)
+				br = bufio.NewReader(r)
+			}
+			if err := c.readBody(br, res); err != nil {
+				if c.closing.Load() {
+					return
+				}
+				base.Fatalf("error reading body from GOCACHEPROG: %v", err)
+			}
+		}
+		if br != nil {
+			// Continue decoding after the bodies, starting with
+			// whatever br buffered.
+			rest, _ := br.Peek(br.Buffered())
+			r = io.MultiReader(bytes.NewReader(bytes.Clone(rest)), r)
+			jd = json.NewDecoder(r)
+		}
+
+		for i, res := range responses {
+			chans[i] <- res
+		}
+	}
+}
+
+// readBody reads the body that follows the response res to a get request
+// from br and stores it in the disk cache, setting res.DiskPath.
+// It returns an error only if the body could not be read; an error storing
+// the body is reported in res.Err.
+func (c *ProgCache) readBody(br *bufio.Reader, res *cacheprog.Response) error {
+	// Skip the newline before the opening quote.
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return err
+		}
+		if b == '"' {
+			break
+		}
+		if b != ' ' && b != '\t' && b != '\r' && b != '\n' {
+			return fmt.Errorf("unexpected %q before body of response %v", b, res.ID)
+		}
+	}
+
+	body := &quotedReader{br: br}
+	var out OutputID
+	var err error
+	if res.BodySize != res.Size {
+		err = fmt.Errorf("GOCACHEPROG sent body of %d bytes for object of %d bytes", res.BodySize, res.Size)
+	} else if copy(out[:], res.OutputID) != len(res.OutputID) {
+		err = errors.New("incomplete cacheprog.Response OutputID")
+	} else {
+		err = c.disk.putOutput(base64.NewDecoder(base64.StdEncoding, body), out, res.BodySize)
 	}
+
+	// Discard the rest of the body, in case it could not be stored.
+	io.Copy(io.Discard, body)
+	if body.err != nil {
+		return body.err
+	}
+	if err != nil {
+		res.Err = err.Error()
+		return nil
+	}
+	c.streamed.Store(true)
+	res.DiskPath = c.disk.OutputFile(out)
+	return nil
+}
+
+// A quotedReader reads the contents of a JSON string literal,
+// whose opening quote has been read, up to the closing quote.
+// It is meant to read base64, which contains no escape sequences.
+type quotedReader struct {
+	br   *bufio.Reader
+	done bool  // read closing quote
+	err  error // error reading br
 }
 
-func (c *ProgCache) send(ctx context.Context, req *ProgRequest) (*ProgResponse, error) {
-	resc := make(chan *ProgResponse, 1)
-	if err := c.writeToChild(req, resc); err != nil {
-		return nil, err
+func (q *quotedReader) Read(p []byte) (int, error) {
+	if q.done {
+		return 0, io.EOF
+	}
+	if q.err != nil {
+		return 0, q.err
+	}
+	if q.br.Buffered() == 0 {
+		if _, err := q.br.Peek(1); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			q.err = err
+			return 0, err
+		}
+	}
+	buf, _ := q.br.Peek(min(len(p), q.br.Buffered()))
+	i := bytes.IndexByte(buf, '"')
+	if i >= 0 {
+		buf = buf[:i]
+	}
+	n := copy(p, buf)
+	q.br.Discard(n)
+	if i >= 0 {
+		q.br.Discard(1)
+		q.done = true
+		if n == 0 {
+			return 0, io.EOF
+		}
+	}
+	return n, nil
+}
+
+func (c *ProgCache) send(ctx context.Context, req *cacheprog.Request) (*cacheprog.Response, error) {
+	resc := make(chan *cacheprog.Response, 1)
+	c.mu.Lock()
+	c.nextID++
+	req.ID = c.nextID
+	c.inFlight[req.ID] = resc
+	c.queue = append(c.queue, req)
+	write := !c.writing
+	c.writing = true
+	c.mu.Unlock()
+
+	if write {
+		c.writeQueue()
 	}
+
 	select {
 	case res := <-resc:
 		if res.Err != "" {
@@ -265,58 +355,144 @@ func (c *ProgCache) send(ctx context.Context, req *ProgRequest) (*ProgResponse,
 	}
 }
 
-func (c *ProgCache) writeToChild(req *ProgRequest, resc chan<- *ProgResponse) (err error) {
-	c.mu.Lock()
-	c.nextID++
-	req.ID = c.nextID
-	c.inFlight[req.ID] = resc
-	c.mu.Unlock()
-
-	defer func() {
-		if err != nil {
-			c.mu.Lock()
-			delete(c.inFlight, req.ID)
+// writeQueue writes the queued requests to the child process until the
+// queue is empty. Gets and puts queued by other goroutines while it is
+// writing are coalesced into batch requests, if the child supports them.
+func (c *ProgCache) writeQueue() {
+	for {
+		c.mu.Lock()
+		queue := c.queue
+		c.queue = nil
+		if len(queue) == 0 {
+			c.writing = false
 			c.mu.Unlock()
+			return
 		}
-	}()
+		reqs := c.batch(queue)
+		c.mu.Unlock()
 
-	c.writeMu.Lock()
-	defer c.writeMu.Unlock()
+		for _, req := range reqs {
+			if err := c.writeToChild(req); err != nil {
+				c.fail(req, err)
+			}
+		}
+	}
+}
+
+// batch returns the requests to write for the queued requests,
+// grouping gets and puts into batch requests.
+// c.mu must be held.
+func (c *ProgCache) batch(queue []*cacheprog.Request) []*cacheprog.Request {
+	var reqs, gets, puts []*cacheprog.Request
+	for _, req := range queue {
+		switch {
+		case req.Command == cacheprog.CmdGet && c.can[cacheprog.CmdGetBatch]:
+			gets = append(gets, req)
+		case req.Command == cacheprog.CmdPut && c.can[cacheprog.CmdPutBatch]:
+			puts = append(puts, req)
+		default:
+			reqs = append(reqs, req)
+		}
+	}
+	reqs = c.appendBatches(reqs, cacheprog.CmdGetBatch, gets)
+	reqs = c.appendBatches(reqs, cacheprog.CmdPutBatch, puts)
+	return reqs
+}
+
+// appendBatches appends to reqs the batch requests with the given
+// command for the requests in batch, and returns the result.
+// c.mu must be held.
+func (c *ProgCache) appendBatches(reqs []*cacheprog.Request, cmd cacheprog.Cmd, batch []*cacheprog.Request) []*cacheprog.Request {
+	for len(batch) > 0 {
+		n := min(len(batch), maxBatch)
+		if n == 1 {
+			reqs = append(reqs, batch[0])
+		} else {
+			c.nextID++
+			c.inFlight[c.nextID] = nil
+			c.batches[c.nextID] = batch[:n:n]
+			reqs = append(reqs, &cacheprog.Request{
+				ID:      c.nextID,
+				Command: cmd,
+				Batch:   batch[:n:n],
+			})
+		}
+		batch = batch[n:]
+	}
+	return reqs
+}
+
+// fail answers req, and the requests in its batch, with err.
+func (c *ProgCache) fail(req *cacheprog.Request, err error) {
+	reqs := []*cacheprog.Request{req}
+	if req.Batch != nil {
+		reqs = req.Batch
+	}
+	c.mu.Lock()
+	delete(c.inFlight, req.ID)
+	delete(c.batches, req.ID)
+	chans := make([]chan<- *cacheprog.Response, len(reqs))
+	for i, req := range reqs {
+		chans[i] = c.inFlight[req.ID]
+		delete(c.inFlight, req.ID)
+	}
+	c.mu.Unlock()
+	for i, ch := range chans {
+		if ch != nil {
+			ch <- &cacheprog.Response{ID: reqs[i].ID, Err: err.Error()}
+		}
+	}
+}
 
+// writeToChild writes req, followed by its bodies, to the child process.
+// Only the goroutine running writeQueue may call it.
+func (c *ProgCache) writeToChild(req *cacheprog.Request) error {
 	if err := c.jenc.Encode(req); err != nil {
 		return err
 	}
 	if err := c.bw.WriteByte('\n'); err != nil {
 		return err
 	}
-	if req.Body != nil && req.BodySize > 0 {
-		if err := c.bw.WriteByte('"'); err != nil {
-			return err
-		}
-		e := base64.NewEncoder(base64.StdEncoding, c.bw)
-		wrote, err := io.Copy(e, req.Body)
-		if err != nil {
-			return err
-		}
-		if err := e.Close(); err != nil {
-			return nil
-		}
-		if wrote != req.BodySize {
-			return fmt.Errorf("short write writing body to GOCACHEPROG for action %x, object %x: wrote %v; expected %v",
-				req.ActionID, req.ObjectID, wrote, req.BodySize)
-		}
-		if _, err := c.bw.WriteString("\"\n"); err != nil {
+	bodies := []*cacheprog.Request{req}
+	if req.Batch != nil {
+		bodies = req.Batch
+	}
+	for _, req := range bodies {
+		if err := c.writeBody(req); err != nil {
 			return err
 		}
 	}
-	if err := c.bw.Flush(); err != nil {
+	return c.bw.Flush()
+}
+
+// writeBody writes the body of req, if any, to the child process.
+func (c *ProgCache) writeBody(req *cacheprog.Request) error {
+	if req.Body == nil || req.BodySize == 0 {
+		return nil
+	}
+	if err := c.bw.WriteByte('"'); err != nil {
+		return err
+	}
+	e := base64.NewEncoder(base64.StdEncoding, c.bw)
+	wrote, err := io.Copy(e, req.Body)
+	if err != nil {
+		return err
+	}
+	if err := e.Close(); err != nil {
+		return err
+	}
+	if wrote != req.BodySize {
+		return fmt.Errorf("short write writing body to GOCACHEPROG for action %x, object %x: wrote %v; expected %v",
+			req.ActionID, req.ObjectID, wrote, req.BodySize)
+	}
+	if _, err := c.bw.WriteString("\"\n"); err != nil {
 		return err
 	}
 	return nil
 }
 
 func (c *ProgCache) Get(a ActionID) (Entry, error) {
-	if !c.can[cmdGet] {
+	if !c.can[cacheprog.CmdGet] {
 		// They can't do a "get". Maybe they're a write-only cache.
 		//
 		// TODO(bradfitz,bcmills): figure out the proper error type here. Maybe
@@ -326,9 +502,10 @@ func (c *ProgCache) Get(a ActionID) (Entry, error) {
 		// error types on the Cache interface.
 		return Entry{}, &entryNotFoundError{}
 	}
-	res, err := c.send(c.ctx, &ProgRequest{
-		Command:  cmdGet,
-		ActionID: a[:],
+	res, err := c.send(c.ctx, &cacheprog.Request{
+		Command:    cacheprog.CmdGet,
+		ActionID:   a[:],
+		AcceptBody: true,
 	})
 	if err != nil {
 		return Entry{}, err // TODO(bradfitz): or entryNotFoundError? Audit callers.
@@ -348,7 +525,7 @@ func (c *ProgCache) Get(a ActionID) (Entry, error) {
 		return Entry{}, &entryNotFoundError{errors.New("GOCACHEPROG didn't populate DiskPath on get hit")}
 	}
 	if copy(e.OutputID[:], res.OutputID) != len(res.OutputID) {
-		return Entry{}, &entryNotFoundError{errors.New("incomplete ProgResponse OutputID")}
+		return Entry{}, &entryNotFoundError{errors.New("incomplete cacheprog.Response OutputID")}
 	}
 	c.noteOutputFile(e.OutputID, res.DiskPath)
 	return e, nil
@@ -383,13 +560,13 @@ func (c *ProgCache) Put(a ActionID, file io.ReadSeeker) (_ OutputID, size int64,
 		return OutputID{}, 0, err
 	}
 
-	if !c.can[cmdPut] {
+	if !c.can[cacheprog.CmdPut] {
 		// Child is a read-only cache. Do nothing.
 		return out, size, nil
 	}
 
-	res, err := c.send(c.ctx, &ProgRequest{
-		Command:  cmdPut,
+	res, err := c.send(c.ctx, &cacheprog.Request{
+		Command:  cacheprog.CmdPut,
 		ActionID: a[:],
 		ObjectID: out[:],
 		Body:     file,
@@ -412,16 +589,24 @@ func (c *ProgCache) Close() error {
 	// First write a "close" message to the child so it can exit nicely
 	// and clean up if it wants. Only after that exchange do we cancel
 	// the context that kills the process.
-	if c.can[cmdClose] {
-		_, err = c.send(c.ctx, &ProgRequest{Command: cmdClose})
+	if c.can[cacheprog.CmdClose] {
+		_, err = c.send(c.ctx, &cacheprog.Request{Command: cacheprog.CmdClose})
 	}
 	c.ctxCancel()
 	<-c.readLoopDone
+
+	// The objects streamed by the child are stored in the disk cache,
+	// which must be trimmed like any other.
+	if c.streamed.Load() {
+		if terr := c.disk.Trim(); err == nil {
+			err = terr
+		}
+	}
 	return err
 }
 
 func (c *ProgCache) FuzzDir() string {
 	// TODO(bradfitz): figure out what to do here. For now just use the
 	// disk-based default.
-	return c.fuzzDirCache.FuzzDir()
+	return c.disk.FuzzDir()
 }
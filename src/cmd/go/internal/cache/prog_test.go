@@ -0,0 +1,62 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"cmd/go/internal/cacheprog"
+)
+
+func TestProgReadBody(t *testing.T) {
+	dir := t.TempDir()
+	c, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pc := &ProgCache{disk: c}
+
+	data := []byte("hello, world\n")
+	out := OutputID(sha256.Sum256(data))
+	stream := "\n\"" + base64.StdEncoding.EncodeToString(data) + "\"\n{\"ID\":2}\n"
+	br := bufio.NewReaderSize(strings.NewReader(stream), 16)
+	res := &cacheprog.Response{ID: 1, OutputID: out[:], Size: int64(len(data)), BodySize: int64(len(data))}
+	if err := pc.readBody(br, res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Err != "" {
+		t.Fatalf("readBody: %s", res.Err)
+	}
+	got, err := os.ReadFile(res.DiskPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(data) {
+		t.Errorf("stored %q; want %q", got, data)
+	}
+	if rest, _ := io.ReadAll(br); string(rest) != "\n{\"ID\":2}\n" {
+		t.Errorf("after body, read %q; want the next response", rest)
+	}
+
+	// A body that does not match its output ID is consumed but not stored.
+	br = bufio.NewReader(strings.NewReader("\"" + base64.StdEncoding.EncodeToString([]byte("HELLO, WORLD\n")) + "\"\n"))
+	res = &cacheprog.Response{ID: 3, OutputID: out[:], Size: int64(len(data)), BodySize: int64(len(data))}
+	os.Remove(c.OutputFile(out))
+	if err := pc.readBody(br, res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Err == "" || res.DiskPath != "" {
+		t.Errorf("readBody stored mismatched body: Err=%q DiskPath=%q", res.Err, res.DiskPath)
+	}
+	if rest, _ := io.ReadAll(br); string(rest) != "\n" {
+		t.Errorf("after mismatched body, read %q; want %q", rest, "\n")
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package cacheprog defines the protocol for a GOCACHEPROG program.
+//
+// By default, the go command manages a build cache stored in the file
+// system itself. GOCACHEPROG can be set to the name of a command (with
+// optional space-separated flags) that implements the go command build
+// cache externally. This permits defining a different cache policy,
+// such as a cache shared by several machines.
+//
+// The go command starts the GOCACHEPROG as a subprocess and communicates
+// with it using JSON messages over its stdin and stdout. The subprocess's
+// stderr is connected to the go command's stderr.
+//
+// The subprocess must immediately write a [Response] with ID 0 whose
+// KnownCommands lists the commands it supports. After that, the go
+// command writes a stream of [Request] messages, and the subprocess
+// replies to each with a [Response] with the same ID. Responses may be
+// written in any order.
+//
+// # Versioning
+//
+// The protocol is versioned by its set of commands: the go command only
+// sends the commands that the subprocess declared in KnownCommands, and
+// falls back to older commands when newer ones are not supported. New
+// behavior is added only as new commands, or as new request fields that
+// a subprocess is free to ignore, so that any subprocess written against
+// an earlier version of this package keeps working.
+//
+// The first version of the protocol defined [CmdGet], [CmdPut] and
+// [CmdClose]. The second version added [CmdGetBatch], [CmdPutBatch]
+// and the streaming of get bodies requested by [Request.AcceptBody].
+//
+// # Bodies
+//
+// The contents of a cache object are written as a body after the JSON
+// message that refers to it: a line holding the object as a
+// base64-encoded JSON string literal. Bodies are never embedded in the
+// JSON message itself, so that large objects can be streamed in both
+// directions. A message whose BodySize is zero has no body.
+//
+// A [CmdPut] request is followed by the body of the object being stored.
+// A [CmdPutBatch] request is followed by the body of each request in its
+// Batch with a non-zero BodySize, in order.
+//
+// When a get request sets AcceptBody, the subprocess may answer a hit by
+// streaming the object as a body after its response instead of storing
+// it on disk and setting DiskPath. The go command then writes the body
+// directly into its local cache directory. A [CmdGetBatch] response is
+// followed by the bodies of the responses in its Batch, in order.
+package cacheprog
+
+import (
+	"io"
+	"time"
+)
+
+// Cmd is a command that can be issued to a child process.
+//
+// If the interface needs to grow, the go command can add new commands or
+// new versioned commands like "get2".
+type Cmd string
+
+const (
+	// CmdGet looks up the object stored for Request.ActionID.
+	CmdGet = Cmd("get")
+
+	// CmdPut stores the body, whose ID is Request.ObjectID,
+	// as the object for Request.ActionID.
+	CmdPut = Cmd("put")
+
+	// CmdClose is sent before the go command exits.
+	// The subprocess should flush any pending work and reply,
+	// after which its stdin is closed.
+	CmdClose = Cmd("close")
+
+	// CmdGetBatch performs the get requests in Request.Batch.
+	// The Response.Batch holds a response for each of them, in order.
+	CmdGetBatch = Cmd("get-batch")
+
+	// CmdPutBatch performs the put requests in Request.Batch.
+	// The Response.Batch holds a response for each of them, in order.
+	CmdPutBatch = Cmd("put-batch")
+)
+
+// Request is the JSON-encoded message that's sent from the go command to
+// the GOCACHEPROG child process over stdin. Each JSON object is on its
+// own line. A Request of Command "put" with BodySize > 0 will be followed
+// by a line containing a base64-encoded JSON string literal of the body.
+type Request struct {
+	// ID is a unique number per process across all requests,
+	// including the requests in a Batch.
+	// It must be echoed in the Response from the child.
+	ID int64
+
+	// Command is the type of request.
+	// The go command will only send commands that were declared
+	// as supported by the child.
+	Command Cmd
+
+	// ActionID is the cache key for "get" and "put" requests.
+	ActionID []byte `json:",omitempty"` // or nil if not used
+
+	// ObjectID is set for "put" requests. It is the SHA-256 of the body.
+	ObjectID []byte `json:",omitempty"` // or nil if not used
+
+	// Body is the body for "put" requests. It's sent after the JSON object
+	// as a base64-encoded JSON string when BodySize is non-zero.
+	// It's sent as a separate JSON value instead of being a struct field
+	// sent in this JSON object so large values can be streamed in both directions.
+	// The base64 string body of a Request will always be written
+	// immediately after the JSON object and a newline.
+	Body io.Reader `json:"-"`
+
+	// BodySize is the number of bytes of Body. If zero, the body isn't written.
+	BodySize int64 `json:",omitempty"`
+
+	// AcceptBody is set for "get" requests when the go command accepts
+	// the object of a hit as a body following the Response, in which
+	// case the Response need not set DiskPath.
+	AcceptBody bool `json:",omitempty"`
+
+	// Batch holds the requests of a "get-batch" or "put-batch" request,
+	// all of which have the Command "get" or "put", respectively.
+	Batch []*Request `json:",omitempty"`
+}
+
+// Response is the JSON response from the child process to the go command.
+//
+// With the exception of the first protocol message that the child writes to its
+// stdout with ID==0 and KnownCommands populated, these are only sent in
+// response to a Request from the go command.
+//
+// Responses can be sent in any order. The ID must match the request they're
+// replying to.
+type Response struct {
+	ID  int64  // that corresponds to Request; they can be answered out of order
+	Err string `json:",omitempty"` // if non-empty, the error
+
+	// KnownCommands is included in the first message that cache helper program
+	// writes to stdout on startup (with ID==0). It includes the
+	// Request.Command types that are supported by the program.
+	//
+	// This lets the go command extend the protocol gracefully over time
+	// (adding "get2", etc), or fail gracefully when needed. It also lets
+	// the go command verify the program wants to be a cache helper.
+	KnownCommands []Cmd `json:",omitempty"`
+
+	// For "get" requests.
+
+	Miss     bool       `json:",omitempty"` // cache miss
+	OutputID []byte     `json:",omitempty"` // the ObjectID stored with the ActionID
+	Size     int64      `json:",omitempty"` // in bytes
+	Time     *time.Time `json:",omitempty"` // when the object was put in the cache
+
+	// DiskPath is the absolute path on disk of the object corresponding
+	// to a "get" request's ActionID (on cache hit) or a "put" request's
+	// provided ObjectID.
+	DiskPath string `json:",omitempty"`
+
+	// BodySize is set in the response to a "get" request with
+	// AcceptBody set, when the object is written as a body following the
+	// response instead of being stored at DiskPath. It must equal Size.
+	BodySize int64 `json:",omitempty"`
+
+	// Batch holds the responses to the requests in the Batch of a
+	// "get-batch" or "put-batch" request, in the same order.
+	Batch []*Response `json:",omitempty"`
+}
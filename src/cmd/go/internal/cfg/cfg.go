@@ -78,8 +78,10 @@ var (
 	BuildCover         bool                    // -cover flag
 	BuildCoverMode     string                  // -covermode flag
 	BuildCoverPkg      []string                // -coverpkg flag
+	BuildJSON          bool                    // -json flag of build, install and vet
 	BuildN             bool                    // -n flag
 	BuildO             string                  // -o flag
+	BuildOptDetails    string                  // -optdetails flag
 	BuildP             = runtime.GOMAXPROCS(0) // -p flag
 	BuildPGO           string                  // -pgo flag
 	BuildPkgdir        string                  // -pkgdir flag
@@ -87,6 +89,7 @@ var (
 	BuildToolexec      []string                // -toolexec flag
 	BuildToolchainName string
 	BuildTrimpath      bool // -trimpath flag
+	BuildBuildpkgs     bool // -buildpkgs flag
 	BuildV             bool // -v flag
 	BuildWork          bool // -work flag
 	BuildX             bool // -x flag
@@ -433,6 +436,7 @@ var (
 	GONOSUMDB, GONOSUMDBChanged = EnvOrAndChanged("GONOSUMDB", GOPRIVATE)
 	GOINSECURE                  = Getenv("GOINSECURE")
 	GOVCS                       = Getenv("GOVCS")
+	GOCACHEPROG                 = Getenv("GOCACHEPROG")
 )
 
 // EnvOrAndChanged returns the environment variable value
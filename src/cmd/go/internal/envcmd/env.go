@@ -82,6 +82,7 @@ func MkEnv() []cfg.EnvVar {
 		{Name: "GOARCH", Value: cfg.Goarch, Changed: cfg.Goarch != runtime.GOARCH},
 		{Name: "GOBIN", Value: cfg.GOBIN},
 		{Name: "GOCACHE"},
+		{Name: "GOCACHEPROG", Value: cfg.GOCACHEPROG},
 		{Name: "GOENV", Value: envFile, Changed: envFileChanged},
 		{Name: "GOEXE", Value: cfg.ExeSuffix},
 
@@ -121,7 +122,7 @@ func MkEnv() []cfg.EnvVar {
 			if env[i].Value != "on" && env[i].Value != "" {
 				env[i].Changed = true
 			}
-		case "GOBIN", "GOEXPERIMENT", "GOFLAGS", "GOINSECURE", "GOPRIVATE", "GOTMPDIR", "GOVCS":
+		case "GOBIN", "GOCACHEPROG", "GOEXPERIMENT", "GOFLAGS", "GOINSECURE", "GOPRIVATE", "GOTMPDIR", "GOVCS":
 			if env[i].Value != "" {
 				env[i].Changed = true
 			}
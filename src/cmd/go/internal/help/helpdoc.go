@@ -49,7 +49,7 @@ environment variable (For more details see: 'go help gopath').
 If no import paths are given, the action applies to the
 package in the current directory.
 
-There are four reserved names for paths that should not be used
+There are five reserved names for paths that should not be used
 for packages to be built with the go tool:
 
 - "main" denotes the top-level package in a stand-alone executable.
@@ -57,8 +57,8 @@ for packages to be built with the go tool:
 - "all" expands to all packages found in all the GOPATH
 trees. For example, 'go list all' lists all the packages on the local
 system. When using modules, "all" expands to all packages in
-the main module and their dependencies, including dependencies
-needed by tests of any of those.
+the main module, the tools it declares, and their dependencies,
+including dependencies needed by tests of any of those.
 
 - "std" is like all but expands to just the packages in the standard
 Go library.
@@ -66,6 +66,10 @@ Go library.
 - "cmd" expands to the Go repository's commands and their
 internal libraries.
 
+- "tool" expands to the tools declared by tool directives in the go.mod
+file of the main module, which 'go get -tool' adds. It requires module
+mode.
+
 Import paths beginning with "cmd/" only match source code in
 the Go repository.
 
@@ -498,6 +502,11 @@ General-purpose environment variables:
 	GOCACHE
 		The directory where the go command will store cached
 		information for reuse in future builds.
+	GOCACHEPROG
+		A command (with optional space-separated flags) that implements an
+		external go command build cache, such as one shared by several
+		machines. See 'go doc cmd/go/internal/cacheprog' for the protocol,
+		and $GOROOT/src/cmd/go/testdata/cacheprog for an example.
 	GOMODCACHE
 		The directory where the go command will store downloaded modules.
 	GODEBUG
@@ -724,6 +733,68 @@ more details.
 	`,
 }
 
+var HelpBuildJSON = &base.Command{
+	UsageLine: "buildjson",
+	Short:     "build -json encoding",
+	Long: `
+The 'go build' and 'go install' commands take a -json flag, and the
+'go vet' command a -buildjson flag, that reports the progress of the build,
+and the output of the compiler, linker and other tools it runs, as a stream
+of JSON objects on standard output, one per line, instead of printing that
+output on standard error. Each object is a BuildEvent:
+
+	type BuildEvent struct {
+		Time       time.Time
+		Action     string
+		ID         int
+		Mode       string
+		ImportPath string  // omitted if the action has no package
+		ActionID   string  // omitted if not yet known
+		Cached     bool    // omitted if false
+		Elapsed    float64 // seconds; omitted if zero
+		Output     string  // omitted if empty
+		File       string  // omitted if empty
+		Line       int     // omitted if zero
+		Col        int     // omitted if zero
+		Message    string  // omitted if empty
+		Analyzer   string  // omitted if empty
+	}
+
+Each event describes one action of the build, such as compiling or
+linking a package. ID is the number of the action, and ActionID its
+build cache key: these are the same as the ID and ActionID fields of the
+action graph written by the -debug-actiongraph flag. Mode is the kind of
+action, such as "build", "link" or "vet", and ImportPath is the package
+it works on.
+
+The Action field is one of a fixed set of action descriptions:
+
+	start      - the action started
+	output     - the action printed output (Output)
+	diagnostic - the output contained a diagnostic
+	pass       - the action succeeded
+	fail       - the action failed
+
+Output events carry the text printed by the action, or the error that
+made it fail, such as a link failure, in the same form as without -json.
+Each output event is followed by a diagnostic event for each diagnostic
+of the form file:line:col: message that it contains, with the position in
+File, Line and (if printed) Col, and the text in Message. For
+'go vet -buildjson -json', where the analysis tool itself reports in JSON,
+the diagnostics are those in its report, and Analyzer names the analyzer
+that reported each one.
+
+The pass and fail events end an action. Cached reports whether its result
+was taken from the build cache rather than computed, and Elapsed how long
+the action ran. Actions that do not run because a dependency failed report
+no events.
+
+Commands printed by the -n and -x flags, and errors that prevent the build
+from starting, such as errors loading packages, are still printed on
+standard error.
+	`,
+}
+
 var HelpBuildmode = &base.Command{
 	UsageLine: "buildmode",
 	Short:     "build modes",
@@ -2266,8 +2266,22 @@ func (p *Package) setBuildInfo(ctx context.Context, autoVCS bool) {
 		main = *debugModFromModinfo(p.Module)
 	}
 
+	pkgPath := p.ImportPath
+	if p.Internal.CmdlineFiles {
+		pkgPath = "command-line-arguments"
+	}
+
+	// Record the modules that provide the packages in the import graph of
+	// the binary and, with -buildpkgs, the packages themselves, other than
+	// those of the standard library. These are the packages built for the
+	// binary: the list includes packages whose code the linker then
+	// discards as unreachable.
 	visited := make(map[*Package]bool)
 	mdeps := make(map[module.Version]*debug.Module)
+	var pkgs []string
+	if cfg.BuildBuildpkgs && !p.Standard {
+		pkgs = append(pkgs, pkgPath)
+	}
 	var q []*Package
 	q = append(q, p.Internal.Imports...)
 	for len(q) > 0 {
@@ -2277,6 +2291,9 @@ func (p *Package) setBuildInfo(ctx context.Context, autoVCS bool) {
 			continue
 		}
 		visited[p1] = true
+		if cfg.BuildBuildpkgs && !p1.Standard {
+			pkgs = append(pkgs, p1.ImportPath)
+		}
 		if p1.Module != nil {
 			m := module.Version{Path: p1.Module.Path, Version: p1.Module.Version}
 			if p1.Module.Path != main.Path && mdeps[m] == nil {
@@ -2295,14 +2312,12 @@ func (p *Package) setBuildInfo(ctx context.Context, autoVCS bool) {
 		deps[i] = mdeps[mod]
 	}
 
-	pkgPath := p.ImportPath
-	if p.Internal.CmdlineFiles {
-		pkgPath = "command-line-arguments"
-	}
+	slices.Sort(pkgs)
 	info := &debug.BuildInfo{
-		Path: pkgPath,
-		Main: main,
-		Deps: deps,
+		Path:     pkgPath,
+		Main:     main,
+		Deps:     deps,
+		Packages: pkgs,
 	}
 	appendSetting := func(key, value string) {
 		appendBuildSetting(info, key, value)
@@ -2326,6 +2341,9 @@ func (p *Package) setBuildInfo(ctx context.Context, autoVCS bool) {
 		}
 	}
 	appendSetting("-buildmode", buildmode)
+	if cfg.BuildBuildpkgs {
+		appendSetting("-buildpkgs", "true")
+	}
 	appendSetting("-compiler", cfg.BuildContext.Compiler)
 	if gccgoflags := BuildGccgoflags.String(); gccgoflags != "" && cfg.BuildContext.Compiler == "gccgo" {
 		appendSetting("-gccgoflags", gccgoflags)
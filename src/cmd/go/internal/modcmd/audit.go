@@ -0,0 +1,291 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package modcmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+
+	"cmd/go/internal/base"
+	"cmd/go/internal/gover"
+	"cmd/go/internal/modfetch"
+	"cmd/go/internal/modload"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+var cmdAudit = &base.Command{
+	UsageLine: "go mod audit [-json] [-db url] [-allow licenses] [-deny licenses]",
+	Short:     "report known vulnerabilities and licenses of dependencies",
+	Long: `
+Audit reports, for each module in the build list of the main module,
+the known vulnerabilities that affect the selected version of the module
+and the license found in the module's zip file.
+
+Vulnerabilities are read from a database in the OSV format, laid out as
+described at https://go.dev/security/vuln/database: an index of affected
+modules in index/modules.json, and each entry in ID/<id>.json. The -db flag
+sets the location of the database, which may be a local directory or a URL,
+including a file:// URL. It defaults to the value of the GOVULNDB environment
+variable, or https://vuln.go.dev if that is not set. A vulnerability is
+reported when the selected version of a module falls in one of the affected
+ranges of an entry, whether or not the main module uses the vulnerable
+packages. The standard library is checked against the version of the go
+command, if it is a release.
+
+The license of a module is detected from the LICENSE, LICENCE, COPYING and
+UNLICENSE files at the root of its zip file, which is downloaded if needed,
+and is reported as an SPDX identifier, such as BSD-3-Clause, or "unknown".
+A module with several license files reports their identifiers joined by
+" AND ".
+
+The -allow and -deny flags define a license policy, as comma-separated
+lists of SPDX identifiers. With -allow, each module must have only allowed
+licenses; a module whose license is unknown violates that policy. With
+-deny, no module may have a denied license.
+
+Audit exits with a non-zero status if any module is affected by a
+vulnerability, violates the license policy, or could not be checked.
+
+By default, audit prints one line for each module, giving its path, version
+and license, followed by an indented line for each vulnerability and policy
+violation. The -json flag causes audit to print instead a sequence of JSON
+objects, one for each module, corresponding to this Go struct:
+
+    type Module struct {
+        Path         string   // module path
+        Version      string   // module version
+        License      string   // detected license
+        LicenseFiles []string // license files at the root of the module
+        Vulns        []Vuln   // vulnerabilities affecting the module
+        Violations   []string // license policy violations
+        Error        string   // error checking the module
+    }
+
+    type Vuln struct {
+        ID      string   // vulnerability database ID, such as GO-2024-0001
+        Aliases []string // other IDs, such as CVE and GHSA IDs
+        Summary string   // short description
+        Fixed   string   // earliest fixed version, if any
+    }
+
+Audit only uses the network to read a remote vulnerability database and to
+download the zip files missing from the module cache. With a local database
+and GOFLAGS=-mod=mod GOPROXY=off, it runs entirely offline.
+	`,
+}
+
+var (
+	auditJSON  = cmdAudit.Flag.Bool("json", false, "")
+	auditDB    = cmdAudit.Flag.String("db", "", "")
+	auditAllow = cmdAudit.Flag.String("allow", "", "")
+	auditDeny  = cmdAudit.Flag.String("deny", "", "")
+)
+
+func init() {
+	cmdAudit.Run = runAudit // break init cycle
+
+	base.AddChdirFlag(&cmdAudit.Flag)
+	base.AddModCommonFlags(&cmdAudit.Flag)
+}
+
+// An auditModule is the report of go mod audit for a module.
+type auditModule struct {
+	Path         string
+	Version      string      `json:",omitempty"`
+	License      string      `json:",omitempty"`
+	LicenseFiles []string    `json:",omitempty"`
+	Vulns        []auditVuln `json:",omitempty"`
+	Violations   []string    `json:",omitempty"`
+	Error        string      `json:",omitempty"`
+}
+
+// An auditVuln is a vulnerability affecting a module.
+type auditVuln struct {
+	ID      string
+	Aliases []string `json:",omitempty"`
+	Summary string   `json:",omitempty"`
+	Fixed   string   `json:",omitempty"`
+}
+
+func runAudit(ctx context.Context, cmd *base.Command, args []string) {
+	modload.InitWorkfile()
+
+	if len(args) != 0 {
+		base.Fatalf("go: audit takes no arguments")
+	}
+	modload.ForceUseModules = true
+	modload.RootMode = modload.NeedRoot
+
+	allow, deny := splitLicenses(*auditAllow), splitLicenses(*auditDeny)
+	db, err := openVulnDB(*auditDB)
+	if err != nil {
+		base.Fatal(err)
+	}
+	index, err := db.index()
+	if err != nil {
+		base.Fatal(err)
+	}
+
+	mg, err := modload.LoadModGraph(ctx, "")
+	if err != nil {
+		base.Fatal(err)
+	}
+	var mods []module.Version
+	if v := "v" + gover.FromToolchain(runtime.Version()); semver.IsValid(v) {
+		// A released toolchain: check its standard library,
+		// which the vulnerability database calls "stdlib".
+		mods = append(mods, module.Version{Path: "stdlib", Version: v})
+	}
+	for _, m := range mg.BuildList() {
+		if gover.IsToolchain(m.Path) || modload.MainModules.Contains(m.Path) {
+			continue
+		}
+		mods = append(mods, m)
+	}
+
+	// Detect licenses concurrently, up to GOMAXPROCS zips at once,
+	// but report them in build list order.
+	type token struct{}
+	sem := make(chan token, runtime.GOMAXPROCS(0))
+	reports := make([]*auditModule, len(mods))
+	done := make([]chan struct{}, len(mods))
+	for i, m := range mods {
+		reports[i] = &auditModule{Path: m.Path, Version: m.Version}
+		done[i] = make(chan struct{})
+		sem <- token{}
+		go func() {
+			defer func() { <-sem; close(done[i]) }()
+			r := reports[i]
+			if m.Path == "stdlib" {
+				r.License = "BSD-3-Clause"
+				return
+			}
+			if err := auditLicense(ctx, m, r); err != nil {
+				r.Error = err.Error()
+			}
+		}()
+	}
+
+	vulns, violations := 0, 0
+	for i, m := range mods {
+		<-done[i]
+		r := reports[i]
+		if r.Error == "" {
+			var err error
+			r.Vulns, err = db.vulns(index, m)
+			if err != nil {
+				r.Error = err.Error()
+			}
+		}
+		if r.Error == "" {
+			r.Violations = checkLicensePolicy(r.License, allow, deny)
+		}
+		vulns += len(r.Vulns)
+		violations += len(r.Violations)
+		if r.Error != "" {
+			base.SetExitStatus(1)
+		}
+		printAuditModule(r)
+	}
+
+	if vulns > 0 || violations > 0 {
+		if !*auditJSON {
+			fmt.Fprintf(os.Stderr, "go: found %s and %s\n",
+				plural(vulns, "vulnerability", "vulnerabilities"),
+				plural(violations, "license policy violation", "license policy violations"))
+		}
+		base.SetExitStatus(1)
+	}
+}
+
+// auditLicense detects the license of module m from its zip file,
+// or from the module that replaces it, and records it in r.
+func auditLicense(ctx context.Context, m module.Version, r *auditModule) error {
+	var err error
+	if rm := modload.Replacement(m); rm.Path != "" && rm.Version == "" {
+		r.License, r.LicenseFiles, err = detectDirLicense(modload.ReplacementDir(rm))
+		return err
+	} else if rm.Path != "" {
+		m = rm
+	}
+	zipfile, err := modfetch.DownloadZip(ctx, m)
+	if err != nil {
+		return err
+	}
+	r.License, r.LicenseFiles, err = detectZipLicense(zipfile, m)
+	return err
+}
+
+// checkLicensePolicy returns the violations of the license policy defined
+// by allow and deny by a module with the given license.
+func checkLicensePolicy(license string, allow, deny []string) []string {
+	var violations []string
+	for _, id := range strings.Split(license, " AND ") {
+		if len(allow) > 0 && !slices.Contains(allow, id) {
+			violations = append(violations, fmt.Sprintf("license %s not allowed by -allow", id))
+		}
+		if slices.Contains(deny, id) {
+			violations = append(violations, fmt.Sprintf("license %s denied by -deny", id))
+		}
+	}
+	return violations
+}
+
+// splitLicenses splits a comma-separated list of license identifiers.
+func splitLicenses(list string) []string {
+	var ids []string
+	for _, id := range strings.Split(list, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func printAuditModule(r *auditModule) {
+	if *auditJSON {
+		b, err := json.MarshalIndent(r, "", "\t")
+		if err != nil {
+			base.Fatal(err)
+		}
+		os.Stdout.Write(append(b, '\n'))
+		return
+	}
+
+	if r.Error != "" {
+		base.Errorf("go: %s@%s: %s", r.Path, r.Version, r.Error)
+		return
+	}
+	fmt.Printf("%s %s %s\n", r.Path, r.Version, r.License)
+	for _, v := range r.Vulns {
+		fmt.Printf("\t%s", v.ID)
+		if v.Summary != "" {
+			fmt.Printf(": %s", v.Summary)
+		}
+		if v.Fixed != "" {
+			fmt.Printf(" (fixed in %s)", v.Fixed)
+		} else {
+			fmt.Printf(" (no fixed version)")
+		}
+		fmt.Printf("\n")
+	}
+	for _, v := range r.Violations {
+		fmt.Printf("\t%s\n", v)
+	}
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return "1 " + one
+	}
+	return fmt.Sprintf("%d %s", n, many)
+}
@@ -0,0 +1,252 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// go mod explain
+
+package modcmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"cmd/go/internal/base"
+	"cmd/go/internal/gover"
+	"cmd/go/internal/modload"
+
+	"golang.org/x/mod/module"
+)
+
+var cmdExplain = &base.Command{
+	UsageLine: "go mod explain [-json] modules...",
+	Short:     "explain why modules are selected at their versions",
+	Long: `
+Explain shows, for each of the listed modules, why minimal version
+selection selected the version of the module in the build list: the
+requirement paths through the module graph, from the main module, that
+require the module.
+
+The output is a sequence of stanzas, one for each module on the command
+line, separated by blank lines. Each stanza begins with a comment line
+"# module version" giving the selected version, followed by a comment
+line for the replacement of that version, if any. Each following line
+gives a requirement path, as a sequence of modules separated by " -> ",
+ending with a requirement on the module. Paths requiring the selected
+version come first; those are the paths that raised the module to that
+version. They are followed by paths requiring a lower version, marked
+"(lower than selected)", and by requirements dropped from the module
+graph because the main module's go.mod file excludes the required
+version, marked "(excluded)". A final comment line notes how many
+modules in the graph had their requirements pruned out, because the main
+module and those modules declare go 1.17 or higher: such requirements
+cannot affect the selected version. If the module is not in the module
+graph, the stanza displays a single parenthesized note indicating that
+fact.
+
+For example:
+
+	$ go mod explain example.com/c
+	# example.com/c v1.2.0
+	example.com/m -> example.com/c@v1.2.0
+	example.com/m -> example.com/a@v1.0.0 -> example.com/c@v1.2.0
+	example.com/m -> example.com/b@v1.0.0 -> example.com/c@v1.1.0 (lower than selected)
+	example.com/m -> example.com/d@v1.0.0 -> example.com/c@v1.3.0 (excluded)
+	# requirements of 2 modules pruned out of the module graph
+	$
+
+The -json flag causes explain to print instead a sequence of JSON
+objects, one for each module, corresponding to this Go struct:
+
+    type Explanation struct {
+        Path         string         // module path
+        Version      string         // selected version
+        Replace      *Module        // replacement of the selected version, if any
+        Excluded     []string       // versions excluded by the main module
+        Requirements []Requirement  // requirements on the module
+        Pruned       []string       // modules whose requirements are pruned out
+        Error        string         // error explaining the module
+    }
+
+    type Requirement struct {
+        Version string   // required version
+        Status  string   // "selected", "lower", or "excluded"
+        Path    []string // requirement path, starting at the main module
+    }
+
+    type Module struct {
+        Path    string
+        Version string
+    }
+
+Modules in requirement paths are identified as strings of the form
+path@version, except for the main module, which has no @version suffix.
+
+See also 'go mod graph' and 'go mod why'.
+	`,
+}
+
+var explainJSON = cmdExplain.Flag.Bool("json", false, "")
+
+func init() {
+	cmdExplain.Run = runExplain // break init cycle
+	base.AddChdirFlag(&cmdExplain.Flag)
+	base.AddModCommonFlags(&cmdExplain.Flag)
+}
+
+// An explanation is the output of go mod explain for a module.
+type explanation struct {
+	Path         string
+	Version      string               `json:",omitempty"`
+	Replace      *module.Version      `json:",omitempty"`
+	Excluded     []string             `json:",omitempty"`
+	Requirements []explainRequirement `json:",omitempty"`
+	Pruned       []string             `json:",omitempty"`
+	Error        string               `json:",omitempty"`
+}
+
+// An explainRequirement is a requirement on the explained module.
+type explainRequirement struct {
+	Version string
+	Status  string
+	Path    []string
+}
+
+func runExplain(ctx context.Context, cmd *base.Command, args []string) {
+	modload.InitWorkfile()
+
+	if len(args) == 0 {
+		base.Fatalf("go: 'go mod explain' requires at least one module")
+	}
+	modload.ForceUseModules = true
+	modload.RootMode = modload.NeedRoot
+
+	mg, err := modload.LoadModGraph(ctx, "")
+	if err != nil {
+		base.Fatal(err)
+	}
+
+	sep := ""
+	for _, path := range args {
+		if err := module.CheckImportPath(path); err != nil {
+			base.Fatalf("go: %s: %v", path, err)
+		}
+		e := explainModule(mg, path)
+		if *explainJSON {
+			b, err := json.MarshalIndent(e, "", "\t")
+			if err != nil {
+				base.Fatal(err)
+			}
+			os.Stdout.Write(append(b, '\n'))
+			continue
+		}
+		fmt.Print(sep)
+		sep = "\n"
+		printExplanation(e)
+	}
+}
+
+// explainModule explains the version of the module with the given path
+// selected in the module graph mg.
+func explainModule(mg *modload.ModuleGraph, path string) *explanation {
+	e := &explanation{Path: path}
+	selected := mg.Selected(path)
+	if selected == "none" {
+		e.Error = "module is not in the module graph"
+		return e
+	}
+	if modload.MainModules.Contains(path) {
+		e.Error = "module is a main module"
+		return e
+	}
+	e.Version = selected
+	if r := modload.Replacement(module.Version{Path: path, Version: selected}); r.Path != "" {
+		e.Replace = &r
+	}
+	e.Excluded = modload.ExcludedVersions(path)
+
+	mg.WalkBreadthFirst(func(m module.Version) {
+		reqs, ok := mg.RequiredBy(m)
+		if !ok {
+			if !gover.IsToolchain(m.Path) {
+				e.Pruned = append(e.Pruned, m.Path+"@"+m.Version)
+			}
+			return
+		}
+		var found []explainRequirement
+		for _, r := range reqs {
+			if r.Path == path {
+				status := "selected"
+				if r.Version != selected {
+					status = "lower"
+				}
+				found = append(found, explainRequirement{Version: r.Version, Status: status})
+			}
+		}
+		for _, r := range modload.ExcludedRequirements(m) {
+			if r.Path == path {
+				found = append(found, explainRequirement{Version: r.Version, Status: "excluded"})
+			}
+		}
+		if len(found) == 0 {
+			return
+		}
+		chain := mg.FindPath(func(v module.Version) bool { return v == m })
+		for _, r := range found {
+			for _, v := range chain {
+				r.Path = append(r.Path, formatModule(v))
+			}
+			r.Path = append(r.Path, path+"@"+r.Version)
+			e.Requirements = append(e.Requirements, r)
+		}
+	})
+
+	// Report the requirements that raised the module to the selected version
+	// first, then the others from the highest version down, each group
+	// by increasing length of the requirement path.
+	rank := map[string]int{"selected": 0, "lower": 1, "excluded": 2}
+	slices.SortStableFunc(e.Requirements, func(a, b explainRequirement) int {
+		if rank[a.Status] != rank[b.Status] {
+			return rank[a.Status] - rank[b.Status]
+		}
+		if c := gover.ModCompare(path, a.Version, b.Version); c != 0 {
+			return -c
+		}
+		return len(a.Path) - len(b.Path)
+	})
+	return e
+}
+
+func formatModule(m module.Version) string {
+	if m.Version == "" {
+		return m.Path
+	}
+	return m.Path + "@" + m.Version
+}
+
+func printExplanation(e *explanation) {
+	if e.Error != "" {
+		fmt.Printf("# %s\n(%s)\n", e.Path, e.Error)
+		return
+	}
+	fmt.Printf("# %s %s\n", e.Path, e.Version)
+	if e.Replace != nil {
+		fmt.Printf("# replaced by %s\n", strings.TrimSpace(e.Replace.Path+" "+e.Replace.Version))
+	}
+	for _, r := range e.Requirements {
+		fmt.Print(strings.Join(r.Path, " -> "))
+		switch r.Status {
+		case "lower":
+			fmt.Print(" (lower than selected)")
+		case "excluded":
+			fmt.Print(" (excluded)")
+		}
+		fmt.Print("\n")
+	}
+	if len(e.Pruned) > 0 {
+		fmt.Printf("# requirements of %s pruned out of the module graph\n", plural(len(e.Pruned), "module", "modules"))
+	}
+}
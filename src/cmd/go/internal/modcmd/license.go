@@ -0,0 +1,159 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package modcmd
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/module"
+)
+
+// maxLicenseSize is the number of bytes of a license file
+// read to detect its license.
+const maxLicenseSize = 64 << 10
+
+// isLicenseFile reports whether name, the name of a file at the root
+// of a module, is a license file, such as LICENSE, LICENSE.md or COPYING.
+func isLicenseFile(name string) bool {
+	name = strings.ToUpper(name)
+	for _, prefix := range []string{"LICENSE", "LICENCE", "COPYING", "UNLICENSE"} {
+		if rest, ok := strings.CutPrefix(name, prefix); ok && (rest == "" || strings.ContainsAny(rest[:1], ".-_")) {
+			return true
+		}
+	}
+	return false
+}
+
+// detectZipLicense returns the license of module m, read from its zip file,
+// and the names of the license files that determine it.
+func detectZipLicense(zipfile string, m module.Version) (license string, files []string, err error) {
+	z, err := zip.OpenReader(zipfile)
+	if err != nil {
+		return "", nil, err
+	}
+	defer z.Close()
+
+	prefix := m.Path + "@" + m.Version + "/"
+	var texts []string
+	for _, f := range z.File {
+		name, ok := strings.CutPrefix(f.Name, prefix)
+		if !ok || strings.Contains(name, "/") || !isLicenseFile(name) {
+			continue
+		}
+		r, err := f.Open()
+		if err != nil {
+			return "", nil, err
+		}
+		text, err := io.ReadAll(io.LimitReader(r, maxLicenseSize))
+		r.Close()
+		if err != nil {
+			return "", nil, err
+		}
+		files = append(files, name)
+		texts = append(texts, string(text))
+	}
+	return licenseOf(texts), files, nil
+}
+
+// detectDirLicense returns the license of the module in dir
+// and the names of the license files that determine it.
+func detectDirLicense(dir string) (license string, files []string, err error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", nil, err
+	}
+	var texts []string
+	for _, e := range entries {
+		if !e.Type().IsRegular() || !isLicenseFile(e.Name()) {
+			continue
+		}
+		f, err := os.Open(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return "", nil, err
+		}
+		text, err := io.ReadAll(io.LimitReader(f, maxLicenseSize))
+		f.Close()
+		if err != nil {
+			return "", nil, err
+		}
+		files = append(files, e.Name())
+		texts = append(texts, string(text))
+	}
+	return licenseOf(texts), files, nil
+}
+
+// licenseOf returns the SPDX identifiers of the licenses with the given
+// texts, sorted and joined by " AND ", or "unknown".
+func licenseOf(texts []string) string {
+	var ids []string
+	for _, text := range texts {
+		if id := classifyLicense(text); id != "" && !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return "unknown"
+	}
+	slices.Sort(ids)
+	return strings.Join(ids, " AND ")
+}
+
+// licensePhrases lists, for each license, phrases that all appear in its
+// text, in lower case. More specific licenses come first.
+var licensePhrases = []struct {
+	id      string
+	phrases []string
+}{
+	{"AGPL-3.0", []string{"gnu affero general public license", "version 3"}},
+	{"LGPL-2.1", []string{"gnu lesser general public license", "version 2.1"}},
+	{"LGPL-3.0", []string{"gnu lesser general public license", "version 3"}},
+	{"GPL-3.0", []string{"gnu general public license", "version 3"}},
+	{"GPL-2.0", []string{"gnu general public license", "version 2"}},
+	{"MPL-2.0", []string{"mozilla public license", "2.0"}},
+	{"Apache-2.0", []string{"apache license", "version 2.0"}},
+	{"BSD-3-Clause", []string{"redistribution and use in source and binary forms", "neither the name"}},
+	{"BSD-2-Clause", []string{"redistribution and use in source and binary forms"}},
+	{"MIT", []string{"permission is hereby granted, free of charge"}},
+	{"ISC", []string{"permission to use, copy, modify, and/or distribute this software for any purpose"}},
+	{"BSL-1.0", []string{"boost software license"}},
+	{"Unlicense", []string{"this is free and unencumbered software released into the public domain"}},
+	{"CC0-1.0", []string{"cc0 1.0 universal"}},
+}
+
+// classifyLicense returns the SPDX identifier of the license with the
+// given text, or "" if it is not recognized. An SPDX-License-Identifier
+// line takes precedence over the text itself.
+func classifyLicense(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if _, id, ok := strings.Cut(line, "SPDX-License-Identifier:"); ok {
+			if id = strings.TrimSpace(id); id != "" {
+				return id
+			}
+		}
+	}
+
+	// Compare lower-case words, ignoring line breaks and comment markers.
+	text = strings.ToLower(strings.Join(strings.FieldsFunc(text, func(r rune) bool {
+		return r == ' ' || r == '\t' || r == '\r' || r == '\n' || r == '#' || r == '*'
+	}), " "))
+	for _, l := range licensePhrases {
+		matched := true
+		for _, p := range l.phrases {
+			if !strings.Contains(text, p) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return l.id
+		}
+	}
+	return ""
+}
@@ -21,8 +21,10 @@ See 'go help modules' for an overview of module functionality.
 	`,
 
 	Commands: []*base.Command{
+		cmdAudit,
 		cmdDownload,
 		cmdEdit,
+		cmdExplain,
 		cmdGraph,
 		cmdInit,
 		cmdTidy,
@@ -0,0 +1,233 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package modcmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"cmd/go/internal/web"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// defaultVulnDB is the vulnerability database used when neither
+// the -db flag nor GOVULNDB is set.
+const defaultVulnDB = "https://vuln.go.dev"
+
+// A vulnDB is a vulnerability database in the layout served by
+// vuln.go.dev, read from a local directory or a URL.
+type vulnDB struct {
+	dir string   // local directory, or ""
+	url *url.URL // URL, if dir is ""
+}
+
+// openVulnDB returns the vulnerability database at location,
+// which defaults to $GOVULNDB or defaultVulnDB.
+func openVulnDB(location string) (*vulnDB, error) {
+	if location == "" {
+		location = os.Getenv("GOVULNDB")
+	}
+	if location == "" {
+		location = defaultVulnDB
+	}
+	if !strings.Contains(location, "://") {
+		dir, err := filepath.Abs(location)
+		if err != nil {
+			return nil, err
+		}
+		return &vulnDB{dir: dir}, nil
+	}
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vulnerability database URL: %v", err)
+	}
+	return &vulnDB{url: u}, nil
+}
+
+// get returns the contents of the file with the given slash-separated
+// path in the database.
+func (db *vulnDB) get(path string) ([]byte, error) {
+	if db.dir != "" {
+		return os.ReadFile(filepath.Join(db.dir, filepath.FromSlash(path)))
+	}
+	return web.GetBytes(web.Join(db.url, path))
+}
+
+// An osvIndexModule is an entry of index/modules.json:
+// a module and the vulnerabilities that affect some of its versions.
+type osvIndexModule struct {
+	Path  string `json:"path"`
+	Vulns []struct {
+		ID    string `json:"id"`
+		Fixed string `json:"fixed,omitempty"`
+	} `json:"vulns"`
+}
+
+// An osvEntry is the subset of an OSV entry that go mod audit uses.
+// See https://ossf.github.io/osv-schema/.
+type osvEntry struct {
+	ID        string        `json:"id"`
+	Aliases   []string      `json:"aliases,omitempty"`
+	Summary   string        `json:"summary,omitempty"`
+	Details   string        `json:"details,omitempty"`
+	Withdrawn *time.Time    `json:"withdrawn,omitempty"`
+	Affected  []osvAffected `json:"affected"`
+}
+
+type osvAffected struct {
+	Package struct {
+		Name      string `json:"name"`
+		Ecosystem string `json:"ecosystem"`
+	} `json:"package"`
+	Ranges []struct {
+		Type   string `json:"type"`
+		Events []struct {
+			Introduced string `json:"introduced,omitempty"`
+			Fixed      string `json:"fixed,omitempty"`
+		} `json:"events"`
+	} `json:"ranges,omitempty"`
+}
+
+// index returns the index of the database, mapping each module path
+// to the IDs of the entries that affect it.
+func (db *vulnDB) index() (map[string][]string, error) {
+	data, err := db.get("index/modules.json")
+	if err != nil {
+		return nil, fmt.Errorf("reading vulnerability database: %v", err)
+	}
+	var mods []osvIndexModule
+	if err := json.Unmarshal(data, &mods); err != nil {
+		return nil, fmt.Errorf("reading vulnerability database: index/modules.json: %v", err)
+	}
+	index := make(map[string][]string)
+	for _, m := range mods {
+		for _, v := range m.Vulns {
+			if !validOSVID(v.ID) {
+				return nil, fmt.Errorf("reading vulnerability database: index/modules.json: invalid vulnerability ID %q", v.ID)
+			}
+			index[m.Path] = append(index[m.Path], v.ID)
+		}
+	}
+	return index, nil
+}
+
+// validOSVID reports whether id is a valid OSV entry ID, such as
+// GO-2024-0001, and so can be used as a file name in the database.
+func validOSVID(id string) bool {
+	if id == "" || id[0] == '.' || strings.Contains(id, "..") {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case 'A' <= r && r <= 'Z', 'a' <= r && r <= 'z', '0' <= r && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// vulns returns the vulnerabilities in the database that affect m.
+func (db *vulnDB) vulns(index map[string][]string, m module.Version) ([]auditVuln, error) {
+	var vulns []auditVuln
+	for _, id := range index[m.Path] {
+		data, err := db.get("ID/" + id + ".json")
+		if err != nil {
+			return nil, fmt.Errorf("reading vulnerability database: %v", err)
+		}
+		var e osvEntry
+		if err := json.Unmarshal(data, &e); err != nil {
+			return nil, fmt.Errorf("reading vulnerability database: ID/%s.json: %v", id, err)
+		}
+		if e.Withdrawn != nil {
+			continue
+		}
+		for _, a := range e.Affected {
+			if a.Package.Name != m.Path {
+				continue
+			}
+			if affected, fixed := a.affects(m.Version); affected {
+				summary := e.Summary
+				if summary == "" {
+					summary, _, _ = strings.Cut(e.Details, "\n")
+				}
+				vulns = append(vulns, auditVuln{ID: e.ID, Aliases: e.Aliases, Summary: summary, Fixed: fixed})
+				break
+			}
+		}
+	}
+	return vulns, nil
+}
+
+// affects reports whether the module version v is in one of the affected
+// ranges of a, and returns the earliest version after v that fixes it, if any.
+// OSV versions are semantic versions without the leading "v".
+func (a *osvAffected) affects(v string) (affected bool, fixed string) {
+	if len(a.Ranges) == 0 {
+		// No ranges: all versions are affected.
+		return true, ""
+	}
+	for _, r := range a.Ranges {
+		if r.Type != "SEMVER" {
+			continue
+		}
+		type event struct {
+			v          string
+			introduced bool
+		}
+		var events []event
+		for _, e := range r.Events {
+			switch {
+			case e.Introduced == "0":
+				events = append(events, event{"", true})
+			case e.Introduced != "":
+				events = append(events, event{"v" + e.Introduced, true})
+			case e.Fixed != "":
+				events = append(events, event{"v" + e.Fixed, false})
+			}
+		}
+		sort.SliceStable(events, func(i, j int) bool {
+			return compareOSVVersion(events[i].v, events[j].v) < 0
+		})
+
+		// Replay the events up to v: the last one decides whether v is
+		// affected, and the first fix after v fixes it.
+		in := false
+		for _, e := range events {
+			if compareOSVVersion(e.v, v) <= 0 {
+				in = e.introduced
+			} else if in && !e.introduced {
+				return true, e.v
+			}
+		}
+		if in {
+			return true, ""
+		}
+	}
+	return false, ""
+}
+
+// compareOSVVersion compares the semantic versions v and w,
+// where "" is lower than any version.
+func compareOSVVersion(v, w string) int {
+	switch {
+	case v == w:
+		return 0
+	case v == "":
+		return -1
+	case w == "":
+		return +1
+	}
+	return semver.Compare(v, w)
+}
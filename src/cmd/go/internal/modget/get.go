@@ -28,9 +28,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -54,7 +56,7 @@ import (
 var CmdGet = &base.Command{
 	// Note: flags below are listed explicitly because they're the most common.
 	// Do not send CLs removing them because they're covered by [get flags].
-	UsageLine: "go get [-t] [-u] [-v] [build flags] [packages]",
+	UsageLine: "go get [-t] [-u] [-tool] [-v] [build flags] [packages]",
 	Short:     "add dependencies to current module and install them",
 	Long: `
 Get resolves its command-line arguments to packages at specific module versions,
@@ -81,6 +83,18 @@ To upgrade the Go toolchain to the latest patch release of the current Go toolch
 
 	go get toolchain@patch
 
+To add a tool to the main module, so that it can be run with 'go tool':
+
+	go get -tool example.com/cmd/tool
+
+To upgrade the tools of the main module to their latest versions:
+
+	go get tool
+
+To remove a tool from the main module:
+
+	go get -tool example.com/cmd/tool@none
+
 See https://golang.org/ref/mod#go-get for details.
 
 In earlier versions of Go, 'go get' was used to build and install packages.
@@ -109,6 +123,13 @@ but changes the default to select patch releases.
 When the -t and -u flags are used together, get will update
 test dependencies as well.
 
+The -tool flag instructs get to add a tool directive to go.mod for each
+main package named on the command line, in addition to requiring the
+module that provides it. With the version suffix @none, it removes the
+matching tool directives instead, leaving the module requirements in
+place. The 'tool' pattern matches the tools declared in go.mod; see
+'go help packages'.
+
 The -x flag prints commands as they are executed. This is useful for
 debugging version control commands when a module is downloaded directly
 from a repository.
@@ -216,6 +237,7 @@ var (
 	getFix      = CmdGet.Flag.Bool("fix", false, "")
 	getM        = CmdGet.Flag.Bool("m", false, "")
 	getT        = CmdGet.Flag.Bool("t", false, "")
+	getTool     = CmdGet.Flag.Bool("tool", false, "")
 	getU        upgradeFlag
 	getInsecure = CmdGet.Flag.Bool("insecure", false, "")
 	// -v is cfg.BuildV
@@ -325,9 +347,10 @@ func runGet(ctx context.Context, cmd *base.Command, args []string) {
 			"\tor run 'go help get' or 'go help install'.")
 	}
 
-	dropToolchain, queries := parseArgs(ctx, args)
+	dropToolchain, dropTools, queries := parseArgs(ctx, args)
 	opts := modload.WriteOpts{
 		DropToolchain: dropToolchain,
+		DropTools:     dropTools,
 	}
 	for _, q := range queries {
 		if q.pattern == "toolchain" {
@@ -402,6 +425,10 @@ func runGet(ctx context.Context, cmd *base.Command, args []string) {
 	}
 	r.checkPackageProblems(ctx, pkgPatterns)
 
+	if *getTool {
+		updateTools(ctx, queries, &opts)
+	}
+
 	// Everything succeeded. Update go.mod.
 	oldReqs := reqsFromGoMod(modload.ModFile())
 
@@ -428,17 +455,63 @@ func runGet(ctx context.Context, cmd *base.Command, args []string) {
 // parseArgs parses command-line arguments and reports errors.
 //
 // The command-line arguments are of the form path@version or simply path, with
-// implicit @upgrade. path@none is "downgrade away".
-func parseArgs(ctx context.Context, rawArgs []string) (dropToolchain bool, queries []*query) {
+// implicit @upgrade. path@none is "downgrade away", or with the -tool flag,
+// "remove the matching tool directives", which are returned in dropTools.
+func parseArgs(ctx context.Context, rawArgs []string) (dropToolchain bool, dropTools []string, queries []*query) {
 	defer base.ExitIfErrors()
 
+	var args []string
 	for _, arg := range search.CleanPatterns(rawArgs) {
+		pattern, vers, found := strings.Cut(arg, "@")
+		if pattern != "tool" {
+			args = append(args, arg)
+			continue
+		}
+		// The "tool" pattern stands for each tool declared by the main module.
+		if *getTool {
+			base.Errorf("go: -tool cannot be used with the 'tool' pattern")
+			continue
+		}
+		modload.LoadModFile(ctx)
+		tools := slices.Sorted(maps.Keys(modload.MainModules.Tools()))
+		if len(tools) == 0 {
+			fmt.Fprintf(os.Stderr, "go: warning: %q matched no packages\n", arg)
+		}
+		for _, t := range tools {
+			if found {
+				t += "@" + vers
+			}
+			args = append(args, t)
+		}
+	}
+
+	for _, arg := range args {
 		q, err := newQuery(arg)
 		if err != nil {
 			base.Error(err)
 			continue
 		}
 
+		if *getTool {
+			if search.IsMetaPackage(q.pattern) || q.pattern == "go" || q.pattern == "toolchain" || q.patternIsLocal && q.isWildcard() {
+				base.Errorf("go: -tool cannot be used with %q", q.raw)
+				continue
+			}
+			if q.version == "none" {
+				modload.LoadModFile(ctx)
+				n := len(dropTools)
+				for t := range modload.MainModules.Tools() {
+					if q.matchesPath(t) {
+						dropTools = append(dropTools, t)
+					}
+				}
+				if len(dropTools) == n {
+					base.Errorf("go: %s is not a tool of the main module", q.pattern)
+				}
+				continue
+			}
+		}
+
 		if q.version == "none" {
 			switch q.pattern {
 			case "go":
@@ -473,7 +546,46 @@ func parseArgs(ctx context.Context, rawArgs []string) (dropToolchain bool, queri
 		queries = append(queries, q)
 	}
 
-	return dropToolchain, queries
+	return dropToolchain, dropTools, queries
+}
+
+// updateTools adds to opts.AddTools the main packages matched by queries,
+// for 'go get -tool'. It reports an error for a package path that names a
+// package other than a main package.
+func updateTools(ctx context.Context, queries []*query, opts *modload.WriteOpts) {
+	defer base.ExitIfErrors()
+
+	var patterns []string
+	for _, q := range queries {
+		patterns = append(patterns, q.pattern)
+	}
+	if len(patterns) == 0 {
+		return
+	}
+
+	pkgOpts := modload.PackageOpts{
+		VendorModulesInGOROOTSrc: true,
+		ResolveMissingImports:    false,
+		AllowErrors:              true,
+		SilenceNoGoErrors:        true,
+	}
+	matches, _ := modload.LoadPackages(ctx, pkgOpts, patterns...)
+	for i, m := range matches {
+		for _, pkg := range m.Pkgs {
+			dir, _, err := modload.Lookup("", false, pkg)
+			if err != nil {
+				// Already reported by checkPackageProblems.
+				continue
+			}
+			if bp, err := cfg.BuildContext.ImportDir(dir, 0); err != nil || bp.Name != "main" {
+				if !queries[i].isWildcard() {
+					base.Errorf("go: %s is not a main package", pkg)
+				}
+				continue
+			}
+			opts.AddTools = append(opts.AddTools, pkg)
+		}
+	}
 }
 
 type resolver struct {
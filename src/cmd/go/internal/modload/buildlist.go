@@ -508,6 +508,13 @@ func (mg *ModuleGraph) WalkBreadthFirst(f func(m module.Version)) {
 	mg.g.WalkBreadthFirst(f)
 }
 
+// FindPath reports a shortest requirement path starting at one of the roots of
+// the graph and ending at a module version m for which f(m) returns true, or
+// nil if no such path exists.
+func (mg *ModuleGraph) FindPath(f func(module.Version) bool) []module.Version {
+	return mg.g.FindPath(f)
+}
+
 // BuildList returns the selected versions of all modules present in the graph,
 // beginning with the main modules.
 //
@@ -28,6 +28,7 @@ import (
 	"cmd/go/internal/lockedfile"
 	"cmd/go/internal/modfetch"
 	"cmd/go/internal/search"
+	"cmd/go/internal/str"
 
 	"golang.org/x/mod/modfile"
 	"golang.org/x/mod/module"
@@ -108,6 +109,10 @@ type MainModuleSet struct {
 
 	modFiles map[module.Version]*modfile.File
 
+	// tools is the set of package paths declared by the tool directives
+	// of the main modules.
+	tools map[string]bool
+
 	modContainingCWD module.Version
 
 	workFile *modfile.WorkFile
@@ -287,6 +292,15 @@ func (mms *MainModuleSet) Toolchain() string {
 	return "go" + mms.GoVersion()
 }
 
+// Tools returns the set of package paths declared by the tool directives
+// of the main modules. The caller must not modify the result.
+func (mms *MainModuleSet) Tools() map[string]bool {
+	if mms == nil {
+		return nil
+	}
+	return mms.tools
+}
+
 func (mms *MainModuleSet) WorkFileReplaceMap() map[module.Version]module.Version {
 	return mms.workFileReplaceMap
 }
@@ -830,6 +844,12 @@ func LoadModFile(ctx context.Context) *Requirements {
 	return rs
 }
 
+// TryLoadModFile is like LoadModFile, but returns an error instead of
+// exiting if the module requirements cannot be loaded.
+func TryLoadModFile(ctx context.Context) (*Requirements, error) {
+	return loadModFile(ctx, nil)
+}
+
 func loadModFile(ctx context.Context, opts *PackageOpts) (*Requirements, error) {
 	if requirements != nil {
 		return requirements, nil
@@ -1217,6 +1237,7 @@ func makeMainModules(ms []module.Version, rootDirs []string, modFiles []*modfile
 		pathPrefix:      map[module.Version]string{},
 		modRoot:         map[module.Version]string{},
 		modFiles:        map[module.Version]*modfile.File{},
+		tools:           map[string]bool{},
 		indices:         map[module.Version]*modFileIndex{},
 		highestReplaced: map[string]string{},
 		workFile:        workFile,
@@ -1272,6 +1293,10 @@ func makeMainModules(ms []module.Version, rootDirs []string, modFiles []*modfile
 		}
 
 		if modFiles[i] != nil {
+			for _, t := range modFiles[i].Tool {
+				mainModules.tools[t.Path] = true
+			}
+
 			curModuleReplaces := make(map[module.Version]bool)
 			for _, r := range modFiles[i].Replace {
 				if replacedByWorkFile[r.Old.Path] {
@@ -1744,6 +1769,9 @@ type WriteOpts struct {
 	DropToolchain     bool // go get toolchain@none
 	ExplicitToolchain bool // go get has set explicit toolchain version
 
+	AddTools  []string // go get -tool example.com/m1
+	DropTools []string // go get -tool example.com/m1@none
+
 	// TODO(bcmills): Make 'go mod tidy' update the go version in the Requirements
 	// instead of writing directly to the modfile.File
 	TidyWroteGo bool // Go.Version field already updated by 'go mod tidy'
@@ -1775,6 +1803,37 @@ func UpdateGoModFromReqs(ctx context.Context, opts WriteOpts) (before, after []b
 		return nil, nil, nil, err
 	}
 
+	// Update tool directives, and treat the modules providing tools
+	// as direct dependencies.
+	for _, t := range opts.DropTools {
+		if err := modFile.DropTool(t); err != nil {
+			return nil, nil, nil, err
+		}
+		delete(MainModules.tools, t)
+	}
+	if len(opts.DropTools) > 0 {
+		// DropTool clears the dropped entries but, unlike the other
+		// Drop methods, Cleanup does not remove them.
+		modFile.Tool = slices.DeleteFunc(modFile.Tool, func(t *modfile.Tool) bool { return t.Path == "" })
+	}
+	for _, t := range opts.AddTools {
+		if err := modFile.AddTool(t); err != nil {
+			return nil, nil, nil, err
+		}
+		MainModules.tools[t] = true
+	}
+
+	toolMods := make(map[string]bool)
+	for t := range MainModules.tools {
+		best := ""
+		for _, m := range requirements.rootModules {
+			if str.HasPathPrefix(t, m.Path) && len(m.Path) > len(best) {
+				best = m.Path
+			}
+		}
+		toolMods[best] = true
+	}
+
 	var list []*modfile.Require
 	toolchain := ""
 	goVersion := ""
@@ -1789,7 +1848,7 @@ func UpdateGoModFromReqs(ctx context.Context, opts WriteOpts) (before, after []b
 		}
 		list = append(list, &modfile.Require{
 			Mod:      m,
-			Indirect: !requirements.direct[m.Path],
+			Indirect: !requirements.direct[m.Path] && !toolMods[m.Path],
 		})
 	}
 
@@ -324,14 +324,17 @@ func LoadPackages(ctx context.Context, opts PackageOpts, patterns ...string) (ma
 
 			case m.Pattern() == "all":
 				if ld == nil {
-					// The initial roots are the packages in the main module.
-					// loadFromRoots will expand that to "all".
+					// The initial roots are the packages in the main module
+					// and its tools. loadFromRoots will expand that to "all".
 					m.Errs = m.Errs[:0]
 					matchModules := MainModules.Versions()
 					if opts.MainModule != (module.Version{}) {
 						matchModules = []module.Version{opts.MainModule}
 					}
 					matchPackages(ctx, m, opts.Tags, omitStd, matchModules)
+					if opts.MainModule == (module.Version{}) {
+						m.Pkgs = append(m.Pkgs, sortedTools()...)
+					}
 				} else {
 					// Starting with the packages in the main module,
 					// enumerate the full list of "all".
@@ -343,6 +346,9 @@ func LoadPackages(ctx context.Context, opts PackageOpts, patterns ...string) (ma
 					m.MatchPackages() // Locate the packages within GOROOT/src.
 				}
 
+			case m.Pattern() == "tool":
+				m.Pkgs = sortedTools()
+
 			default:
 				panic(fmt.Sprintf("internal error: modload missing case for pattern %s", m.Pattern()))
 			}
@@ -1382,6 +1388,11 @@ func (ld *loader) updateRequirements(ctx context.Context) (changed bool, err err
 				}
 			}
 		}
+		if pkg.err == nil && pkg.fromExternalModule() && MainModules.Tools()[pkg.path] {
+			// The module providing a tool declared by a main module is a
+			// direct dependency, even though nothing imports the tool.
+			direct[pkg.mod.Path] = true
+		}
 		if pkg.mod.Version != "" || !MainModules.Contains(pkg.mod.Path) {
 			continue
 		}
@@ -1853,7 +1864,7 @@ func (ld *loader) load(ctx context.Context, pkg *loadPkg) {
 	if pkg.dir == "" {
 		return
 	}
-	if MainModules.Contains(pkg.mod.Path) {
+	if MainModules.Contains(pkg.mod.Path) || MainModules.Tools()[pkg.path] {
 		// Go ahead and mark pkg as in "all". This provides the invariant that a
 		// package that is *only* imported by other packages in "all" is always
 		// marked as such before loading its imports.
@@ -1988,6 +1999,11 @@ func (ld *loader) stdVendor(parentPath, path string) string {
 	return path
 }
 
+// sortedTools returns the tools declared by the main modules, in order.
+func sortedTools() []string {
+	return slices.Sorted(maps.Keys(MainModules.Tools()))
+}
+
 // computePatternAll returns the list of packages matching pattern "all",
 // starting with a list of the import paths for the packages in the main module.
 func (ld *loader) computePatternAll() (all []string) {
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"unicode"
@@ -25,6 +26,7 @@ import (
 
 	"golang.org/x/mod/modfile"
 	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
 )
 
 // ReadModFile reads and parses the mod file at gomod. ReadModFile properly applies the
@@ -74,6 +76,7 @@ type modFileIndex struct {
 	require      map[module.Version]requireMeta
 	replace      map[module.Version]module.Version
 	exclude      map[module.Version]bool
+	tool         map[string]bool
 }
 
 type requireMeta struct {
@@ -141,6 +144,46 @@ func CheckExclusions(ctx context.Context, m module.Version) error {
 	return nil
 }
 
+// ExcludedRequirements returns the requirements of module m, other than a
+// main module, on versions excluded by the main modules' go.mod files.
+// Those requirements are dropped from the module graph.
+func ExcludedRequirements(m module.Version) []module.Version {
+	if cfg.BuildMod == "vendor" || gover.IsToolchain(m.Path) || MainModules.Contains(m.Path) {
+		return nil
+	}
+	summary, err := rawGoModSummary(resolveReplacement(m))
+	if err != nil {
+		return nil
+	}
+	var excluded []module.Version
+	for _, r := range summary.require {
+		for _, mainModule := range MainModules.Versions() {
+			if index := MainModules.Index(mainModule); index != nil && index.exclude[r] {
+				excluded = append(excluded, r)
+				break
+			}
+		}
+	}
+	return excluded
+}
+
+// ExcludedVersions returns the versions of the module with the given path
+// excluded by the main modules' go.mod files, in semver order.
+func ExcludedVersions(path string) []string {
+	var versions []string
+	for _, mainModule := range MainModules.Versions() {
+		if index := MainModules.Index(mainModule); index != nil {
+			for m := range index.exclude {
+				if m.Path == path && !slices.Contains(versions, m.Version) {
+					versions = append(versions, m.Version)
+				}
+			}
+		}
+	}
+	semver.Sort(versions)
+	return versions
+}
+
 var errExcluded = &excludedError{}
 
 type excludedError struct{}
@@ -351,6 +394,15 @@ func replacementFrom(mod module.Version) (r module.Version, modroot string, from
 	return found, foundModRoot, modFilePath(foundModRoot)
 }
 
+// ReplacementDir returns the directory holding the source of r,
+// a replacement by a directory as returned by Replacement.
+func ReplacementDir(r module.Version) string {
+	if filepath.IsAbs(r.Path) {
+		return r.Path
+	}
+	return filepath.Join(replaceRelativeTo(), r.Path)
+}
+
 func replaceRelativeTo() string {
 	if workFilePath := WorkFilePath(); workFilePath != "" {
 		return filepath.Dir(workFilePath)
@@ -436,6 +488,11 @@ func indexModFile(data []byte, modFile *modfile.File, mod module.Version, needsF
 		i.exclude[x.Mod] = true
 	}
 
+	i.tool = make(map[string]bool, len(modFile.Tool))
+	for _, t := range modFile.Tool {
+		i.tool[t.Path] = true
+	}
+
 	return i
 }
 
@@ -472,7 +529,8 @@ func (i *modFileIndex) modFileIsDirty(modFile *modfile.File) bool {
 		toolchain != i.toolchain ||
 		len(modFile.Require) != len(i.require) ||
 		len(modFile.Replace) != len(i.replace) ||
-		len(modFile.Exclude) != len(i.exclude) {
+		len(modFile.Exclude) != len(i.exclude) ||
+		len(modFile.Tool) != len(i.tool) {
 		return true
 	}
 
@@ -503,6 +561,12 @@ func (i *modFileIndex) modFileIsDirty(modFile *modfile.File) bool {
 		}
 	}
 
+	for _, t := range modFile.Tool {
+		if !i.tool[t.Path] {
+			return true
+		}
+	}
+
 	return false
 }
 
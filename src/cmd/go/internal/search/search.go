@@ -10,6 +10,7 @@ import (
 	"cmd/go/internal/fsys"
 	"cmd/go/internal/str"
 	"cmd/internal/pkgpattern"
+	"errors"
 	"fmt"
 	"go/build"
 	"io/fs"
@@ -60,14 +61,14 @@ func (m *Match) IsLocal() bool {
 }
 
 // IsMeta reports whether the pattern is a “meta-package” keyword that represents
-// multiple packages, such as "std", "cmd", or "all".
+// multiple packages, such as "std", "cmd", "tool", or "all".
 func (m *Match) IsMeta() bool {
 	return IsMetaPackage(m.pattern)
 }
 
 // IsMetaPackage checks if name is a reserved package name that expands to multiple packages.
 func IsMetaPackage(name string) bool {
-	return name == "std" || name == "cmd" || name == "all"
+	return name == "std" || name == "cmd" || name == "tool" || name == "all"
 }
 
 // A MatchError indicates an error that occurred while attempting to match a
@@ -92,6 +93,7 @@ func (e *MatchError) Unwrap() error {
 // can be found under the $GOPATH directories and $GOROOT that match the
 // pattern. The pattern must be either "all" (all packages), "std" (standard
 // packages), "cmd" (standard commands), or a path including "...".
+// The "tool" pattern requires module mode and is reported as an error.
 //
 // If any errors may have caused the set of packages to be incomplete,
 // MatchPackages appends those errors to m.Errs.
@@ -107,6 +109,11 @@ func (m *Match) MatchPackages() {
 		return
 	}
 
+	if m.pattern == "tool" {
+		m.AddError(errors.New("tools are declared in go.mod and require module mode"))
+		return
+	}
+
 	match := func(string) bool { return true }
 	treeCanMatch := func(string) bool { return true }
 	if !m.IsMeta() {
@@ -0,0 +1,277 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+
+	"cmd/go/internal/base"
+	"cmd/go/internal/cache"
+	"cmd/go/internal/cfg"
+	"cmd/go/internal/load"
+	"cmd/go/internal/str"
+	"cmd/go/internal/work"
+)
+
+// A shardFlag is the value of the -shard flag: the index of the shard
+// to run, counting from zero, and the number of shards.
+type shardFlag struct {
+	i, n int
+}
+
+func (f *shardFlag) String() string {
+	if f.n == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d", f.i, f.n)
+}
+
+func (f *shardFlag) Set(value string) error {
+	si, sn, ok := strings.Cut(value, "/")
+	i, err1 := strconv.Atoi(si)
+	n, err2 := strconv.Atoi(sn)
+	if !ok || err1 != nil || err2 != nil || n < 1 || i < 0 || i >= n {
+		return fmt.Errorf("must be of the form i/n, with 0 <= i < n")
+	}
+	*f = shardFlag{i, n}
+	return nil
+}
+
+// shardTimes holds the times read from the file named by the -shardtimes
+// flag, or nil if the flag is not set.
+var shardTimes *shardTimesFile
+
+// A shardTimesFile records how long the tests took in an earlier run,
+// for balancing shards.
+type shardTimesFile struct {
+	pkgs  map[string]float64            // seconds taken by the tests of each package
+	tests map[string]map[string]float64 // seconds taken by each top-level test, by package
+}
+
+// readShardTimes reads the times in file, which holds the output of
+// 'go test -json'. Lines that are not test events are ignored, so that
+// the output of several runs may simply be joined together.
+func readShardTimes(file string) (*shardTimesFile, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	times := &shardTimesFile{
+		pkgs:  make(map[string]float64),
+		tests: make(map[string]map[string]float64),
+	}
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		var e struct {
+			Action  string
+			Package string
+			Test    string
+			Elapsed *float64
+		}
+		if json.Unmarshal(line, &e) != nil || e.Package == "" || e.Elapsed == nil {
+			continue
+		}
+		if e.Action != "pass" && e.Action != "fail" {
+			continue
+		}
+		switch {
+		case e.Test == "":
+			times.pkgs[e.Package] = *e.Elapsed
+		case !strings.Contains(e.Test, "/"):
+			if times.tests[e.Package] == nil {
+				times.tests[e.Package] = make(map[string]float64)
+			}
+			times.tests[e.Package][e.Test] = *e.Elapsed
+		}
+	}
+	return times, nil
+}
+
+// testResultIndexKey returns the cache key of the entry recording the key
+// of the latest test result entry of package p, which saveOutput writes
+// along with the result. Unlike the key of a test result, it does not
+// depend on the test binary, so that the result can be found before the
+// test is built.
+func testResultIndexKey(p *load.Package) cache.ActionID {
+	h := cache.NewHash("testResultIndex")
+	fmt.Fprintf(h, "test result index %s %s/%s\n", p.ImportPath, cfg.Goos, cfg.Goarch)
+	return h.Sum()
+}
+
+// readCacheShardTimes returns the times taken by the tests of pkgs in
+// their latest runs whose results are recorded in the build cache, for
+// -shardtimes=cache. The times of the top-level tests of a package are
+// known only if that run used -v.
+func readCacheShardTimes(pkgs []*load.Package) *shardTimesFile {
+	times := &shardTimesFile{
+		pkgs:  make(map[string]float64),
+		tests: make(map[string]map[string]float64),
+	}
+	c := cache.Default()
+	for _, p := range pkgs {
+		key, _, err := cache.GetBytes(c, testResultIndexKey(p))
+		if err != nil || len(key) != cache.HashSize {
+			continue
+		}
+		out, _, err := cache.GetBytes(c, cache.ActionID(key))
+		if err != nil {
+			continue
+		}
+		for _, line := range strings.Split(string(out), "\n") {
+			if rest, ok := strings.CutPrefix(line, "ok  \t"+p.ImportPath+"\t"); ok {
+				// ok  \tpkg\t1.234s
+				elapsed, _, _ := strings.Cut(rest, "\t")
+				if t, err := strconv.ParseFloat(strings.TrimSuffix(elapsed, "s"), 64); err == nil {
+					times.pkgs[p.ImportPath] = t
+				}
+			} else if rest, ok := strings.CutPrefix(line, "--- PASS: "); ok {
+				// --- PASS: TestName (0.12s)
+				name, elapsed, ok := strings.Cut(rest, " (")
+				if !ok || strings.Contains(name, "/") {
+					continue
+				}
+				if t, err := strconv.ParseFloat(strings.TrimSuffix(elapsed, "s)"), 64); err == nil {
+					if times.tests[p.ImportPath] == nil {
+						times.tests[p.ImportPath] = make(map[string]float64)
+					}
+					times.tests[p.ImportPath][name] = t
+				}
+			}
+		}
+	}
+	return times
+}
+
+// shardPackages returns the packages in pkgs that belong to the shard
+// selected by the -shard flag.
+func shardPackages(pkgs []*load.Package) []*load.Package {
+	names := make([]string, len(pkgs))
+	for i, p := range pkgs {
+		names[i] = p.ImportPath
+	}
+	var durations map[string]float64
+	if shardTimes != nil {
+		durations = shardTimes.pkgs
+	}
+	selected := selectShard(names, durations, testShard.i, testShard.n)
+	return slices.DeleteFunc(pkgs, func(p *load.Package) bool {
+		return !slices.Contains(selected, p.ImportPath)
+	})
+}
+
+// selectShard partitions names into n shards and returns the names in
+// shard i, in their original order.
+//
+// The names with a known duration are assigned greedily, longest first,
+// to the shard with the least total duration so far. The others are
+// assigned by a hash of the name. Either way, the assignment depends
+// only on the set of names and on durations, not on their order,
+// so every shard computes the same one.
+func selectShard(names []string, durations map[string]float64, i, n int) []string {
+	var known []string
+	for _, name := range names {
+		if _, ok := durations[name]; ok {
+			known = append(known, name)
+		}
+	}
+	slices.SortFunc(known, func(a, b string) int {
+		if durations[a] != durations[b] {
+			if durations[a] > durations[b] {
+				return -1
+			}
+			return +1
+		}
+		return strings.Compare(a, b)
+	})
+
+	shard := make(map[string]int)
+	total := make([]float64, n)
+	for _, name := range known {
+		least := 0
+		for j := range total {
+			if total[j] < total[least] {
+				least = j
+			}
+		}
+		shard[name] = least
+		total[least] += durations[name]
+	}
+
+	var selected []string
+	for _, name := range names {
+		j, ok := shard[name]
+		if !ok {
+			h := fnv.New32a()
+			h.Write([]byte(name))
+			j = int(h.Sum32() % uint32(n))
+		}
+		if j == i {
+			selected = append(selected, name)
+		}
+	}
+	return selected
+}
+
+// shardTestsPattern returns the -test.run pattern that selects the tests
+// of the shard selected by the -shard flag, given the output of the test
+// binary's -test.list flag and the recorded times of the tests.
+func shardTestsPattern(list []byte, durations map[string]float64) string {
+	var names []string
+	for _, line := range strings.Split(string(list), "\n") {
+		if strings.HasPrefix(line, "Test") || strings.HasPrefix(line, "Example") || strings.HasPrefix(line, "Fuzz") {
+			names = append(names, line)
+		}
+	}
+	selected := selectShard(names, durations, testShard.i, testShard.n)
+	if len(selected) == 0 {
+		return "^$"
+	}
+	return "^(" + strings.Join(selected, "|") + ")$"
+}
+
+// listShardTests runs the test binary built by the dependency of a
+// to list the top-level tests matching the -run flag, and returns the
+// -test.run pattern that selects those in the shard selected by -shard.
+func listShardTests(ctx context.Context, a *work.Action, execCmd []string) (string, error) {
+	pattern := testRunPattern(testArgs)
+	if pattern == "" {
+		pattern = "."
+	}
+	args := str.StringList(execCmd, a.Deps[0].BuiltTarget(), "-test.list="+pattern)
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = a.Package.Dir
+	cmd.Env = base.AppendPWD(base.AppendPATH(slices.Clip(cfg.OrigEnv)), cmd.Dir)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("listing tests of %s: %v", a.Package.ImportPath, err)
+	}
+	var durations map[string]float64
+	if shardTimes != nil {
+		durations = shardTimes.tests[a.Package.ImportPath]
+	}
+	return shardTestsPattern(out, durations), nil
+}
+
+// testRunPattern returns the -run pattern in the test arguments, if any.
+func testRunPattern(args []string) string {
+	pattern := ""
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if p, ok := strings.CutPrefix(arg, "-test.run="); ok {
+			pattern = p
+		}
+	}
+	return pattern
+}
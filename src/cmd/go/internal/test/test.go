@@ -15,6 +15,7 @@ import (
 	"io/fs"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"regexp"
 	"slices"
@@ -138,6 +139,13 @@ unchanged. A cached test result is treated as executing in no time
 at all, so a successful package test result will be cached and
 reused regardless of -timeout setting.
 
+Files are normally considered unchanged if their size and modification
+time are unchanged. When the -trimpath build flag is set, go test instead
+compares the content of the files, and records their names relative to
+the root of the package's module, so that a cached result can be reused
+by a copy of the module in a different directory or on a different
+machine, for example through a build cache shared using GOCACHEPROG.
+
 In addition to the build flags, the flags handled by 'go test' itself are:
 
 	-args
@@ -165,6 +173,37 @@ In addition to the build flags, the flags handled by 'go test' itself are:
 	    If file ends in a slash or names an existing directory,
 	    the test is written to pkg.test in that directory.
 
+	-shard i/n
+	    Split the packages to test into n shards and test only those
+	    in shard i, counting from zero, so that n workers running
+	    'go test -shard=0/n', ..., 'go test -shard=n-1/n' together
+	    test every package exactly once. Packages are assigned by a
+	    hash of their import path, or balanced by the times given by
+	    the -shardtimes flag. The assignment depends only on the
+	    packages and those times, so every worker computes the same one.
+
+	-shardtimes file
+	    With -shard, balance the shards by the times that the tests
+	    took in an earlier run, read from file. The file holds the
+	    output of 'go test -json', for example that of every shard of
+	    an earlier run joined together. Packages and tests not listed
+	    in the file are assigned by a hash of their name. Every worker
+	    must be given the same file.
+	    The special value "cache" balances the shards by the times
+	    recorded in the test results in the build cache instead: those
+	    of the latest cached run of each package and, if that run used
+	    -v, of its top-level tests. The workers agree on the assignment
+	    only if they see the same cached results, for example through a
+	    build cache shared by GOCACHEPROG that is not written to while
+	    the workers start; otherwise, use a file.
+
+	-shardtests
+	    With -shard, split the top-level tests, examples and fuzz tests
+	    of each package matching -run, instead of the packages, and
+	    run only those in shard i. Tests are balanced by their times
+	    given by the -shardtimes flag. Test results are not cached when
+	    -shardtests is set, and -run may not select subtests.
+
 The test binary also accepts flags that control execution of the test; these
 flags are also accessible by 'go test'. See 'go help testflag' for details.
 
@@ -548,6 +587,9 @@ var (
 	testList         string                            // -list flag
 	testO            string                            // -o flag
 	testOutputDir    outputdirFlag                     // -outputdir flag
+	testShard        shardFlag                         // -shard flag
+	testShardTests   bool                              // -shardtests flag
+	testShardTimes   string                            // -shardtimes flag
 	testShuffle      shuffleFlag                       // -shuffle flag
 	testTimeout      time.Duration                     // -timeout flag
 	testV            testVFlag                         // -v flag
@@ -758,6 +800,36 @@ func runTest(ctx context.Context, cmd *base.Command, args []string) {
 		base.Fatalf("cannot use %s flag with multiple packages", testProfile())
 	}
 
+	if testShardTests && testShard.n == 0 {
+		base.Fatalf("cannot use -shardtests flag without -shard flag")
+	}
+	if testShardTimes != "" && testShard.n == 0 {
+		base.Fatalf("cannot use -shardtimes flag without -shard flag")
+	}
+	if testShard.n > 0 {
+		if testFuzz != "" || testBench != "" {
+			base.Fatalf("cannot use -shard flag with -fuzz or -bench flag")
+		}
+		if testShardTests && strings.Contains(testRunPattern(testArgs), "/") {
+			base.Fatalf("cannot use -shardtests flag with a -run pattern that matches subtests")
+		}
+		if testShardTimes == "cache" {
+			shardTimes = readCacheShardTimes(pkgs)
+		} else if testShardTimes != "" {
+			var err error
+			if shardTimes, err = readShardTimes(testShardTimes); err != nil {
+				base.Fatalf("reading -shardtimes file: %v", err)
+			}
+		}
+		if !testShardTests {
+			pkgs = shardPackages(pkgs)
+			if len(pkgs) == 0 {
+				fmt.Fprintf(os.Stderr, "go: no packages to test in shard %v\n", &testShard)
+				return
+			}
+		}
+	}
+
 	if testO != "" {
 		if strings.HasSuffix(testO, "/") || strings.HasSuffix(testO, string(os.PathSeparator)) {
 			testODir = true
@@ -1538,6 +1610,18 @@ func (r *runTestActor) Act(b *work.Builder, ctx context.Context, a *work.Action)
 		}
 	}
 
+	if testShardTests && !cfg.BuildN {
+		// Run only this shard's top-level tests among those matching -run.
+		pattern, err := listShardTests(ctx, a, execCmd)
+		if err != nil {
+			return err
+		}
+		args = slices.DeleteFunc(args, func(arg string) bool {
+			return strings.HasPrefix(arg, "-test.run=")
+		})
+		args = slices.Insert(args, len(execCmd)+1, "-test.run="+pattern)
+	}
+
 	if cfg.BuildN || cfg.BuildX {
 		sh.ShowCmd("", "%s", strings.Join(args, " "))
 		if cfg.BuildN {
@@ -1692,6 +1776,16 @@ func (c *runCache) tryCache(b *work.Builder, a *work.Action) bool {
 }
 
 func (c *runCache) tryCacheWithID(b *work.Builder, a *work.Action, id string) bool {
+	if testShardTests {
+		// The tests to run are chosen by running the test binary,
+		// after the cache lookup.
+		if cache.DebugTest {
+			fmt.Fprintf(os.Stderr, "testcache: caching disabled for -shardtests\n")
+		}
+		c.disableCache = true
+		return false
+	}
+
 	if len(pkgArgs) == 0 {
 		// Caching does not apply to "go test",
 		// only to "go test foo" (including "go test .").
@@ -1774,6 +1868,9 @@ func (c *runCache) tryCacheWithID(b *work.Builder, a *work.Action, id string) bo
 
 	h := cache.NewHash("testResult")
 	fmt.Fprintf(h, "test binary %s args %q execcmd %q", id, cacheArgs, work.ExecCmd)
+	if cfg.BuildTrimpath {
+		fmt.Fprintf(h, " relocatable")
+	}
 	testID := h.Sum()
 	if c.id1 == (cache.ActionID{}) {
 		c.id1 = testID
@@ -1854,14 +1951,52 @@ func (c *runCache) tryCacheWithID(b *work.Builder, a *work.Action, id string) bo
 var errBadTestInputs = errors.New("error parsing test inputs")
 var testlogMagic = []byte("# test log\n") // known to testing/internal/testdeps/deps.go
 
+// testlogRoot replaces the module, GOPATH, or GOROOT root of the package
+// in the file names of a relocatable test log. See relocateTestlog.
+const testlogRoot = "$ROOT"
+
+// relocateTestlog returns a copy of the test log in which the absolute
+// file names within the package's module, GOPATH, or GOROOT root are
+// made relative to testlogRoot, so that the log can be used by a copy
+// of the root in a different directory. computeTestInputsID undoes that.
+func relocateTestlog(a *work.Action, testlog []byte) []byte {
+	var out bytes.Buffer
+	for _, line := range bytes.SplitAfter(testlog, []byte("\n")) {
+		op, name, found := strings.Cut(string(line), " ")
+		if found && filepath.IsAbs(name) {
+			if rel := search.InDir(strings.TrimSuffix(name, "\n"), a.Package.Root); rel != "" {
+				fmt.Fprintf(&out, "%s %s\n", op, path.Join(testlogRoot, filepath.ToSlash(rel)))
+				continue
+			}
+		}
+		out.Write(line)
+	}
+	return out.Bytes()
+}
+
 // computeTestInputsID computes the "test inputs ID"
 // (see comment in tryCacheWithID above) for the
 // test log.
+//
+// When the -trimpath flag is set, the ID does not depend on the location
+// of the package's module, GOPATH, or GOROOT root: files are identified by
+// their name relative to the root and by their content.
 func computeTestInputsID(a *work.Action, testlog []byte) (cache.ActionID, error) {
 	testlog = bytes.TrimPrefix(testlog, testlogMagic)
 	h := cache.NewHash("testInputs")
 	// The runtime always looks at GODEBUG, without telling us in the testlog.
 	fmt.Fprintf(h, "env GODEBUG %x\n", hashGetenv("GODEBUG"))
+	relocatable := cfg.BuildTrimpath && a.Package.Root != ""
+	// key returns the name under which the absolute file name
+	// is recorded in the test inputs ID.
+	key := func(name string) string {
+		if relocatable {
+			if rel := search.InDir(name, a.Package.Root); rel != "" {
+				return path.Join(testlogRoot, filepath.ToSlash(rel))
+			}
+		}
+		return name
+	}
 	pwd := a.Package.Dir
 	for _, line := range bytes.Split(testlog, []byte("\n")) {
 		if len(line) == 0 {
@@ -1875,6 +2010,9 @@ func computeTestInputsID(a *work.Action, testlog []byte) (cache.ActionID, error)
 			}
 			return cache.ActionID{}, errBadTestInputs
 		}
+		if rel, ok := strings.CutPrefix(name, testlogRoot+"/"); ok && relocatable {
+			name = filepath.Join(a.Package.Root, filepath.FromSlash(rel))
+		}
 		switch op {
 		default:
 			if cache.DebugTest {
@@ -1885,7 +2023,7 @@ func computeTestInputsID(a *work.Action, testlog []byte) (cache.ActionID, error)
 			fmt.Fprintf(h, "env %s %x\n", name, hashGetenv(name))
 		case "chdir":
 			pwd = name // always absolute
-			fmt.Fprintf(h, "chdir %s %x\n", name, hashStat(name))
+			fmt.Fprintf(h, "chdir %s %x\n", key(name), hashStat(name, relocatable))
 		case "stat":
 			if !filepath.IsAbs(name) {
 				name = filepath.Join(pwd, name)
@@ -1894,7 +2032,7 @@ func computeTestInputsID(a *work.Action, testlog []byte) (cache.ActionID, error)
 				// Do not recheck files outside the module, GOPATH, or GOROOT root.
 				break
 			}
-			fmt.Fprintf(h, "stat %s %x\n", name, hashStat(name))
+			fmt.Fprintf(h, "stat %s %x\n", key(name), hashStat(name, relocatable))
 		case "open":
 			if !filepath.IsAbs(name) {
 				name = filepath.Join(pwd, name)
@@ -1903,14 +2041,14 @@ func computeTestInputsID(a *work.Action, testlog []byte) (cache.ActionID, error)
 				// Do not recheck files outside the module, GOPATH, or GOROOT root.
 				break
 			}
-			fh, err := hashOpen(name)
+			fh, err := hashOpen(name, relocatable)
 			if err != nil {
 				if cache.DebugTest {
 					fmt.Fprintf(os.Stderr, "testcache: %s: input file %s: %s\n", a.Package.ImportPath, name, err)
 				}
 				return cache.ActionID{}, err
 			}
-			fmt.Fprintf(h, "open %s %x\n", name, fh)
+			fmt.Fprintf(h, "open %s %x\n", key(name), fh)
 		}
 	}
 	sum := h.Sum()
@@ -1933,28 +2071,56 @@ const modTimeCutoff = 2 * time.Second
 
 var errFileTooNew = errors.New("file used as input is too new")
 
-func hashOpen(name string) (cache.ActionID, error) {
+// maxHashedFileSize is the size above which the files opened by a test
+// are not hashed by content, and the test result is not cached.
+const maxHashedFileSize = 64 << 20
+
+var errFileTooLarge = errors.New("file used as input is too large to hash")
+
+// hashOpen returns a hash of the file or directory name, read by a test.
+// If byContent is set, the hash depends on the content of a file and the
+// list of entries of a directory, but not on their modification times.
+func hashOpen(name string, byContent bool) (cache.ActionID, error) {
 	h := cache.NewHash("open")
 	info, err := os.Stat(name)
 	if err != nil {
-		fmt.Fprintf(h, "err %v\n", err)
+		fmt.Fprintf(h, "err %v\n", errText(err, byContent))
 		return h.Sum(), nil
 	}
-	hashWriteStat(h, info)
+	hashWriteStat(h, info, byContent)
 	if info.IsDir() {
 		files, err := os.ReadDir(name)
 		if err != nil {
-			fmt.Fprintf(h, "err %v\n", err)
+			fmt.Fprintf(h, "err %v\n", errText(err, byContent))
 		}
 		for _, f := range files {
 			fmt.Fprintf(h, "file %s ", f.Name())
 			finfo, err := f.Info()
 			if err != nil {
-				fmt.Fprintf(h, "err %v\n", err)
+				fmt.Fprintf(h, "err %v\n", errText(err, byContent))
 			} else {
-				hashWriteStat(h, finfo)
+				hashWriteStat(h, finfo, byContent)
 			}
 		}
+	} else if info.Mode().IsRegular() && byContent {
+		// Hash the content directly, not through cache.FileHash,
+		// which would not notice the test changing the file.
+		// Give up on files too large to hash on every test run.
+		if info.Size() > maxHashedFileSize {
+			return cache.ActionID{}, errFileTooLarge
+		}
+		f, err := os.Open(name)
+		if err != nil {
+			return cache.ActionID{}, err
+		}
+		n, err := io.CopyN(h, f, maxHashedFileSize+1)
+		f.Close()
+		if err != nil && err != io.EOF {
+			return cache.ActionID{}, err
+		}
+		if n > maxHashedFileSize {
+			return cache.ActionID{}, errFileTooLarge
+		}
 	} else if info.Mode().IsRegular() {
 		// Because files might be very large, do not attempt
 		// to hash the entirety of their content. Instead assume
@@ -1972,25 +2138,47 @@ func hashOpen(name string) (cache.ActionID, error) {
 	return h.Sum(), nil
 }
 
-func hashStat(name string) cache.ActionID {
+// hashStat returns a hash of the result of stat and lstat of name.
+// If byContent is set, the hash does not depend on modification times.
+func hashStat(name string, byContent bool) cache.ActionID {
 	h := cache.NewHash("stat")
 	if info, err := os.Stat(name); err != nil {
-		fmt.Fprintf(h, "err %v\n", err)
+		fmt.Fprintf(h, "err %v\n", errText(err, byContent))
 	} else {
-		hashWriteStat(h, info)
+		hashWriteStat(h, info, byContent)
 	}
 	if info, err := os.Lstat(name); err != nil {
-		fmt.Fprintf(h, "err %v\n", err)
+		fmt.Fprintf(h, "err %v\n", errText(err, byContent))
 	} else {
-		hashWriteStat(h, info)
+		hashWriteStat(h, info, byContent)
 	}
 	return h.Sum()
 }
 
-func hashWriteStat(h io.Writer, info fs.FileInfo) {
+func hashWriteStat(h io.Writer, info fs.FileInfo, byContent bool) {
+	if byContent {
+		// The size of a directory depends on the file system,
+		// not on its entries.
+		size := info.Size()
+		if info.IsDir() {
+			size = 0
+		}
+		fmt.Fprintf(h, "stat %d %x %v\n", size, uint64(info.Mode()), info.IsDir())
+		return
+	}
 	fmt.Fprintf(h, "stat %d %x %v %v\n", info.Size(), uint64(info.Mode()), info.ModTime(), info.IsDir())
 }
 
+// errText returns the text of err to hash. If byContent is set,
+// errors about a file are reduced to their cause, which unlike
+// the error itself does not mention the name of the file.
+func errText(err error, byContent bool) string {
+	if pe, ok := err.(*fs.PathError); ok && byContent {
+		return pe.Op + ": " + pe.Err.Error()
+	}
+	return err.Error()
+}
+
 // testAndInputKey returns the actual cache key for the pair (testID, testInputsID).
 func testAndInputKey(testID, testInputsID cache.ActionID) cache.ActionID {
 	return cache.Subkey(testID, fmt.Sprintf("inputs:%x", testInputsID))
@@ -2017,6 +2205,9 @@ func (c *runCache) saveOutput(a *work.Action) {
 	if err != nil {
 		return
 	}
+	if cfg.BuildTrimpath && a.Package.Root != "" {
+		testlog = relocateTestlog(a, testlog)
+	}
 	if c.id1 != (cache.ActionID{}) {
 		if cache.DebugTest {
 			fmt.Fprintf(os.Stderr, "testcache: %s: save test ID %x => input ID %x => %x\n", a.Package.ImportPath, c.id1, testInputsID, testAndInputKey(c.id1, testInputsID))
@@ -2031,6 +2222,12 @@ func (c *runCache) saveOutput(a *work.Action) {
 		cache.PutNoVerify(cache.Default(), c.id2, bytes.NewReader(testlog))
 		cache.PutNoVerify(cache.Default(), testAndInputKey(c.id2, testInputsID), bytes.NewReader(a.TestOutput.Bytes()))
 	}
+	// Record where the result is, for -shardtimes=cache.
+	key := testAndInputKey(c.id1, testInputsID)
+	if c.id1 == (cache.ActionID{}) {
+		key = testAndInputKey(c.id2, testInputsID)
+	}
+	cache.PutBytes(cache.Default(), testResultIndexKey(a.Package), key[:])
 }
 
 // coveragePercentage returns the coverage results (if enabled) for the
@@ -35,6 +35,9 @@ func init() {
 	cf.Var((*base.StringsFlag)(&work.ExecCmd), "exec", "")
 	cf.BoolVar(&testJSON, "json", false, "")
 	cf.Var(&testVet, "vet", "")
+	cf.Var(&testShard, "shard", "")
+	cf.BoolVar(&testShardTests, "shardtests", false, "")
+	cf.StringVar(&testShardTimes, "shardtimes", "", "")
 
 	// Register flags to be forwarded to the test binary. We retain variables for
 	// some of them so that cmd/go knows what to do with the test output, or knows
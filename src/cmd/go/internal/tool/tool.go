@@ -13,14 +13,23 @@ import (
 	"fmt"
 	"go/build"
 	"internal/platform"
+	"maps"
 	"os"
 	"os/exec"
 	"os/signal"
+	"path"
+	"slices"
 	"sort"
 	"strings"
 
 	"cmd/go/internal/base"
 	"cmd/go/internal/cfg"
+	"cmd/go/internal/load"
+	"cmd/go/internal/modload"
+	"cmd/go/internal/str"
+	"cmd/go/internal/work"
+
+	"golang.org/x/mod/module"
 )
 
 var CmdTool = &base.Command{
@@ -31,6 +40,13 @@ var CmdTool = &base.Command{
 Tool runs the go tool command identified by the arguments.
 With no arguments it prints the list of known tools.
 
+The command may also name a tool declared by a tool directive in the
+go.mod file of the main module, either by its package path or by the
+last element of that path (ignoring a major version suffix such as /v2).
+Such a tool is built in the context of the main module, at the version
+selected by its requirements, and then run. Tools are added to go.mod
+with 'go get -tool'.
+
 The -n flag causes tool to print the command that would be
 executed but not execute it.
 
@@ -59,10 +75,17 @@ func init() {
 func runTool(ctx context.Context, cmd *base.Command, args []string) {
 	if len(args) == 0 {
 		counter.Inc("go/subcommand:tool")
-		listTools()
+		listTools(ctx)
 		return
 	}
 	toolName := args[0]
+	if _, err := base.ToolPath(toolName); err != nil || strings.ContainsAny(toolName, "/\\") {
+		if tool := loadModTool(ctx, toolName); tool != "" {
+			counter.Inc("go/subcommand:tool-modtool")
+			runModTool(ctx, toolName, tool, args[1:])
+			return
+		}
+	}
 	// The tool name must be lower-case letters, numbers or underscores.
 	for _, c := range toolName {
 		switch {
@@ -115,19 +138,7 @@ func runTool(ctx context.Context, cmd *base.Command, args []string) {
 		Stdout: os.Stdout,
 		Stderr: os.Stderr,
 	}
-	err = toolCmd.Start()
-	if err == nil {
-		c := make(chan os.Signal, 100)
-		signal.Notify(c)
-		go func() {
-			for sig := range c {
-				toolCmd.Process.Signal(sig)
-			}
-		}()
-		err = toolCmd.Wait()
-		signal.Stop(c)
-		close(c)
-	}
+	err = runToolCmd(toolCmd)
 	if err != nil {
 		// Only print about the exit status if the command
 		// didn't even run (not an ExitError) or it didn't exit cleanly
@@ -142,8 +153,28 @@ func runTool(ctx context.Context, cmd *base.Command, args []string) {
 	}
 }
 
-// listTools prints a list of the available tools in the tools directory.
-func listTools() {
+// runToolCmd runs toolCmd, forwarding the signals that the go command
+// receives to it.
+func runToolCmd(toolCmd *exec.Cmd) error {
+	err := toolCmd.Start()
+	if err == nil {
+		c := make(chan os.Signal, 100)
+		signal.Notify(c)
+		go func() {
+			for sig := range c {
+				toolCmd.Process.Signal(sig)
+			}
+		}()
+		err = toolCmd.Wait()
+		signal.Stop(c)
+		close(c)
+	}
+	return err
+}
+
+// listTools prints a list of the available tools in the tools directory,
+// followed by the tools of the main module.
+func listTools(ctx context.Context) {
 	f, err := os.Open(build.ToolDir)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "go: no tool directory: %s\n", err)
@@ -171,6 +202,123 @@ func listTools() {
 		}
 		fmt.Println(name)
 	}
+
+	// If the main module cannot be loaded, list the built-in tools only:
+	// the listing should not fail because of a broken go.mod file.
+	tools, _ := modTools(ctx)
+	for _, tool := range tools {
+		fmt.Println(tool)
+	}
+}
+
+// modTools returns the package paths of the tools declared by the main
+// module, in order, or nil if there is no main module.
+func modTools(ctx context.Context) ([]string, error) {
+	if !modload.WillBeEnabled() {
+		return nil, nil
+	}
+	modload.InitWorkfile()
+	modload.Init()
+	if !modload.HasModRoot() {
+		return nil, nil
+	}
+	if _, err := modload.TryLoadModFile(ctx); err != nil {
+		return nil, err
+	}
+	return slices.Sorted(maps.Keys(modload.MainModules.Tools())), nil
+}
+
+// loadModTool returns the package path of the tool of the main module
+// named name, or "" if there is none. It exits with an error if name
+// matches more than one tool.
+func loadModTool(ctx context.Context, name string) string {
+	tools, err := modTools(ctx)
+	if err != nil {
+		base.Fatal(err)
+	}
+	var matches []string
+	for _, tool := range tools {
+		if tool == name || modToolName(tool) == name {
+			matches = append(matches, tool)
+		}
+	}
+	if len(matches) > 1 {
+		base.Fatalf("go: tool %q is ambiguous; use one of:\n\t%s", name, strings.Join(matches, "\n\t"))
+	}
+	if len(matches) == 0 {
+		return ""
+	}
+	return matches[0]
+}
+
+// modToolName returns the short name of the tool with package path tool:
+// the last element of the path, ignoring a major version suffix, which
+// is also the name of the executable 'go install' would build.
+func modToolName(tool string) string {
+	if prefix, _, ok := module.SplitPathVersion(tool); ok {
+		tool = prefix
+	}
+	return path.Base(tool)
+}
+
+// runModTool builds and runs the tool of the main module with package
+// path tool, as 'go run' would, passing it args.
+func runModTool(ctx context.Context, name, tool string, args []string) {
+	if toolN {
+		cfg.BuildN = true
+	}
+	work.BuildInit()
+	b := work.NewBuilder("")
+	defer func() {
+		if err := b.Close(); err != nil {
+			base.Fatal(err)
+		}
+	}()
+
+	pkgs := load.PackagesAndErrors(ctx, load.PackageOpts{MainOnly: true}, []string{tool})
+	load.CheckPackageErrors(pkgs)
+	p := pkgs[0]
+	p.Internal.OmitDebug = true
+	p.Internal.ExeName = name
+	p.Target = "" // must build - not up to date
+
+	a1 := b.LinkAction(work.ModeBuild, work.ModeBuild, p)
+	a := &work.Action{Mode: "go tool", Actor: work.ActorFunc(runBuiltTool), Args: args, Deps: []*work.Action{a1}}
+	b.Do(ctx, a)
+}
+
+// runBuiltTool is the action for running a tool that has already been
+// built. If the tool fails, so does the go command, with the exit
+// status of the tool.
+func runBuiltTool(b *work.Builder, ctx context.Context, a *work.Action) error {
+	cmdline := str.StringList(work.FindExecCmd(), a.Deps[0].Target, a.Args)
+	if cfg.BuildN || cfg.BuildX {
+		b.Shell(a).ShowCmd("", "%s", strings.Join(cmdline, " "))
+		if cfg.BuildN {
+			return nil
+		}
+	}
+
+	// The command may be a go_$GOOS_$GOARCH_exec wrapper found in $PATH,
+	// so look it up as exec.Command does.
+	toolCmd := exec.Command(cmdline[0], cmdline[1:]...)
+	toolCmd.Stdin = os.Stdin
+	toolCmd.Stdout = os.Stdout
+	toolCmd.Stderr = os.Stderr
+	if err := runToolCmd(toolCmd); err != nil {
+		// As for the tools in the tool directory, assume that a
+		// tool that exited cleanly printed any messages it wanted to.
+		e, ok := err.(*exec.ExitError)
+		if !ok || !e.Exited() || cfg.BuildX {
+			fmt.Fprintf(os.Stderr, "go tool %s: %s\n", a.Deps[0].Package.Internal.ExeName, err)
+		}
+		if ok && e.Exited() {
+			base.SetExitStatus(e.ExitCode())
+		} else {
+			base.SetExitStatus(1)
+		}
+	}
+	return nil
 }
 
 func impersonateDistList(args []string) (handled bool) {
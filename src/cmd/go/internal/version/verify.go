@@ -0,0 +1,392 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"debug/buildinfo"
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"strconv"
+	"strings"
+
+	"cmd/go/internal/base"
+	"cmd/go/internal/cfg"
+	"cmd/go/internal/gover"
+	"cmd/go/internal/work"
+	"cmd/internal/quoted"
+)
+
+// verifyFile rebuilds the binary file, whose build information is bi,
+// from the module versions and with the build settings recorded in bi,
+// and reports whether the result is identical to file.
+func verifyFile(file string, bi *debug.BuildInfo) {
+	rebuilt, tmp, err := rebuild(bi)
+	if tmp != "" {
+		defer os.RemoveAll(tmp)
+	}
+	if err != nil {
+		fmt.Printf("\tverify: cannot rebuild: %v\n", strings.ReplaceAll(strings.TrimSpace(err.Error()), "\n", "\n\t\t"))
+		base.SetExitStatus(1)
+		return
+	}
+
+	have, err := os.ReadFile(file)
+	if err != nil {
+		fmt.Printf("\tverify: %v\n", err)
+		base.SetExitStatus(1)
+		return
+	}
+	want, err := os.ReadFile(rebuilt)
+	if err != nil {
+		fmt.Printf("\tverify: %v\n", err)
+		base.SetExitStatus(1)
+		return
+	}
+	if bytes.Equal(have, want) {
+		fmt.Printf("\tverify: ok\n")
+		return
+	}
+
+	base.SetExitStatus(1)
+	fmt.Printf("\tverify: rebuilt binary differs\n")
+	if sections, err := diffSections(file, rebuilt); err != nil {
+		fmt.Printf("\t\tsections: %v\n", err)
+	} else if len(sections) > 0 {
+		fmt.Printf("\t\tsections: %s\n", strings.Join(sections, " "))
+	}
+	if rbi, err := buildinfo.ReadFile(rebuilt); err == nil {
+		for _, line := range diffLines(bi.String(), rbi.String()) {
+			fmt.Printf("\t\tbuild info: %s\n", line)
+		}
+	}
+}
+
+// rebuild rebuilds the binary described by bi in a new temporary directory
+// tmp, using 'go install path@version', and returns the name of the result.
+func rebuild(bi *debug.BuildInfo) (file, tmp string, err error) {
+	if bi.Main.Replace != nil {
+		return "", "", fmt.Errorf("main module %s is replaced", bi.Main.Path)
+	}
+	if bi.Main.Version == "" || bi.Main.Version == "(devel)" {
+		return "", "", fmt.Errorf("main module %s has no version", bi.Main.Path)
+	}
+	for _, dep := range bi.Deps {
+		if dep.Replace != nil {
+			return "", "", fmt.Errorf("module %s is replaced", dep.Path)
+		}
+	}
+
+	// Use the toolchain recorded in the binary. A development toolchain
+	// cannot be downloaded, so it must be the running one.
+	toolchain, _, _ := strings.Cut(bi.GoVersion, " ")
+	if !gover.IsValid(gover.FromToolchain(toolchain)) || strings.HasPrefix(bi.GoVersion, "devel") {
+		if bi.GoVersion != runtime.Version() {
+			return "", "", fmt.Errorf("built with development toolchain %s", bi.GoVersion)
+		}
+		toolchain = "local"
+	} else if bi.GoVersion == runtime.Version() {
+		toolchain = "local"
+	}
+
+	tmp, err = os.MkdirTemp("", "go-version-verify-")
+	if err != nil {
+		return "", "", err
+	}
+
+	args := []string{"install"}
+	env := []string{
+		"GOPATH=" + tmp,
+		"GOMODCACHE=" + cfg.GOMODCACHE,
+		"GOBIN=",
+		"GOFLAGS=",
+		"GOWORK=off",
+		"GO111MODULE=on",
+		"GOTOOLCHAIN=" + toolchain,
+		"GOEXPERIMENT=",
+	}
+	// The settings come from the binary, which may have been crafted to
+	// run arbitrary programs during the rebuild, for example with
+	// -ldflags=-extld=prog. Only pass on settings with known safe values.
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "-buildmode":
+			// The default build mode of a main package is recorded as
+			// "exe", and an explicit -buildmode=exe changes the build ID.
+			switch s.Value {
+			case "exe":
+			case "pie":
+				args = append(args, s.Key+"="+s.Value)
+			default:
+				return "", tmp, fmt.Errorf("cannot reproduce -buildmode=%s", s.Value)
+			}
+		case "-compiler":
+			if s.Value != "gc" {
+				return "", tmp, fmt.Errorf("cannot reproduce -compiler=%s", s.Value)
+			}
+		case "-gccgoflags":
+			return "", tmp, fmt.Errorf("cannot reproduce -gccgoflags")
+		case "-asan", "-buildpkgs", "-cover", "-msan", "-race", "-trimpath":
+			if _, err := strconv.ParseBool(s.Value); err != nil {
+				return "", tmp, fmt.Errorf("invalid %s=%s", s.Key, s.Value)
+			}
+			args = append(args, s.Key+"="+s.Value)
+		case "-asmflags", "-gcflags", "-ldflags":
+			if err := checkToolFlags(s.Key, s.Value); err != nil {
+				return "", tmp, err
+			}
+			args = append(args, s.Key+"="+s.Value)
+		case "-tags":
+			for _, tag := range strings.Split(s.Value, ",") {
+				if !validTag(tag) {
+					return "", tmp, fmt.Errorf("invalid -tags=%s", s.Value)
+				}
+			}
+			args = append(args, s.Key+"="+s.Value)
+		case "-pgo":
+			// With the default -pgo=auto, the profile is the default.pgo
+			// file in the main package's directory.
+			if filepath.Base(s.Value) != "default.pgo" {
+				return "", tmp, fmt.Errorf("cannot reproduce -pgo=%s", s.Value)
+			}
+		case "CGO_CFLAGS", "CGO_CPPFLAGS", "CGO_CXXFLAGS", "CGO_LDFLAGS":
+			// The go command trusts the flags in its environment, so
+			// check them as it checks the flags in #cgo directives.
+			list, err := quoted.Split(s.Value)
+			if err == nil {
+				err = work.CheckCgoEnvFlags(strings.TrimPrefix(s.Key, "CGO_"), list)
+			}
+			if err != nil {
+				return "", tmp, fmt.Errorf("%s: %v", s.Key, err)
+			}
+			env = append(env, s.Key+"="+s.Value)
+		case "CGO_ENABLED", "GOARCH", "GOOS", "GOEXPERIMENT",
+			"GO386", "GOAMD64", "GOARM", "GOARM64", "GOMIPS", "GOMIPS64", "GOPPC64", "GORISCV64", "GOWASM":
+			env = append(env, s.Key+"="+s.Value)
+		default:
+			// Other settings, such as DefaultGODEBUG and the vcs settings,
+			// follow from the source and are not inputs of the build.
+		}
+	}
+	args = append(args, bi.Path+"@"+bi.Main.Version)
+
+	exe, err := os.Executable()
+	if err != nil {
+		return "", tmp, err
+	}
+	cmd := exec.Command(exe, args...)
+	cmd.Dir = tmp
+	cmd.Env = append(os.Environ(), env...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", tmp, fmt.Errorf("go %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+
+	// The binary is installed in $GOPATH/bin, or one of its
+	// subdirectories if cross-compiled.
+	filepath.WalkDir(filepath.Join(tmp, "bin"), func(path string, d fs.DirEntry, err error) error {
+		if err == nil && d.Type().IsRegular() {
+			file = path
+		}
+		return nil
+	})
+	if file == "" {
+		return "", tmp, fmt.Errorf("go %s: no binary installed", strings.Join(args, " "))
+	}
+	return file, tmp, nil
+}
+
+// A toolFlag describes a compiler, assembler or linker flag that
+// 'go version -verify' may pass on when rebuilding a binary.
+type toolFlag struct {
+	hasValue bool     // the flag takes a value
+	values   []string // the allowed values, if limited
+}
+
+// verifyToolFlags lists, for each of -asmflags, -gcflags and -ldflags,
+// the flags that are allowed in its value. Flags that run other
+// programs or select a different toolchain, such as -extld, -extar,
+// -linkmode=external or -toolexec, and flags that write files, such as
+// -cpuprofile, are not allowed.
+var verifyToolFlags = map[string]map[string]toolFlag{
+	"-asmflags": {
+		"D":       {hasValue: true},
+		"spectre": {hasValue: true},
+	},
+	"-gcflags": {
+		"B":                  {},
+		"C":                  {},
+		"N":                  {},
+		"c":                  {hasValue: true},
+		"dwarf":              {},
+		"dwarflocationlists": {},
+		"l":                  {},
+		"m":                  {},
+		"spectre":            {hasValue: true},
+	},
+	"-ldflags": {
+		"B":             {hasValue: true},
+		"X":             {hasValue: true},
+		"buildid":       {hasValue: true},
+		"compressdwarf": {},
+		"linkmode":      {hasValue: true, values: []string{"internal"}},
+		"s":             {},
+		"w":             {},
+	},
+}
+
+// checkToolFlags returns an error if value, the value of the build
+// setting key (-asmflags, -gcflags or -ldflags), contains a flag that
+// is not in verifyToolFlags.
+func checkToolFlags(key, value string) error {
+	value = strings.TrimSpace(value)
+	if value != "" && !strings.HasPrefix(value, "-") {
+		// Strip the package pattern of <pattern>=<flags>.
+		_, value, _ = strings.Cut(value, "=")
+	}
+	args, err := quoted.Split(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %v", key, err)
+	}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") {
+			return fmt.Errorf("invalid %s: unexpected argument %q", key, arg)
+		}
+		name, v, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		f, ok := verifyToolFlags[key][name]
+		if !ok {
+			return fmt.Errorf("refusing to rebuild with %s flag -%s", key, name)
+		}
+		if f.hasValue && !hasValue {
+			if i+1 == len(args) {
+				return fmt.Errorf("invalid %s: missing value for -%s", key, name)
+			}
+			i++
+			v = args[i]
+		}
+		if f.values != nil && !slices.Contains(f.values, v) {
+			return fmt.Errorf("refusing to rebuild with %s flag -%s=%s", key, name, v)
+		}
+	}
+	return nil
+}
+
+// validTag reports whether tag is a valid build tag.
+func validTag(tag string) bool {
+	if tag == "" {
+		return false
+	}
+	for _, c := range tag {
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '_' || c == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+// diffSections returns the names of the sections that differ
+// between the object files a and b.
+func diffSections(a, b string) ([]string, error) {
+	sa, err := sectionHashes(a)
+	if err != nil {
+		return nil, err
+	}
+	sb, err := sectionHashes(b)
+	if err != nil {
+		return nil, err
+	}
+	var diff []string
+	for name, h := range sa {
+		if hb, ok := sb[name]; !ok || hb != h {
+			diff = append(diff, name)
+		}
+	}
+	for name := range sb {
+		if _, ok := sa[name]; !ok {
+			diff = append(diff, name)
+		}
+	}
+	slices.Sort(diff)
+	return diff, nil
+}
+
+// sectionHashes returns the hashes of the contents of the sections of
+// the ELF, Mach-O, or PE file, by section name.
+func sectionHashes(file string) (map[string][sha256.Size]byte, error) {
+	hashes := make(map[string][sha256.Size]byte)
+	add := func(name string, data []byte, err error) error {
+		if err != nil {
+			return fmt.Errorf("section %s: %v", name, err)
+		}
+		hashes[name] = sha256.Sum256(data)
+		return nil
+	}
+	if f, err := elf.Open(file); err == nil {
+		defer f.Close()
+		for _, s := range f.Sections {
+			if s.Type == elf.SHT_NOBITS || s.Type == elf.SHT_NULL {
+				continue
+			}
+			data, err := s.Data()
+			if err := add(s.Name, data, err); err != nil {
+				return nil, err
+			}
+		}
+		return hashes, nil
+	}
+	if f, err := macho.Open(file); err == nil {
+		defer f.Close()
+		for _, s := range f.Sections {
+			if s.Offset == 0 {
+				continue // zero fill
+			}
+			data, err := s.Data()
+			if err := add(s.Seg+","+s.Name, data, err); err != nil {
+				return nil, err
+			}
+		}
+		return hashes, nil
+	}
+	if f, err := pe.Open(file); err == nil {
+		defer f.Close()
+		for _, s := range f.Sections {
+			data, err := s.Data()
+			if err := add(s.Name, data, err); err != nil {
+				return nil, err
+			}
+		}
+		return hashes, nil
+	}
+	return nil, fmt.Errorf("unrecognized object file format")
+}
+
+// diffLines returns the lines of a missing from b, prefixed by "-",
+// followed by the lines of b missing from a, prefixed by "+".
+func diffLines(a, b string) []string {
+	la := strings.Split(strings.TrimSuffix(a, "\n"), "\n")
+	lb := strings.Split(strings.TrimSuffix(b, "\n"), "\n")
+	var diff []string
+	for _, line := range la {
+		if !slices.Contains(lb, line) {
+			diff = append(diff, "- "+strings.ReplaceAll(line, "\t", " "))
+		}
+	}
+	for _, line := range lb {
+		if !slices.Contains(la, line) {
+			diff = append(diff, "+ "+strings.ReplaceAll(line, "\t", " "))
+		}
+	}
+	return diff
+}
@@ -21,7 +21,7 @@ import (
 )
 
 var CmdVersion = &base.Command{
-	UsageLine: "go version [-m] [-v] [file ...]",
+	UsageLine: "go version [-m [-format=format]] [-v] [-verify] [file ...]",
 	Short:     "print Go version",
 	Long: `Version prints the build information for Go binary files.
 
@@ -40,6 +40,35 @@ module version information, when available. In the output, the module
 information consists of multiple lines following the version line, each
 indented by a leading tab character.
 
+The -format flag, which requires -m, causes go version to print the
+module information of each file as a software bill of materials (SBOM)
+instead, in the given format: "spdx-json" for an SPDX 2.3 document or
+"cyclonedx-json" for a CycloneDX 1.5 document, both in JSON. The default
+format, "text", is the one described above. The SBOM lists the main
+module, the dependency modules, and the standard library, and, for files
+built by Go 1.24 or later with the -buildpkgs build flag, the packages
+of each module built into the file, as recorded by the go command from
+the import graph of the main package. See 'go doc runtime/debug.BuildInfo.WriteSBOM' for details.
+
+The -verify flag causes go version to check that each file can be
+reproduced from the information embedded in it: go version runs
+'go install path@version' for the main package and module version
+recorded in the file, in a temporary directory, with the recorded build
+flags and environment settings such as GOOS, GOARCH and CGO_ENABLED,
+and with the recorded Go toolchain, which is downloaded if needed (see
+'go help toolchain'). It then compares the rebuilt binary with the file
+and, if they differ, reports the names of the object file sections that
+differ and the differences in build information. Files built from a
+module without a version, from replaced modules, or with settings that
+cannot be reproduced, such as a -pgo profile other than default.pgo,
+cannot be verified. Neither can files whose recorded settings could make
+the rebuild run other programs or use a different toolchain, such as
+-ldflags=-extld=prog or a CGO_CFLAGS value that is not allowed in #cgo
+directives. Note that build information does not record all the
+inputs of a build: in particular, a build using cgo depends on the C
+toolchain and libraries, and -ldflags is omitted when -trimpath is set.
+go version exits with a non-zero status if any file cannot be verified.
+
 See also: go doc runtime/debug.BuildInfo.
 `,
 }
@@ -50,8 +79,10 @@ func init() {
 }
 
 var (
-	versionM = CmdVersion.Flag.Bool("m", false, "")
-	versionV = CmdVersion.Flag.Bool("v", false, "")
+	versionM      = CmdVersion.Flag.Bool("m", false, "")
+	versionV      = CmdVersion.Flag.Bool("v", false, "")
+	versionVerify = CmdVersion.Flag.Bool("verify", false, "")
+	versionFormat = CmdVersion.Flag.String("format", "text", "")
 )
 
 func runVersion(ctx context.Context, cmd *base.Command, args []string) {
@@ -68,6 +99,10 @@ func runVersion(ctx context.Context, cmd *base.Command, args []string) {
 			argOnlyFlag = "-m"
 		} else if !base.InGOFLAGS("-v") && *versionV {
 			argOnlyFlag = "-v"
+		} else if !base.InGOFLAGS("-verify") && *versionVerify {
+			argOnlyFlag = "-verify"
+		} else if !base.InGOFLAGS("-format") && *versionFormat != "text" {
+			argOnlyFlag = "-format"
 		}
 		if argOnlyFlag != "" {
 			fmt.Fprintf(os.Stderr, "go: 'go version' only accepts %s flag with arguments\n", argOnlyFlag)
@@ -82,6 +117,19 @@ func runVersion(ctx context.Context, cmd *base.Command, args []string) {
 		return
 	}
 
+	switch *versionFormat {
+	case "text":
+	case "spdx-json", "cyclonedx-json":
+		if !*versionM {
+			base.Fatalf("go: -format=%s requires -m", *versionFormat)
+		}
+		if *versionVerify {
+			base.Fatalf("go: -format=%s cannot be used with -verify", *versionFormat)
+		}
+	default:
+		base.Fatalf("go: invalid -format=%s: must be text, spdx-json, or cyclonedx-json", *versionFormat)
+	}
+
 	for _, arg := range args {
 		info, err := os.Stat(arg)
 		if err != nil {
@@ -164,10 +212,23 @@ func scanFile(file string, info fs.FileInfo, mustPrint bool) {
 		return
 	}
 
+	if *versionFormat != "text" {
+		if err := bi.WriteSBOM(os.Stdout, *versionFormat); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", file, err)
+			base.SetExitStatus(1)
+		}
+		return
+	}
+
 	fmt.Printf("%s: %s\n", file, bi.GoVersion)
-	bi.GoVersion = "" // suppress printing go version again
-	mod := bi.String()
+	text := *bi
+	text.GoVersion = "" // suppress printing go version again
+	text.Packages = nil // listed only in SBOMs
+	mod := text.String()
 	if *versionM && len(mod) > 0 {
 		fmt.Printf("\t%s\n", strings.ReplaceAll(mod[:len(mod)-1], "\n", "\n\t"))
 	}
+	if *versionVerify {
+		verifyFile(file, bi)
+	}
 }
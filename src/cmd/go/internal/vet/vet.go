@@ -42,6 +42,11 @@ For example, the 'shadow' analyzer can be built and run using these commands:
   go install golang.org/x/tools/go/analysis/passes/shadow/cmd/shadow@latest
   go vet -vettool=$(which shadow)
 
+The -buildjson flag causes vet to report its progress and diagnostics as
+a stream of JSON events on standard output, as for 'go build -json'.
+See 'go help buildjson' for the format. It is separate from the -json flag,
+which is passed to the analysis tool and leaves the vet output unchanged.
+
 The build flags supported by go vet are those that control package resolution
 and execution, such as -C, -n, -x, -v, -tags, and -toolexec.
 For more about these flags, see 'go help build'.
@@ -17,6 +17,7 @@ import (
 	"strings"
 
 	"cmd/go/internal/base"
+	"cmd/go/internal/cfg"
 	"cmd/go/internal/cmdflag"
 	"cmd/go/internal/work"
 )
@@ -40,6 +41,7 @@ var vetTool string // -vettool
 func init() {
 	work.AddBuildFlags(CmdVet, work.DefaultBuildFlags)
 	CmdVet.Flag.StringVar(&vetTool, "vettool", "", "")
+	CmdVet.Flag.BoolVar(&cfg.BuildJSON, "buildjson", false, "")
 }
 
 func parseVettoolFlag(args []string) {
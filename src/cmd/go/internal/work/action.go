@@ -111,6 +111,7 @@ type Action struct {
 	pending      int               // number of deps yet to complete
 	priority     int               // relative execution priority
 	Failed       bool              // whether the action failed
+	cached       bool              // whether the result came from the build cache
 	json         *actionJSON       // action graph information
 	nonGoOverlay map[string]string // map from non-.go source files to copied files in objdir. Nil if no overlay is used.
 	traceSpan    *trace.Span
@@ -190,6 +191,18 @@ type cacheKey struct {
 }
 
 func actionGraphJSON(a *Action) string {
+	list := actionGraph(a)
+	js, err := json.MarshalIndent(list, "", "\t")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "go: writing debug action graph: %v\n", err)
+		return ""
+	}
+	return string(js)
+}
+
+// actionGraph numbers the actions in the graph rooted at a, allocating
+// their action graph information, and returns that information.
+func actionGraph(a *Action) []*actionJSON {
 	var workq []*Action
 	var inWorkq = make(map[*Action]int)
 
@@ -235,13 +248,7 @@ func actionGraphJSON(a *Action) string {
 		}
 		list = append(list, a.json)
 	}
-
-	js, err := json.MarshalIndent(list, "", "\t")
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "go: writing debug action graph: %v\n", err)
-		return ""
-	}
-	return string(js)
+	return list
 }
 
 // BuildMode specifies the build mode:
@@ -27,7 +27,7 @@ import (
 )
 
 var CmdBuild = &base.Command{
-	UsageLine: "go build [-o output] [build flags] [packages]",
+	UsageLine: "go build [-o output] [-json] [build flags] [packages]",
 	Short:     "compile packages and dependencies",
 	Long: `
 Build compiles the packages named by the import paths,
@@ -59,6 +59,11 @@ in the last two paragraphs. If the named output is an existing directory or
 ends with a slash or backslash, then any resulting executables
 will be written to that directory.
 
+The -json flag causes build to report its progress and the output of the
+compiler, linker and other tools as a stream of JSON events on standard
+output, instead of printing the output as text on standard error.
+See 'go help buildjson' for the format.
+
 The build flags are shared by the build, clean, get, install, list, run,
 and test commands:
 
@@ -119,6 +124,12 @@ and test commands:
 		arguments to pass on each go tool asm invocation.
 	-buildmode mode
 		build mode to use. See 'go help buildmode' for more.
+	-buildpkgs
+		stamp binaries with the import paths of the packages built into
+		them, other than those of the standard library, so that
+		'go version -m -format' can list them in a software bill of
+		materials. This adds one line per package, typically a few
+		kilobytes, to the build information of the binary.
 	-buildvcs
 		Whether to stamp binaries with version control information
 		("true", "false", or "auto"). By default ("auto"), version control
@@ -161,6 +172,15 @@ and test commands:
 		directory, but it is not accessed. When -modfile is specified, an
 		alternate go.sum file is also used: its path is derived from the
 		-modfile flag by trimming the ".mod" extension and appending ".sum".
+	-optdetails dir
+		write the compiler's optimization decisions for the packages named on
+		the command line to dir, as LSP diagnostics in JSON. The diagnostics
+		for each source file are written to a file in a subdirectory named
+		for the package's URL-escaped import path. They cover inlining,
+		escape analysis (with the chain of assignments that makes a value
+		escape), and the bounds and nil checks that remain after optimization.
+		Packages built with this flag are always recompiled, so that their
+		diagnostics are written.
 	-overlay file
 		read a JSON config file that provides an overlay for build operations.
 		The file is a JSON struct with a single field, named 'Replace', that
@@ -178,6 +198,7 @@ and test commands:
 		build, the go command selects a file named "default.pgo" in the package's
 		directory if that file exists, and applies it to the (transitive)
 		dependencies of the main package (other packages are not affected).
+		The linker also uses the profile to place hot functions together.
 		Special name "off" turns off PGO. The default is "auto".
 	-pkgdir dir
 		install and load all packages from dir instead of the usual locations.
@@ -240,6 +261,8 @@ func init() {
 	CmdInstall.Run = runInstall
 
 	CmdBuild.Flag.StringVar(&cfg.BuildO, "o", "", "output file or directory")
+	CmdBuild.Flag.BoolVar(&cfg.BuildJSON, "json", false, "")
+	CmdInstall.Flag.BoolVar(&cfg.BuildJSON, "json", false, "")
 
 	AddBuildFlags(CmdBuild, DefaultBuildFlags)
 	AddBuildFlags(CmdInstall, DefaultBuildFlags)
@@ -331,6 +354,7 @@ func AddBuildFlags(cmd *base.Command, mask BuildFlagMask) {
 	cmd.Flag.StringVar(&cfg.BuildContext.InstallSuffix, "installsuffix", "", "")
 	cmd.Flag.Var(&load.BuildLdflags, "ldflags", "")
 	cmd.Flag.BoolVar(&cfg.BuildLinkshared, "linkshared", false, "")
+	cmd.Flag.StringVar(&cfg.BuildOptDetails, "optdetails", "", "")
 	cmd.Flag.StringVar(&cfg.BuildPGO, "pgo", "auto", "")
 	cmd.Flag.StringVar(&cfg.BuildPkgdir, "pkgdir", "", "")
 	cmd.Flag.BoolVar(&cfg.BuildRace, "race", false, "")
@@ -341,6 +365,7 @@ func AddBuildFlags(cmd *base.Command, mask BuildFlagMask) {
 	cmd.Flag.BoolVar(&cfg.BuildTrimpath, "trimpath", false, "")
 	cmd.Flag.BoolVar(&cfg.BuildWork, "work", false, "")
 	cmd.Flag.Var((*buildvcsFlag)(&cfg.BuildBuildvcs), "buildvcs", "")
+	cmd.Flag.BoolVar(&cfg.BuildBuildpkgs, "buildpkgs", false, "")
 
 	// Undocumented, unstable debugging flags.
 	cmd.Flag.StringVar(&cfg.DebugActiongraph, "debug-actiongraph", "", "")
@@ -560,7 +585,7 @@ func runBuild(ctx context.Context, cmd *base.Command, args []string) {
 }
 
 var CmdInstall = &base.Command{
-	UsageLine: "go install [build flags] [packages]",
+	UsageLine: "go install [-json] [build flags] [packages]",
 	Short:     "compile and install packages and dependencies",
 	Long: `
 Install compiles and installs the packages named by the import paths.
@@ -614,6 +639,9 @@ Starting in Go 1.20, the standard library is built and cached but not installed.
 Setting GODEBUG=installgoroot=all restores the use of
 $GOROOT/pkg/$GOOS_$GOARCH.
 
+The -json flag reports the progress and output of the build as a stream
+of JSON events on standard output, as for 'go build -json'.
+
 For more about build flags, see 'go help build'.
 
 For more about specifying packages, see 'go help packages'.
@@ -459,10 +459,24 @@ func (b *Builder) useCache(a *Action, actionHash cache.ActionID, target string,
 		return false
 	}
 
+	// Likewise for packages whose optimization details are requested
+	// with -optdetails, because the compiler only writes them when it runs.
+	if cfg.BuildOptDetails != "" && a.Mode == "build" {
+		if p := a.Package; p != nil && p.Internal.CmdlinePkg {
+			if !p.Stale {
+				p.Stale = true
+				p.StaleReason = "build -optdetails flag in use"
+			}
+			a.output = []byte{}
+			return false
+		}
+	}
+
 	defer func() {
 		// Increment counters for cache hits and misses based on the return value
 		// of this function. Don't increment counters if we return early because of
 		// cfg.BuildA above because we don't even look at the cache in that case.
+		a.cached = ok
 		if ok {
 			counterCacheHit.Inc()
 		} else {
@@ -0,0 +1,186 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// JSON build output (-json flag).
+
+package work
+
+import (
+	"encoding/json"
+	"internal/lazyregexp"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"cmd/go/internal/cfg"
+)
+
+// A buildEvent is a single event in the output of the -json build flag.
+// See 'go help buildjson' for a description of the fields.
+type buildEvent struct {
+	Time       time.Time
+	Action     string
+	ID         int
+	Mode       string
+	ImportPath string  `json:",omitempty"`
+	ActionID   string  `json:",omitempty"`
+	Cached     bool    `json:",omitempty"`
+	Elapsed    float64 `json:",omitempty"`
+	Output     string  `json:",omitempty"`
+	File       string  `json:",omitempty"`
+	Line       int     `json:",omitempty"`
+	Col        int     `json:",omitempty"`
+	Message    string  `json:",omitempty"`
+	Analyzer   string  `json:",omitempty"`
+}
+
+// buildJSON serializes the events written to standard output.
+var buildJSON struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// emitEvent writes the event ev about action a to standard output.
+// The action graph must have been numbered by actionGraph.
+func emitEvent(a *Action, ev *buildEvent) {
+	ev.Time = time.Now()
+	ev.Mode = a.Mode
+	if a.json != nil {
+		ev.ID = a.json.ID
+		ev.ActionID = a.json.ActionID
+	}
+	if a.Package != nil {
+		ev.ImportPath = a.Package.ImportPath
+	}
+
+	buildJSON.mu.Lock()
+	defer buildJSON.mu.Unlock()
+	if buildJSON.enc == nil {
+		buildJSON.enc = json.NewEncoder(os.Stdout)
+	}
+	buildJSON.enc.Encode(ev)
+}
+
+// emitOutput writes an output event for the text out printed by action a,
+// followed by a diagnostic event for each diagnostic found in out.
+func emitOutput(a *Action, out string) {
+	if out == "" {
+		return
+	}
+	emitEvent(a, &buildEvent{Action: "output", Output: out})
+	for _, d := range parseDiagnostics(out) {
+		emitEvent(a, &d)
+	}
+}
+
+// emitDone writes the pass or fail event that ends action a,
+// which started at start.
+func emitDone(a *Action, start time.Time) {
+	ev := &buildEvent{
+		Action:  "pass",
+		Cached:  a.cached,
+		Elapsed: time.Since(start).Round(time.Millisecond).Seconds(),
+	}
+	if a.Failed {
+		ev.Action = "fail"
+	}
+	emitEvent(a, ev)
+}
+
+// diagRE matches a diagnostic in the usual file:line:col: message form,
+// as printed by the compiler, assembler, cgo and vet. The column is optional.
+var diagRE = lazyregexp.New(`^(.+?\.[A-Za-z0-9]+):([0-9]+)(?::([0-9]+))?: (.*)$`)
+
+// parseDiagnostics returns diagnostic events for the diagnostics in out,
+// the text printed by a tool. Lines starting with a tab continue the
+// previous diagnostic. Out may also be the report of a vet tool run with
+// its own -json flag, in which case the diagnostics carry the name of the
+// analyzer that reported them.
+func parseDiagnostics(out string) []buildEvent {
+	var diags []buildEvent
+	var text []string
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasPrefix(line, "#") {
+			// Header naming the package or command.
+			continue
+		}
+		text = append(text, line)
+	}
+	if js := strings.TrimSpace(strings.Join(text, "\n")); strings.HasPrefix(js, "{") {
+		if diags, ok := parseVetJSON(js); ok {
+			return diags
+		}
+	}
+
+	for _, line := range text {
+		if strings.HasPrefix(line, "\t") && len(diags) > 0 {
+			d := &diags[len(diags)-1]
+			d.Message += "\n" + strings.TrimPrefix(line, "\t")
+			continue
+		}
+		m := diagRE.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		d := buildEvent{Action: "diagnostic", File: m[1], Message: m[4]}
+		d.Line, _ = strconv.Atoi(m[2])
+		d.Col, _ = strconv.Atoi(m[3])
+		diags = append(diags, d)
+	}
+	return diags
+}
+
+// parseVetJSON parses the output of 'go tool vet -json', which maps each
+// package to the diagnostics of each analyzer, and reports whether it could.
+func parseVetJSON(js string) (diags []buildEvent, ok bool) {
+	dec := json.NewDecoder(strings.NewReader(js))
+	for dec.More() {
+		var tree map[string]map[string]json.RawMessage
+		if err := dec.Decode(&tree); err != nil {
+			return nil, false
+		}
+		for _, analyzers := range tree {
+			names := make([]string, 0, len(analyzers))
+			for name := range analyzers {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				var list []struct {
+					Posn    string `json:"posn"`
+					Message string `json:"message"`
+				}
+				if err := json.Unmarshal(analyzers[name], &list); err != nil {
+					// An analyzer that failed reports {"error": "..."}.
+					var e struct {
+						Err string `json:"error"`
+					}
+					if json.Unmarshal(analyzers[name], &e) != nil {
+						return nil, false
+					}
+					diags = append(diags, buildEvent{Action: "diagnostic", Analyzer: name, Message: e.Err})
+					continue
+				}
+				for _, v := range list {
+					d := buildEvent{Action: "diagnostic", Analyzer: name, Message: v.Message}
+					if m := diagRE.FindStringSubmatch(v.Posn + ": "); m != nil {
+						d.File = m[1]
+						d.Line, _ = strconv.Atoi(m[2])
+						d.Col, _ = strconv.Atoi(m[3])
+					}
+					diags = append(diags, d)
+				}
+			}
+		}
+	}
+	return diags, true
+}
+
+// jsonAction reports whether action a reports events in -json mode.
+func jsonAction(a *Action) bool {
+	return cfg.BuildJSON && a.Actor != nil
+}
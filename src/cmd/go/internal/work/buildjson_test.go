@@ -0,0 +1,63 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package work
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDiagnostics(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		out  string
+		want []buildEvent
+	}{
+		{"", nil},
+		{"# p\nsome text\n", nil},
+		{
+			"# p\n./a.go:3:9: undefined: x\nb.s:12: bad instruction\n",
+			[]buildEvent{
+				{Action: "diagnostic", File: "./a.go", Line: 3, Col: 9, Message: "undefined: x"},
+				{Action: "diagnostic", File: "b.s", Line: 12, Message: "bad instruction"},
+			},
+		},
+		{
+			"# p\n./a.go:3:9: cannot use x\n\thave int\n\twant string\n",
+			[]buildEvent{
+				{Action: "diagnostic", File: "./a.go", Line: 3, Col: 9, Message: "cannot use x\nhave int\nwant string"},
+			},
+		},
+		{
+			"# p\nmain.main: relocation target p.f not defined\n",
+			nil,
+		},
+		{
+			`# p
+# [p]
+{
+	"p": {
+		"printf": [
+			{
+				"posn": "/src/p/a.go:5:15",
+				"message": "bad format"
+			}
+		],
+		"assign": {"error": "failed"}
+	}
+}
+`,
+			[]buildEvent{
+				{Action: "diagnostic", Analyzer: "assign", Message: "failed"},
+				{Action: "diagnostic", Analyzer: "printf", File: "/src/p/a.go", Line: 5, Col: 15, Message: "bad format"},
+			},
+		},
+	}
+	for _, test := range tests {
+		if got := parseDiagnostics(test.out); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("parseDiagnostics(%q) = %+v, want %+v", test.out, got, test.want)
+		}
+	}
+}
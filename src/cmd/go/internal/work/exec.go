@@ -116,6 +116,11 @@ func (b *Builder) Do(ctx context.Context, root *Action) {
 		}
 	}
 	writeActionGraph()
+	if cfg.BuildJSON {
+		// Number the actions so that events identify them as the action
+		// graph does.
+		actionGraph(root)
+	}
 
 	b.readySema = make(chan bool, len(all))
 
@@ -134,11 +139,17 @@ func (b *Builder) Do(ctx context.Context, root *Action) {
 	// Handle runs a single action and takes care of triggering
 	// any actions that are runnable as a result.
 	handle := func(ctx context.Context, a *Action) {
+		start := time.Now()
 		if a.json != nil {
-			a.json.TimeStart = time.Now()
+			a.json.TimeStart = start
 		}
 		var err error
+		ran := false
 		if a.Actor != nil && (!a.Failed || a.IgnoreFail) {
+			ran = true
+			if jsonAction(a) {
+				emitEvent(a, &buildEvent{Action: "start"})
+			}
 			// TODO(matloob): Better action descriptions
 			desc := "Executing action (" + a.Mode
 			if a.Package != nil {
@@ -168,6 +179,11 @@ func (b *Builder) Do(ctx context.Context, root *Action) {
 					a.Package.Error = &load.PackageError{Err: err}
 					a.Package.Incomplete = true
 				}
+			} else if jsonAction(a) {
+				// Report the error as output of the action rather than
+				// on standard error.
+				emitOutput(a, strings.TrimSuffix(err.Error(), "\n")+"\n")
+				base.SetExitStatus(1)
 			} else {
 				var ipe load.ImportPathError
 				if a.Package != nil && (!errors.As(err, &ipe) || ipe.ImportPath() != a.Package.ImportPath) {
@@ -177,6 +193,9 @@ func (b *Builder) Do(ctx context.Context, root *Action) {
 			}
 			a.Failed = true
 		}
+		if ran && jsonAction(a) {
+			emitDone(a, start)
+		}
 
 		for _, a0 := range a.triggers {
 			if a.Failed {
@@ -1326,6 +1345,7 @@ func (b *Builder) vet(ctx context.Context, a *Action) error {
 		c := cache.Default()
 		if file, _, err := cache.GetFile(c, key); err == nil {
 			a.built = file
+			a.cached = true
 			return nil
 		}
 	}
@@ -118,6 +118,9 @@ func (gcToolchain) gc(b *Builder, a *Action, archive string, importcfg, embedcfg
 	if pgoProfile != "" {
 		defaultGcFlags = append(defaultGcFlags, "-pgoprofile="+pgoProfile)
 	}
+	if cfg.BuildOptDetails != "" && p.Internal.CmdlinePkg {
+		defaultGcFlags = append(defaultGcFlags, "-json=0,"+cfg.BuildOptDetails)
+	}
 	if symabis != "" {
 		defaultGcFlags = append(defaultGcFlags, "-symabis", symabis)
 	}
@@ -650,6 +653,19 @@ func (gcToolchain) ld(b *Builder, root *Action, targetPath, importcfg, mainpkg s
 	if root.buildID != "" {
 		ldflags = append(ldflags, "-buildid="+root.buildID)
 	}
+	// The linker lays out the text using the PGO profile of the main
+	// package. The profile already contributes to the action ID
+	// through the build ID of the main package.
+	for _, a1 := range root.Deps {
+		if a1.Package != root.Package {
+			continue
+		}
+		for _, a2 := range a1.Deps {
+			if a2.Mode == "preprocess PGO profile" {
+				ldflags = append(ldflags, "-pgoprofile="+a2.built)
+			}
+		}
+	}
 	ldflags = append(ldflags, forcedLdflags...)
 	ldflags = append(ldflags, root.Package.Internal.Ldflags...)
 	ldflags, err := setextld(ldflags, compiler)
@@ -52,6 +52,17 @@ func BuildInit() {
 		cfg.BuildPkgdir = p
 	}
 
+	// Likewise for -optdetails.
+	if cfg.BuildOptDetails != "" && !filepath.IsAbs(cfg.BuildOptDetails) {
+		p, err := filepath.Abs(cfg.BuildOptDetails)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "go: evaluating -optdetails: %v\n", err)
+			base.SetExitStatus(2)
+			base.Exit()
+		}
+		cfg.BuildOptDetails = p
+	}
+
 	if cfg.BuildP <= 0 {
 		base.Fatalf("go: -p must be a positive integer: %v\n", cfg.BuildP)
 	}
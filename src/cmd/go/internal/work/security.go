@@ -250,6 +250,18 @@ func checkLinkerFlags(name, source string, list []string) error {
 	return checkFlags(name, source, list, invalidLinkerFlags, validLinkerFlags, validLinkerFlagsWithNextArg, checkOverrides)
 }
 
+// CheckCgoEnvFlags returns an error if list, the flags in the
+// environment variable CGO_name, contains a flag that is not allowed in
+// #cgo directives. The go command trusts the flags in its environment,
+// but 'go version -verify' must not trust those recorded in a binary.
+func CheckCgoEnvFlags(name string, list []string) error {
+	source := "$CGO_" + name
+	if name == "LDFLAGS" {
+		return checkLinkerFlags(name, source, list)
+	}
+	return checkCompilerFlags(name, source, list)
+}
+
 // checkCompilerFlagsForInternalLink returns an error if 'list'
 // contains a flag or flags that may not be fully supported by
 // internal linking (meaning that we should punt the link to the
@@ -68,7 +68,14 @@ func NewShell(workDir string, print func(a ...any) (int, error)) *Shell {
 
 // Print emits a to this Shell's output stream, formatting it like fmt.Print.
 // It is safe to call concurrently.
+//
+// With the -json build flag, the output of a Shell bound to an Action is
+// reported as events of that Action instead.
 func (sh *Shell) Print(a ...any) {
+	if sh.action != nil && jsonAction(sh.action) {
+		emitOutput(sh.action, fmt.Sprint(a...))
+		return
+	}
 	sh.printLock.Lock()
 	defer sh.printLock.Unlock()
 	sh.printFunc(a...)
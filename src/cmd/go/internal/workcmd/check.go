@@ -0,0 +1,161 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// go work check
+
+package workcmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"cmd/go/internal/base"
+	"cmd/go/internal/gover"
+	"cmd/go/internal/imports"
+	"cmd/go/internal/modload"
+	"cmd/go/internal/toolchain"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+var cmdCheck = &base.Command{
+	UsageLine: "go work check",
+	Short:     "check that workspace modules build on their own",
+	Long: `Check reports the requirements of the workspace's modules that
+the workspace overrides, so that a module that builds and passes its
+tests in the workspace might not on its own, once released.
+
+For each workspace module, check loads the packages in "all", as 'go work
+sync' does, and reports each module providing one of those packages for
+which the workspace module's go.mod file disagrees with the workspace:
+
+  - another workspace module that the go.mod file does not require;
+  - a module that the go.mod file requires at a lower version than the
+    version selected in the workspace, or, if the go.mod file declares
+    go 1.17 or higher, does not require at all;
+  - a module replaced by the go.work file but not by the go.mod file.
+
+Check exits with a non-zero status if it reports any problem. Running
+'go work sync' fixes the problems of the second kind.
+
+See the workspaces reference at https://go.dev/ref/mod#workspaces
+for more information.
+`,
+	Run: runCheck,
+}
+
+func init() {
+	base.AddChdirFlag(&cmdCheck.Flag)
+	base.AddModCommonFlags(&cmdCheck.Flag)
+}
+
+func runCheck(ctx context.Context, cmd *base.Command, args []string) {
+	modload.ForceUseModules = true
+	modload.InitWorkfile()
+	if modload.WorkFilePath() == "" {
+		base.Fatalf("go: no go.work file found\n\t(run 'go work init' first or specify path using GOWORK environment variable)")
+	}
+	if len(args) > 0 {
+		base.Fatalf("go: 'go work check' accepts no arguments")
+	}
+
+	if _, err := modload.LoadModGraph(ctx, ""); err != nil {
+		toolchain.SwitchOrFatal(ctx, err)
+	}
+
+	mms := modload.MainModules
+	opts := modload.PackageOpts{
+		Tags:                     imports.AnyTags(),
+		VendorModulesInGOROOTSrc: true,
+		ResolveMissingImports:    false,
+		LoadTests:                true,
+		AllowErrors:              true,
+		SilencePackageErrors:     true,
+		SilenceUnmatchedWarnings: true,
+	}
+	problems, sync := 0, false
+	for _, m := range mms.Versions() {
+		mf := mms.ModFile(m)
+		if mf == nil {
+			continue
+		}
+		opts.MainModule = m
+		_, pkgs := modload.LoadPackages(ctx, opts, "all")
+		opts.MainModule = module.Version{} // reset
+
+		var used []module.Version
+		seen := map[module.Version]bool{m: true}
+		for _, pkg := range pkgs {
+			if r := modload.PackageModule(pkg); r.Path != "" && !seen[r] {
+				used = append(used, r)
+				seen[r] = true
+			}
+		}
+		gover.ModSort(used) // ensure determinism
+
+		report := func(format string, args ...any) {
+			fmt.Fprintf(os.Stderr, "go: %s: %s\n", m.Path, fmt.Sprintf(format, args...))
+			problems++
+		}
+		for _, r := range used {
+			required := requiredVersion(mf, r.Path)
+			if mms.Contains(r.Path) {
+				if required == "" {
+					report("uses workspace module %s, but go.mod does not require it", r.Path)
+				}
+				continue
+			}
+			switch {
+			case required == "" && mf.Go != nil && gover.Compare(mf.Go.Version, gover.ExplicitIndirectVersion) >= 0:
+				report("uses %s@%s from the workspace, but go.mod does not require it", r.Path, r.Version)
+				sync = true
+			case required != "" && gover.ModCompare(r.Path, required, r.Version) < 0:
+				report("requires %s@%s, but the workspace selects %s", r.Path, required, r.Version)
+				sync = true
+			}
+			if isReplaced(mms.WorkFileReplaceMap(), r) && !isReplacedIn(mf, r) {
+				report("uses %s@%s, replaced by go.work but not by go.mod", r.Path, r.Version)
+			}
+		}
+	}
+
+	if problems > 0 {
+		if sync {
+			fmt.Fprintf(os.Stderr, "go: to update the go.mod files of the workspace modules, run:\n\tgo work sync\n")
+		}
+		base.SetExitStatus(1)
+	}
+}
+
+// requiredVersion returns the version of the module with the given path
+// required by the go.mod file mf, or "" if none.
+func requiredVersion(mf *modfile.File, path string) string {
+	for _, r := range mf.Require {
+		if r.Mod.Path == path {
+			return r.Mod.Version
+		}
+	}
+	return ""
+}
+
+// isReplaced reports whether module m is replaced in the replacement map.
+func isReplaced(replaces map[module.Version]module.Version, m module.Version) bool {
+	if _, ok := replaces[m]; ok {
+		return true
+	}
+	_, ok := replaces[module.Version{Path: m.Path}]
+	return ok
+}
+
+// isReplacedIn reports whether module m is replaced by the go.mod file mf.
+func isReplacedIn(mf *modfile.File, m module.Version) bool {
+	for _, r := range mf.Replace {
+		if r.Old.Path == m.Path && (r.Old.Version == "" || r.Old.Version == m.Version) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// go work graph
+
+package workcmd
+
+import (
+	"bufio"
+	"context"
+	"os"
+
+	"cmd/go/internal/base"
+	"cmd/go/internal/modload"
+	"cmd/go/internal/toolchain"
+)
+
+var cmdGraph = &base.Command{
+	UsageLine: "go work graph",
+	Short:     "print requirement graph of workspace modules",
+	Long: `Graph prints the requirements among the modules of the workspace,
+as listed in their go.mod files, in text form. Each line in the output
+has two space-separated fields: a workspace module and a workspace module
+that it requires, identified as a string of the form path@version, with
+the version required by the go.mod file. A workspace module that requires
+no other workspace module is printed on a line by itself.
+
+In the workspace, the requirements printed by graph are satisfied by the
+workspace modules themselves, whatever their required versions. Use
+'go work check' to find the requirements of workspace modules that the
+workspace overrides.
+
+See the workspaces reference at https://go.dev/ref/mod#workspaces
+for more information.
+`,
+	Run: runGraph,
+}
+
+func init() {
+	base.AddChdirFlag(&cmdGraph.Flag)
+	base.AddModCommonFlags(&cmdGraph.Flag)
+}
+
+func runGraph(ctx context.Context, cmd *base.Command, args []string) {
+	modload.ForceUseModules = true
+	modload.InitWorkfile()
+	if modload.WorkFilePath() == "" {
+		base.Fatalf("go: no go.work file found\n\t(run 'go work init' first or specify path using GOWORK environment variable)")
+	}
+	if len(args) > 0 {
+		base.Fatalf("go: 'go work graph' accepts no arguments")
+	}
+
+	if _, err := modload.LoadModGraph(ctx, ""); err != nil {
+		toolchain.SwitchOrFatal(ctx, err)
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+
+	mms := modload.MainModules
+	for _, m := range mms.Versions() {
+		mf := mms.ModFile(m)
+		if mf == nil {
+			continue
+		}
+		n := 0
+		for _, r := range mf.Require {
+			if mms.Contains(r.Mod.Path) {
+				w.WriteString(m.Path + " " + r.Mod.Path + "@" + r.Mod.Version + "\n")
+				n++
+			}
+		}
+		if n == 0 {
+			w.WriteString(m.Path + "\n")
+		}
+	}
+}
@@ -70,7 +70,9 @@ used.
 `,
 
 	Commands: []*base.Command{
+		cmdCheck,
 		cmdEdit,
+		cmdGraph,
 		cmdInit,
 		cmdSync,
 		cmdUse,
@@ -0,0 +1,564 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Cacheprog is a reference implementation of a GOCACHEPROG program:
+// a go command build cache stored in a local directory and, optionally,
+// shared with other machines through an HTTP server.
+//
+// Usage:
+//
+//	GOCACHEPROG="cacheprog -dir DIR [-remote URL] [-stream]"
+//	cacheprog -serve ADDR -dir DIR [-addrfile FILE]
+//
+// In its first form, cacheprog speaks the protocol documented in the
+// cmd/go/internal/cacheprog package. It stores the cache in DIR.
+// If -remote is set, objects missing from DIR are fetched from the HTTP
+// server at URL, and objects put in the cache are uploaded to it.
+// If URL starts with '@', the URL is read from the named file instead,
+// waiting for the file to be written. If -stream is set, hits are
+// streamed to the go command instead of being read from DIR.
+//
+// In its second form, cacheprog serves the cache stored in DIR over HTTP
+// on ADDR, for use by -remote. If -addrfile is set, the URL of the server
+// is written to FILE once it is listening. The server is a sketch: it
+// trusts its clients, never deletes objects, and does no authentication.
+//
+// The layout of DIR, which is also the path of each entry on the server,
+// is a/XX/ACTIONID for the JSON-encoded entry of each action and
+// o/XX/OUTPUTID for each object, where XX is the first byte of the ID.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// These types mirror those of cmd/go/internal/cacheprog,
+// which cannot be imported from outside cmd/go.
+
+type Cmd string
+
+type Request struct {
+	ID         int64
+	Command    Cmd
+	ActionID   []byte     `json:",omitempty"`
+	ObjectID   []byte     `json:",omitempty"`
+	BodySize   int64      `json:",omitempty"`
+	AcceptBody bool       `json:",omitempty"`
+	Batch      []*Request `json:",omitempty"`
+
+	body []byte // the body of a put
+}
+
+type Response struct {
+	ID            int64       `json:",omitempty"`
+	Err           string      `json:",omitempty"`
+	KnownCommands []Cmd       `json:",omitempty"`
+	Miss          bool        `json:",omitempty"`
+	OutputID      []byte      `json:",omitempty"`
+	Size          int64       `json:",omitempty"`
+	Time          *time.Time  `json:",omitempty"`
+	DiskPath      string      `json:",omitempty"`
+	BodySize      int64       `json:",omitempty"`
+	Batch         []*Response `json:",omitempty"`
+}
+
+// An entry is the JSON-encoded record of the output of an action.
+type entry struct {
+	OutputID string
+	Size     int64
+	Time     time.Time
+}
+
+var (
+	dir      = flag.String("dir", "", "store the cache in `dir`")
+	remote   = flag.String("remote", "", "share the cache with the HTTP server at `url`")
+	stream   = flag.Bool("stream", false, "stream hits to the go command")
+	serve    = flag.String("serve", "", "serve the cache over HTTP on `addr`")
+	addrFile = flag.String("addrfile", "", "with -serve, write the server URL to `file`")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: cacheprog -dir dir [-remote url] [-stream]\n")
+	fmt.Fprintf(os.Stderr, "       cacheprog -serve addr -dir dir [-addrfile file]\n")
+	os.Exit(2)
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("cacheprog: ")
+	flag.Usage = usage
+	flag.Parse()
+	if *dir == "" || flag.NArg() != 0 {
+		usage()
+	}
+	st := &store{dir: *dir}
+	if *serve != "" {
+		runServer(st)
+		return
+	}
+
+	p := &prog{
+		local: st,
+		w:     bufio.NewWriter(os.Stdout),
+	}
+	if *remote != "" {
+		p.remote = &client{url: *remote}
+	}
+	p.run(os.Stdin)
+}
+
+// A store is a cache stored in a directory.
+type store struct {
+	dir string
+}
+
+func (s *store) path(kind string, id []byte) string {
+	x := hex.EncodeToString(id)
+	return filepath.Join(s.dir, kind, x[:2], x)
+}
+
+// getEntry returns the entry for the action with the given ID,
+// or nil if there is none.
+func (s *store) getEntry(actionID []byte) (*entry, error) {
+	data, err := os.ReadFile(s.path("a", actionID))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	e := new(entry)
+	if err := json.Unmarshal(data, e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (s *store) putEntry(actionID []byte, e *entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return s.write(s.path("a", actionID), bytes.NewReader(data))
+}
+
+// putObject stores the object read from r, checking that its ID is outputID.
+func (s *store) putObject(outputID []byte, r io.Reader) error {
+	h := sha256.New()
+	name := s.path("o", outputID)
+	if _, err := os.Stat(name); err == nil {
+		return nil
+	}
+	if err := s.write(name, io.TeeReader(r, h)); err != nil {
+		return err
+	}
+	if !bytes.Equal(h.Sum(nil), outputID) {
+		os.Remove(name)
+		return fmt.Errorf("object %x has the wrong content", outputID)
+	}
+	return nil
+}
+
+// write writes the file name atomically, so that concurrent readers
+// never observe a partial file.
+func (s *store) write(name string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0777); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(name), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, r)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), name)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
+
+// A prog serves the requests of the go command.
+type prog struct {
+	local  *store
+	remote *client // or nil
+
+	mu sync.Mutex // guards w
+	w  *bufio.Writer
+
+	uploads sync.WaitGroup
+}
+
+func (p *prog) run(stdin io.Reader) {
+	p.write(&Response{KnownCommands: []Cmd{"get", "put", "close", "get-batch", "put-batch"}}, nil)
+
+	var wg sync.WaitGroup
+	dec := json.NewDecoder(bufio.NewReader(stdin))
+	for {
+		req := new(Request)
+		if err := dec.Decode(req); err != nil {
+			if err == io.EOF {
+				break
+			}
+			log.Fatalf("reading request: %v", err)
+		}
+		puts := []*Request{req}
+		if req.Command == "put-batch" {
+			puts = req.Batch
+		}
+		for _, put := range puts {
+			if put.Command == "put" && put.BodySize > 0 {
+				// A base64-encoded JSON string decodes as a []byte.
+				if err := dec.Decode(&put.body); err != nil {
+					log.Fatalf("reading body of request %d: %v", put.ID, err)
+				}
+			}
+		}
+		if req.Command == "close" {
+			wg.Wait()
+			p.uploads.Wait()
+			p.write(&Response{ID: req.ID}, nil)
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.handle(req)
+		}()
+	}
+	wg.Wait()
+}
+
+// handle answers req.
+func (p *prog) handle(req *Request) {
+	res := &Response{ID: req.ID}
+	var bodies []*Response
+	switch req.Command {
+	case "get", "put":
+		res = p.do(req)
+		bodies = append(bodies, res)
+	case "get-batch", "put-batch":
+		for _, req := range req.Batch {
+			r := p.do(req)
+			res.Batch = append(res.Batch, r)
+			bodies = append(bodies, r)
+		}
+	default:
+		res.Err = fmt.Sprintf("unknown command %q", req.Command)
+	}
+	p.write(res, bodies)
+}
+
+// do performs the get or put request req.
+func (p *prog) do(req *Request) *Response {
+	res := &Response{ID: req.ID}
+	var err error
+	switch req.Command {
+	case "get":
+		err = p.get(req, res)
+	case "put":
+		err = p.put(req, res)
+	default:
+		err = fmt.Errorf("unknown command %q in batch", req.Command)
+	}
+	if err != nil {
+		res.Err = err.Error()
+	}
+	return res
+}
+
+func (p *prog) get(req *Request, res *Response) error {
+	e, err := p.local.getEntry(req.ActionID)
+	if err != nil {
+		return err
+	}
+	if e == nil && p.remote != nil {
+		if e, err = p.fetch(req.ActionID); err != nil {
+			// The remote cache is an optimization.
+			// Treat any failure to reach it as a miss.
+			log.Printf("fetching %x: %v", req.ActionID, err)
+			e = nil
+		}
+	}
+	if e == nil {
+		res.Miss = true
+		return nil
+	}
+	outputID, err := hex.DecodeString(e.OutputID)
+	if err != nil {
+		return err
+	}
+	name := p.local.path("o", outputID)
+	if _, err := os.Stat(name); err != nil {
+		res.Miss = true
+		return nil
+	}
+	res.OutputID = outputID
+	res.Size = e.Size
+	res.Time = &e.Time
+	if *stream && req.AcceptBody && e.Size > 0 {
+		res.BodySize = e.Size
+	} else {
+		res.DiskPath = name
+	}
+	return nil
+}
+
+// fetch copies the entry for actionID, and its object, from the remote
+// cache to the local one. It returns nil if the remote cache has no entry.
+func (p *prog) fetch(actionID []byte) (*entry, error) {
+	e, err := p.remote.getEntry(actionID)
+	if e == nil || err != nil {
+		return nil, err
+	}
+	outputID, err := hex.DecodeString(e.OutputID)
+	if err != nil {
+		return nil, err
+	}
+	body, err := p.remote.getObject(outputID)
+	if body == nil || err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	if err := p.local.putObject(outputID, body); err != nil {
+		return nil, err
+	}
+	if err := p.local.putEntry(actionID, e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (p *prog) put(req *Request, res *Response) error {
+	if int64(len(req.body)) != req.BodySize {
+		return fmt.Errorf("got %d bytes of body; want %d", len(req.body), req.BodySize)
+	}
+	if err := p.local.putObject(req.ObjectID, bytes.NewReader(req.body)); err != nil {
+		return err
+	}
+	e := &entry{
+		OutputID: hex.EncodeToString(req.ObjectID),
+		Size:     req.BodySize,
+		Time:     time.Now(),
+	}
+	if err := p.local.putEntry(req.ActionID, e); err != nil {
+		return err
+	}
+	res.DiskPath = p.local.path("o", req.ObjectID)
+
+	if p.remote != nil {
+		p.uploads.Add(1)
+		go func() {
+			defer p.uploads.Done()
+			if err := p.remote.put(req.ActionID, req.ObjectID, req.body, e); err != nil {
+				log.Printf("uploading %x: %v", req.ActionID, err)
+			}
+		}()
+	}
+	return nil
+}
+
+// write writes res to the go command, followed by the body of each
+// response in bodies that has a BodySize.
+func (p *prog) write(res *Response, bodies []*Response) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	data, err := json.Marshal(res)
+	if err != nil {
+		log.Fatal(err)
+	}
+	p.w.Write(data)
+	p.w.WriteByte('\n')
+	for _, r := range bodies {
+		if r.BodySize == 0 {
+			continue
+		}
+		f, err := os.Open(p.local.path("o", r.OutputID))
+		if err != nil {
+			log.Fatal(err)
+		}
+		p.w.WriteByte('"')
+		enc := base64.NewEncoder(base64.StdEncoding, p.w)
+		n, err := io.Copy(enc, f)
+		f.Close()
+		if err != nil || n != r.BodySize {
+			log.Fatalf("streaming object %x: wrote %d of %d bytes: %v", r.OutputID, n, r.BodySize, err)
+		}
+		enc.Close()
+		p.w.WriteString("\"\n")
+	}
+	if err := p.w.Flush(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// A client talks to a cache served by runServer.
+type client struct {
+	once sync.Once
+	url  string // or "@file"
+	err  error
+}
+
+// base returns the URL of the server, reading it from a file if needed.
+func (c *client) base() (string, error) {
+	c.once.Do(func() {
+		file, ok := strings.CutPrefix(c.url, "@")
+		if !ok {
+			return
+		}
+		for start := time.Now(); ; time.Sleep(50 * time.Millisecond) {
+			data, err := os.ReadFile(file)
+			if err == nil && bytes.HasSuffix(data, []byte("\n")) {
+				c.url = strings.TrimSpace(string(data))
+				return
+			}
+			if time.Since(start) > time.Minute {
+				c.err = fmt.Errorf("no server URL in %s", file)
+				return
+			}
+		}
+	})
+	return c.url, c.err
+}
+
+// do sends an HTTP request for the path kind/id, returning the response
+// body, or nil if there is none.
+func (c *client) do(method, kind string, id []byte, body []byte) (io.ReadCloser, error) {
+	base, err := c.base()
+	if err != nil {
+		return nil, err
+	}
+	x := hex.EncodeToString(id)
+	req, err := http.NewRequest(method, base+"/"+kind+"/"+x[:2]+"/"+x, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		resp.Body.Close()
+		return nil, nil
+	case resp.StatusCode != http.StatusOK:
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s %s: %s", method, req.URL, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+func (c *client) getEntry(actionID []byte) (*entry, error) {
+	body, err := c.do("GET", "a", actionID, nil)
+	if body == nil || err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	e := new(entry)
+	if err := json.NewDecoder(body).Decode(e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (c *client) getObject(outputID []byte) (io.ReadCloser, error) {
+	return c.do("GET", "o", outputID, nil)
+}
+
+// put uploads the object, and then the entry for it, so that the server
+// never has an entry for a missing object.
+func (c *client) put(actionID, outputID, object []byte, e *entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	for _, kind := range []string{"o", "a"} {
+		id, body := outputID, object
+		if kind == "a" {
+			id, body = actionID, data
+		}
+		r, err := c.do("PUT", kind, id, body)
+		if err != nil {
+			return err
+		}
+		if r != nil {
+			r.Close()
+		}
+	}
+	return nil
+}
+
+// runServer serves the store st over HTTP.
+func runServer(st *store) {
+	ln, err := net.Listen("tcp", *serve)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *addrFile != "" {
+		url := "http://" + ln.Addr().String() + "\n"
+		if err := st.write(*addrFile, strings.NewReader(url)); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	http.HandleFunc("GET /{kind}/{xx}/{id}", func(w http.ResponseWriter, r *http.Request) {
+		name, _, ok := serverPath(st, r)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeFile(w, r, name)
+	})
+	http.HandleFunc("PUT /{kind}/{xx}/{id}", func(w http.ResponseWriter, r *http.Request) {
+		name, id, ok := serverPath(st, r)
+		if !ok {
+			http.Error(w, "bad path", http.StatusBadRequest)
+			return
+		}
+		var err error
+		if r.PathValue("kind") == "o" {
+			err = st.putObject(id, r.Body)
+		} else {
+			err = st.write(name, io.LimitReader(r.Body, 1<<20))
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+	log.Fatal(http.Serve(ln, nil))
+}
+
+// serverPath returns the file and ID named by the path of r,
+// and reports whether the path is valid.
+func serverPath(st *store, r *http.Request) (name string, id []byte, ok bool) {
+	kind, x := r.PathValue("kind"), r.PathValue("id")
+	id, err := hex.DecodeString(x)
+	if (kind != "a" && kind != "o") || err != nil || len(id) != sha256.Size || r.PathValue("xx") != x[:2] {
+		return "", nil, false
+	}
+	return st.path(kind, id), id, true
+}
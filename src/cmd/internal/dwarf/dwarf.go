@@ -366,6 +366,22 @@ type dwAbbrev struct {
 
 var abbrevsFinalized bool
 
+// dwarf5 is set by UseDWARF5.
+var dwarf5 bool
+
+// UseDWARF5 arranges for Abbrevs to return the abbrevs for DWARF 5
+// output, in which the compilation unit and type DIEs, which are
+// generated by the linker, refer to their strings and addresses
+// through the .debug_str_offsets and .debug_addr tables of the unit.
+// The DIEs generated by the compiler are the same in both versions.
+// UseDWARF5 must be called before the first call to Abbrevs.
+func UseDWARF5() {
+	if abbrevsFinalized {
+		panic("dwarf: UseDWARF5 called after Abbrevs")
+	}
+	dwarf5 = true
+}
+
 // expandPseudoForm takes an input DW_FORM_xxx value and translates it
 // into a platform-appropriate concrete form. Existing concrete/real
 // DW_FORM values are left untouched. For the moment the only
@@ -396,6 +412,17 @@ func Abbrevs() []dwAbbrev {
 			abbrevs[i].attr[j].form = expandPseudoForm(abbrevs[i].attr[j].form)
 		}
 	}
+	if dwarf5 {
+		abbrevs[DW_ABRV_COMPUNIT] = compunit5
+		abbrevs[DW_ABRV_COMPUNIT_TEXTLESS] = compunitTextless5
+		for i := DW_ABRV_STRUCTFIELD; i <= DW_ABRV_TYPEDECL; i++ {
+			for j := range abbrevs[i].attr {
+				if abbrevs[i].attr[j].form == DW_FORM_string {
+					abbrevs[i].attr[j].form = DW_FORM_strx
+				}
+			}
+		}
+	}
 	abbrevsFinalized = true
 	return abbrevs
 }
@@ -803,6 +830,40 @@ var abbrevs = []dwAbbrev{
 	},
 }
 
+// compunit5 and compunitTextless5 replace the COMPUNIT and
+// COMPUNIT_TEXTLESS abbrevs in DWARF 5 output. The strings are
+// indexes into the string offsets table of the unit, and the
+// low_pc is an index into its address table.
+var compunit5 = dwAbbrev{
+	DW_TAG_compile_unit,
+	DW_CHILDREN_yes,
+	[]dwAttrForm{
+		{DW_AT_name, DW_FORM_strx},
+		{DW_AT_language, DW_FORM_data1},
+		{DW_AT_stmt_list, DW_FORM_sec_offset},
+		{DW_AT_low_pc, DW_FORM_addrx},
+		{DW_AT_ranges, DW_FORM_sec_offset},
+		{DW_AT_comp_dir, DW_FORM_strx},
+		{DW_AT_producer, DW_FORM_strx},
+		{DW_AT_go_package_name, DW_FORM_strx},
+		{DW_AT_str_offsets_base, DW_FORM_sec_offset},
+		{DW_AT_addr_base, DW_FORM_sec_offset},
+	},
+}
+
+var compunitTextless5 = dwAbbrev{
+	DW_TAG_compile_unit,
+	DW_CHILDREN_yes,
+	[]dwAttrForm{
+		{DW_AT_name, DW_FORM_strx},
+		{DW_AT_language, DW_FORM_data1},
+		{DW_AT_comp_dir, DW_FORM_strx},
+		{DW_AT_producer, DW_FORM_strx},
+		{DW_AT_go_package_name, DW_FORM_strx},
+		{DW_AT_str_offsets_base, DW_FORM_sec_offset},
+	},
+}
+
 // GetAbbrev returns the contents of the .debug_abbrev section.
 func GetAbbrev() []byte {
 	abbrevs := Abbrevs()
@@ -925,6 +986,14 @@ func putattr(ctxt Context, s Sym, abbrev int, form int, cls int, value int64, da
 			ctxt.AddInt(s, 1, 0)
 		}
 
+	case DW_FORM_strx: // string
+		// The caller has replaced the string with its index.
+		Uleb128put(ctxt, s, value)
+
+	case DW_FORM_addrx: // address
+		// value is an index into the address table.
+		Uleb128put(ctxt, s, value)
+
 	case DW_FORM_flag: // flag
 		if value != 0 {
 			ctxt.AddInt(s, 1, 1)
@@ -187,6 +187,11 @@ const (
 	DW_AT_elemental      = 0x66 // flag
 	DW_AT_pure           = 0x67 // flag
 	DW_AT_recursive      = 0x68 // flag
+	// Dwarf5
+	DW_AT_str_offsets_base = 0x72 // stroffsetsptr
+	DW_AT_addr_base        = 0x73 // addrptr
+	DW_AT_rnglists_base    = 0x74 // rnglistsptr
+	DW_AT_loclists_base    = 0x8c // loclistsptr
 
 	DW_AT_lo_user = 0x2000 // ---
 	DW_AT_hi_user = 0x3fff // ---
@@ -220,6 +225,12 @@ const (
 	DW_FORM_exprloc      = 0x18 // exprloc
 	DW_FORM_flag_present = 0x19 // flag
 	DW_FORM_ref_sig8     = 0x20 // reference
+	// Dwarf5
+	DW_FORM_strx      = 0x1a // string
+	DW_FORM_addrx     = 0x1b // address
+	DW_FORM_line_strp = 0x1f // string
+	DW_FORM_loclistx  = 0x22 // loclist
+	DW_FORM_rnglistx  = 0x23 // rnglist
 	// Pseudo-form: expanded to data4 on IOS, udata elsewhere.
 	DW_FORM_udata_pseudo = 0x99
 )
@@ -446,6 +457,15 @@ const (
 	DW_LNE_hi_user      = 0xff
 )
 
+// Dwarf5 line number header entry formats
+const (
+	DW_LNCT_path            = 0x1
+	DW_LNCT_directory_index = 0x2
+	DW_LNCT_timestamp       = 0x3
+	DW_LNCT_size            = 0x4
+	DW_LNCT_MD5             = 0x5
+)
+
 // Table 39
 const (
 	DW_MACINFO_define     = 0x01
@@ -491,3 +511,40 @@ const (
 	DW_CFA_offset      = 0x2 << 6 // +register (ULEB128 offset)
 	DW_CFA_restore     = 0x3 << 6 // +register
 )
+
+// Dwarf5 unit header unit types
+const (
+	DW_UT_compile       = 0x01
+	DW_UT_type          = 0x02
+	DW_UT_partial       = 0x03
+	DW_UT_skeleton      = 0x04
+	DW_UT_split_compile = 0x05
+	DW_UT_split_type    = 0x06
+)
+
+// Dwarf5 range list entries
+const (
+	DW_RLE_end_of_list   = 0x00
+	DW_RLE_base_addressx = 0x01 // ULEB128 address index
+	DW_RLE_startx_endx   = 0x02 // ULEB128 address index, ULEB128 address index
+	DW_RLE_startx_length = 0x03 // ULEB128 address index, ULEB128 length
+	DW_RLE_offset_pair   = 0x04 // ULEB128 offset, ULEB128 offset
+	DW_RLE_base_address  = 0x05 // address
+	DW_RLE_start_end     = 0x06 // address, address
+	DW_RLE_start_length  = 0x07 // address, ULEB128 length
+)
+
+// Dwarf5 location list entries. Except for DW_LLE_end_of_list and
+// DW_LLE_base_address*, the operands are followed by a ULEB128
+// length and a location expression of that length.
+const (
+	DW_LLE_end_of_list      = 0x00
+	DW_LLE_base_addressx    = 0x01 // ULEB128 address index
+	DW_LLE_startx_endx      = 0x02 // ULEB128 address index, ULEB128 address index
+	DW_LLE_startx_length    = 0x03 // ULEB128 address index, ULEB128 length
+	DW_LLE_offset_pair      = 0x04 // ULEB128 offset, ULEB128 offset
+	DW_LLE_default_location = 0x05
+	DW_LLE_base_address     = 0x06 // address
+	DW_LLE_start_end        = 0x07 // address, address
+	DW_LLE_start_length     = 0x08 // address, ULEB128 length
+)
@@ -0,0 +1,398 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package heapdump reads heap dumps written by runtime/debug.WriteHeapDump.
+//
+// The format is documented in runtime/heapdump.go.
+package heapdump
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// Header is the header that starts every heap dump this package can read.
+const Header = "go1.24 heap dump\n"
+
+// Record tags. These must match runtime/heapdump.go.
+const (
+	fieldKindEol       = 0
+	fieldKindPtr       = 1
+	tagEOF             = 0
+	tagObject          = 1
+	tagOtherRoot       = 2
+	tagType            = 3
+	tagGoroutine       = 4
+	tagStackFrame      = 5
+	tagParams          = 6
+	tagFinalizer       = 7
+	tagItab            = 8
+	tagOSThread        = 9
+	tagMemStats        = 10
+	tagQueuedFinalizer = 11
+	tagData            = 12
+	tagBSS             = 13
+	tagDefer           = 14
+	tagPanic           = 15
+	tagMemProf         = 16
+	tagAllocSample     = 17
+)
+
+// A Dump is a parsed heap dump.
+type Dump struct {
+	BigEndian bool
+	PtrSize   uint64
+	GOARCH    string
+	GoVersion string
+
+	// Types maps type descriptor addresses to types.
+	Types map[uint64]*Type
+
+	// Objects lists the heap objects, sorted by address.
+	Objects []*Object
+
+	// Roots lists the non-heap locations that may hold pointers into
+	// the heap: global variables, stack frames, finalizers and
+	// miscellaneous runtime roots.
+	Roots []*Root
+}
+
+// A Type is a Go type descriptor.
+type Type struct {
+	Addr uint64
+	Size uint64
+	Name string
+}
+
+// An Object is an allocated heap object.
+type Object struct {
+	Addr uint64
+	Size uint64
+
+	// Type is the type recorded by the allocator, or nil if unknown.
+	// Objects allocated for a slice or array have the element type.
+	Type *Type
+
+	// Ptrs holds the non-nil pointers stored in the object.
+	Ptrs []uint64
+}
+
+// A RootKind identifies the kind of a Root.
+type RootKind int
+
+const (
+	RootData      RootKind = iota // initialized global variables
+	RootBSS                       // zero-initialized global variables
+	RootStack                     // a goroutine stack frame
+	RootFinalizer                 // a finalizer, queued or not
+	RootOther                     // other runtime-internal roots
+)
+
+// A Root is a non-heap location holding pointers into the heap.
+type Root struct {
+	Kind RootKind
+
+	// Name is the function name for RootStack, and a description
+	// of the root for RootOther. It is empty for other kinds.
+	Name string
+
+	// Ptrs holds the non-nil pointers held by the root.
+	Ptrs []uint64
+}
+
+// Parse reads a heap dump from r.
+func Parse(r io.Reader) (*Dump, error) {
+	p := &parser{r: bufio.NewReader(r)}
+	d, err := p.parse()
+	if err != nil {
+		return nil, fmt.Errorf("parsing heap dump: %v", err)
+	}
+	return d, nil
+}
+
+type parser struct {
+	r     *bufio.Reader
+	d     *Dump
+	error error // first error encountered
+}
+
+func (p *parser) parse() (*Dump, error) {
+	hdr := make([]byte, len(Header))
+	if _, err := io.ReadFull(p.r, hdr); err != nil {
+		return nil, err
+	}
+	if string(hdr) != Header {
+		return nil, errors.New("not a heap dump or unsupported version")
+	}
+	p.d = &Dump{PtrSize: 8, Types: make(map[uint64]*Type)}
+	objTypes := make(map[*Object]uint64)
+	for {
+		tag := p.uint()
+		switch tag {
+		case tagEOF:
+			if err := p.err(); err != nil {
+				return nil, err
+			}
+			for obj, addr := range objTypes {
+				obj.Type = p.d.Types[addr]
+			}
+			slices.SortFunc(p.d.Objects, func(a, b *Object) int {
+				return cmp.Compare(a.Addr, b.Addr)
+			})
+			return p.d, nil
+		case tagObject:
+			obj := &Object{Addr: p.uint()}
+			if typ := p.uint(); typ != 0 {
+				objTypes[obj] = typ
+			}
+			contents := p.bytes()
+			obj.Size = uint64(len(contents))
+			obj.Ptrs = p.fields(contents, nil)
+			p.d.Objects = append(p.d.Objects, obj)
+		case tagOtherRoot:
+			name := p.string()
+			p.addRoot(RootOther, name, p.uint())
+		case tagType:
+			t := &Type{Addr: p.uint(), Size: p.uint(), Name: p.string()}
+			p.uint() // indirect
+			p.d.Types[t.Addr] = t
+		case tagGoroutine:
+			p.skip(4) // address, sp, id, creation pc
+			p.skip(4) // status, system, background, wait since
+			p.string()
+			ctxt := p.uint()
+			p.skip(3) // m, defer, panic
+			p.addRoot(RootOther, "goroutine context", ctxt)
+		case tagStackFrame:
+			p.skip(3) // sp, depth, child sp
+			contents := p.bytes()
+			p.skip(3) // entry pc, pc, continuation pc
+			name := p.string()
+			p.d.Roots = append(p.d.Roots, &Root{Kind: RootStack, Name: name, Ptrs: p.fields(contents, nil)})
+		case tagParams:
+			p.d.BigEndian = p.uint() != 0
+			p.d.PtrSize = p.uint()
+			p.skip(2) // heap start, heap end
+			p.d.GOARCH = p.string()
+			p.d.GoVersion = p.string()
+			p.uint() // ncpu
+			if p.d.PtrSize != 4 && p.d.PtrSize != 8 {
+				return nil, fmt.Errorf("unsupported pointer size %d", p.d.PtrSize)
+			}
+		case tagFinalizer, tagQueuedFinalizer:
+			obj := p.uint()
+			fn := p.uint()
+			p.skip(3) // code pointer, argument type, pointer-to-object type
+			p.addRoot(RootFinalizer, "", obj, fn)
+		case tagItab:
+			p.skip(2)
+		case tagOSThread:
+			p.skip(3)
+		case tagMemStats:
+			p.skip(24 + 256 + 1)
+		case tagData, tagBSS:
+			kind := RootData
+			if tag == tagBSS {
+				kind = RootBSS
+			}
+			p.uint() // address
+			contents := p.bytes()
+			p.d.Roots = append(p.d.Roots, &Root{Kind: kind, Ptrs: p.fields(contents, nil)})
+		case tagDefer:
+			p.skip(4) // address, goroutine, sp, pc
+			fn := p.uint()
+			p.skip(2) // code pointer, next
+			p.addRoot(RootOther, "defer", fn)
+		case tagPanic:
+			p.skip(3) // address, goroutine, argument type
+			arg := p.uint()
+			p.skip(2) // unused, next
+			p.addRoot(RootOther, "panic", arg)
+		case tagMemProf:
+			p.skip(2) // bucket, size
+			n := p.uint()
+			for range n {
+				p.string()
+				p.string()
+				p.uint()
+			}
+			p.skip(2) // allocs, frees
+		case tagAllocSample:
+			p.skip(2)
+		default:
+			if err := p.err(); err != nil {
+				return nil, err
+			}
+			return nil, fmt.Errorf("unknown record tag %d", tag)
+		}
+		if err := p.err(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// addRoot adds a root with the non-nil pointers among ptrs.
+func (p *parser) addRoot(kind RootKind, name string, ptrs ...uint64) {
+	var live []uint64
+	for _, ptr := range ptrs {
+		if ptr != 0 {
+			live = append(live, ptr)
+		}
+	}
+	if len(live) > 0 {
+		p.d.Roots = append(p.d.Roots, &Root{Kind: kind, Name: name, Ptrs: live})
+	}
+}
+
+// fields reads a field list and appends to ptrs the non-nil pointers
+// it describes within contents.
+func (p *parser) fields(contents []byte, ptrs []uint64) []uint64 {
+	var order binary.ByteOrder = binary.LittleEndian
+	if p.d.BigEndian {
+		order = binary.BigEndian
+	}
+	for {
+		switch kind := p.uint(); kind {
+		case fieldKindEol:
+			return ptrs
+		case fieldKindPtr:
+			off := p.uint()
+			if off+p.d.PtrSize > uint64(len(contents)) {
+				p.fail(fmt.Errorf("field offset %d out of range", off))
+				return ptrs
+			}
+			var v uint64
+			if p.d.PtrSize == 8 {
+				v = order.Uint64(contents[off:])
+			} else {
+				v = uint64(order.Uint32(contents[off:]))
+			}
+			if v != 0 {
+				ptrs = append(ptrs, v)
+			}
+		default:
+			if p.err() == nil {
+				p.fail(fmt.Errorf("unknown field kind %d", kind))
+			}
+			return ptrs
+		}
+	}
+}
+
+// fail records err as the parser's error, unless one is already set.
+// Reads after an error return zero values.
+func (p *parser) fail(err error) {
+	if p.error == nil {
+		p.error = err
+	}
+}
+
+func (p *parser) err() error {
+	return p.error
+}
+
+func (p *parser) uint() uint64 {
+	if p.err() != nil {
+		return 0
+	}
+	v, err := binary.ReadUvarint(p.r)
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		p.fail(err)
+	}
+	return v
+}
+
+func (p *parser) skip(n int) {
+	for range n {
+		p.uint()
+	}
+}
+
+func (p *parser) bytes() []byte {
+	n := p.uint()
+	if p.err() != nil {
+		return nil
+	}
+	if n > 1<<40 {
+		p.fail(fmt.Errorf("record too large (%d bytes)", n))
+		return nil
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(p.r, b); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		p.fail(err)
+	}
+	return b
+}
+
+func (p *parser) string() string {
+	return string(p.bytes())
+}
+
+// Find returns the object containing addr, or nil if there is none.
+func (d *Dump) Find(addr uint64) *Object {
+	i, _ := slices.BinarySearchFunc(d.Objects, addr, func(o *Object, addr uint64) int {
+		if o.Addr+o.Size <= addr {
+			return -1
+		}
+		if o.Addr > addr {
+			return 1
+		}
+		return 0
+	})
+	if i < len(d.Objects) {
+		if o := d.Objects[i]; o.Addr <= addr && addr < o.Addr+o.Size {
+			return o
+		}
+	}
+	return nil
+}
+
+// A Retainer is the reason an object is reachable: the object or root
+// that points to it on a shortest path from the roots.
+// Exactly one of Object and Root is set.
+type Retainer struct {
+	Object *Object
+	Root   *Root
+}
+
+// Retainers returns the retainer of each object reachable from the roots.
+// Unreachable objects, which are garbage not yet collected, are absent.
+func (d *Dump) Retainers() map[*Object]Retainer {
+	ret := make(map[*Object]Retainer)
+	var queue []*Object
+	for _, r := range d.Roots {
+		for _, ptr := range r.Ptrs {
+			if o := d.Find(ptr); o != nil {
+				if _, ok := ret[o]; !ok {
+					ret[o] = Retainer{Root: r}
+					queue = append(queue, o)
+				}
+			}
+		}
+	}
+	for len(queue) > 0 {
+		from := queue[0]
+		queue = queue[1:]
+		for _, ptr := range from.Ptrs {
+			if o := d.Find(ptr); o != nil {
+				if _, ok := ret[o]; !ok {
+					ret[o] = Retainer{Object: from}
+					queue = append(queue, o)
+				}
+			}
+		}
+	}
+	return ret
+}
@@ -0,0 +1,82 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heapdump
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"testing"
+	"unsafe"
+)
+
+// node is large enough to be allocated with an allocation header,
+// so that the heap dump records its type.
+type node struct {
+	next *node
+	pad  [128]*int
+}
+
+var list *node
+
+func TestParse(t *testing.T) {
+	if runtime.GOOS == "js" || runtime.GOOS == "wasip1" {
+		t.Skipf("WriteHeapDump is not available on %s.", runtime.GOOS)
+	}
+	list = &node{next: &node{next: &node{}}}
+	defer func() { list = nil }()
+
+	name := filepath.Join(t.TempDir(), "dump")
+	f, err := os.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	debug.WriteHeapDump(f.Fd())
+	if _, err := f.Seek(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	d, err := Parse(f)
+	f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.GOARCH != runtime.GOARCH {
+		t.Errorf("GOARCH = %q, want %q", d.GOARCH, runtime.GOARCH)
+	}
+	if len(d.Types) == 0 || len(d.Objects) == 0 || len(d.Roots) == 0 {
+		t.Fatalf("got %d types, %d objects, %d roots; want some of each", len(d.Types), len(d.Objects), len(d.Roots))
+	}
+
+	// Follow the list from its head, which must be retained by a global.
+	retainers := d.Retainers()
+	var n int
+	for o := d.Find(addr(list)); o != nil; n++ {
+		if o.Type == nil || o.Type.Name != "cmd/internal/heapdump.node" {
+			t.Fatalf("list node %d has type %v, want cmd/internal/heapdump.node", n, o.Type)
+		}
+		r, ok := retainers[o]
+		switch {
+		case !ok:
+			t.Fatalf("list node %d is unreachable", n)
+		case n == 0 && (r.Root == nil || r.Root.Kind != RootBSS):
+			t.Errorf("list head retained by %+v, want bss root", r)
+		}
+		var next *Object
+		for _, ptr := range o.Ptrs {
+			if x := d.Find(ptr); x != nil && x != o && x.Type == o.Type {
+				next = x
+			}
+		}
+		o = next
+	}
+	if n != 3 {
+		t.Errorf("found %d list nodes, want 3", n)
+	}
+}
+
+func addr(n *node) uint64 {
+	return uint64(uintptr(unsafe.Pointer(n)))
+}
@@ -72,6 +72,9 @@ Flags:
 		system tools now assume the presence of the header.
 	-dumpdep
 		Dump symbol dependency graph.
+	-dwarf5
+		Generate DWARF version 5 debug info instead of version 4.
+		Not supported on AIX.
 	-extar ar
 		Set the external archive program (default "ar").
 		Used only for -buildmode=c-archive.
@@ -83,6 +86,10 @@ Flags:
 		Ignore version mismatch in the linked archives.
 	-g
 		Disable Go package data checks.
+	-icf
+		Fold instantiations of a generic function that compile to
+		identical code into a single function. Stack traces are
+		unaffected, but debuggers see only one of the instantiations.
 	-importcfg file
 		Read import configuration from file.
 		In the file, set packagefile, packageshlib to specify import resolution.
@@ -110,6 +117,10 @@ Flags:
 		Link with C/C++ memory sanitizer support.
 	-o file
 		Write output to file (default a.out, or a.out.exe on Windows).
+	-pgoprofile file
+		Lay out functions using the PGO profile in file, placing the
+		functions that the profile shows to be hot together at the start
+		of the text. The go command passes the profile used by -pgo.
 	-pluginpath path
 		The path name used to prefix exported plugin symbols.
 	-r dir1:dir2:...
@@ -118,6 +129,11 @@ Flags:
 		Link with race detection libraries.
 	-s
 		Omit the symbol table and debug information.
+	-staticpie
+		With -buildmode=pie, link an executable that is started without
+		a dynamic linker and applies its own relocations.
+		Supported on linux/amd64 and linux/arm64. When linking
+		internally, the program must not use cgo.
 	-tmpdir dir
 		Write temporary files to dir.
 		Temporary files are only used in external linking mode.
@@ -560,3 +560,70 @@ func TestFlagR(t *testing.T) {
 		t.Errorf("executable failed to run: %v\n%s", err, out)
 	}
 }
+
+const staticPIESource = `
+package main
+
+import "fmt"
+
+var m = map[string]*int{"x": new(int)}
+
+func main() {
+	*m["x"] = 42
+	fmt.Println(*m["x"])
+	panic("static PIE")
+}
+`
+
+func TestStaticPIE(t *testing.T) {
+	// Test that -staticpie generates a position independent executable
+	// without an interpreter that relocates itself correctly.
+	testenv.MustHaveGoBuild(t)
+	if runtime.GOOS != "linux" || (runtime.GOARCH != "amd64" && runtime.GOARCH != "arm64") {
+		t.Skip("-staticpie not supported")
+	}
+	t.Parallel()
+
+	tmpdir := t.TempDir()
+	src := filepath.Join(tmpdir, "x.go")
+	if err := os.WriteFile(src, []byte(staticPIESource), 0444); err != nil {
+		t.Fatal(err)
+	}
+	exe := filepath.Join(tmpdir, "x.exe")
+
+	cmd := testenv.Command(t, testenv.GoToolPath(t), "build", "-buildmode=pie", "-ldflags=-linkmode=internal -staticpie", "-o", exe, src)
+	cmd.Env = append(os.Environ(), "CGO_ENABLED=0")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("build failed: %v, output:\n%s", err, out)
+	}
+
+	ef, err := elf.Open(exe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ef.Close()
+	if ef.Type != elf.ET_DYN {
+		t.Errorf("got ELF type %v, want %v", ef.Type, elf.ET_DYN)
+	}
+	for _, p := range ef.Progs {
+		if p.Type == elf.PT_INTERP {
+			t.Errorf("static PIE has a PT_INTERP segment")
+		}
+	}
+	if ef.Section(".interp") != nil {
+		t.Errorf("static PIE has an .interp section")
+	}
+
+	// The executable must print the value and a traceback that
+	// points at the panic.
+	cmd = testenv.Command(t, exe)
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("executable succeeded unexpectedly:\n%s", out)
+	}
+	for _, want := range []string{"42\n", "panic: static PIE", "main.main()", "x.go:11"} {
+		if !bytes.Contains(out, []byte(want)) {
+			t.Errorf("output does not contain %q:\n%s", want, out)
+		}
+	}
+}
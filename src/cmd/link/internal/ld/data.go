@@ -113,6 +113,9 @@ func trampoline(ctxt *Link, s loader.Sym) {
 
 	ldr := ctxt.loader
 	relocs := ldr.Relocs(s)
+	// Unless the layout is randomized or guided by a profile, the
+	// functions of a package are laid out together.
+	pkgLayout := *flagRandLayout == 0 && *flagPGOProfile == ""
 	for ri := 0; ri < relocs.Count(); ri++ {
 		r := relocs.At(ri)
 		rt := r.Type()
@@ -126,10 +129,10 @@ func trampoline(ctxt *Link, s loader.Sym) {
 
 		if ldr.SymValue(rs) == 0 && ldr.SymType(rs) != sym.SDYNIMPORT && ldr.SymType(rs) != sym.SUNDEFEXT {
 			// Symbols in the same package are laid out together (if we
-			// don't randomize the function order).
+			// don't change the function order).
 			// Except that if SymPkg(s) == "", it is a host object symbol
 			// which may call an external symbol via PLT.
-			if ldr.SymPkg(s) != "" && ldr.SymPkg(rs) == ldr.SymPkg(s) && *flagRandLayout == 0 {
+			if ldr.SymPkg(s) != "" && ldr.SymPkg(rs) == ldr.SymPkg(s) && pkgLayout {
 				// RISC-V is only able to reach +/-1MiB via a JAL instruction.
 				// We need to generate a trampoline when an address is
 				// currently unknown.
@@ -138,7 +141,7 @@ func trampoline(ctxt *Link, s loader.Sym) {
 				}
 			}
 			// Runtime packages are laid out together.
-			if isRuntimeDepPkg(ldr.SymPkg(s)) && isRuntimeDepPkg(ldr.SymPkg(rs)) && *flagRandLayout == 0 {
+			if isRuntimeDepPkg(ldr.SymPkg(s)) && isRuntimeDepPkg(ldr.SymPkg(rs)) && pkgLayout {
 				continue
 			}
 		}
@@ -2412,8 +2415,7 @@ func (ctxt *Link) textaddress() {
 
 	ldr := ctxt.loader
 
-	if *flagRandLayout != 0 {
-		r := rand.New(rand.NewSource(*flagRandLayout))
+	if *flagRandLayout != 0 || *flagPGOProfile != "" {
 		textp := ctxt.Textp
 		i := 0
 		// don't move the buildid symbol
@@ -2427,9 +2429,14 @@ func (ctxt *Link) textaddress() {
 			i++
 		}
 		textp = textp[i:]
-		r.Shuffle(len(textp), func(i, j int) {
-			textp[i], textp[j] = textp[j], textp[i]
-		})
+		if *flagRandLayout != 0 {
+			r := rand.New(rand.NewSource(*flagRandLayout))
+			r.Shuffle(len(textp), func(i, j int) {
+				textp[i], textp[j] = textp[j], textp[i]
+			})
+		} else {
+			ctxt.pgoLayout(textp)
+		}
 	}
 
 	text := ctxt.xdefine("runtime.text", sym.STEXT, 0)
@@ -2980,6 +2987,25 @@ func (ctxt *Link) address() []*sym.Segment {
 	ctxt.xdefine("runtime.ecovctrs", sym.SCOVERAGE_COUNTER, int64(noptrbss.Vaddr+covCounterDataStartOff+covCounterDataLen))
 	ctxt.xdefine("runtime.end", sym.SBSS, int64(Segdata.Vaddr+Segdata.Length))
 
+	if ctxt.IsELF {
+		// A static PIE applies its own dynamic relocations at startup,
+		// those between runtime.rela and runtime.erela. For any other
+		// program the two are equal.
+		relaSect, rela, erela := rodata, int64(rodata.Vaddr), int64(rodata.Vaddr)
+		if ctxt.IsStaticPIE() && ctxt.IsInternal() {
+			elfCheckStaticPIERelocs(ctxt)
+			if sect := ldr.SymSect(ctxt.Rela); sect != nil {
+				relaSect = sect
+				rela = ldr.SymValue(ctxt.Rela)
+				erela = rela + ldr.SymSize(ctxt.Rela)
+			}
+		}
+		ctxt.xdefine("runtime.rela", sym.SRODATA, rela)
+		ldr.SetSymSect(ldr.Lookup("runtime.rela", 0), relaSect)
+		ctxt.xdefine("runtime.erela", sym.SRODATA, erela)
+		ldr.SetSymSect(ldr.Lookup("runtime.erela", 0), relaSect)
+	}
+
 	if fuzzCounters != nil {
 		ctxt.xdefine("runtime.__start___sancov_cntrs", sym.SLIBFUZZER_8BIT_COUNTER, int64(fuzzCounters.Vaddr))
 		ctxt.xdefine("runtime.__stop___sancov_cntrs", sym.SLIBFUZZER_8BIT_COUNTER, int64(fuzzCounters.Vaddr+fuzzCounters.Length))
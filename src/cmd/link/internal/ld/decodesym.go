@@ -159,6 +159,27 @@ func decodetypeMapValue(ldr *loader.Loader, arch *sys.Arch, symIdx loader.Sym) l
 	return decodeRelocSym(ldr, symIdx, &relocs, int32(commonsize(arch))+int32(arch.PtrSize)) // 0x20 / 0x38
 }
 
+// decodetypeMapGroup returns the group type of a map type
+// (internal/abi.SwissMapType.Group).
+func decodetypeMapGroup(ldr *loader.Loader, arch *sys.Arch, symIdx loader.Sym) loader.Sym {
+	relocs := ldr.Relocs(symIdx)
+	return decodeRelocSym(ldr, symIdx, &relocs, int32(commonsize(arch))+2*int32(arch.PtrSize))
+}
+
+// decodetypeMapSlotSize returns the slot size of a map type
+// (internal/abi.SwissMapType.SlotSize).
+func decodetypeMapSlotSize(ldr *loader.Loader, arch *sys.Arch, symIdx loader.Sym) int64 {
+	off := commonsize(arch) + 5*arch.PtrSize
+	return int64(decodeInuxi(arch, ldr.Data(symIdx)[off:], arch.PtrSize))
+}
+
+// decodetypeMapElemOff returns the offset of the elem in a map slot
+// (internal/abi.SwissMapType.ElemOff).
+func decodetypeMapElemOff(ldr *loader.Loader, arch *sys.Arch, symIdx loader.Sym) int64 {
+	off := commonsize(arch) + 6*arch.PtrSize
+	return int64(decodeInuxi(arch, ldr.Data(symIdx)[off:], arch.PtrSize))
+}
+
 func decodetypePtrElem(ldr *loader.Loader, arch *sys.Arch, symIdx loader.Sym) loader.Sym {
 	relocs := ldr.Relocs(symIdx)
 	return decodeRelocSym(ldr, symIdx, &relocs, int32(commonsize(arch))) // 0x1c / 0x30
@@ -401,7 +401,9 @@ func (d *dwctxt) dtolsym(s dwarf.Sym) loader.Sym {
 	return dws
 }
 
-func (d *dwctxt) putdie(syms []loader.Sym, die *dwarf.DWDie) []loader.Sym {
+// putdie writes die and its children, appending any new symbols to
+// syms. In DWARF 5, strs is the string table of the unit.
+func (d *dwctxt) putdie(syms []loader.Sym, die *dwarf.DWDie, strs *dwStrings) []loader.Sym {
 	s := d.dtolsym(die.Sym)
 	if s == 0 {
 		s = syms[len(syms)-1]
@@ -409,11 +411,14 @@ func (d *dwctxt) putdie(syms []loader.Sym, die *dwarf.DWDie) []loader.Sym {
 		syms = append(syms, s)
 	}
 	sDwsym := dwSym(s)
+	if strs != nil {
+		strs.indexStrings(die)
+	}
 	dwarf.Uleb128put(d, sDwsym, int64(die.Abbrev))
 	dwarf.PutAttrs(d, sDwsym, die.Abbrev, die.Attr)
 	if dwarf.HasChildren(die) {
 		for die := die.Child; die != nil; die = die.Link {
-			syms = d.putdie(syms, die)
+			syms = d.putdie(syms, die, strs)
 		}
 		dsu := d.ldr.MakeSymbolUpdater(syms[len(syms)-1])
 		dsu.AddUint8(0)
@@ -873,7 +878,6 @@ func (d *dwctxt) synthesizemaptypes(ctxt *Link, die *dwarf.DWDie) {
 
 func (d *dwctxt) synthesizemaptypesSwiss(ctxt *Link, die *dwarf.DWDie) {
 	hash := walktypedef(d.findprotodie(ctxt, "type:runtime.hmap"))
-	bucket := walktypedef(d.findprotodie(ctxt, "type:runtime.bmap"))
 
 	if hash == nil {
 		return
@@ -886,12 +890,14 @@ func (d *dwctxt) synthesizemaptypesSwiss(ctxt *Link, die *dwarf.DWDie) {
 		gotype := loader.Sym(getattr(die, dwarf.DW_AT_type).Data.(dwSym))
 		keytype := decodetypeMapKey(d.ldr, d.arch, gotype)
 		valtype := decodetypeMapValue(d.ldr, d.arch, gotype)
+		grouptype := decodetypeMapGroup(d.ldr, d.arch, gotype)
 		keydata := d.ldr.Data(keytype)
 		valdata := d.ldr.Data(valtype)
 		keysize, valsize := decodetypeSize(d.arch, keydata), decodetypeSize(d.arch, valdata)
+		groupsize := decodetypeSize(d.arch, d.ldr.Data(grouptype))
 		keytype, valtype = d.walksymtypedef(d.defgotype(keytype)), d.walksymtypedef(d.defgotype(valtype))
 
-		// compute size info like hashmap.c does.
+		// compute size info like the runtime does.
 		indirectKey, indirectVal := false, false
 		if keysize > abi.SwissMapMaxKeyBytes {
 			keysize = int64(d.arch.PtrSize)
@@ -901,64 +907,58 @@ func (d *dwctxt) synthesizemaptypesSwiss(ctxt *Link, die *dwarf.DWDie) {
 			valsize = int64(d.arch.PtrSize)
 			indirectVal = true
 		}
+		slotsize := decodetypeMapSlotSize(d.ldr, d.arch, gotype)
+		valoff := decodetypeMapElemOff(d.ldr, d.arch, gotype)
 
-		// Construct type to represent an array of BucketSize keys
+		// Construct slot<K,V>
 		keyname := d.nameFromDIESym(keytype)
-		dwhks := d.mkinternaltype(ctxt, dwarf.DW_ABRV_ARRAYTYPE, "[]key", keyname, "", func(dwhk *dwarf.DWDie) {
-			newattr(dwhk, dwarf.DW_AT_byte_size, dwarf.DW_CLS_CONSTANT, abi.SwissMapBucketCount*keysize, 0)
+		valname := d.nameFromDIESym(valtype)
+		dwss := d.mkinternaltype(ctxt, dwarf.DW_ABRV_STRUCTTYPE, "slot", keyname, valname, func(dws *dwarf.DWDie) {
 			t := keytype
 			if indirectKey {
 				t = d.defptrto(keytype)
 			}
-			d.newrefattr(dwhk, dwarf.DW_AT_type, t)
-			fld := d.newdie(dwhk, dwarf.DW_ABRV_ARRAYRANGE, "size")
-			newattr(fld, dwarf.DW_AT_count, dwarf.DW_CLS_CONSTANT, abi.SwissMapBucketCount, 0)
-			d.newrefattr(fld, dwarf.DW_AT_type, d.uintptrInfoSym)
-		})
+			fld := d.newdie(dws, dwarf.DW_ABRV_STRUCTFIELD, "key")
+			d.newrefattr(fld, dwarf.DW_AT_type, t)
+			newmemberoffsetattr(fld, 0)
 
-		// Construct type to represent an array of BucketSize values
-		valname := d.nameFromDIESym(valtype)
-		dwhvs := d.mkinternaltype(ctxt, dwarf.DW_ABRV_ARRAYTYPE, "[]val", valname, "", func(dwhv *dwarf.DWDie) {
-			newattr(dwhv, dwarf.DW_AT_byte_size, dwarf.DW_CLS_CONSTANT, abi.SwissMapBucketCount*valsize, 0)
-			t := valtype
+			t = valtype
 			if indirectVal {
 				t = d.defptrto(valtype)
 			}
-			d.newrefattr(dwhv, dwarf.DW_AT_type, t)
-			fld := d.newdie(dwhv, dwarf.DW_ABRV_ARRAYRANGE, "size")
-			newattr(fld, dwarf.DW_AT_count, dwarf.DW_CLS_CONSTANT, abi.SwissMapBucketCount, 0)
-			d.newrefattr(fld, dwarf.DW_AT_type, d.uintptrInfoSym)
-		})
+			fld = d.newdie(dws, dwarf.DW_ABRV_STRUCTFIELD, "elem")
+			d.newrefattr(fld, dwarf.DW_AT_type, t)
+			newmemberoffsetattr(fld, int32(valoff))
 
-		// Construct bucket<K,V>
-		dwhbs := d.mkinternaltype(ctxt, dwarf.DW_ABRV_STRUCTTYPE, "bucket", keyname, valname, func(dwhb *dwarf.DWDie) {
-			// Copy over all fields except the field "data" from the generic
-			// bucket. "data" will be replaced with keys/values below.
-			d.copychildrenexcept(ctxt, dwhb, bucket, findchild(bucket, "data"))
+			newattr(dws, dwarf.DW_AT_byte_size, dwarf.DW_CLS_CONSTANT, slotsize, 0)
+		})
 
-			fld := d.newdie(dwhb, dwarf.DW_ABRV_STRUCTFIELD, "keys")
-			d.newrefattr(fld, dwarf.DW_AT_type, dwhks)
-			newmemberoffsetattr(fld, abi.SwissMapBucketCount)
-			fld = d.newdie(dwhb, dwarf.DW_ABRV_STRUCTFIELD, "values")
-			d.newrefattr(fld, dwarf.DW_AT_type, dwhvs)
-			newmemberoffsetattr(fld, abi.SwissMapBucketCount+abi.SwissMapBucketCount*int32(keysize))
-			fld = d.newdie(dwhb, dwarf.DW_ABRV_STRUCTFIELD, "overflow")
-			d.newrefattr(fld, dwarf.DW_AT_type, d.defptrto(d.dtolsym(dwhb.Sym)))
-			newmemberoffsetattr(fld, abi.SwissMapBucketCount+abi.SwissMapBucketCount*(int32(keysize)+int32(valsize)))
-			if d.arch.RegSize > d.arch.PtrSize {
-				fld = d.newdie(dwhb, dwarf.DW_ABRV_STRUCTFIELD, "pad")
-				d.newrefattr(fld, dwarf.DW_AT_type, d.uintptrInfoSym)
-				newmemberoffsetattr(fld, abi.SwissMapBucketCount+abi.SwissMapBucketCount*(int32(keysize)+int32(valsize))+int32(d.arch.PtrSize))
-			}
+		// Construct type to represent an array of SwissMapGroupSlots slots
+		dwsa := d.mkinternaltype(ctxt, dwarf.DW_ABRV_ARRAYTYPE, "[]slot", keyname, valname, func(dwsa *dwarf.DWDie) {
+			newattr(dwsa, dwarf.DW_AT_byte_size, dwarf.DW_CLS_CONSTANT, abi.SwissMapGroupSlots*slotsize, 0)
+			d.newrefattr(dwsa, dwarf.DW_AT_type, dwss)
+			fld := d.newdie(dwsa, dwarf.DW_ABRV_ARRAYRANGE, "size")
+			newattr(fld, dwarf.DW_AT_count, dwarf.DW_CLS_CONSTANT, abi.SwissMapGroupSlots, 0)
+			d.newrefattr(fld, dwarf.DW_AT_type, d.uintptrInfoSym)
+		})
 
-			newattr(dwhb, dwarf.DW_AT_byte_size, dwarf.DW_CLS_CONSTANT, abi.SwissMapBucketCount+abi.SwissMapBucketCount*keysize+abi.SwissMapBucketCount*valsize+int64(d.arch.RegSize), 0)
+		// Construct group<K,V>
+		dwgs := d.mkinternaltype(ctxt, dwarf.DW_ABRV_STRUCTTYPE, "group", keyname, valname, func(dwg *dwarf.DWDie) {
+			fld := d.newdie(dwg, dwarf.DW_ABRV_STRUCTFIELD, "ctrl")
+			d.newrefattr(fld, dwarf.DW_AT_type, d.defgotype(d.lookupOrDiag("type:uint64")))
+			newmemberoffsetattr(fld, 0)
+			fld = d.newdie(dwg, dwarf.DW_ABRV_STRUCTFIELD, "slots")
+			d.newrefattr(fld, dwarf.DW_AT_type, dwsa)
+			newmemberoffsetattr(fld, 8)
+			newattr(dwg, dwarf.DW_AT_byte_size, dwarf.DW_CLS_CONSTANT, groupsize, 0)
 		})
 
 		// Construct hash<K,V>
 		dwhs := d.mkinternaltype(ctxt, dwarf.DW_ABRV_STRUCTTYPE, "hash", keyname, valname, func(dwh *dwarf.DWDie) {
 			d.copychildren(ctxt, dwh, hash)
-			d.substitutetype(dwh, "buckets", d.defptrto(dwhbs))
-			d.substitutetype(dwh, "oldbuckets", d.defptrto(dwhbs))
+			// dirPtr points to a single group for small maps,
+			// and to the directory of tables otherwise.
+			d.substitutetype(dwh, "dirPtr", d.defptrto(dwgs))
 			newattr(dwh, dwarf.DW_AT_byte_size, dwarf.DW_CLS_CONSTANT, getattr(hash, dwarf.DW_AT_byte_size).Value, nil)
 		})
 
@@ -1248,8 +1248,8 @@ func expandFile(fname string) string {
 // described in section 6.2.4 of the DWARF 4 standard. It walks the
 // filepaths for the unit to discover any common directories, which
 // are emitted to the directory table first, then the file table is
-// emitted after that.
-func (d *dwctxt) writeDirFileTables(unit *sym.CompilationUnit, lsu *loader.SymbolBuilder) {
+// emitted after that. In DWARF 5, the strings go in lineStr.
+func (d *dwctxt) writeDirFileTables(unit *sym.CompilationUnit, lsu *loader.SymbolBuilder, lineStr loader.Sym) {
 	type fileDir struct {
 		base string
 		dir  int
@@ -1293,9 +1293,47 @@ func (d *dwctxt) writeDirFileTables(unit *sym.CompilationUnit, lsu *loader.Symbo
 		}
 	}
 
+	lsDwsym := dwSym(lsu.Sym())
+	if lineStr != 0 {
+		// DWARF 5 numbers directories and files from 0, and directory
+		// 0 is the compilation directory. The compiler numbers files
+		// from 1, so file 0, the primary source file, repeats file 1.
+		lssu := d.ldr.MakeSymbolUpdater(lineStr)
+		offs := make(map[string]int64)
+		strp := func(s string) {
+			off, ok := offs[s]
+			if !ok {
+				off = lssu.Addstring(s)
+				offs[s] = off
+			}
+			d.AddDWARFAddrSectionOffset(lsDwsym, dwSym(lineStr), off)
+		}
+		lsu.AddUint8(1) // directory_entry_format_count
+		dwarf.Uleb128put(d, lsDwsym, dwarf.DW_LNCT_path)
+		dwarf.Uleb128put(d, lsDwsym, dwarf.DW_FORM_line_strp)
+		dwarf.Uleb128put(d, lsDwsym, int64(len(dirs)))
+		strp(getCompilationDir())
+		for _, dir := range dirs[1:] {
+			strp(dir)
+		}
+		lsu.AddUint8(2) // file_name_entry_format_count
+		dwarf.Uleb128put(d, lsDwsym, dwarf.DW_LNCT_path)
+		dwarf.Uleb128put(d, lsDwsym, dwarf.DW_FORM_line_strp)
+		dwarf.Uleb128put(d, lsDwsym, dwarf.DW_LNCT_directory_index)
+		dwarf.Uleb128put(d, lsDwsym, dwarf.DW_FORM_udata)
+		if len(files) > 0 {
+			files = append(files[:1:1], files...)
+		}
+		dwarf.Uleb128put(d, lsDwsym, int64(len(files)))
+		for _, f := range files {
+			strp(f.base)
+			dwarf.Uleb128put(d, lsDwsym, int64(f.dir))
+		}
+		return
+	}
+
 	// Emit directory section. This is a series of nul terminated
 	// strings, followed by a single zero byte.
-	lsDwsym := dwSym(lsu.Sym())
 	for k := 1; k < len(dirs); k++ {
 		d.AddString(lsDwsym, dirs[k])
 	}
@@ -1319,7 +1357,8 @@ func (d *dwctxt) writeDirFileTables(unit *sym.CompilationUnit, lsu *loader.Symbo
 // (one per live function), and finally an epilog symbol containing an
 // end-of-sequence operator. The prologue and epilog symbols are passed
 // in (having been created earlier); here we add content to them.
-func (d *dwctxt) writelines(unit *sym.CompilationUnit, lineProlog loader.Sym) []loader.Sym {
+// In DWARF 5, the strings of the file table go in lineStr.
+func (d *dwctxt) writelines(unit *sym.CompilationUnit, lineProlog, lineStr loader.Sym) []loader.Sym {
 	is_stmt := uint8(1) // initially = recommended default_is_stmt = 1, tracks is_stmt toggles.
 
 	unitstart := int64(-1)
@@ -1337,13 +1376,22 @@ func (d *dwctxt) writelines(unit *sym.CompilationUnit, lineProlog loader.Sym) []
 	unitLengthOffset := lsu.Size()
 	d.createUnitLength(lsu, 0) // unit_length (*), filled in at end
 	unitstart = lsu.Size()
-	lsu.AddUint16(d.arch, 2) // dwarf version (appendix F) -- version 3 is incompatible w/ XCode 9.0's dsymutil, latest supported on OSX 10.12 as of 2018-05
+	if d.linkctxt.dwarf5 {
+		lsu.AddUint16(d.arch, 5)            // dwarf version
+		lsu.AddUint8(uint8(d.arch.PtrSize)) // address_size
+		lsu.AddUint8(0)                     // segment_selector_size
+	} else {
+		lsu.AddUint16(d.arch, 2) // dwarf version (appendix F) -- version 3 is incompatible w/ XCode 9.0's dsymutil, latest supported on OSX 10.12 as of 2018-05
+	}
 	headerLengthOffset := lsu.Size()
 	d.addDwarfAddrField(lsu, 0) // header_length (*), filled in at end
 	headerstart = lsu.Size()
 
 	// cpos == unitstart + 4 + 2 + 4
-	lsu.AddUint8(1)                // minimum_instruction_length
+	lsu.AddUint8(1) // minimum_instruction_length
+	if d.linkctxt.dwarf5 {
+		lsu.AddUint8(1) // maximum_operations_per_instruction
+	}
 	lsu.AddUint8(is_stmt)          // default_is_stmt
 	lsu.AddUint8(LINE_BASE & 0xFF) // line_base
 	lsu.AddUint8(LINE_RANGE)       // line_range
@@ -1360,7 +1408,7 @@ func (d *dwctxt) writelines(unit *sym.CompilationUnit, lineProlog loader.Sym) []
 	lsu.AddUint8(0)                // standard_opcode_lengths[10]
 
 	// Call helper to emit dir and file sections.
-	d.writeDirFileTables(unit, lsu)
+	d.writeDirFileTables(unit, lsu, lineStr)
 
 	// capture length at end of file names.
 	headerend = lsu.Size()
@@ -1402,11 +1450,25 @@ func (d *dwctxt) writepcranges(unit *sym.CompilationUnit, base loader.Sym, pcs [
 	syms = append(syms, rangeProlog)
 	rsu := d.ldr.MakeSymbolUpdater(rangeProlog)
 	rDwSym := dwSym(rangeProlog)
+	if d.linkctxt.dwarf5 {
+		d.writeListsHeader(rsu)
+	}
 
-	// Create PC ranges for the compilation unit DIE.
+	// Create PC ranges for the compilation unit DIE. In DWARF 5,
+	// DW_AT_low_pc is an index into the address table of the unit,
+	// where base is the first entry.
 	newattr(unit.DWInfo, dwarf.DW_AT_ranges, dwarf.DW_CLS_PTR, rsu.Size(), rDwSym)
 	newattr(unit.DWInfo, dwarf.DW_AT_low_pc, dwarf.DW_CLS_ADDRESS, 0, dwSym(base))
-	dwarf.PutBasedRanges(d, rDwSym, pcs)
+	if d.linkctxt.dwarf5 {
+		for _, r := range pcs {
+			rsu.AddUint8(dwarf.DW_RLE_offset_pair)
+			dwarf.Uleb128put(d, rDwSym, r.Start)
+			dwarf.Uleb128put(d, rDwSym, r.End)
+		}
+		rsu.AddUint8(dwarf.DW_RLE_end_of_list)
+	} else {
+		dwarf.PutBasedRanges(d, rDwSym, pcs)
+	}
 
 	// Collect up the ranges for functions in the unit.
 	rsize := uint64(rsu.Size())
@@ -1415,6 +1477,9 @@ func (d *dwctxt) writepcranges(unit *sym.CompilationUnit, base loader.Sym, pcs [
 		syms = append(syms, s)
 		rsize += uint64(d.ldr.SymSize(s))
 	}
+	if d.linkctxt.dwarf5 {
+		d.setListsLength(rsu, int64(rsize))
+	}
 
 	if d.linkctxt.HeadType == objabi.Haix {
 		addDwsectCUSize(".debug_ranges", unit.Lib.Pkg, rsize)
@@ -1609,7 +1674,10 @@ const (
 	COMPUNITHEADERSIZE = 4 + 2 + 4 + 1
 )
 
-func (d *dwctxt) writeUnitInfo(u *sym.CompilationUnit, abbrevsym loader.Sym, infoEpilog loader.Sym) []loader.Sym {
+// writeUnitInfo writes the .debug_info contribution of compilation
+// unit u. In DWARF 5, it also writes the .debug_addr,
+// .debug_str_offsets and .debug_str contributions of the unit.
+func (d *dwctxt) writeUnitInfo(u *sym.CompilationUnit, abbrevsym loader.Sym, us *dwUnitSyms) []loader.Sym {
 	syms := []loader.Sym{}
 	if len(u.Textp) == 0 && u.DWInfo.Child == nil && len(u.VarDIEs) == 0 {
 		return syms
@@ -1623,12 +1691,29 @@ func (d *dwctxt) writeUnitInfo(u *sym.CompilationUnit, abbrevsym loader.Sym, inf
 	// Fields marked with (*) must be changed for 64-bit dwarf
 	// This must match COMPUNITHEADERSIZE above.
 	d.createUnitLength(su, 0) // unit_length (*), will be filled in later.
-	su.AddUint16(d.arch, 4)   // dwarf version (appendix F)
+	var strs *dwStrings
+	if d.linkctxt.dwarf5 {
+		su.AddUint16(d.arch, 5)            // dwarf version
+		su.AddUint8(dwarf.DW_UT_compile)   // unit_type
+		su.AddUint8(uint8(d.arch.PtrSize)) // address_size
+		d.addDwarfAddrRef(su, abbrevsym)   // debug_abbrev_offset
+
+		strs = new(dwStrings)
+		newattr(compunit, dwarf.DW_AT_str_offsets_base, dwarf.DW_CLS_PTR, strOffsetsHeaderSize, dwSym(us.strOffsets))
+		if compunit.Abbrev == dwarf.DW_ABRV_COMPUNIT {
+			newattr(compunit, dwarf.DW_AT_addr_base, dwarf.DW_CLS_PTR, addrHeaderSize, dwSym(us.addr))
+			d.writeaddrs(&us.addrs, us.addr)
+			us.addrsyms = []loader.Sym{us.addr}
+		}
+		strs.indexStrings(compunit)
+	} else {
+		su.AddUint16(d.arch, 4) // dwarf version (appendix F)
 
-	// debug_abbrev_offset (*)
-	d.addDwarfAddrRef(su, abbrevsym)
+		// debug_abbrev_offset (*)
+		d.addDwarfAddrRef(su, abbrevsym)
 
-	su.AddUint8(uint8(d.arch.PtrSize)) // address_size
+		su.AddUint8(uint8(d.arch.PtrSize)) // address_size
+	}
 
 	ds := dwSym(s)
 	dwarf.Uleb128put(d, ds, int64(compunit.Abbrev))
@@ -1651,7 +1736,7 @@ func (d *dwctxt) writeUnitInfo(u *sym.CompilationUnit, abbrevsym loader.Sym, inf
 	for die := compunit.Child; die != nil; die = die.Link {
 		l := len(cu)
 		lastSymSz := int64(len(d.ldr.Data(cu[l-1])))
-		cu = d.putdie(cu, die)
+		cu = d.putdie(cu, die, strs)
 		if lastSymSz != int64(len(d.ldr.Data(cu[l-1]))) {
 			// putdie will sometimes append directly to the last symbol of the list
 			cusize = cusize - lastSymSz + int64(len(d.ldr.Data(cu[l-1])))
@@ -1661,11 +1746,17 @@ func (d *dwctxt) writeUnitInfo(u *sym.CompilationUnit, abbrevsym loader.Sym, inf
 		}
 	}
 
-	culu := d.ldr.MakeSymbolUpdater(infoEpilog)
+	culu := d.ldr.MakeSymbolUpdater(us.infoEpilog)
 	culu.AddUint8(0) // closes compilation unit DIE
-	cu = append(cu, infoEpilog)
+	cu = append(cu, us.infoEpilog)
 	cusize++
 
+	if strs != nil {
+		d.writestrs(strs, us.strOffsets, us.str)
+		us.stroffsetssyms = []loader.Sym{us.strOffsets}
+		us.strsyms = []loader.Sym{us.str}
+	}
+
 	// Save size for AIX symbol table.
 	if d.linkctxt.HeadType == objabi.Haix {
 		addDwsectCUSize(".debug_info", d.getPkgFromCUSym(s), uint64(cusize))
@@ -1846,6 +1937,10 @@ func dwarfGenerateDebugInfo(ctxt *Link) {
 	d.typeRuntimeEface = d.lookupOrDiag("type:runtime.eface")
 	d.typeRuntimeIface = d.lookupOrDiag("type:runtime.iface")
 
+	if ctxt.dwarf5 {
+		dwarf.UseDWARF5()
+	}
+
 	if ctxt.HeadType == objabi.Haix {
 		// Initial map used to store package size for each DWARF section.
 		dwsectCUSize = make(map[string]uint64)
@@ -2064,11 +2159,25 @@ type dwUnitSyms struct {
 	rangeProlog loader.Sym
 	infoEpilog  loader.Sym
 
+	// Inputs for a given unit in DWARF 5.
+	locProlog  loader.Sym
+	addr       loader.Sym
+	strOffsets loader.Sym
+	str        loader.Sym
+	lineStr    loader.Sym
+	addrs      dwAddrs
+
 	// Outputs for a given unit.
 	linesyms   []loader.Sym
 	infosyms   []loader.Sym
 	locsyms    []loader.Sym
 	rangessyms []loader.Sym
+
+	// Outputs for a given unit in DWARF 5.
+	addrsyms       []loader.Sym
+	stroffsetssyms []loader.Sym
+	strsyms        []loader.Sym
+	linestrsyms    []loader.Sym
 }
 
 // dwUnitPortion assembles the DWARF content for a given compilation
@@ -2078,12 +2187,15 @@ type dwUnitSyms struct {
 // hence they have to happen before the call to writeUnitInfo.
 func (d *dwctxt) dwUnitPortion(u *sym.CompilationUnit, abbrevsym loader.Sym, us *dwUnitSyms) {
 	if u.DWInfo.Abbrev != dwarf.DW_ABRV_COMPUNIT_TEXTLESS {
-		us.linesyms = d.writelines(u, us.lineProlog)
+		us.linesyms = d.writelines(u, us.lineProlog, us.lineStr)
+		if us.lineStr != 0 {
+			us.linestrsyms = []loader.Sym{us.lineStr}
+		}
 		base := loader.Sym(u.Textp[0])
 		us.rangessyms = d.writepcranges(u, base, u.PCs, us.rangeProlog)
-		us.locsyms = d.collectUnitLocs(u)
+		us.locsyms = d.collectUnitLocs(u, us.locProlog)
 	}
-	us.infosyms = d.writeUnitInfo(u, abbrevsym, us.infoEpilog)
+	us.infosyms = d.writeUnitInfo(u, abbrevsym, us)
 }
 
 func (d *dwctxt) dwarfGenerateDebugSyms() {
@@ -2115,10 +2227,14 @@ func (d *dwctxt) dwarfGenerateDebugSyms() {
 	}
 
 	// Create the section symbols.
+	locName, rangesName := ".debug_loc", ".debug_ranges"
+	if d.linkctxt.dwarf5 {
+		locName, rangesName = ".debug_loclists", ".debug_rnglists"
+	}
 	frameSym := mkSecSym(".debug_frame")
-	locSym := mkSecSym(".debug_loc")
+	locSym := mkSecSym(locName)
 	lineSym := mkSecSym(".debug_line")
-	rangesSym := mkSecSym(".debug_ranges")
+	rangesSym := mkSecSym(rangesName)
 	infoSym := mkSecSym(".debug_info")
 
 	// Create the section objects
@@ -2139,6 +2255,27 @@ func (d *dwctxt) dwarfGenerateDebugSyms() {
 		us.infoEpilog = mkAnonSym(sym.SDWARFFCN)
 	}
 
+	var addrSec, strOffsetsSec, strSec, lineStrSec dwarfSecInfo
+	if d.linkctxt.dwarf5 {
+		addrSec.syms = []loader.Sym{mkSecSym(".debug_addr")}
+		strOffsetsSec.syms = []loader.Sym{mkSecSym(".debug_str_offsets")}
+		strSec.syms = []loader.Sym{mkSecSym(".debug_str")}
+		lineStrSec.syms = []loader.Sym{mkSecSym(".debug_line_str")}
+		for i, u := range d.linkctxt.compUnits {
+			us := &unitSyms[i]
+			us.locProlog = mkAnonSym(sym.SDWARFLOC)
+			us.addr = mkAnonSym(sym.SDWARFSECT)
+			us.strOffsets = mkAnonSym(sym.SDWARFSECT)
+			us.str = mkAnonSym(sym.SDWARFSECT)
+			us.lineStr = mkAnonSym(sym.SDWARFSECT)
+			if u.DWInfo.Abbrev != dwarf.DW_ABRV_COMPUNIT_TEXTLESS {
+				// The base address of the unit comes first.
+				us.addrs.addrx(loader.Sym(u.Textp[0]), 0)
+				d.convertUnitLists(u, &us.addrs)
+			}
+		}
+	}
+
 	var wg sync.WaitGroup
 	sema := make(chan struct{}, runtime.GOMAXPROCS(0))
 
@@ -2185,6 +2322,12 @@ func (d *dwctxt) dwarfGenerateDebugSyms() {
 		infoSec.syms = append(infoSec.syms, markReachable(r.infosyms)...)
 		locSec.syms = append(locSec.syms, markReachable(r.locsyms)...)
 		rangesSec.syms = append(rangesSec.syms, markReachable(r.rangessyms)...)
+		if d.linkctxt.dwarf5 {
+			addrSec.syms = append(addrSec.syms, markReachable(r.addrsyms)...)
+			strOffsetsSec.syms = append(strOffsetsSec.syms, markReachable(r.stroffsetssyms)...)
+			strSec.syms = append(strSec.syms, markReachable(r.strsyms)...)
+			lineStrSec.syms = append(lineStrSec.syms, markReachable(r.linestrsyms)...)
+		}
 	}
 	dwarfp = append(dwarfp, lineSec)
 	dwarfp = append(dwarfp, frameSec)
@@ -2197,6 +2340,9 @@ func (d *dwctxt) dwarfGenerateDebugSyms() {
 		dwarfp = append(dwarfp, locSec)
 	}
 	dwarfp = append(dwarfp, rangesSec)
+	if d.linkctxt.dwarf5 {
+		dwarfp = append(dwarfp, addrSec, strOffsetsSec, strSec, lineStrSec)
+	}
 
 	// Check to make sure we haven't listed any symbols more than once
 	// in the info section. This used to be done by setting and
@@ -2212,7 +2358,10 @@ func (d *dwctxt) dwarfGenerateDebugSyms() {
 	}
 }
 
-func (d *dwctxt) collectUnitLocs(u *sym.CompilationUnit) []loader.Sym {
+// collectUnitLocs returns the location list symbols of the functions
+// in compilation unit u. In DWARF 5, they are preceded by locProlog,
+// which holds the header of the .debug_loclists contribution of u.
+func (d *dwctxt) collectUnitLocs(u *sym.CompilationUnit, locProlog loader.Sym) []loader.Sym {
 	syms := []loader.Sym{}
 	for _, fn := range u.FuncDIEs {
 		relocs := d.ldr.Relocs(loader.Sym(fn))
@@ -2229,6 +2378,16 @@ func (d *dwctxt) collectUnitLocs(u *sym.CompilationUnit) []loader.Sym {
 			}
 		}
 	}
+	if locProlog != 0 && len(syms) > 0 {
+		lsu := d.ldr.MakeSymbolUpdater(locProlog)
+		d.writeListsHeader(lsu)
+		size := lsu.Size()
+		for _, s := range syms {
+			size += d.ldr.SymSize(s)
+		}
+		d.setListsLength(lsu, size)
+		syms = append([]loader.Sym{locProlog}, syms...)
+	}
 	return syms
 }
 
@@ -2240,6 +2399,9 @@ func dwarfaddshstrings(ctxt *Link, add func(string)) {
 	}
 
 	secs := []string{"abbrev", "frame", "info", "loc", "line", "gdb_scripts", "ranges"}
+	if ctxt.dwarf5 {
+		secs = []string{"abbrev", "frame", "info", "loclists", "line", "gdb_scripts", "rnglists", "addr", "str_offsets", "str", "line_str"}
+	}
 	for _, sec := range secs {
 		add(".debug_" + sec)
 		if ctxt.IsExternal() {
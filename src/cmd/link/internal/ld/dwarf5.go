@@ -0,0 +1,302 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ld
+
+import (
+	"cmd/internal/dwarf"
+	"cmd/internal/objabi"
+	"cmd/link/internal/loader"
+	"cmd/link/internal/sym"
+)
+
+// DWARF 5 output (-dwarf5).
+//
+// The compiler writes the same DWARF for both versions. The DIEs it
+// generates only use forms that mean the same in DWARF 4 and 5, and
+// the linker writes the headers of the units, the line table and the
+// compilation unit and type DIEs in the requested version. The only
+// compiler output that differs between the versions is the location
+// and range lists of functions, which the linker converts from the
+// DWARF 4 .debug_loc and .debug_ranges encoding to the more compact
+// .debug_loclists and .debug_rnglists encoding.
+//
+// Each compilation unit has its own contribution to .debug_addr,
+// .debug_str_offsets, .debug_str, .debug_line_str, .debug_rnglists
+// and .debug_loclists. Lists refer to function addresses through
+// the address table of their unit, and the strings of the linker
+// generated DIEs are indexes into the string offsets table.
+//
+// 64-bit DWARF, which is only used on AIX, is not supported.
+
+// dwAddr is an entry in the address table of a compilation unit.
+type dwAddr struct {
+	sym loader.Sym
+	add int64
+}
+
+// dwAddrs is the address table of a compilation unit.
+type dwAddrs struct {
+	addrs []dwAddr
+	index map[dwAddr]int
+}
+
+// addrx returns the index of s+add in t, adding it if needed.
+func (t *dwAddrs) addrx(s loader.Sym, add int64) int64 {
+	a := dwAddr{s, add}
+	if i, ok := t.index[a]; ok {
+		return int64(i)
+	}
+	if t.index == nil {
+		t.index = make(map[dwAddr]int)
+	}
+	t.index[a] = len(t.addrs)
+	t.addrs = append(t.addrs, a)
+	return int64(len(t.addrs) - 1)
+}
+
+// dwStrings is the string table of a compilation unit.
+type dwStrings struct {
+	strs  []string
+	index map[string]int
+}
+
+// strx returns the index of str in t, adding it if needed.
+func (t *dwStrings) strx(str string) int64 {
+	if i, ok := t.index[str]; ok {
+		return int64(i)
+	}
+	if t.index == nil {
+		t.index = make(map[string]int)
+	}
+	t.index[str] = len(t.strs)
+	t.strs = append(t.strs, str)
+	return int64(len(t.strs) - 1)
+}
+
+// indexStrings replaces the values of the string attributes of die,
+// which must be a compilation unit or type DIE, with their indexes
+// in t. The abbrevs of these DIEs use DW_FORM_strx in DWARF 5.
+func (t *dwStrings) indexStrings(die *dwarf.DWDie) {
+	for a := die.Attr; a != nil; a = a.Link {
+		if a.Cls == dwarf.DW_CLS_STRING {
+			a.Value = t.strx(a.Data.(string))
+		}
+	}
+}
+
+// Sizes of the headers of the .debug_addr and .debug_str_offsets
+// contributions, which DW_AT_addr_base and DW_AT_str_offsets_base
+// point past.
+const (
+	addrHeaderSize       = 4 + 2 + 1 + 1
+	strOffsetsHeaderSize = 4 + 2 + 2
+)
+
+// writeaddrs writes the .debug_addr contribution of a compilation
+// unit with address table t to s.
+func (d *dwctxt) writeaddrs(t *dwAddrs, s loader.Sym) {
+	su := d.ldr.MakeSymbolUpdater(s)
+	d.createUnitLength(su, 0) // unit_length, filled in below
+	su.AddUint16(d.arch, 5)   // version
+	su.AddUint8(uint8(d.arch.PtrSize))
+	su.AddUint8(0) // segment_selector_size
+	for _, a := range t.addrs {
+		su.AddAddrPlus(d.arch, a.sym, a.add)
+	}
+	su.SetUint32(d.arch, 0, uint32(su.Size()-4))
+}
+
+// writestrs writes the .debug_str_offsets and .debug_str
+// contributions of a compilation unit with string table t to
+// offsets and str.
+func (d *dwctxt) writestrs(t *dwStrings, offsets, str loader.Sym) {
+	osu := d.ldr.MakeSymbolUpdater(offsets)
+	d.createUnitLength(osu, 0) // unit_length, filled in below
+	osu.AddUint16(d.arch, 5)   // version
+	osu.AddUint16(d.arch, 0)   // padding
+	ssu := d.ldr.MakeSymbolUpdater(str)
+	for _, s := range t.strs {
+		d.AddDWARFAddrSectionOffset(dwSym(offsets), dwSym(str), ssu.Addstring(s))
+	}
+	osu.SetUint32(d.arch, 0, uint32(osu.Size()-4))
+}
+
+// writeListsHeader writes the header of a .debug_rnglists or
+// .debug_loclists contribution to su. The unit length is set by
+// setListsLength once all the lists of the unit are known.
+func (d *dwctxt) writeListsHeader(su *loader.SymbolBuilder) {
+	d.createUnitLength(su, 0) // unit_length
+	su.AddUint16(d.arch, 5)   // version
+	su.AddUint8(uint8(d.arch.PtrSize))
+	su.AddUint8(0)          // segment_selector_size
+	su.AddUint32(d.arch, 0) // offset_entry_count
+}
+
+// setListsLength sets the unit length in the lists header in su,
+// given the total size of the contribution.
+func (d *dwctxt) setListsLength(su *loader.SymbolBuilder, size int64) {
+	su.SetUint32(d.arch, 0, uint32(size-4))
+}
+
+// convertUnitLists converts the location and range lists of the
+// functions in compilation unit u to DWARF 5, adding the addresses
+// they refer to to t. The offsets of the lists change, so the
+// references to them in the function DIEs are updated as well.
+//
+// This updates symbols loaded from object files, so it must not run
+// concurrently with anything else that uses the loader.
+func (d *dwctxt) convertUnitLists(u *sym.CompilationUnit, t *dwAddrs) {
+	converted := make(map[loader.Sym]map[int64]int64)
+	for _, fn := range u.FuncDIEs {
+		s := loader.Sym(fn)
+		relocs := d.ldr.Relocs(s)
+		var su *loader.SymbolBuilder
+		for i := 0; i < relocs.Count(); i++ {
+			r := relocs.At(i)
+			if r.Type() != objabi.R_DWARFSECREF {
+				continue
+			}
+			rs := r.Sym()
+			var loclist bool
+			switch d.ldr.SymType(rs) {
+			case sym.SDWARFLOC:
+				loclist = true
+			case sym.SDWARFRANGE:
+			default:
+				continue
+			}
+			offsets, ok := converted[rs]
+			if !ok {
+				offsets = d.convertLists(rs, loclist, t)
+				converted[rs] = offsets
+			}
+			off, ok := offsets[r.Add()]
+			if !ok {
+				d.linkctxt.Errorf(s, "reference to %s+%d is not the start of a list", d.ldr.SymName(rs), r.Add())
+				continue
+			}
+			if off == r.Add() {
+				continue
+			}
+			if su == nil {
+				su = d.ldr.MakeSymbolUpdater(s)
+			}
+			su.SetRelocAdd(i, off)
+		}
+	}
+}
+
+// convertLists converts the DWARF 4 location lists (if loclist is
+// set) or range lists in s, as written by the compiler, to DWARF 5.
+// It returns a map from the old offset of each list in s to its new
+// offset.
+//
+// The compiler either starts each list with a base address selection
+// entry, followed by entries relative to that base address, or
+// writes entries relative to the start of the compilation unit with
+// R_ADDRCUOFF relocations (on Darwin). In both cases the converted
+// list sets its base address to the function using an index into
+// the address table, and the entries are offset pairs from there.
+func (d *dwctxt) convertLists(s loader.Sym, loclist bool, t *dwAddrs) map[int64]int64 {
+	endOfList, baseAddressx, startxEndx, offsetPair := dwarf.DW_RLE_end_of_list, dwarf.DW_RLE_base_addressx, dwarf.DW_RLE_startx_endx, dwarf.DW_RLE_offset_pair
+	if loclist {
+		endOfList, baseAddressx, startxEndx, offsetPair = dwarf.DW_LLE_end_of_list, dwarf.DW_LLE_base_addressx, dwarf.DW_LLE_startx_endx, dwarf.DW_LLE_offset_pair
+	}
+
+	ps := d.arch.PtrSize
+	data := d.ldr.Data(s)
+	relocs := d.ldr.Relocs(s)
+	rels := make(map[int]loader.Reloc, relocs.Count())
+	for i := 0; i < relocs.Count(); i++ {
+		r := relocs.At(i)
+		rels[int(r.Off())] = r
+	}
+	word := func(off int) uint64 {
+		if ps == 8 {
+			return d.arch.ByteOrder.Uint64(data[off:])
+		}
+		return uint64(d.arch.ByteOrder.Uint32(data[off:]))
+	}
+	allOnes := ^uint64(0) >> (64 - 8*ps)
+
+	// Relocations in location expressions, with their new offsets.
+	type exprReloc struct {
+		typ objabi.RelocType
+		off int32
+		siz uint8
+		sym loader.Sym
+		add int64
+	}
+	var exprRelocs []exprReloc
+
+	var out []byte
+	offsets := map[int64]int64{0: 0}
+	var base dwAddr // zero for the base address of the unit
+	for off := 0; off+2*ps <= len(data); {
+		r0, ok0 := rels[off]
+		r1, ok1 := rels[off+ps]
+		lo, hi := word(off), word(off+ps)
+		off += 2 * ps
+		switch {
+		case !ok0 && !ok1 && lo == 0 && hi == 0:
+			out = append(out, byte(endOfList))
+			offsets[int64(off)] = int64(len(out))
+			base = dwAddr{}
+			continue
+		case !ok0 && ok1 && lo == allOnes:
+			base = dwAddr{r1.Sym(), r1.Add()}
+			out = append(out, byte(baseAddressx))
+			out = dwarf.AppendUleb128(out, uint64(t.addrx(base.sym, base.add)))
+			continue
+		case !ok0 && !ok1:
+			out = append(out, byte(offsetPair))
+			out = dwarf.AppendUleb128(out, lo)
+			out = dwarf.AppendUleb128(out, hi)
+		case ok0 && ok1 && r0.Type() == objabi.R_ADDRCUOFF && r1.Type() == objabi.R_ADDRCUOFF:
+			if r0.Sym() != r1.Sym() {
+				out = append(out, byte(startxEndx))
+				out = dwarf.AppendUleb128(out, uint64(t.addrx(r0.Sym(), r0.Add())))
+				out = dwarf.AppendUleb128(out, uint64(t.addrx(r1.Sym(), r1.Add())))
+				break
+			}
+			if fn := (dwAddr{r0.Sym(), 0}); base != fn {
+				base = fn
+				out = append(out, byte(baseAddressx))
+				out = dwarf.AppendUleb128(out, uint64(t.addrx(fn.sym, 0)))
+			}
+			out = append(out, byte(offsetPair))
+			out = dwarf.AppendUleb128(out, uint64(r0.Add()))
+			out = dwarf.AppendUleb128(out, uint64(r1.Add()))
+		default:
+			d.linkctxt.Errorf(s, "unexpected list entry at offset %d", off-2*ps)
+			return offsets
+		}
+		if loclist {
+			n := int(d.arch.ByteOrder.Uint16(data[off:]))
+			off += 2
+			out = dwarf.AppendUleb128(out, uint64(n))
+			for k := off; k < off+n; k++ {
+				if r, ok := rels[k]; ok {
+					exprRelocs = append(exprRelocs, exprReloc{r.Type(), int32(len(out) + k - off), r.Siz(), r.Sym(), r.Add()})
+				}
+			}
+			out = append(out, data[off:off+n]...)
+			off += n
+		}
+	}
+
+	su := d.ldr.MakeSymbolUpdater(s)
+	su.SetData(out)
+	su.SetSize(int64(len(out)))
+	su.ResetRelocs()
+	for _, er := range exprRelocs {
+		r, _ := su.AddRel(er.typ)
+		r.SetOff(er.off)
+		r.SetSiz(er.siz)
+		r.SetSym(er.sym)
+		r.SetAdd(er.add)
+	}
+	return offsets
+}
@@ -2084,3 +2084,146 @@ func TestConsistentGoKindAndRuntimeType(t *testing.T) {
 		t.Logf("%d types checked\n", typesChecked)
 	}
 }
+
+func TestDWARF5(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+
+	mustHaveDWARF(t)
+	if runtime.GOOS == "aix" {
+		t.Skip("-dwarf5 is not supported on aix")
+	}
+
+	t.Parallel()
+
+	const prog = `
+package main
+
+import "fmt"
+
+type T struct {
+	A, B int
+	S    string
+}
+
+//go:noinline
+func f(n int) int {
+	s := 0
+	for i := 0; i < n; i++ {
+		t := T{A: i, B: s}
+		s += g(t)
+	}
+	return s
+}
+
+func g(t T) int { return t.A*2 + t.B }
+
+func main() {
+	fmt.Println(f(5))
+}
+`
+
+	dir := t.TempDir()
+	read := func(ldflags string) (*dwarf.Data, []*dwarf.Entry) {
+		t.Helper()
+		f := gobuild(t, dir, prog, "-ldflags="+ldflags)
+		defer f.Close()
+		d, err := f.DWARF()
+		if err != nil {
+			t.Fatalf("error reading DWARF: %v", err)
+		}
+		var entries []*dwarf.Entry
+		rdr := d.Reader()
+		for {
+			e, err := rdr.Next()
+			if err != nil {
+				t.Fatalf("error reading DWARF: %v", err)
+			}
+			if e == nil {
+				break
+			}
+			entries = append(entries, e)
+		}
+		return d, entries
+	}
+	d4, entries4 := read("-compressdwarf=false")
+	d5, entries5 := read("-compressdwarf=false -dwarf5")
+
+	// Apart from the attributes that refer to other sections or
+	// DIEs, the debug info must be the same for both versions, and
+	// the address ranges and line tables must match.
+	if len(entries4) != len(entries5) {
+		t.Fatalf("got %d entries with -dwarf5, want %d", len(entries5), len(entries4))
+	}
+	fields := func(e *dwarf.Entry) map[dwarf.Attr]any {
+		m := make(map[dwarf.Attr]any)
+		for _, f := range e.Field {
+			switch f.Class {
+			case dwarf.ClassAddrPtr, dwarf.ClassLinePtr, dwarf.ClassLocListPtr, dwarf.ClassRangeListPtr,
+				dwarf.ClassReference, dwarf.ClassStrOffsetsPtr:
+				continue
+			}
+			m[f.Attr] = f.Val
+		}
+		return m
+	}
+	var cus, locs int
+	for i, e4 := range entries4 {
+		e5 := entries5[i]
+		if e4.Tag != e5.Tag || !reflect.DeepEqual(fields(e4), fields(e5)) {
+			t.Fatalf("entry %d: got %v with -dwarf5, want %v", i, e5, e4)
+		}
+		if e5.AttrField(dwarf.AttrLocation) != nil && e5.AttrField(dwarf.AttrLocation).Class == dwarf.ClassLocListPtr {
+			locs++
+		}
+		switch e4.Tag {
+		case dwarf.TagCompileUnit:
+			cus++
+			if e5.AttrField(dwarf.AttrStrOffsetsBase) == nil {
+				t.Errorf("compile unit %v has no string offsets base", e5)
+			}
+			lr4, err := d4.LineReader(e4)
+			if err != nil {
+				t.Fatal(err)
+			}
+			lr5, err := d5.LineReader(e5)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (lr4 == nil) != (lr5 == nil) {
+				t.Fatalf("compile unit %v: line table mismatch", e5)
+			}
+			for lr4 != nil {
+				var le4, le5 dwarf.LineEntry
+				err4, err5 := lr4.Next(&le4), lr5.Next(&le5)
+				if err4 != err5 {
+					t.Fatalf("compile unit %v: reading line table: got error %v with -dwarf5, want %v", e5, err5, err4)
+				}
+				if err4 == io.EOF {
+					break
+				}
+				if err4 != nil {
+					t.Fatal(err4)
+				}
+				if le4.Address != le5.Address || le4.File.Name != le5.File.Name || le4.Line != le5.Line {
+					t.Fatalf("compile unit %v: got line entry %#x %s:%d with -dwarf5, want %#x %s:%d", e5, le5.Address, le5.File.Name, le5.Line, le4.Address, le4.File.Name, le4.Line)
+				}
+			}
+			fallthrough
+		case dwarf.TagSubprogram, dwarf.TagInlinedSubroutine, dwarf.TagLexDwarfBlock:
+			r4, err := d4.Ranges(e4)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r5, err := d5.Ranges(e5)
+			if err != nil {
+				t.Fatalf("ranges of %v: %v", e5, err)
+			}
+			if !reflect.DeepEqual(r4, r5) {
+				t.Fatalf("ranges of %v: got %#x with -dwarf5, want %#x", e5, r5, r4)
+			}
+		}
+	}
+	if cus == 0 || locs == 0 {
+		t.Errorf("found %d compile units and %d location lists, want some of each", cus, locs)
+	}
+}
@@ -546,6 +546,34 @@ func elfwriteinterp(out *OutBuf) int {
 	return int(sh.Size)
 }
 
+// elfCheckStaticPIERelocs reports an error if a static PIE needs a
+// dynamic relocation other than a relative one, as those are the only
+// kind the runtime applies when it relocates itself.
+func elfCheckStaticPIERelocs(ctxt *Link) {
+	var relative uint32
+	switch ctxt.Arch.Family {
+	case sys.AMD64:
+		relative = uint32(elf.R_X86_64_RELATIVE)
+	case sys.ARM64:
+		relative = uint32(elf.R_AARCH64_RELATIVE)
+	default:
+		Errorf(nil, "static PIE is not supported on %s", ctxt.Arch.Name)
+		return
+	}
+	ldr := ctxt.loader
+	if ctxt.RelaPLT != 0 && ldr.SymSize(ctxt.RelaPLT) != 0 {
+		Errorf(nil, "static PIE cannot use PLT relocations")
+	}
+	data := ldr.Data(ctxt.Rela)
+	for off := 0; off+24 <= len(data); off += 24 {
+		info := ctxt.Arch.ByteOrder.Uint64(data[off+8:])
+		if typ := elf.R_TYPE64(info); typ != relative {
+			Errorf(nil, "static PIE cannot use dynamic relocation type %d", typ)
+			return
+		}
+	}
+}
+
 // member of .gnu.attributes of MIPS for fpAbi
 const (
 	// No floating point is present in the module (default)
@@ -1522,7 +1550,9 @@ func (ctxt *Link) doelf() {
 	shstrtabAddstring(".shstrtab")
 
 	if !*FlagD { /* -d suppresses dynamic loader format */
-		shstrtabAddstring(".interp")
+		if !ctxt.IsStaticPIE() {
+			shstrtabAddstring(".interp")
+		}
 		shstrtabAddstring(".hash")
 		shstrtabAddstring(".got")
 		if ctxt.IsPPC64() {
@@ -1905,7 +1935,9 @@ func asmbElf(ctxt *Link) {
 		Segtext.Filelen += uint64(o)
 	}
 
-	if !*FlagD { /* -d suppresses dynamic loader format */
+	// A static PIE keeps its dynamic section, for its relocations,
+	// but is started by the kernel without an interpreter.
+	if !*FlagD && !ctxt.IsStaticPIE() { /* -d suppresses dynamic loader format */
 		/* interpreter */
 		sh := elfshname(".interp")
 
@@ -2345,7 +2377,7 @@ elfobj:
 	a += int64(elfwritehdr(ctxt.Out))
 	a += int64(elfwritephdrs(ctxt.Out))
 	a += int64(elfwriteshdrs(ctxt.Out))
-	if !*FlagD {
+	if !*FlagD && !ctxt.IsStaticPIE() {
 		a += int64(elfwriteinterp(ctxt.Out))
 	}
 	if ctxt.IsMIPS() {
@@ -0,0 +1,315 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ld
+
+import (
+	"cmd/link/internal/loader"
+	"cmd/link/internal/sym"
+	"encoding/binary"
+	"strings"
+)
+
+// Identical code folding (-icf).
+//
+// The compiler instantiates a generic function once per GC shape of
+// its type arguments, and for many functions the instantiations for
+// different shapes (say, int and uint, or int and int64) compile to
+// exactly the same machine code. icf finds such functions and keeps
+// just one copy of each, redirecting all references to the others to
+// the copy that is kept.
+//
+// Folding must not change what a program reports about itself, so
+// two functions are only folded if they have the same name once the
+// type arguments are elided, as the runtime does when it prints a
+// function name (see runtime.funcNameForPrint), and the same metadata:
+// frame layout, pc-value tables, files, inlining tree, and funcdata.
+// A stack trace or a runtime.Frame is then the same whichever of the
+// two functions was actually called. In practice this limits folding
+// to instantiations of the same generic function.
+//
+// Two functions with calls or other references to functions that are
+// themselves folded may become identical, so icf partitions the
+// candidates by their contents and then repeatedly refines the
+// partition by the relocation targets until it no longer changes.
+// Recursive instantiations are folded as a group.
+
+// icf folds identical Go text symbols. It runs after deadcode and
+// linksetup, so that ctxt.Textp holds just the reachable text symbols,
+// and before anything that depends on the set of functions, such as
+// DWARF, pclntab or the symbol table.
+func (ctxt *Link) icf() {
+	if ctxt.DynlinkingGo() {
+		// Another module may refer to any of the functions by name.
+		return
+	}
+	ldr := ctxt.loader
+
+	// Group the candidates by everything that does not depend on
+	// the partition: name, size, contents, metadata, and relocations
+	// other than their targets.
+	var groups [][]loader.Sym
+	index := make(map[string]int)
+	var buf []byte
+	var tmp []loader.Sym
+	for _, s := range ctxt.Textp {
+		if !icfCandidate(ldr, s) {
+			continue
+		}
+		buf, tmp = icfKey(ldr, s, buf[:0], tmp)
+		if i, ok := index[string(buf)]; ok {
+			groups[i] = append(groups[i], s)
+		} else {
+			index[string(buf)] = len(groups)
+			groups = append(groups, []loader.Sym{s})
+		}
+	}
+
+	// class maps a candidate that might still be folded to the
+	// index of its equivalence class. Other symbols stand for
+	// themselves.
+	class := make(map[loader.Sym]int)
+	nclass := 0
+	keep := groups[:0]
+	for _, g := range groups {
+		if len(g) < 2 {
+			continue
+		}
+		for _, s := range g {
+			class[s] = nclass
+		}
+		nclass++
+		keep = append(keep, g)
+	}
+	groups = keep
+
+	// Refine the partition until the relocation targets of all the
+	// members of each class are in the same classes.
+	for changed := true; changed; {
+		changed = false
+		var next [][]loader.Sym
+		for _, g := range groups {
+			for k := range index {
+				delete(index, k)
+			}
+			var split [][]loader.Sym
+			for _, s := range g {
+				buf = buf[:0]
+				relocs := ldr.Relocs(s)
+				for ri := 0; ri < relocs.Count(); ri++ {
+					r := relocs.At(ri)
+					if r.Siz() == 0 {
+						continue
+					}
+					if c, ok := class[r.Sym()]; ok {
+						buf = binary.AppendVarint(buf, -int64(c)-1)
+					} else {
+						buf = binary.AppendVarint(buf, int64(r.Sym()))
+					}
+				}
+				if i, ok := index[string(buf)]; ok {
+					split[i] = append(split[i], s)
+				} else {
+					index[string(buf)] = len(split)
+					split = append(split, []loader.Sym{s})
+				}
+			}
+			if len(split) > 1 {
+				changed = true
+				for _, h := range split[1:] {
+					for _, s := range h {
+						class[s] = nclass
+					}
+					nclass++
+				}
+			}
+			for _, h := range split {
+				if len(h) > 1 {
+					next = append(next, h)
+				} else {
+					delete(class, h[0])
+				}
+			}
+		}
+		groups = next
+	}
+
+	if len(groups) == 0 {
+		return
+	}
+
+	// Keep the first member of each class, which is the first in
+	// layout order, and drop the others.
+	folded := make(map[loader.Sym]loader.Sym)
+	var saved int64
+	for _, g := range groups {
+		for _, s := range g[1:] {
+			folded[s] = g[0]
+			saved += ldr.SymSize(s)
+			ldr.SetAttrReachable(s, false)
+		}
+	}
+	isFolded := func(s sym.LoaderSym) bool {
+		_, ok := folded[loader.Sym(s)]
+		return ok
+	}
+	textp := ctxt.Textp[:0]
+	for _, s := range ctxt.Textp {
+		if !isFolded(sym.LoaderSym(s)) {
+			textp = append(textp, s)
+		}
+	}
+	ctxt.Textp = textp
+	for _, lib := range ctxt.Library {
+		for _, unit := range lib.Units {
+			unitTextp := unit.Textp[:0]
+			for _, s := range unit.Textp {
+				if !isFolded(s) {
+					unitTextp = append(unitTextp, s)
+				}
+			}
+			unit.Textp = unitTextp
+		}
+	}
+
+	// Redirect all references to the dropped functions.
+	for s := loader.Sym(1); s < loader.Sym(ldr.NSym()); s++ {
+		if !ldr.AttrReachable(s) {
+			continue
+		}
+		relocs := ldr.Relocs(s)
+		var su *loader.SymbolBuilder
+		for ri := 0; ri < relocs.Count(); ri++ {
+			to, ok := folded[relocs.At(ri).Sym()]
+			if !ok {
+				continue
+			}
+			if su == nil {
+				su = ldr.MakeSymbolUpdater(s)
+			}
+			su.SetRelocSym(ri, to)
+		}
+	}
+
+	if ctxt.Debugvlog != 0 {
+		ctxt.Logf("icf: folded %d functions, %d bytes\n", len(folded), saved)
+	}
+}
+
+// icfCandidate reports whether s may be folded.
+func icfCandidate(ldr *loader.Loader, s loader.Sym) bool {
+	if ldr.SymType(s) != sym.STEXT || ldr.IsExternal(s) || ldr.IsFromAssembly(s) {
+		return false
+	}
+	if ldr.SymVersion(s) != sym.SymVerABIInternal {
+		return false
+	}
+	if ldr.OuterSym(s) != 0 || ldr.SubSym(s) != 0 {
+		return false
+	}
+	if ldr.AttrSpecial(s) || ldr.AttrCgoExport(s) || ldr.AttrShared(s) {
+		return false
+	}
+	if !strings.Contains(ldr.SymName(s), "[") {
+		// Only generic instantiations have names that are equal
+		// when printed without being equal.
+		return false
+	}
+	fi := ldr.FuncInfo(s)
+	return fi.Valid()
+}
+
+// icfName returns the name of a function the way the runtime prints
+// it, with its type arguments elided.
+func icfName(name string) string {
+	i := strings.IndexByte(name, '[')
+	j := strings.LastIndexByte(name, ']')
+	if i < 0 || j <= i {
+		return name
+	}
+	return name[:i] + "[...]" + name[j+1:]
+}
+
+// icfKey appends to buf an encoding of everything about text symbol s
+// that must match for it to be folded with another, apart from the
+// targets of its relocations.
+func icfKey(ldr *loader.Loader, s loader.Sym, buf []byte, tmp []loader.Sym) ([]byte, []loader.Sym) {
+	str := func(s string) {
+		buf = binary.AppendUvarint(buf, uint64(len(s)))
+		buf = append(buf, s...)
+	}
+	num := func(v int64) {
+		buf = binary.AppendVarint(buf, v)
+	}
+	data := func(s loader.Sym) {
+		if s == 0 {
+			num(-1)
+			return
+		}
+		str(string(ldr.Data(s)))
+	}
+
+	str(icfName(ldr.SymName(s)))
+	num(ldr.SymSize(s))
+	num(int64(ldr.SymAlign(s)))
+	str(string(ldr.Data(s)))
+
+	relocs := ldr.Relocs(s)
+	for ri := 0; ri < relocs.Count(); ri++ {
+		r := relocs.At(ri)
+		if r.Siz() == 0 {
+			// Marker relocations, such as R_USETYPE, only
+			// matter to deadcode.
+			continue
+		}
+		num(int64(r.Type()))
+		num(int64(r.Off()))
+		num(int64(r.Siz()))
+		num(r.Add())
+	}
+	num(-1)
+
+	// Everything that goes into the function's entry in pclntab.
+	unit := ldr.SymUnit(s)
+	fi := ldr.FuncInfo(s)
+	fi.Preload()
+	num(int64(fi.Args()))
+	num(int64(fi.Locals()))
+	num(int64(fi.FuncID()))
+	num(int64(fi.FuncFlag()))
+	num(int64(fi.StartLine()))
+	num(int64(fi.NumFile()))
+	for k := 0; k < int(fi.NumFile()); k++ {
+		str(unit.FileTable[fi.File(k)])
+	}
+	num(int64(fi.NumInlTree()))
+	for k := 0; k < int(fi.NumInlTree()); k++ {
+		call := fi.InlTree(k)
+		num(int64(call.Parent))
+		str(unit.FileTable[call.File])
+		num(int64(call.Line))
+		str(icfName(ldr.SymName(call.Func)))
+		num(int64(call.ParentPC))
+	}
+
+	pcsp, pcfile, pcline, pcinline, pcdata := ldr.PcdataAuxs(s, tmp)
+	data(pcsp)
+	data(pcfile)
+	data(pcline)
+	data(pcinline)
+	num(int64(len(pcdata)))
+	for _, p := range pcdata {
+		data(p)
+	}
+
+	// Funcdata symbols are content addressed, but some of them,
+	// such as stack object records, refer to type descriptors, so
+	// they are only compared by identity.
+	funcdata := ldr.Funcdata(s, pcdata)
+	num(int64(len(funcdata)))
+	for _, fd := range funcdata {
+		num(int64(fd))
+	}
+	return buf, funcdata
+}
@@ -0,0 +1,115 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ld
+
+import (
+	"bytes"
+	"internal/testenv"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const icfSource = `
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+//go:noinline
+func Sum[T ~int | ~int64 | ~uint | ~uint64](s []T) T {
+	var t T
+	for _, v := range s {
+		t += v
+	}
+	return t
+}
+
+//go:noinline
+func Index[T ~int | ~uint](s []T, i int) T {
+	return s[i]
+}
+
+func main() {
+	fmt.Println(Sum([]int{1, 2}), Sum([]int64{3}), Sum([]uint{4}), Sum([]uint64{5}))
+	fmt.Println(Index([]int{6}, 0), Index([]uint{7}, 0))
+	Index([]uint{8}, len(os.Args))
+}
+`
+
+func TestICF(t *testing.T) {
+	// Test that -icf folds identical instantiations of generic
+	// functions, and that tracebacks are unchanged.
+	testenv.MustHaveGoBuild(t)
+	t.Parallel()
+
+	tmpdir := t.TempDir()
+	src := filepath.Join(tmpdir, "icf.go")
+	if err := os.WriteFile(src, []byte(icfSource), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	var syms, outs [2][]byte
+	for i, flag := range []string{"", "-icf"} {
+		exe := filepath.Join(tmpdir, "icf"+flag+".exe")
+		cmd := testenv.Command(t, testenv.GoToolPath(t), "build", "-ldflags="+flag, "-o", exe, src)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("%v: %v:\n%s", cmd.Args, err, out)
+		}
+		cmd = testenv.Command(t, exe)
+		out, err := cmd.CombinedOutput()
+		if err == nil {
+			t.Fatalf("%s: executable succeeded unexpectedly:\n%s", exe, out)
+		}
+		outs[i] = out
+		cmd = testenv.Command(t, testenv.GoToolPath(t), "tool", "nm", exe)
+		syms[i], err = cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("%v: %v:\n%s", cmd.Args, err, syms[i])
+		}
+	}
+
+	// The tracebacks contain PCs, which are different.
+	trim := func(b []byte) string {
+		var lines []string
+		for _, l := range strings.Split(string(b), "\n") {
+			if i := strings.Index(l, " +0x"); i >= 0 {
+				l = l[:i]
+			}
+			if i := strings.Index(l, "("); i >= 0 {
+				l = l[:i]
+			}
+			lines = append(lines, l)
+		}
+		return strings.Join(lines, "\n")
+	}
+	if trim(outs[0]) != trim(outs[1]) {
+		t.Errorf("output changed with -icf:\n%s\n===\n%s", outs[0], outs[1])
+	}
+	if !bytes.Contains(outs[1], []byte("main.Index[...]")) {
+		t.Errorf("traceback does not mention main.Index[...]:\n%s", outs[1])
+	}
+
+	count := func(syms []byte, prefix string) int {
+		n := 0
+		for _, l := range strings.Split(string(syms), "\n") {
+			if f := strings.Fields(l); len(f) == 3 && f[1] == "T" && strings.HasPrefix(f[2], prefix) {
+				n++
+			}
+		}
+		return n
+	}
+	for _, fn := range []string{"main.Sum[", "main.Index["} {
+		if n := count(syms[0], fn); n < 2 {
+			t.Errorf("without -icf, got %d instantiations of %s...], want at least 2", n, fn)
+		}
+		if n := count(syms[1], fn); n != 1 {
+			t.Errorf("with -icf, got %d instantiations of %s...], want 1", n, fn)
+		}
+	}
+}
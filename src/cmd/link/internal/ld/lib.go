@@ -828,6 +828,12 @@ func (ctxt *Link) linksetup() {
 		}
 	}
 
+	// Without a dynamic linker there is nothing to resolve dynamic
+	// imports, and the runtime can only apply relative relocations.
+	if ctxt.IsStaticPIE() && ctxt.IsInternal() && havedynamic != 0 {
+		Exitf("-staticpie cannot be used with dynamic imports when linking internally")
+	}
+
 	if ctxt.LinkMode == LinkExternal && ctxt.Arch.Family == sys.PPC64 && buildcfg.GOOS != "aix" {
 		toc := ctxt.loader.LookupOrCreateSym(".TOC.", 0)
 		sb := ctxt.loader.MakeSymbolUpdater(toc)
@@ -1601,7 +1607,11 @@ func (ctxt *Link) hostlink() {
 			if ctxt.UseRelro() {
 				argv = append(argv, "-Wl,-z,relro")
 			}
-			argv = append(argv, "-pie")
+			if ctxt.IsStaticPIE() {
+				argv = append(argv, "-static-pie")
+			} else {
+				argv = append(argv, "-pie")
+			}
 		}
 	case BuildModeCShared:
 		if ctxt.HeadType == objabi.Hdarwin {
@@ -64,6 +64,7 @@ type Link struct {
 	Loaded bool // set after all inputs have been loaded as symbols
 
 	compressDWARF bool
+	dwarf5        bool
 
 	Libdir       []string
 	Library      []*sym.Library
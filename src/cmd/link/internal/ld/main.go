@@ -106,6 +106,8 @@ var (
 	flagEntrySymbol   = flag.String("E", "", "set `entry` symbol name")
 	flagPruneWeakMap  = flag.Bool("pruneweakmap", true, "prune weak mapinit refs")
 	flagRandLayout    = flag.Int64("randlayout", 0, "randomize function layout")
+	flagICF           = flag.Bool("icf", false, "fold identical instantiations of generic functions")
+	flagPGOProfile    = flag.String("pgoprofile", "", "lay out functions using the PGO profile in `file`")
 	cpuprofile        = flag.String("cpuprofile", "", "write cpu profile to `file`")
 	memprofile        = flag.String("memprofile", "", "write memory profile to `file`")
 	memprofilerate    = flag.Int64("memprofilerate", 0, "set runtime.MemProfileRate to `rate`")
@@ -193,9 +195,11 @@ func Main(arch *sys.Arch, theArch Arch) {
 	}
 	flagHeadType := flag.String("H", "", "set header `type`")
 	flag.BoolVar(&ctxt.linkShared, "linkshared", false, "link against installed Go shared libraries")
+	flag.BoolVar(&ctxt.staticPIE, "staticpie", false, "link a position independent executable that does not use a dynamic linker")
 	flag.Var(&ctxt.LinkMode, "linkmode", "set link `mode`")
 	flag.Var(&ctxt.BuildMode, "buildmode", "set build `mode`")
 	flag.BoolVar(&ctxt.compressDWARF, "compressdwarf", true, "compress DWARF if possible")
+	flag.BoolVar(&ctxt.dwarf5, "dwarf5", false, "generate DWARF version 5 debug info")
 	objabi.Flagfn1("B", "add an ELF NT_GNU_BUILD_ID `note` when using ELF; use \"gobuildid\" to generate it from the Go build ID", addbuildinfo)
 	objabi.Flagfn1("L", "add specified `directory` to library path", func(a string) { Lflag(ctxt, a) })
 	objabi.AddVersionFlag() // -V
@@ -273,6 +277,19 @@ func Main(arch *sys.Arch, theArch Arch) {
 		ctxt.BuildMode.Set("exe")
 	}
 
+	if ctxt.staticPIE {
+		if ctxt.BuildMode != BuildModePIE {
+			Exitf("-staticpie requires -buildmode=pie")
+		}
+		if buildcfg.GOOS != "linux" || (buildcfg.GOARCH != "amd64" && buildcfg.GOARCH != "arm64") {
+			Exitf("-staticpie is not supported on %s/%s", buildcfg.GOOS, buildcfg.GOARCH)
+		}
+	}
+
+	if ctxt.dwarf5 && ctxt.HeadType == objabi.Haix {
+		Exitf("-dwarf5 is not supported on %s", ctxt.HeadType)
+	}
+
 	if ctxt.BuildMode != BuildModeShared && flag.NArg() != 1 {
 		usage()
 	}
@@ -362,6 +379,11 @@ func Main(arch *sys.Arch, theArch Arch) {
 	bench.Start("linksetup")
 	ctxt.linksetup()
 
+	if *flagICF {
+		bench.Start("icf")
+		ctxt.icf()
+	}
+
 	bench.Start("dostrdata")
 	ctxt.dostrdata()
 	if buildcfg.Experiment.FieldTrack {
@@ -0,0 +1,216 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ld
+
+import (
+	"bufio"
+	"cmd/internal/pgo"
+	"cmd/link/internal/loader"
+	"os"
+	"sort"
+)
+
+// Profile-guided function layout (-pgoprofile).
+//
+// The linker reads the same profile as the compiler, which cmd/go
+// passes to both when building with -pgo, and places the functions
+// that the profile shows to be hot at the start of the text, with
+// callers and callees that call each other often next to each other.
+// Keeping the hot code of a large program dense reduces instruction
+// TLB and cache misses. The other functions keep their usual order
+// after the hot ones.
+//
+// The hot functions are ordered with the call-chain clustering (C3)
+// algorithm from Ottoni and Maher, "Optimizing Function Placement for
+// Large-Scale Data-Center Applications" (CGO 2017), as also used by
+// lld's --call-graph-profile-sort. Each function starts in a cluster
+// of its own. In order of decreasing hotness, the cluster of each
+// function is appended to the cluster of its hottest caller, unless
+// the result would be too large or much less dense. The clusters are
+// then laid out in order of decreasing density.
+//
+// Functions are moved whole. Their rarely executed blocks are not
+// split into a separate cold section: the pclntab, tracebacks and the
+// runtime all assume that the code of a function is contiguous, so the
+// compiler only moves such blocks off the hot path within their function.
+
+const (
+	// pgoMaxClusterSize is the size above which clusters are not
+	// merged any further.
+	pgoMaxClusterSize = 1 << 20
+
+	// pgoMaxDensityDegradation is the factor by which merging two
+	// clusters may reduce the density of the caller's cluster.
+	pgoMaxDensityDegradation = 8
+)
+
+// pgoCluster is a sequence of functions that are laid out together.
+type pgoCluster struct {
+	syms   []loader.Sym
+	size   int64
+	weight int64
+}
+
+func (c *pgoCluster) density() float64 {
+	return pgoDensity(c.weight, c.size)
+}
+
+// pgoDensity returns the density of a cluster of the given weight and
+// size in bytes.
+func pgoDensity(weight, size int64) float64 {
+	if size < 1 {
+		size = 1
+	}
+	return float64(weight) / float64(size)
+}
+
+// readPGOProfile reads the profile in file, which may be a pprof
+// profile or a profile preprocessed by cmd/preprofile.
+func readPGOProfile(file string) (*pgo.Profile, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r := bufio.NewReader(f)
+	serialized, err := pgo.IsSerialized(r)
+	if err != nil {
+		return nil, err
+	}
+	if serialized {
+		return pgo.FromSerialized(r)
+	}
+	return pgo.FromPProf(r)
+}
+
+// pgoLayout reorders textp, which holds the text symbols that may be
+// moved, according to the profile given by -pgoprofile.
+func (ctxt *Link) pgoLayout(textp []loader.Sym) {
+	p, err := readPGOProfile(*flagPGOProfile)
+	if err != nil {
+		Exitf("reading PGO profile: %v", err)
+	}
+	if p.TotalWeight == 0 {
+		return
+	}
+	ldr := ctxt.loader
+
+	// Profiles name generic functions the way the runtime prints
+	// them, so all the instantiations of a generic function share
+	// its weights.
+	byName := make(map[string][]loader.Sym)
+	for _, s := range textp {
+		name := icfName(ldr.SymName(s))
+		byName[name] = append(byName[name], s)
+	}
+
+	// Sum the weights of the edges between each pair of functions
+	// over their call sites. The hotness of a function is the weight
+	// of all the edges from and to it.
+	type edge struct{ caller, callee string }
+	edges := make(map[edge]int64)
+	hotness := make(map[string]int64)
+	for _, e := range p.NamedEdgeMap.ByWeight {
+		w := p.NamedEdgeMap.Weight[e]
+		if w == 0 || byName[e.CallerName] == nil || byName[e.CalleeName] == nil {
+			continue
+		}
+		edges[edge{e.CallerName, e.CalleeName}] += w
+		hotness[e.CallerName] += w
+		if e.CalleeName != e.CallerName {
+			hotness[e.CalleeName] += w
+		}
+	}
+	if len(hotness) == 0 {
+		return
+	}
+
+	// The hottest caller of each function.
+	callers := make(map[string]edge)
+	for e, w := range edges {
+		if e.caller == e.callee {
+			continue
+		}
+		if c, ok := callers[e.callee]; !ok || w > edges[c] || w == edges[c] && e.caller < c.caller {
+			callers[e.callee] = e
+		}
+	}
+
+	// Functions in order of decreasing hotness, with ties broken by
+	// name to keep the layout deterministic.
+	funcs := make([]string, 0, len(hotness))
+	for name := range hotness {
+		funcs = append(funcs, name)
+	}
+	sort.Slice(funcs, func(i, j int) bool {
+		hi, hj := hotness[funcs[i]], hotness[funcs[j]]
+		if hi != hj {
+			return hi > hj
+		}
+		return funcs[i] < funcs[j]
+	})
+
+	cluster := make(map[string]*pgoCluster, len(funcs))
+	for _, name := range funcs {
+		c := &pgoCluster{syms: byName[name], weight: hotness[name]}
+		for _, s := range c.syms {
+			c.size += ldr.SymSize(s)
+		}
+		cluster[name] = c
+	}
+	for _, name := range funcs {
+		e, ok := callers[name]
+		if !ok {
+			continue
+		}
+		from, to := cluster[name], cluster[e.caller]
+		if from == to || from.size+to.size > pgoMaxClusterSize {
+			continue
+		}
+		merged := pgoDensity(from.weight+to.weight, from.size+to.size)
+		if merged*pgoMaxDensityDegradation < to.density() {
+			continue
+		}
+		to.syms = append(to.syms, from.syms...)
+		to.size += from.size
+		to.weight += from.weight
+		for _, s := range from.syms {
+			cluster[icfName(ldr.SymName(s))] = to
+		}
+	}
+
+	var clusters []*pgoCluster
+	seen := make(map[*pgoCluster]bool)
+	for _, name := range funcs {
+		if c := cluster[name]; !seen[c] {
+			seen[c] = true
+			clusters = append(clusters, c)
+		}
+	}
+	sort.SliceStable(clusters, func(i, j int) bool {
+		return clusters[i].density() > clusters[j].density()
+	})
+
+	// Lay out the clusters, followed by the functions that are not
+	// in any of them in their original order.
+	hot := make(map[loader.Sym]bool)
+	order := make([]loader.Sym, 0, len(textp))
+	for _, c := range clusters {
+		for _, s := range c.syms {
+			hot[s] = true
+			order = append(order, s)
+		}
+	}
+	for _, s := range textp {
+		if !hot[s] {
+			order = append(order, s)
+		}
+	}
+	copy(textp, order)
+
+	if ctxt.Debugvlog != 0 {
+		ctxt.Logf("pgo: laid out %d hot functions in %d clusters\n", len(hot), len(clusters))
+	}
+}
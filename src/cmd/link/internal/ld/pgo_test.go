@@ -0,0 +1,136 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ld
+
+import (
+	"internal/testenv"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const pgoLayoutSource = `
+package main
+
+import (
+	"fmt"
+	"strconv"
+)
+
+//go:noinline
+func hotA(n int) int { return hotB(n) + 1 }
+
+//go:noinline
+func hotB(n int) int {
+	v, _ := strconv.ParseInt(fmt.Sprint(n), 10, 64)
+	return int(v) * 2
+}
+
+//go:noinline
+func cold(n int) int { return n - 1 }
+
+//go:noinline
+func G[T any](t T) T { return t }
+
+func main() {
+	fmt.Println(hotA(3), cold(2), G(4))
+}
+`
+
+// pgoLayoutProfile is a preprocessed profile for pgoLayoutSource, in
+// the format written by cmd/preprofile.
+const pgoLayoutProfile = `GO PREPROFILE V1
+main.main
+main.hotA
+1 100
+main.hotA
+main.hotB
+0 90
+main.hotB
+strconv.ParseInt
+0 50
+main.main
+main.G[...]
+1 40
+`
+
+func TestPGOLayout(t *testing.T) {
+	// Test that -pgoprofile places the hot functions together at the
+	// start of the text, with callees after their callers.
+	testenv.MustHaveGoBuild(t)
+	t.Parallel()
+
+	tmpdir := t.TempDir()
+	src := filepath.Join(tmpdir, "pgo.go")
+	if err := os.WriteFile(src, []byte(pgoLayoutSource), 0666); err != nil {
+		t.Fatal(err)
+	}
+	prof := filepath.Join(tmpdir, "pgo.preprofile")
+	if err := os.WriteFile(prof, []byte(pgoLayoutProfile), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	hot := []string{"main.main", "main.hotA", "main.hotB", "strconv.ParseInt", "main.G[...]"}
+	type textSym struct {
+		name       string
+		addr, size uint64
+	}
+	// layout returns the hot functions in address order and the
+	// size of the text spanned by them.
+	layout := func(flag string) ([]textSym, uint64) {
+		exe := filepath.Join(tmpdir, "pgo.exe")
+		cmd := testenv.Command(t, testenv.GoToolPath(t), "build", "-ldflags="+flag, "-o", exe, src)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("%v: %v:\n%s", cmd.Args, err, out)
+		}
+		if out, err := testenv.Command(t, exe).CombinedOutput(); err != nil || string(out) != "7 1 4\n" {
+			t.Fatalf("%s: got %q, %v; want %q", exe, out, err, "7 1 4\n")
+		}
+		cmd = testenv.Command(t, testenv.GoToolPath(t), "tool", "nm", "-n", "-size", exe)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("%v: %v:\n%s", cmd.Args, err, out)
+		}
+		var syms []textSym
+		for _, l := range strings.Split(string(out), "\n") {
+			f := strings.Fields(l)
+			if len(f) != 4 || f[2] != "T" || !slices.Contains(hot, icfName(f[3])) {
+				continue
+			}
+			addr, err1 := strconv.ParseUint(f[0], 16, 64)
+			size, err2 := strconv.ParseUint(f[1], 10, 64)
+			if err1 != nil || err2 != nil {
+				t.Fatalf("bad nm output: %s", l)
+			}
+			syms = append(syms, textSym{icfName(f[3]), addr, size})
+		}
+		if len(syms) != len(hot) {
+			t.Fatalf("found %v, want %v", syms, hot)
+		}
+		last := syms[len(syms)-1]
+		return syms, last.addr + last.size - syms[0].addr
+	}
+
+	_, span := layout("")
+	syms, pgoSpan := layout("-pgoprofile=" + prof)
+	t.Logf("hot text spans %d bytes without a profile, %d bytes with -pgoprofile", span, pgoSpan)
+
+	var names []string
+	var size uint64
+	for _, s := range syms {
+		names = append(names, s.name)
+		size += s.size
+	}
+	if !slices.Equal(names, hot) {
+		t.Errorf("got hot functions in order %v, want %v", names, hot)
+	}
+	// Allow for the alignment of each function.
+	if pgoSpan > size+uint64(len(syms))*64 {
+		t.Errorf("hot functions of %d bytes span %d bytes of text", size, pgoSpan)
+	}
+}
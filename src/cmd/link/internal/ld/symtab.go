@@ -461,6 +461,10 @@ func (ctxt *Link) symtab(pcln *pclntab) []sym.SymKind {
 	ctxt.xdefine("runtime.end", sym.SBSS, 0)
 	ctxt.xdefine("runtime.epclntab", sym.SRODATA, 0)
 	ctxt.xdefine("runtime.esymtab", sym.SRODATA, 0)
+	if ctxt.IsELF {
+		ctxt.xdefine("runtime.rela", sym.SRODATA, 0)
+		ctxt.xdefine("runtime.erela", sym.SRODATA, 0)
+	}
 
 	// garbage collection symbols
 	s := ldr.CreateSymForUpdate("runtime.gcdata", 0)
@@ -21,6 +21,7 @@ type Target struct {
 
 	linkShared    bool
 	canUsePlugins bool
+	staticPIE     bool
 	IsELF         bool
 }
 
@@ -52,6 +53,12 @@ func (t *Target) IsPIE() bool {
 	return t.BuildMode == BuildModePIE
 }
 
+// IsStaticPIE reports whether we are building a position independent
+// executable that is loaded without a dynamic linker.
+func (t *Target) IsStaticPIE() bool {
+	return t.BuildMode == BuildModePIE && t.staticPIE
+}
+
 func (t *Target) IsSharedGoLink() bool {
 	return t.linkShared
 }
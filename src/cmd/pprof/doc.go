@@ -12,5 +12,11 @@
 //
 //	go tool pprof -h
 //
+// Pprof also reads heap dumps written by runtime/debug.WriteHeapDump,
+// presenting the in-use heap attributed to the chain of object types
+// that retains each object:
+//
+//	go tool pprof heapdump
+//
 // For an example, see https://blog.golang.org/profiling-go-programs.
 package main
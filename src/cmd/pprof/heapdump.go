@@ -0,0 +1,129 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"cmd/internal/heapdump"
+
+	"github.com/google/pprof/profile"
+)
+
+// maxRetainerDepth bounds the number of frames in a retainer path.
+// Longer paths are cut off below the root.
+const maxRetainerDepth = 64
+
+// readHeapDump returns the profile for the heap dump in the named file.
+// It returns a nil profile and error if the file is not a heap dump.
+func readHeapDump(name string) (*profile.Profile, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, nil
+	}
+	defer f.Close()
+	r := bufio.NewReader(f)
+	if hdr, err := r.Peek(len(heapdump.Header)); err != nil || string(hdr) != heapdump.Header {
+		return nil, nil
+	}
+	d, err := heapdump.Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	return heapDumpProfile(d), nil
+}
+
+// heapDumpProfile returns an inuse_space profile for d in which each
+// object is attributed to its retainer path: the chain of object types
+// from a root to the object, along a shortest path. Consecutive objects
+// of the same type, as found in linked lists and trees, are shown as a
+// single frame. Objects unreachable from any root are attributed to an
+// "unreachable" root.
+func heapDumpProfile(d *heapdump.Dump) *profile.Profile {
+	p := &profile.Profile{
+		SampleType: []*profile.ValueType{
+			{Type: "inuse_objects", Unit: "count"},
+			{Type: "inuse_space", Unit: "bytes"},
+		},
+		DefaultSampleType: "inuse_space",
+		Mapping:           []*profile.Mapping{{ID: 1, HasFunctions: true}},
+	}
+	locs := make(map[string]*profile.Location)
+	loc := func(name string) *profile.Location {
+		if l := locs[name]; l != nil {
+			return l
+		}
+		fn := &profile.Function{ID: uint64(len(p.Function) + 1), Name: name, SystemName: name}
+		p.Function = append(p.Function, fn)
+		l := &profile.Location{ID: uint64(len(p.Location) + 1), Mapping: p.Mapping[0], Line: []profile.Line{{Function: fn}}}
+		p.Location = append(p.Location, l)
+		locs[name] = l
+		return l
+	}
+
+	retainers := d.Retainers()
+	samples := make(map[string]*profile.Sample)
+	var names []string
+	for _, o := range d.Objects {
+		names = names[:0]
+		prev := ""
+		for x := o; x != nil; {
+			if name := objectName(x); name != prev {
+				if len(names) == maxRetainerDepth-1 {
+					names = append(names, "...")
+					break
+				}
+				names = append(names, name)
+				prev = name
+			}
+			r, ok := retainers[x]
+			switch {
+			case !ok:
+				names = append(names, "unreachable")
+			case r.Root != nil:
+				names = append(names, rootName(r.Root))
+			}
+			x = r.Object
+		}
+
+		key := strings.Join(names, "\x00")
+		s := samples[key]
+		if s == nil {
+			s = &profile.Sample{Value: make([]int64, 2)}
+			for _, name := range names {
+				s.Location = append(s.Location, loc(name))
+			}
+			samples[key] = s
+			p.Sample = append(p.Sample, s)
+		}
+		s.Value[0]++
+		s.Value[1] += int64(o.Size)
+	}
+	return p
+}
+
+func objectName(o *heapdump.Object) string {
+	if o.Type == nil {
+		return fmt.Sprintf("untyped %d-byte object", o.Size)
+	}
+	return o.Type.Name
+}
+
+func rootName(r *heapdump.Root) string {
+	switch r.Kind {
+	case heapdump.RootData:
+		return "global (data)"
+	case heapdump.RootBSS:
+		return "global (bss)"
+	case heapdump.RootStack:
+		return "stack: " + r.Name
+	case heapdump.RootFinalizer:
+		return "finalizer"
+	}
+	return r.Name
+}
@@ -0,0 +1,59 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"testing"
+)
+
+type retained struct {
+	p   *int
+	pad [128]*int
+}
+
+var retainedGlobal *retained
+
+func TestHeapDumpProfile(t *testing.T) {
+	if runtime.GOOS == "js" || runtime.GOOS == "wasip1" {
+		t.Skipf("WriteHeapDump is not available on %s.", runtime.GOOS)
+	}
+	retainedGlobal = new(retained)
+	defer func() { retainedGlobal = nil }()
+
+	name := filepath.Join(t.TempDir(), "dump")
+	f, err := os.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	debug.WriteHeapDump(f.Fd())
+	f.Close()
+
+	p, _, err := new(fetcher).Fetch(name, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p == nil {
+		t.Fatal("heap dump not recognized")
+	}
+	if err := p.CheckValid(); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"cmd/pprof.retained", "global (bss)"}
+	for _, s := range p.Sample {
+		var got []string
+		for _, l := range s.Location {
+			got = append(got, l.Line[0].Function.Name)
+		}
+		if slices.Equal(got, want) {
+			return
+		}
+	}
+	t.Errorf("no sample with retainer path %q", want)
+}
@@ -610,15 +610,26 @@ func (f *File) Section(name string) *Section {
 // DWARF returns the DWARF debug information for the Mach-O file.
 func (f *File) DWARF() (*dwarf.Data, error) {
 	dwarfSuffix := func(s *Section) string {
+		var suffix string
 		switch {
 		case strings.HasPrefix(s.Name, "__debug_"):
-			return s.Name[8:]
+			suffix = s.Name[8:]
 		case strings.HasPrefix(s.Name, "__zdebug_"):
-			return s.Name[9:]
+			suffix = s.Name[9:]
 		default:
 			return ""
 		}
-
+		if len(s.Name) == 16 {
+			// Section names are at most 16 bytes, so the
+			// names of some DWARF 5 sections are truncated,
+			// as in __debug_str_offs.
+			for _, name := range [...]string{"line_str", "loclists", "rnglists", "str_offsets"} {
+				if strings.HasPrefix(name, suffix) {
+					return name
+				}
+			}
+		}
+		return suffix
 	}
 	sectionData := func(s *Section) ([]byte, error) {
 		b, err := s.Data()
@@ -95,6 +95,8 @@ var depsRules = `
 	< internal/msan
 	< internal/asan
 	< internal/weak
+	< internal/concurrent
+	< iter
 	< sync
 	< internal/bisect
 	< internal/godebug
@@ -102,10 +104,11 @@ var depsRules = `
 	< errors
 	< internal/oserror;
 
-	cmp, internal/race, math/bits
-	< iter
+	cmp, iter
 	< maps, slices;
 
+	internal/cpu < simd;
+
 	internal/oserror, maps, slices
 	< RUNTIME;
 
@@ -161,9 +164,6 @@ var depsRules = `
 	MATH
 	< runtime/metrics;
 
-	RUNTIME, math/rand/v2
-	< internal/concurrent;
-
 	MATH, unicode/utf8
 	< strconv;
 
@@ -435,7 +435,7 @@ var depsRules = `
 	encoding, encoding/json,
 	log, log/internal,
 	log/slog/internal, log/slog/internal/buffer,
-	slices
+	runtime/debug, slices
 	< log/slog
 	< log/slog/internal/slogtest, log/slog/internal/benchmarks;
 
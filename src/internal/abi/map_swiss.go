@@ -11,45 +11,58 @@ import (
 // Map constants common to several packages
 // runtime/runtime-gdb.py:MapTypePrinter contains its own copy
 const (
-	// Maximum number of key/elem pairs a bucket can hold.
-	SwissMapBucketCountBits = 3 // log2 of number of elements in a bucket.
-	SwissMapBucketCount     = 1 << SwissMapBucketCountBits
+	// Number of slots in a group.
+	SwissMapGroupSlotsBits = 3 // log2 of number of slots in a group.
+	SwissMapGroupSlots     = 1 << SwissMapGroupSlotsBits
 
 	// Maximum key or elem size to keep inline (instead of mallocing per element).
 	// Must fit in a uint8.
-	// Note: fast map functions cannot handle big elems (bigger than MapMaxElemBytes).
 	SwissMapMaxKeyBytes  = 128
-	SwissMapMaxElemBytes = 128 // Must fit in a uint8.
+	SwissMapMaxElemBytes = 128
+
+	ctrlEmpty = 0b10000000
+	bitsetLSB = 0x0101010101010101
+
+	// Value of the control word of a group with all slots empty.
+	SwissMapCtrlEmpty = bitsetLSB * uint64(ctrlEmpty)
 )
 
 type SwissMapType struct {
 	Type
-	Key    *Type
-	Elem   *Type
-	Bucket *Type // internal type representing a hash bucket
+	Key   *Type
+	Elem  *Type
+	Group *Type // internal type representing a slot group
 	// function for hashing keys (ptr to key, seed) -> hash
-	Hasher     func(unsafe.Pointer, uintptr) uintptr
-	KeySize    uint8  // size of key slot
-	ValueSize  uint8  // size of elem slot
-	BucketSize uint16 // size of bucket
-	Flags      uint32
+	Hasher    func(unsafe.Pointer, uintptr) uintptr
+	GroupSize uintptr // == Group.Size_
+	SlotSize  uintptr // size of key/elem slot
+	ElemOff   uintptr // offset of elem in key/elem slot
+	Flags     uint32
 }
 
+// Flag values
+const (
+	SwissMapIndirectKey = 1 << iota
+	SwissMapIndirectElem
+	SwissMapReflexiveKey
+	SwissMapNeedKeyUpdate
+	SwissMapHashMightPanic
+)
+
 // Note: flag values must match those used in the TMAP case
 // in ../cmd/compile/internal/reflectdata/reflect.go:writeType.
 func (mt *SwissMapType) IndirectKey() bool { // store ptr to key instead of key itself
-	return mt.Flags&1 != 0
+	return mt.Flags&SwissMapIndirectKey != 0
 }
 func (mt *SwissMapType) IndirectElem() bool { // store ptr to elem instead of elem itself
-	return mt.Flags&2 != 0
+	return mt.Flags&SwissMapIndirectElem != 0
 }
 func (mt *SwissMapType) ReflexiveKey() bool { // true if k==k for all keys
-	return mt.Flags&4 != 0
+	return mt.Flags&SwissMapReflexiveKey != 0
 }
 func (mt *SwissMapType) NeedKeyUpdate() bool { // true if we need to update key on an overwrite
-	return mt.Flags&8 != 0
+	return mt.Flags&SwissMapNeedKeyUpdate != 0
 }
 func (mt *SwissMapType) HashMightPanic() bool { // true if hash function might panic
-	return mt.Flags&16 != 0
+	return mt.Flags&SwissMapHashMightPanic != 0
 }
-
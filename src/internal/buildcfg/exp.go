@@ -71,6 +71,8 @@ func ParseGOEXPERIMENT(goos, goarch, goexp string) (*ExperimentFlags, error) {
 		RegabiWrappers:   regabiSupported,
 		RegabiArgs:       regabiSupported,
 		CoverageRedesign: true,
+		SwissMap:         true,
+		SyncHashTrieMap:  true,
 	}
 
 	// Start with the statically enabled set of experiments.
@@ -2,37 +2,65 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-package concurrent
+package concurrent_test
 
 import (
 	"fmt"
+	. "internal/concurrent"
 	"math"
 	"runtime"
 	"strconv"
-	"strings"
 	"sync"
 	"testing"
-	"unsafe"
 )
 
 func TestHashTrieMap(t *testing.T) {
 	testHashTrieMap(t, func() *HashTrieMap[string, int] {
-		return NewHashTrieMap[string, int]()
+		var m HashTrieMap[string, int]
+		return &m
 	})
 }
 
 func TestHashTrieMapBadHash(t *testing.T) {
 	testHashTrieMap(t, func() *HashTrieMap[string, int] {
-		// Stub out the good hash function with a terrible one.
-		// Everything should still work as expected.
-		m := NewHashTrieMap[string, int]()
-		m.keyHash = func(_ unsafe.Pointer, _ uintptr) uintptr {
-			return 0
-		}
-		return m
+		return NewBadHashTrieMap[string, int]()
+	})
+}
+
+func TestHashTrieMapTruncHash(t *testing.T) {
+	testHashTrieMap(t, func() *HashTrieMap[string, int] {
+		// Stub out the good hash function with a different terrible one
+		// (truncated hash). Everything should still work as expected.
+		// This is useful to test independently to catch issues with
+		// near collisions, where only the last few bits of the hash differ.
+		return NewTruncHashTrieMap[string, int]()
 	})
 }
 
+func TestHashTrieMapUncomparableValues(t *testing.T) {
+	var m HashTrieMap[string, []int]
+	m.Store("a", []int{1})
+	if v, ok := m.Load("a"); !ok || len(v) != 1 || v[0] != 1 {
+		t.Errorf("Load(a) = %v, %v; want [1], true", v, ok)
+	}
+	for _, test := range []struct {
+		name string
+		f    func()
+	}{
+		{"CompareAndSwap", func() { m.CompareAndSwap("a", nil, nil) }},
+		{"CompareAndDelete", func() { m.CompareAndDelete("a", nil) }},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s with uncomparable values did not panic", test.name)
+				}
+			}()
+			test.f()
+		}()
+	}
+}
+
 func testHashTrieMap(t *testing.T, newMap func() *HashTrieMap[string, int]) {
 	t.Run("LoadEmpty", func(t *testing.T) {
 		m := newMap()
@@ -137,6 +165,135 @@ func testHashTrieMap(t *testing.T, newMap func() *HashTrieMap[string, int]) {
 			expectMissing(t, s, 0)(m.Load(s))
 		}
 	})
+	t.Run("Store", func(t *testing.T) {
+		m := newMap()
+
+		for i, s := range testData {
+			expectMissing(t, s, 0)(m.Load(s))
+			m.Store(s, i)
+			expectPresent(t, s, i)(m.Load(s))
+			m.Store(s, i+1)
+			expectPresent(t, s, i+1)(m.Load(s))
+		}
+		for i, s := range testData {
+			expectPresent(t, s, i+1)(m.Load(s))
+		}
+	})
+	t.Run("Swap", func(t *testing.T) {
+		m := newMap()
+
+		for i, s := range testData {
+			expectMissing(t, s, 0)(m.Swap(s, i))
+			expectPresent(t, s, i)(m.Load(s))
+			expectLoaded(t, s, i)(m.Swap(s, i+1))
+			expectPresent(t, s, i+1)(m.Load(s))
+		}
+		for i, s := range testData {
+			expectLoaded(t, s, i+1)(m.Swap(s, i))
+			expectPresent(t, s, i)(m.Load(s))
+		}
+	})
+	t.Run("CompareAndSwap", func(t *testing.T) {
+		m := newMap()
+
+		for i, s := range testData {
+			expectNotSwapped(t, s, 0, i)(m.CompareAndSwap(s, 0, i))
+			expectMissing(t, s, 0)(m.Load(s))
+			expectStored(t, s, i)(m.LoadOrStore(s, i))
+		}
+		for i, s := range testData {
+			expectNotSwapped(t, s, math.MaxInt, i+1)(m.CompareAndSwap(s, math.MaxInt, i+1))
+			expectPresent(t, s, i)(m.Load(s))
+			expectSwapped(t, s, i, i+1)(m.CompareAndSwap(s, i, i+1))
+			expectPresent(t, s, i+1)(m.Load(s))
+		}
+		for i, s := range testData {
+			expectPresent(t, s, i+1)(m.Load(s))
+		}
+	})
+	t.Run("LoadAndDelete", func(t *testing.T) {
+		m := newMap()
+
+		for i, s := range testData {
+			expectStored(t, s, i)(m.LoadOrStore(s, i))
+		}
+		for i, s := range testData {
+			expectLoaded(t, s, i)(m.LoadAndDelete(s))
+			expectMissing(t, s, 0)(m.Load(s))
+			expectMissing(t, s, 0)(m.LoadAndDelete(s))
+		}
+		for _, s := range testData {
+			expectMissing(t, s, 0)(m.Load(s))
+		}
+	})
+	t.Run("Delete", func(t *testing.T) {
+		m := newMap()
+
+		for i, s := range testData {
+			expectStored(t, s, i)(m.LoadOrStore(s, i))
+		}
+		for i, s := range testData {
+			if i%2 == 0 {
+				m.Delete(s)
+			}
+		}
+		for i, s := range testData {
+			if i%2 == 0 {
+				expectMissing(t, s, 0)(m.Load(s))
+			} else {
+				expectPresent(t, s, i)(m.Load(s))
+			}
+		}
+	})
+	t.Run("Clear", func(t *testing.T) {
+		m := newMap()
+
+		for i, s := range testData {
+			expectStored(t, s, i)(m.LoadOrStore(s, i))
+		}
+		m.Clear()
+		for _, s := range testData {
+			expectMissing(t, s, 0)(m.Load(s))
+		}
+		m.Range(func(key string, value int) bool {
+			t.Errorf("unexpected key %v with value %v in cleared map", key, value)
+			return true
+		})
+		for i, s := range testData {
+			expectStored(t, s, i)(m.LoadOrStore(s, i))
+		}
+	})
+	t.Run("ConcurrentCompareAndSwapSharedKeys", func(t *testing.T) {
+		m := newMap()
+
+		for _, s := range testData {
+			expectStored(t, s, 0)(m.LoadOrStore(s, 0))
+		}
+		gmp := runtime.GOMAXPROCS(-1)
+		const n = 10
+		var wg sync.WaitGroup
+		for range gmp {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				for range n {
+					for _, s := range testData {
+						for {
+							v, _ := m.Load(s)
+							if m.CompareAndSwap(s, v, v+1) {
+								break
+							}
+						}
+					}
+				}
+			}()
+		}
+		wg.Wait()
+		for _, s := range testData {
+			expectPresent(t, s, gmp*n)(m.Load(s))
+		}
+	})
 	t.Run("ConcurrentLifecycleUnsharedKeys", func(t *testing.T) {
 		m := newMap()
 
@@ -308,6 +465,28 @@ func expectNotDeleted[K, V comparable](t *testing.T, key K, old V) func(deleted
 	}
 }
 
+func expectSwapped[K, V comparable](t *testing.T, key K, old, new V) func(swapped bool) {
+	t.Helper()
+	return func(swapped bool) {
+		t.Helper()
+
+		if !swapped {
+			t.Errorf("expected key %v with value %v to be in map and swapped for %v", key, old, new)
+		}
+	}
+}
+
+func expectNotSwapped[K, V comparable](t *testing.T, key K, old, new V) func(swapped bool) {
+	t.Helper()
+	return func(swapped bool) {
+		t.Helper()
+
+		if swapped {
+			t.Errorf("expected key %v with value %v to not be in map and thus not swapped for %v", key, old, new)
+		}
+	}
+}
+
 func testDataMap(data []string) map[string]int {
 	m := make(map[string]int)
 	for i, s := range data {
@@ -333,39 +512,3 @@ func init() {
 		testDataLarge[i] = fmt.Sprintf("%b", i)
 	}
 }
-
-func dumpMap[K, V comparable](ht *HashTrieMap[K, V]) {
-	dumpNode(ht, &ht.root.node, 0)
-}
-
-func dumpNode[K, V comparable](ht *HashTrieMap[K, V], n *node[K, V], depth int) {
-	var sb strings.Builder
-	for range depth {
-		fmt.Fprintf(&sb, "\t")
-	}
-	prefix := sb.String()
-	if n.isEntry {
-		e := n.entry()
-		for e != nil {
-			fmt.Printf("%s%p [Entry Key=%v Value=%v Overflow=%p, Hash=%016x]\n", prefix, e, e.key, e.value, e.overflow.Load(), ht.keyHash(unsafe.Pointer(&e.key), ht.seed))
-			e = e.overflow.Load()
-		}
-		return
-	}
-	i := n.indirect()
-	fmt.Printf("%s%p [Indirect Parent=%p Dead=%t Children=[", prefix, i, i.parent, i.dead.Load())
-	for j := range i.children {
-		c := i.children[j].Load()
-		fmt.Printf("%p", c)
-		if j != len(i.children)-1 {
-			fmt.Printf(", ")
-		}
-	}
-	fmt.Printf("]]\n")
-	for j := range i.children {
-		c := i.children[j].Load()
-		if c != nil {
-			dumpNode(ht, c, depth+1)
-		}
-	}
-}
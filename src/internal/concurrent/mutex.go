@@ -0,0 +1,61 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package concurrent
+
+import (
+	"internal/race"
+	"sync/atomic"
+	"unsafe"
+)
+
+// A mutex is a mutual exclusion lock for the nodes of a HashTrieMap.
+//
+// Package sync is implemented using HashTrieMap, so HashTrieMap cannot
+// use sync.Mutex. A mutex counts the goroutines holding or waiting for
+// the lock, and those that find it held wait on a runtime semaphore.
+// Unlike sync.Mutex, it does not spin or avoid starvation, which is
+// acceptable for locks that are held only briefly, by mutations of a
+// single node.
+type mutex struct {
+	state atomic.Int32 // number of goroutines holding or waiting for the lock
+	sema  uint32
+}
+
+// Lock locks m.
+func (m *mutex) Lock() {
+	if m.state.Add(1) != 1 {
+		runtime_SemacquireMutex(&m.sema, false, 1)
+	}
+	if race.Enabled {
+		race.Acquire(unsafe.Pointer(m))
+	}
+}
+
+// Unlock unlocks m.
+func (m *mutex) Unlock() {
+	if race.Enabled {
+		race.Release(unsafe.Pointer(m))
+	}
+	if n := m.state.Add(-1); n != 0 {
+		if n < 0 {
+			panic("internal/concurrent: unlock of unlocked mutex")
+		}
+		// Hand the lock to a waiter.
+		runtime_Semrelease(&m.sema, true, 1)
+	}
+}
+
+// Provided by runtime via linkname.
+//
+//go:linkname runtime_SemacquireMutex
+func runtime_SemacquireMutex(s *uint32, lifo bool, skipframes int)
+
+// Provided by runtime via linkname.
+//
+//go:linkname runtime_Semrelease
+func runtime_Semrelease(s *uint32, handoff bool, skipframes int)
+
+//go:linkname runtime_rand runtime.rand
+func runtime_rand() uint64
@@ -0,0 +1,8 @@
+// Code generated by mkconsts.go. DO NOT EDIT.
+
+//go:build !goexperiment.synchashtriemap
+
+package goexperiment
+
+const SyncHashTrieMap = false
+const SyncHashTrieMapInt = 0
@@ -100,10 +100,6 @@ type Flags struct {
 	// copy of the iteration variable.
 	LoopVar bool
 
-	// CacheProg adds support to cmd/go to use a child process to implement
-	// the build cache; see https://github.com/golang/go/issues/59719.
-	CacheProg bool
-
 	// NewInliner enables a new+improved version of the function
 	// inlining phase within the Go compiler.
 	NewInliner bool
@@ -118,4 +114,15 @@ type Flags struct {
 
 	// SwissMap enables the SwissTable-based map implementation.
 	SwissMap bool
+
+	// SyncHashTrieMap enables the HashTrieMap sync.Map implementation.
+	SyncHashTrieMap bool
+
+	// LockOrder enables checking the order in which goroutines acquire
+	// sync.Mutex and sync.RWMutex locks, reporting potential deadlocks.
+	LockOrder bool
+
+	// SIMD enables the simd package and the compiler intrinsics
+	// that implement it.
+	SIMD bool
 }
@@ -0,0 +1,81 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slog
+
+import (
+	"context"
+	"runtime/debug"
+)
+
+// LogRuntimeEvents logs the events reported by
+// [runtime/debug.SubscribeRuntimeEvents] to l at the given level, until
+// the returned function is called. If l is nil, the default logger is
+// used.
+//
+// Each event becomes a record whose time is the end of the event, with
+// one of the following messages and attributes:
+//
+//   - "gc": cycle, duration, heap_start, heap_marked, heap_goal, forced
+//   - "stop the world": reason, duration, stopping
+//   - "memory limit exceeded": total, limit
+//   - "scavenge": duration, released
+//   - "runtime events dropped": count
+//
+// Sizes are in bytes.
+func LogRuntimeEvents(l *Logger, level Level) (stop func()) {
+	if l == nil {
+		l = Default()
+	}
+	return debug.SubscribeRuntimeEvents(func(e debug.RuntimeEvent) {
+		ctx := context.Background()
+		if !l.Enabled(ctx, level) {
+			return
+		}
+		r := runtimeEventRecord(e, level)
+		_ = l.Handler().Handle(ctx, r)
+	})
+}
+
+// runtimeEventRecord returns the record for e.
+func runtimeEventRecord(e debug.RuntimeEvent, level Level) Record {
+	var r Record
+	switch e := e.(type) {
+	case *debug.GCEvent:
+		r = NewRecord(e.End, level, "gc", 0)
+		r.AddAttrs(
+			Uint64("cycle", uint64(e.Cycle)),
+			Duration("duration", e.Duration),
+			Uint64("heap_start", e.HeapStart),
+			Uint64("heap_marked", e.HeapMarked),
+			Uint64("heap_goal", e.HeapGoal),
+			Bool("forced", e.Forced),
+		)
+	case *debug.STWEvent:
+		r = NewRecord(e.End, level, "stop the world", 0)
+		r.AddAttrs(
+			String("reason", e.Reason),
+			Duration("duration", e.Duration),
+			Duration("stopping", e.Stopping),
+		)
+	case *debug.MemoryLimitEvent:
+		r = NewRecord(e.End, level, "memory limit exceeded", 0)
+		r.AddAttrs(
+			Uint64("total", e.Total),
+			Uint64("limit", e.Limit),
+		)
+	case *debug.ScavengeEvent:
+		r = NewRecord(e.End, level, "scavenge", 0)
+		r.AddAttrs(
+			Duration("duration", e.Duration),
+			Uint64("released", e.Released),
+		)
+	case *debug.DroppedEvents:
+		r = NewRecord(e.End, level, "runtime events dropped", 0)
+		r.AddAttrs(Uint64("count", e.Count))
+	default:
+		r = NewRecord(e.EventTime(), level, "runtime event", 0)
+	}
+	return r
+}
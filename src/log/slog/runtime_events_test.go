@@ -0,0 +1,56 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slog
+
+import (
+	"runtime/debug"
+	"testing"
+	"time"
+)
+
+func TestRuntimeEventRecord(t *testing.T) {
+	end := time.Unix(100, 0)
+	for _, test := range []struct {
+		event debug.RuntimeEvent
+		want  string
+	}{
+		{
+			&debug.GCEvent{End: end, Duration: time.Millisecond, Cycle: 3, HeapStart: 10, HeapMarked: 5, HeapGoal: 20, Forced: true},
+			"gc cycle=3 duration=1ms heap_start=10 heap_marked=5 heap_goal=20 forced=true",
+		},
+		{
+			&debug.STWEvent{End: end, Duration: 2 * time.Microsecond, Stopping: time.Microsecond, Reason: "GC mark termination"},
+			"stop the world reason=GC mark termination duration=2µs stopping=1µs",
+		},
+		{
+			&debug.MemoryLimitEvent{End: end, Total: 200, Limit: 100},
+			"memory limit exceeded total=200 limit=100",
+		},
+		{
+			&debug.ScavengeEvent{End: end, Duration: time.Second, Released: 4096},
+			"scavenge duration=1s released=4096",
+		},
+		{
+			&debug.DroppedEvents{End: end, Count: 7},
+			"runtime events dropped count=7",
+		},
+	} {
+		r := runtimeEventRecord(test.event, LevelInfo)
+		if !r.Time.Equal(end) {
+			t.Errorf("%T: got time %v, want %v", test.event, r.Time, end)
+		}
+		if r.Level != LevelInfo {
+			t.Errorf("%T: got level %v, want %v", test.event, r.Level, LevelInfo)
+		}
+		got := r.Message
+		r.Attrs(func(a Attr) bool {
+			got += " " + a.String()
+			return true
+		})
+		if got != test.want {
+			t.Errorf("%T:\ngot  %s\nwant %s", test.event, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,259 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pprof
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"internal/profile"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+	"slices"
+	"time"
+)
+
+// A ProfileSink receives the profiles collected by a [ContinuousProfiler].
+type ProfileSink interface {
+	// WriteProfile stores one profile. The data is in the
+	// gzip-compressed protocol buffer format written by
+	// [runtime/pprof.Profile.WriteTo] with debug=0, and covers the
+	// period from start to end. The name is "cpu" for the CPU profile
+	// and the [runtime/pprof.Profile] name otherwise.
+	WriteProfile(ctx context.Context, name string, start, end time.Time, data []byte) error
+}
+
+// DirSink returns a [ProfileSink] that writes each profile to a new file
+// in the directory dir, named after the profile and the end of the
+// period it covers, as in "heap-20240102T150405.000Z.pb.gz".
+func DirSink(dir string) ProfileSink {
+	return dirSink(dir)
+}
+
+type dirSink string
+
+func (dir dirSink) WriteProfile(ctx context.Context, name string, start, end time.Time, data []byte) error {
+	file := fmt.Sprintf("%s-%s.pb.gz", name, end.UTC().Format("20060102T150405.000Z"))
+	return os.WriteFile(filepath.Join(string(dir), file), data, 0o666)
+}
+
+// HTTPSink returns a [ProfileSink] that sends each profile to url in the
+// body of a POST request, using client, or [http.DefaultClient] if client
+// is nil.
+// The profile name and the start and end of the period it covers, in
+// RFC 3339 format, are added to the URL as the "name", "start" and "end"
+// query parameters. Responses with a status other than 2xx are reported
+// as errors.
+func HTTPSink(client *http.Client, url string) ProfileSink {
+	return &httpSink{client: cmp.Or(client, http.DefaultClient), url: url}
+}
+
+type httpSink struct {
+	client *http.Client
+	url    string
+}
+
+func (s *httpSink) WriteProfile(ctx context.Context, name string, start, end time.Time, data []byte) error {
+	u, err := url.Parse(s.url)
+	if err != nil {
+		return err
+	}
+	q := u.Query()
+	q.Set("name", name)
+	q.Set("start", start.UTC().Format(time.RFC3339Nano))
+	q.Set("end", end.UTC().Format(time.RFC3339Nano))
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", u.String(), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("uploading %s profile: server response: %s", name, resp.Status)
+	}
+	return nil
+}
+
+// DefaultContinuousProfiles are the profiles collected by a
+// [ContinuousProfiler] whose Profiles field is nil.
+var DefaultContinuousProfiles = []string{"cpu", "heap", "mutex", "block", "goroutine"}
+
+// cumulativeProfiles are the profiles that accumulate counts over the
+// lifetime of the program, which a ContinuousProfiler reports as deltas.
+var cumulativeProfiles = map[string]bool{
+	"allocs":       true,
+	"block":        true,
+	"mutex":        true,
+	"threadcreate": true,
+}
+
+// A ContinuousProfiler collects profiles periodically, in the
+// background, and hands them to a [ProfileSink].
+//
+// Profiles that accumulate counts over the lifetime of the program
+// (allocs, block, mutex and threadcreate) are reported as deltas: each
+// covers only the events of its period, as with the seconds parameter
+// of the HTTP handlers. Other profiles are snapshots taken at the end of
+// the period.
+type ContinuousProfiler struct {
+	// Sink receives the collected profiles. It must be set.
+	Sink ProfileSink
+
+	// Interval is the time between collections. If zero, one minute is used.
+	Interval time.Duration
+
+	// CPUDuration is how long the CPU profile runs at the start of each
+	// interval. If zero, ten seconds is used. It is capped at Interval.
+	CPUDuration time.Duration
+
+	// Profiles lists the profiles to collect: "cpu" or any name
+	// accepted by [runtime/pprof.Lookup]. If nil,
+	// DefaultContinuousProfiles is used.
+	Profiles []string
+
+	// ErrorLog receives errors from collecting or storing profiles.
+	// If nil, errors are logged with the log package's standard logger.
+	ErrorLog *log.Logger
+}
+
+// Run collects profiles until ctx is done, and then returns ctx.Err().
+//
+// Failures to collect or store an individual profile are logged and do
+// not stop Run. A profile reported as deltas is also skipped for the
+// period after a failure to collect it, as there is no base to compute
+// its delta from. Only one CPU profile can be active at a time, so if
+// "cpu" is requested while another CPU profile is running, the CPU
+// profile is skipped for that interval.
+func (p *ContinuousProfiler) Run(ctx context.Context) error {
+	if p.Sink == nil {
+		return errors.New("pprof: ContinuousProfiler has no Sink")
+	}
+	interval := cmp.Or(p.Interval, time.Minute)
+	cpuDuration := min(cmp.Or(p.CPUDuration, 10*time.Second), interval)
+	names := p.Profiles
+	if names == nil {
+		names = DefaultContinuousProfiles
+	}
+	for _, name := range names {
+		if name != "cpu" && pprof.Lookup(name) == nil {
+			return fmt.Errorf("pprof: unknown profile %q", name)
+		}
+	}
+
+	// Collect the base for each cumulative profile. A cumulative
+	// profile with no base, because collecting it failed, is not
+	// reported for the period: it would cover the whole lifetime of
+	// the program instead.
+	start := time.Now()
+	base := make(map[string]*profile.Profile)
+	for _, name := range names {
+		if cumulativeProfiles[name] {
+			if b, err := collectProfile(pprof.Lookup(name)); err != nil {
+				p.logf("collecting %s profile: %v", name, err)
+			} else {
+				base[name] = b
+			}
+		}
+	}
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		if slices.Contains(names, "cpu") {
+			if err := p.runCPU(ctx, cpuDuration); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+
+		end := time.Now()
+		for _, name := range names {
+			if name == "cpu" {
+				continue
+			}
+			prof, err := collectProfile(pprof.Lookup(name))
+			if err != nil {
+				p.logf("collecting %s profile: %v", name, err)
+				delete(base, name)
+				continue
+			}
+			if cumulativeProfiles[name] {
+				b, ok := base[name]
+				base[name] = prof
+				if !ok {
+					continue
+				}
+				if prof, err = deltaProfile(b, prof); err != nil {
+					p.logf("computing %s profile delta: %v", name, err)
+					continue
+				}
+			}
+			var buf bytes.Buffer
+			if err := prof.Write(&buf); err != nil {
+				p.logf("writing %s profile: %v", name, err)
+				continue
+			}
+			p.write(ctx, name, start, end, buf.Bytes())
+		}
+		// The next period starts where this one ended, so that
+		// consecutive delta profiles cover the time in between.
+		start = end
+	}
+}
+
+// runCPU collects a CPU profile for duration d, or until ctx is done.
+// It returns ctx.Err() if ctx is done.
+func (p *ContinuousProfiler) runCPU(ctx context.Context, d time.Duration) error {
+	var buf bytes.Buffer
+	start := time.Now()
+	if err := pprof.StartCPUProfile(&buf); err != nil {
+		p.logf("starting CPU profile: %v", err)
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		pprof.StopCPUProfile()
+		return ctx.Err()
+	case <-timer.C:
+	}
+	pprof.StopCPUProfile()
+	p.write(ctx, "cpu", start, time.Now(), buf.Bytes())
+	return nil
+}
+
+func (p *ContinuousProfiler) write(ctx context.Context, name string, start, end time.Time, data []byte) {
+	if err := p.Sink.WriteProfile(ctx, name, start, end, data); err != nil {
+		p.logf("storing %s profile: %v", name, err)
+	}
+}
+
+func (p *ContinuousProfiler) logf(format string, args ...any) {
+	if p.ErrorLog != nil {
+		p.ErrorLog.Printf("pprof: "+format, args...)
+	} else {
+		log.Printf("pprof: "+format, args...)
+	}
+}
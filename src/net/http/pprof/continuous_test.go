@@ -0,0 +1,169 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pprof
+
+import (
+	"bytes"
+	"context"
+	"internal/profile"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type collectedProfile struct {
+	name       string
+	start, end time.Time
+	prof       *profile.Profile
+}
+
+type testSink struct {
+	mu       sync.Mutex
+	profiles []collectedProfile
+	done     chan struct{}
+	want     int // close done after this many profiles
+}
+
+func (s *testSink) WriteProfile(ctx context.Context, name string, start, end time.Time, data []byte) error {
+	p, err := profile.Parse(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.profiles = append(s.profiles, collectedProfile{name, start, end, p})
+	if len(s.profiles) == s.want {
+		close(s.done)
+	}
+	return nil
+}
+
+func TestContinuousProfiler(t *testing.T) {
+	runtime.SetBlockProfileRate(1)
+	defer runtime.SetBlockProfileRate(0)
+
+	sink := &testSink{done: make(chan struct{}), want: 6}
+	var logBuf strings.Builder
+	p := &ContinuousProfiler{
+		Sink:        sink,
+		Interval:    100 * time.Millisecond,
+		CPUDuration: 50 * time.Millisecond,
+		Profiles:    []string{"cpu", "block", "goroutine"},
+		ErrorLog:    log.New(&logBuf, "", 0),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() { errc <- p.Run(ctx) }()
+
+	// Generate blocking events during each interval.
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		for {
+			select {
+			case <-stop:
+				return
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+	}()
+
+	<-sink.done
+	cancel()
+	if err := <-errc; err != context.Canceled {
+		t.Errorf("Run returned %v, want %v", err, context.Canceled)
+	}
+	if logBuf.Len() > 0 && !strings.Contains(logBuf.String(), "CPU profile") {
+		t.Errorf("unexpected errors logged:\n%s", logBuf.String())
+	}
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	var prevBlockEnd time.Time
+	count := make(map[string]int)
+	for _, c := range sink.profiles {
+		count[c.name]++
+		if c.end.Before(c.start) {
+			t.Errorf("%s profile: end %v before start %v", c.name, c.end, c.start)
+		}
+		if c.name == "block" {
+			// Delta profiles cover consecutive periods.
+			if c.prof.DurationNanos <= 0 {
+				t.Errorf("block profile has duration %d, want > 0", c.prof.DurationNanos)
+			}
+			if !prevBlockEnd.IsZero() && !c.start.Equal(prevBlockEnd) {
+				t.Errorf("block profile starts at %v, want the end of the previous one, %v", c.start, prevBlockEnd)
+			}
+			prevBlockEnd = c.end
+		}
+	}
+	for _, name := range []string{"block", "goroutine"} {
+		if count[name] < 2 {
+			t.Errorf("got %d %s profiles, want at least 2", count[name], name)
+		}
+	}
+}
+
+func TestContinuousProfilerUnknownProfile(t *testing.T) {
+	p := &ContinuousProfiler{Sink: DirSink(t.TempDir()), Profiles: []string{"nonexistent"}}
+	if err := p.Run(context.Background()); err == nil || !strings.Contains(err.Error(), "nonexistent") {
+		t.Errorf("Run with unknown profile returned %v, want error", err)
+	}
+}
+
+func TestDirSink(t *testing.T) {
+	dir := t.TempDir()
+	end := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	if err := DirSink(dir).WriteProfile(context.Background(), "heap", end.Add(-time.Minute), end, []byte("data")); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(filepath.Join(dir, "heap-20240102T150405.000Z.pb.gz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "data" {
+		t.Errorf("got %q, want %q", got, "data")
+	}
+}
+
+func TestHTTPSink(t *testing.T) {
+	var gotQuery, gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		b, _ := io.ReadAll(r.Body)
+		gotQuery, gotBody = r.URL.RawQuery, string(b)
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	end := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	start := end.Add(-time.Minute)
+	ctx := context.Background()
+	if err := HTTPSink(srv.Client(), srv.URL+"/upload?service=x").WriteProfile(ctx, "cpu", start, end, []byte("data")); err != nil {
+		t.Fatal(err)
+	}
+	if want := "end=2024-01-02T15%3A04%3A05Z&name=cpu&service=x&start=2024-01-02T15%3A03%3A05Z"; gotQuery != want {
+		t.Errorf("got query %q, want %q", gotQuery, want)
+	}
+	if gotBody != "data" {
+		t.Errorf("got body %q, want %q", gotBody, "data")
+	}
+	if err := HTTPSink(srv.Client(), srv.URL+"/fail").WriteProfile(ctx, "cpu", start, end, nil); err == nil {
+		t.Errorf("upload to failing server succeeded")
+	}
+}
@@ -66,6 +66,15 @@
 // To view all available profiles, open http://localhost:6060/debug/pprof/
 // in your browser.
 //
+// # Continuous profiling
+//
+// A [ContinuousProfiler] collects profiles in the background at a fixed
+// interval and stores them in a [ProfileSink], such as a local directory
+// ([DirSink]) or an HTTP endpoint ([HTTPSink]):
+//
+//	p := &pprof.ContinuousProfiler{Sink: pprof.DirSink("/var/profiles")}
+//	go p.Run(ctx)
+//
 // For a study of the facility in action, visit
 // https://blog.golang.org/2011/06/profiling-go-programs.html.
 package pprof
@@ -317,20 +326,12 @@ func (name handler) serveDeltaProfile(w http.ResponseWriter, r *http.Request, p
 		serveError(w, http.StatusInternalServerError, "failed to collect profile")
 		return
 	}
-	ts := p1.TimeNanos
-	dur := p1.TimeNanos - p0.TimeNanos
-
-	p0.Scale(-1)
-
-	p1, err = profile.Merge([]*profile.Profile{p0, p1})
+	p1, err = deltaProfile(p0, p1)
 	if err != nil {
 		serveError(w, http.StatusInternalServerError, "failed to compute delta")
 		return
 	}
 
-	p1.TimeNanos = ts // set since we don't know what profile.Merge set for TimeNanos.
-	p1.DurationNanos = dur
-
 	w.Header().Set("Content-Type", "application/octet-stream")
 	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-delta"`, name))
 	p1.Write(w)
@@ -350,6 +351,24 @@ func collectProfile(p *pprof.Profile) (*profile.Profile, error) {
 	return p0, nil
 }
 
+// deltaProfile returns the difference p1 - p0 between two collections of
+// the same profile. It modifies p0.
+func deltaProfile(p0, p1 *profile.Profile) (*profile.Profile, error) {
+	ts := p1.TimeNanos
+	dur := p1.TimeNanos - p0.TimeNanos
+
+	p0.Scale(-1)
+
+	p1, err := profile.Merge([]*profile.Profile{p0, p1})
+	if err != nil {
+		return nil, err
+	}
+
+	p1.TimeNanos = ts // set since we don't know what profile.Merge set for TimeNanos.
+	p1.DurationNanos = dur
+	return p1, nil
+}
+
 var profileSupportsDelta = map[handler]bool{
 	"allocs":       true,
 	"block":        true,
@@ -0,0 +1,26 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build goexperiment.swissmap
+
+package reflect
+
+import (
+	"internal/abi"
+	"unsafe"
+)
+
+func MapGroupOf(x, y Type) Type {
+	grp, _ := groupAndSlotOf(x, y)
+	return grp
+}
+
+func CachedGroupOf(m Type) Type {
+	t := m.(*rtype)
+	if Kind(t.t.Kind_&abi.KindMask) != Map {
+		panic("not map")
+	}
+	tt := (*mapType)(unsafe.Pointer(t))
+	return toType(tt.Group)
+}
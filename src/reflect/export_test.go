@@ -91,19 +91,6 @@ var GCBits = gcbits
 
 func gcbits(any) []byte // provided by runtime
 
-func MapBucketOf(x, y Type) Type {
-	return toType(bucketOf(x.common(), y.common()))
-}
-
-func CachedBucketOf(m Type) Type {
-	t := m.(*rtype)
-	if Kind(t.t.Kind_&abi.KindMask) != Map {
-		panic("not map")
-	}
-	tt := (*mapType)(unsafe.Pointer(t))
-	return toType(tt.Bucket)
-}
-
 type EmbedWithUnexpMeth struct{}
 
 func (EmbedWithUnexpMeth) f() {}
@@ -8,7 +8,6 @@ package reflect
 
 import (
 	"internal/abi"
-	"internal/goarch"
 	"unsafe"
 )
 
@@ -65,32 +64,29 @@ func MapOf(key, elem Type) Type {
 	mt.Hash = fnv1(etyp.Hash, 'm', byte(ktyp.Hash>>24), byte(ktyp.Hash>>16), byte(ktyp.Hash>>8), byte(ktyp.Hash))
 	mt.Key = ktyp
 	mt.Elem = etyp
-	mt.Bucket = bucketOf(ktyp, etyp)
+	group, slot := groupAndSlotOf(key, elem)
+	mt.Group = &group.(*rtype).t
 	mt.Hasher = func(p unsafe.Pointer, seed uintptr) uintptr {
 		return typehash(ktyp, p, seed)
 	}
+	mt.GroupSize = mt.Group.Size()
+	mt.SlotSize = slot.Size()
+	mt.ElemOff = slot.Field(1).Offset
 	mt.Flags = 0
 	if ktyp.Size_ > abi.SwissMapMaxKeyBytes {
-		mt.KeySize = uint8(goarch.PtrSize)
-		mt.Flags |= 1 // indirect key
-	} else {
-		mt.KeySize = uint8(ktyp.Size_)
+		mt.Flags |= abi.SwissMapIndirectKey
 	}
 	if etyp.Size_ > abi.SwissMapMaxElemBytes {
-		mt.ValueSize = uint8(goarch.PtrSize)
-		mt.Flags |= 2 // indirect value
-	} else {
-		mt.ValueSize = uint8(etyp.Size_)
+		mt.Flags |= abi.SwissMapIndirectElem
 	}
-	mt.BucketSize = uint16(mt.Bucket.Size_)
 	if isReflexive(ktyp) {
-		mt.Flags |= 4
+		mt.Flags |= abi.SwissMapReflexiveKey
 	}
 	if needKeyUpdate(ktyp) {
-		mt.Flags |= 8
+		mt.Flags |= abi.SwissMapNeedKeyUpdate
 	}
 	if hashMightPanic(ktyp) {
-		mt.Flags |= 16
+		mt.Flags |= abi.SwissMapHashMightPanic
 	}
 	mt.PtrToThis = 0
 
@@ -98,67 +94,50 @@ func MapOf(key, elem Type) Type {
 	return ti.(Type)
 }
 
-func bucketOf(ktyp, etyp *abi.Type) *abi.Type {
-	if ktyp.Size_ > abi.SwissMapMaxKeyBytes {
-		ktyp = ptrTo(ktyp)
-	}
-	if etyp.Size_ > abi.SwissMapMaxElemBytes {
-		etyp = ptrTo(etyp)
-	}
-
-	// Prepare GC data if any.
-	// A bucket is at most bucketSize*(1+maxKeySize+maxValSize)+ptrSize bytes,
-	// or 2064 bytes, or 258 pointer-size words, or 33 bytes of pointer bitmap.
-	// Note that since the key and value are known to be <= 128 bytes,
-	// they're guaranteed to have bitmaps instead of GC programs.
-	var gcdata *byte
-	var ptrdata uintptr
-
-	size := abi.SwissMapBucketCount*(1+ktyp.Size_+etyp.Size_) + goarch.PtrSize
-	if size&uintptr(ktyp.Align_-1) != 0 || size&uintptr(etyp.Align_-1) != 0 {
-		panic("reflect: bad size computation in MapOf")
-	}
-
-	if ktyp.Pointers() || etyp.Pointers() {
-		nptr := (abi.SwissMapBucketCount*(1+ktyp.Size_+etyp.Size_) + goarch.PtrSize) / goarch.PtrSize
-		n := (nptr + 7) / 8
-
-		// Runtime needs pointer masks to be a multiple of uintptr in size.
-		n = (n + goarch.PtrSize - 1) &^ (goarch.PtrSize - 1)
-		mask := make([]byte, n)
-		base := uintptr(abi.SwissMapBucketCount / goarch.PtrSize)
-
-		if ktyp.Pointers() {
-			emitGCMask(mask, base, ktyp, abi.SwissMapBucketCount)
-		}
-		base += abi.SwissMapBucketCount * ktyp.Size_ / goarch.PtrSize
-
-		if etyp.Pointers() {
-			emitGCMask(mask, base, etyp, abi.SwissMapBucketCount)
-		}
-		base += abi.SwissMapBucketCount * etyp.Size_ / goarch.PtrSize
-
-		word := base
-		mask[word/8] |= 1 << (word % 8)
-		gcdata = &mask[0]
-		ptrdata = (word + 1) * goarch.PtrSize
-
-		// overflow word must be last
-		if ptrdata != size {
-			panic("reflect: bad layout computation in MapOf")
-		}
-	}
-
-	b := &abi.Type{
-		Align_:   goarch.PtrSize,
-		Size_:    size,
-		Kind_:    abi.Struct,
-		PtrBytes: ptrdata,
-		GCData:   gcdata,
-	}
-	s := "bucket(" + stringFor(ktyp) + "," + stringFor(etyp) + ")"
-	b.Str = resolveReflectName(newName(s, "", false, false))
-	return b
+// groupAndSlotOf returns the group type and the slot type of a map
+// with the given key and element types. The layout must match the
+// one built by the compiler in
+// ../cmd/compile/internal/reflectdata/map_swiss.go:SwissMapGroupType.
+func groupAndSlotOf(ktyp, etyp Type) (Type, Type) {
+	// type group struct {
+	//     ctrl uint64
+	//     slots [abi.SwissMapGroupSlots]struct {
+	//         key  keyType
+	//         elem elemType
+	//     }
+	// }
+
+	if ktyp.Size() > abi.SwissMapMaxKeyBytes {
+		ktyp = PointerTo(ktyp)
+	}
+	if etyp.Size() > abi.SwissMapMaxElemBytes {
+		etyp = PointerTo(etyp)
+	}
+
+	fields := []StructField{
+		{
+			Name: "Key",
+			Type: ktyp,
+		},
+		{
+			Name: "Elem",
+			Type: etyp,
+		},
+	}
+	slot := StructOf(fields)
+
+	fields = []StructField{
+		{
+			Name: "Ctrl",
+			Type: TypeFor[uint64](),
+		},
+		{
+			Name: "Slots",
+			Type: ArrayOf(abi.SwissMapGroupSlots, slot),
+		},
+	}
+	group := StructOf(fields)
+	return group, slot
 }
 
 var stringType = rtypeOf("")
@@ -180,8 +159,7 @@ func (v Value) MapIndex(key Value) Value {
 	// of unexported fields.
 
 	var e unsafe.Pointer
-	// TODO(#54766): temporarily disable specialized variants.
-	if false && (tt.Key == stringType || key.kind() == String) && tt.Key == key.typ() && tt.Elem.Size() <= abi.SwissMapMaxElemBytes {
+	if (tt.Key == stringType || key.kind() == String) && tt.Key == key.typ() && tt.Elem.Size() <= abi.SwissMapMaxElemBytes {
 		k := *(*string)(key.ptr)
 		e = mapaccess_faststr(v.typ(), v.pointer(), k)
 	} else {
@@ -246,17 +224,14 @@ type hiter struct {
 	elem        unsafe.Pointer
 	t           unsafe.Pointer
 	h           unsafe.Pointer
-	buckets     unsafe.Pointer
-	bptr        unsafe.Pointer
-	overflow    *[]unsafe.Pointer
-	oldoverflow *[]unsafe.Pointer
-	startBucket uintptr
-	offset      uint8
-	wrapped     bool
-	B           uint8
-	i           uint8
-	bucket      uintptr
-	checkBucket uintptr
+	dirPtr      unsafe.Pointer
+	dirLen      int
+	tab         unsafe.Pointer
+	entryOffset uint64
+	dirOffset   uint64
+	clearSeq    uint64
+	dirIdx      int
+	entryIdx    uint64
 }
 
 func (h *hiter) initialized() bool {
@@ -424,8 +399,7 @@ func (v Value) SetMapIndex(key, elem Value) {
 	key.mustBeExported()
 	tt := (*mapType)(unsafe.Pointer(v.typ()))
 
-	// TODO(#54766): temporarily disable specialized variants.
-	if false && (tt.Key == stringType || key.kind() == String) && tt.Key == key.typ() && tt.Elem.Size() <= abi.SwissMapMaxElemBytes {
+	if (tt.Key == stringType || key.kind() == String) && tt.Key == key.typ() && tt.Elem.Size() <= abi.SwissMapMaxElemBytes {
 		k := *(*string)(key.ptr)
 		if elem.typ() == nil {
 			mapdelete_faststr(v.typ(), v.pointer(), k)
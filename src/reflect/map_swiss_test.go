@@ -0,0 +1,104 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build goexperiment.swissmap
+
+package reflect_test
+
+import (
+	"internal/abi"
+	"internal/goarch"
+	. "reflect"
+	"testing"
+)
+
+func testGCBitsMap(t *testing.T) {
+	// Building blocks for types seen by the compiler (like [2]Xscalar).
+	// The compiler will create the type structures for the derived types,
+	// including their GC metadata.
+	type Xscalar struct{ x uintptr }
+	type Xptr struct{ x *byte }
+	type Xptrscalar struct {
+		*byte
+		uintptr
+	}
+	type Xscalarptr struct {
+		uintptr
+		*byte
+	}
+
+	var Tscalar, Tint64, Tptr, Tscalarptr, Tptrscalar Type
+	{
+		// Building blocks for types constructed by reflect.
+		// This code is in a separate block so that code below
+		// cannot accidentally refer to these.
+		// The compiler must NOT see types derived from these
+		// (for example, [2]Scalar must NOT appear in the program),
+		// or else reflect will use it instead of having to construct one.
+		// The goal is to test the construction.
+		type Scalar struct{ x uintptr }
+		type Ptr struct{ x *byte }
+		type Ptrscalar struct {
+			*byte
+			uintptr
+		}
+		type Scalarptr struct {
+			uintptr
+			*byte
+		}
+		type Int64 int64
+		Tscalar = TypeOf(Scalar{})
+		Tint64 = TypeOf(Int64(0))
+		Tptr = TypeOf(Ptr{})
+		Tscalarptr = TypeOf(Scalarptr{})
+		Tptrscalar = TypeOf(Ptrscalar{})
+	}
+
+	empty := []byte{}
+
+	const groupSlots = abi.SwissMapGroupSlots
+
+	// The control word.
+	hdr := make([]byte, 8/goarch.PtrSize)
+
+	verifyMapGroup := func(t *testing.T, k, e Type, m any, want []byte) {
+		verifyGCBits(t, MapGroupOf(k, e), want)
+		verifyGCBits(t, CachedGroupOf(TypeOf(m)), want)
+	}
+	verifyMapGroup(t,
+		Tscalar, Tptr,
+		map[Xscalar]Xptr(nil),
+		join(hdr, rep(groupSlots, lit(0, 1))))
+	verifyMapGroup(t,
+		Tscalarptr, Tptr,
+		map[Xscalarptr]Xptr(nil),
+		join(hdr, rep(groupSlots, lit(0, 1, 1))))
+	verifyMapGroup(t, Tint64, Tptr,
+		map[int64]Xptr(nil),
+		join(hdr, rep(groupSlots, join(rep(8/goarch.PtrSize, lit(0)), lit(1)))))
+	verifyMapGroup(t,
+		Tscalar, Tscalar,
+		map[Xscalar]Xscalar(nil),
+		empty)
+	verifyMapGroup(t,
+		ArrayOf(2, Tscalarptr), ArrayOf(3, Tptrscalar),
+		map[[2]Xscalarptr][3]Xptrscalar(nil),
+		join(hdr, rep(groupSlots, join(rep(2, lit(0, 1)), rep(3, lit(1, 0))))))
+	verifyMapGroup(t,
+		ArrayOf(64/goarch.PtrSize, Tscalarptr), ArrayOf(64/goarch.PtrSize, Tptrscalar),
+		map[[64 / goarch.PtrSize]Xscalarptr][64 / goarch.PtrSize]Xptrscalar(nil),
+		join(hdr, rep(groupSlots, join(rep(64/goarch.PtrSize, lit(0, 1)), rep(64/goarch.PtrSize, lit(1, 0))))))
+	verifyMapGroup(t,
+		ArrayOf(64/goarch.PtrSize+1, Tscalarptr), ArrayOf(64/goarch.PtrSize, Tptrscalar),
+		map[[64/goarch.PtrSize + 1]Xscalarptr][64 / goarch.PtrSize]Xptrscalar(nil),
+		join(hdr, rep(groupSlots, join(lit(1), rep(64/goarch.PtrSize, lit(1, 0))))))
+	verifyMapGroup(t,
+		ArrayOf(64/goarch.PtrSize, Tscalarptr), ArrayOf(64/goarch.PtrSize+1, Tptrscalar),
+		map[[64 / goarch.PtrSize]Xscalarptr][64/goarch.PtrSize + 1]Xptrscalar(nil),
+		join(hdr, rep(groupSlots, join(rep(64/goarch.PtrSize, lit(0, 1)), lit(1)))))
+	verifyMapGroup(t,
+		ArrayOf(64/goarch.PtrSize+1, Tscalarptr), ArrayOf(64/goarch.PtrSize+1, Tptrscalar),
+		map[[64/goarch.PtrSize + 1]Xscalarptr][64/goarch.PtrSize + 1]Xptrscalar(nil),
+		join(hdr, rep(groupSlots, lit(1, 1))))
+}
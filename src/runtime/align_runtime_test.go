@@ -30,6 +30,11 @@ var AtomicFields = []uintptr{
 	unsafe.Offsetof(heapStatsDelta{}.inStacks),
 	unsafe.Offsetof(heapStatsDelta{}.inPtrScalarBits),
 	unsafe.Offsetof(heapStatsDelta{}.inWorkBufs),
+	unsafe.Offsetof(heapStatsDelta{}.mapAlloc),
+	unsafe.Offsetof(heapStatsDelta{}.mapOverhead),
+	unsafe.Offsetof(heapStatsDelta{}.arenaAlloc),
+	unsafe.Offsetof(heapStatsDelta{}.arenaFree),
+	unsafe.Offsetof(heapStatsDelta{}.arenaChunks),
 	unsafe.Offsetof(lfnode{}.next),
 	unsafe.Offsetof(mstats{}.last_gc_nanotime),
 	unsafe.Offsetof(mstats{}.last_gc_unix),
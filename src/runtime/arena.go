@@ -16,6 +16,8 @@
 // space will not be reused until no more pointers into it are found. There's one
 // exception to this: if an arena allocated memory that isn't exhausted, it's placed
 // back into a pool for reuse. This means that a crash is not always guaranteed.
+// Builds with the race detector or a sanitizer never reuse chunks, so that every
+// access to a freed arena is caught.
 //
 // While this may seem unsafe, it still prevents memory corruption, and is in fact
 // necessary in order to make new(T) a valid implementation of arenas. Such a property
@@ -75,7 +77,7 @@
 //    (a) If the GC is not active, exhausted chunks are set to fault and placed on a
 //        quarantine list.
 //    (b) If the GC is active, exhausted chunks are placed on a fault list and will
-//        go through step (a) at a later point in time.
+//        go through step (a) at the end of the GC cycle.
 //    (c) Any remaining partially-used chunk is placed on a reuse list.
 // (4) Once no more pointers are found into quarantined arena chunks, the sweeper
 //     takes these chunks out of quarantine and places them on the ready list.
@@ -130,6 +132,26 @@ func arena_arena_Slice(arena unsafe.Pointer, slice any, cap int) {
 	((*userArena)(arena)).slice(slice, cap)
 }
 
+// arena_arena_Map is a wrapper around (*userArena).makemap, except that typ
+// is an any (must be a *_type, still) and the result is returned as an any.
+//
+//go:linkname arena_arena_Map arena.runtime_arena_arena_Map
+func arena_arena_Map(arena unsafe.Pointer, typ any, hint int) any {
+	t := (*_type)(efaceOf(&typ).data)
+	if t.Kind_&abi.KindMask != abi.Map {
+		throw("arena_Map: non-map type")
+	}
+	if hint < 0 {
+		panic("userArena.makemap: negative hint")
+	}
+	h := ((*userArena)(arena)).makemap((*maptype)(unsafe.Pointer(t)), hint)
+	var result any
+	e := efaceOf(&result)
+	e._type = t
+	e.data = unsafe.Pointer(h)
+	return result
+}
+
 // arena_arena_Free is a wrapper around (*userArena).free.
 //
 //go:linkname arena_arena_Free arena.runtime_arena_arena_Free
@@ -256,6 +278,10 @@ type userArena struct {
 	// This is just a best-effort way to discover a concurrent allocation
 	// and free. Also used to detect a double-free.
 	defunct atomic.Bool
+
+	// allocated is the number of bytes of Go values allocated into the
+	// arena's chunks, for the /gc/arenas metrics.
+	allocated uintptr
 }
 
 // newUserArena creates a new userArena ready to be used.
@@ -355,6 +381,8 @@ func (a *userArena) free() {
 			unlock(&userArenaState.lock)
 		}
 	}
+	recordUserArenaStats(0, a.allocated, -int64(len(a.refs)*int(userArenaChunkBytes)))
+
 	// nil out a.active so that a race with freeing will more likely cause a crash.
 	a.active = nil
 	a.refs = nil
@@ -374,6 +402,16 @@ func (a *userArena) alloc(typ *_type, cap int) unsafe.Pointer {
 		}
 		s = a.refill()
 	}
+	if uintptr(x) >= s.base() && uintptr(x) < s.limit {
+		// x was allocated in the chunk, rather than redirected to
+		// the heap or to zerobase.
+		size := typ.Size_
+		if cap >= 0 {
+			size *= uintptr(cap)
+		}
+		a.allocated += size
+		recordUserArenaStats(size, 0, 0)
+	}
 	return x
 }
 
@@ -418,9 +456,24 @@ func (a *userArena) refill() *mspan {
 	}
 	a.refs = append(a.refs, x)
 	a.active = s
+	recordUserArenaStats(0, 0, int64(userArenaChunkBytes))
 	return s
 }
 
+// recordUserArenaStats records alloc bytes allocated into user arenas,
+// free bytes released by freeing user arenas, and a change of chunks
+// bytes in the memory of chunks held by user arenas, in the /gc/arenas
+// metrics.
+func recordUserArenaStats(alloc, free uintptr, chunks int64) {
+	mp := acquirem()
+	stats := memstats.heapStats.acquire()
+	atomic.Xadd64(&stats.arenaAlloc, int64(alloc))
+	atomic.Xadd64(&stats.arenaFree, int64(free))
+	atomic.Xaddint64(&stats.arenaChunks, chunks)
+	memstats.heapStats.release()
+	releasem(mp)
+}
+
 type liveUserArenaChunk struct {
 	*mspan // Must represent a user arena chunk.
 
@@ -501,6 +554,11 @@ func (s *mspan) userArenaNextFree(typ *_type, cap int) unsafe.Pointer {
 	if s.needzero != 0 {
 		throw("arena chunk needs zeroing, but should already be zeroed")
 	}
+	if asanenabled {
+		// The rest of the chunk stays poisoned, so that ASan reports
+		// accesses outside of the values allocated in it.
+		asanunpoison(ptr, size)
+	}
 	// Set up heap bitmap and do extra accounting.
 	if typ.Pointers() {
 		if cap >= 0 {
@@ -797,14 +855,14 @@ func newUserArenaChunk() (unsafe.Pointer, *mspan) {
 	}
 
 	if asanenabled {
-		// TODO(mknyszek): Track individual objects.
+		// Poison the whole chunk. userArenaNextFree unpoisons
+		// each value as it is allocated.
 		rzSize := computeRZlog(span.elemsize)
 		span.elemsize -= rzSize
 		span.largeType.Size_ = span.elemsize
 		rzStart := span.base() + span.elemsize
 		span.userArenaChunkFree = makeAddrRange(span.base(), rzStart)
-		asanpoison(unsafe.Pointer(rzStart), span.limit-rzStart)
-		asanunpoison(unsafe.Pointer(span.base()), span.elemsize)
+		asanpoison(unsafe.Pointer(span.base()), span.limit-span.base())
 	}
 
 	if rate := MemProfileRate; rate > 0 {
@@ -971,25 +1029,40 @@ func freeUserArenaChunk(s *mspan, x unsafe.Pointer) {
 	mp := acquirem()
 
 	// We can only set user arenas to fault if we're in the _GCoff phase.
+	if gcphase == _GCoff {
+		s.setUserArenaChunkToFault()
+		faultUserArenaChunks()
+
+		// Until the chunk is set to fault, keep it alive.
+		KeepAlive(x)
+	} else {
+		// Put the user arena on the fault list.
+		lock(&userArenaState.lock)
+		userArenaState.fault = append(userArenaState.fault, liveUserArenaChunk{s, x})
+		unlock(&userArenaState.lock)
+	}
+	releasem(mp)
+}
+
+// faultUserArenaChunks sets the chunks on the fault list to fault, if the
+// GC is not active. It is called at the end of each GC cycle, so that
+// chunks freed while the GC was running fault promptly even if no other
+// arena is freed.
+func faultUserArenaChunks() {
+	// Required by setUserArenaChunkToFault.
+	mp := acquirem()
 	if gcphase == _GCoff {
 		lock(&userArenaState.lock)
 		faultList := userArenaState.fault
 		userArenaState.fault = nil
 		unlock(&userArenaState.lock)
 
-		s.setUserArenaChunkToFault()
 		for _, lc := range faultList {
 			lc.mspan.setUserArenaChunkToFault()
 		}
 
 		// Until the chunks are set to fault, keep them alive via the fault list.
-		KeepAlive(x)
 		KeepAlive(faultList)
-	} else {
-		// Put the user arena on the fault list.
-		lock(&userArenaState.lock)
-		userArenaState.fault = append(userArenaState.fault, liveUserArenaChunk{s, x})
-		unlock(&userArenaState.lock)
 	}
 	releasem(mp)
 }
@@ -524,3 +524,28 @@ func TestUserArenaClonePanic(t *testing.T) {
 		t.Errorf("expected panic from Clone")
 	}
 }
+
+func TestUserArenaMap(t *testing.T) {
+	for _, hint := range []int{0, 8, 100} {
+		a := NewUserArena()
+		var x any = map[int]*smallPointer(nil)
+		a.Map(&x, hint)
+		m := x.(map[int]*smallPointer)
+		for i := range 1000 {
+			m[i] = &smallPointer{}
+			if i%100 == 0 {
+				// The map values are only reachable through the arena.
+				GC()
+			}
+		}
+		if len(m) != 1000 {
+			t.Errorf("hint %d: len(m) = %d, want 1000", hint, len(m))
+		}
+		for i := range 1000 {
+			if m[i] == nil {
+				t.Fatalf("hint %d: m[%d] = nil", hint, i)
+			}
+		}
+		a.Free()
+	}
+}
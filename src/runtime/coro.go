@@ -156,6 +156,8 @@ func coroswitch_m(gp *g) {
 			// The CAS failed: use casgstatus, which will take care of
 			// coordinating with the garbage collector about the state change.
 			casgstatus(gp, _Grunning, _Gwaiting)
+		} else if gp.labelAccount != nil {
+			gp.labelAccount.chargeRunning(gp, _Grunning, _Gwaiting)
 		}
 
 		// Clear gp.m.
@@ -213,6 +215,8 @@ func coroswitch_m(gp *g) {
 		// coordinating with the garbage collector about the state change.
 		casgstatus(gnext, _Gwaiting, _Grunnable)
 		casgstatus(gnext, _Grunnable, _Grunning)
+	} else if gnext.labelAccount != nil {
+		gnext.labelAccount.chargeRunning(gnext, _Gwaiting, _Grunning)
 	}
 
 	// Donate locked state.
@@ -0,0 +1,186 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package debug
+
+import (
+	"sync"
+	"time"
+)
+
+// A RuntimeEvent is an event reported by [SubscribeRuntimeEvents].
+// It is one of [*GCEvent], [*STWEvent], [*MemoryLimitEvent],
+// [*ScavengeEvent] or [*DroppedEvents].
+type RuntimeEvent interface {
+	// EventTime returns the time at which the event ended.
+	EventTime() time.Time
+}
+
+// A GCEvent reports a completed garbage collection cycle.
+// It carries the information printed by GODEBUG=gctrace=1.
+type GCEvent struct {
+	End      time.Time     // end of the cycle
+	Duration time.Duration // wall time from the start to the end of the cycle
+
+	Cycle      uint32 // number of the cycle, starting at 1
+	HeapStart  uint64 // live heap in bytes when the cycle started
+	HeapMarked uint64 // heap in bytes marked live by the cycle
+	HeapGoal   uint64 // heap goal in bytes for the cycle
+	Forced     bool   // whether the cycle was forced by runtime.GC
+}
+
+// An STWEvent reports a stop-the-world pause.
+type STWEvent struct {
+	End      time.Time     // time the world was restarted
+	Duration time.Duration // time from starting to stop the world until it was restarted
+	Stopping time.Duration // part of Duration spent waiting for goroutines to stop
+	Reason   string        // why the world was stopped, such as "GC mark termination"
+}
+
+// A MemoryLimitEvent reports a garbage collection cycle that ended with
+// the total memory mapped by the Go runtime above the memory limit
+// (see [SetMemoryLimit]).
+type MemoryLimitEvent struct {
+	End   time.Time // end of the garbage collection cycle
+	Total uint64    // total memory in bytes
+	Limit uint64    // memory limit in bytes
+}
+
+// A ScavengeEvent reports memory returned to the operating system by the
+// background scavenger, from the time it woke up until it went back to
+// sleep.
+type ScavengeEvent struct {
+	End      time.Time     // time the scavenger went back to sleep
+	Duration time.Duration // time spent scavenging, excluding time asleep
+	Released uint64        // memory in bytes returned to the operating system
+}
+
+// DroppedEvents reports that the runtime discarded events because they
+// were not delivered quickly enough.
+type DroppedEvents struct {
+	End   time.Time // time the loss was noticed
+	Count uint64    // number of events lost
+}
+
+func (e *GCEvent) EventTime() time.Time          { return e.End }
+func (e *STWEvent) EventTime() time.Time         { return e.End }
+func (e *MemoryLimitEvent) EventTime() time.Time { return e.End }
+func (e *ScavengeEvent) EventTime() time.Time    { return e.End }
+func (e *DroppedEvents) EventTime() time.Time    { return e.End }
+
+// runtimeEvents is the state behind SubscribeRuntimeEvents.
+var runtimeEvents struct {
+	mu      sync.Mutex
+	subs    map[*func(RuntimeEvent)]bool
+	started bool // whether deliverRuntimeEvents is running
+}
+
+// SubscribeRuntimeEvents arranges for f to be called with each event
+// the runtime reports from now on: completed garbage collection cycles,
+// stop-the-world pauses, memory limit overruns and background scavenger
+// activity. It returns a function that cancels the subscription.
+//
+// The events are structured counterparts of the text printed by
+// GODEBUG=gctrace=1 and GODEBUG=scavtrace=1, and are collected only
+// while there is at least one subscriber.
+//
+// f is called from a single goroutine, one event at a time, in the order
+// the events were reported. The runtime buffers a limited number of
+// events; if f is too slow, events are discarded and reported as a
+// [*DroppedEvents]. To receive events on a channel, use a function that
+// sends without blocking:
+//
+//	ch := make(chan debug.RuntimeEvent, 100)
+//	cancel := debug.SubscribeRuntimeEvents(func(e debug.RuntimeEvent) {
+//		select {
+//		case ch <- e:
+//		default:
+//		}
+//	})
+func SubscribeRuntimeEvents(f func(RuntimeEvent)) (cancel func()) {
+	key := &f
+	runtimeEvents.mu.Lock()
+	defer runtimeEvents.mu.Unlock()
+	if runtimeEvents.subs == nil {
+		runtimeEvents.subs = make(map[*func(RuntimeEvent)]bool)
+	}
+	runtimeEvents.subs[key] = true
+	setDebugEvents(true)
+	if !runtimeEvents.started {
+		runtimeEvents.started = true
+		go deliverRuntimeEvents()
+	}
+	return func() {
+		runtimeEvents.mu.Lock()
+		defer runtimeEvents.mu.Unlock()
+		delete(runtimeEvents.subs, key)
+		if len(runtimeEvents.subs) == 0 {
+			setDebugEvents(false)
+		}
+	}
+}
+
+// deliverRuntimeEvents reads events from the runtime and calls the
+// subscribed functions.
+func deliverRuntimeEvents() {
+	var buf [64][8]uint64
+	var subs []func(RuntimeEvent)
+	for {
+		n, dropped := readDebugEvents(buf[:])
+
+		runtimeEvents.mu.Lock()
+		subs = subs[:0]
+		for f := range runtimeEvents.subs {
+			subs = append(subs, *f)
+		}
+		runtimeEvents.mu.Unlock()
+
+		if dropped > 0 {
+			deliverRuntimeEvent(subs, &DroppedEvents{End: time.Now(), Count: dropped})
+		}
+		for _, raw := range buf[:n] {
+			deliverRuntimeEvent(subs, decodeRuntimeEvent(raw))
+		}
+	}
+}
+
+func deliverRuntimeEvent(subs []func(RuntimeEvent), e RuntimeEvent) {
+	if e == nil {
+		return
+	}
+	for _, f := range subs {
+		f(e)
+	}
+}
+
+// decodeRuntimeEvent decodes an event recorded by the runtime.
+// The format is described in runtime/debugevents.go.
+func decodeRuntimeEvent(raw [8]uint64) RuntimeEvent {
+	end := time.Unix(0, int64(raw[1]))
+	dur := time.Duration(raw[2])
+	switch raw[0] {
+	case 1:
+		return &GCEvent{
+			End:        end,
+			Duration:   dur,
+			Cycle:      uint32(raw[3]),
+			HeapStart:  raw[4],
+			HeapMarked: raw[5],
+			HeapGoal:   raw[6],
+			Forced:     raw[7] != 0,
+		}
+	case 2:
+		return &STWEvent{
+			End:      end,
+			Duration: dur,
+			Reason:   debugEventSTWReason(raw[3]),
+			Stopping: time.Duration(raw[4]),
+		}
+	case 3:
+		return &MemoryLimitEvent{End: end, Total: raw[3], Limit: raw[4]}
+	case 4:
+		return &ScavengeEvent{End: end, Duration: dur, Released: raw[3]}
+	}
+	return nil
+}
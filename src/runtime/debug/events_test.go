@@ -0,0 +1,55 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package debug_test
+
+import (
+	"runtime"
+	. "runtime/debug"
+	"testing"
+	"time"
+)
+
+func TestSubscribeRuntimeEvents(t *testing.T) {
+	events := make(chan RuntimeEvent, 100)
+	cancel := SubscribeRuntimeEvents(func(e RuntimeEvent) {
+		select {
+		case events <- e:
+		default:
+		}
+	})
+	defer cancel()
+
+	start := time.Now()
+	runtime.GC()
+
+	var gc *GCEvent
+	var stw *STWEvent
+	timeout := time.After(10 * time.Second)
+	for gc == nil || stw == nil {
+		select {
+		case e := <-events:
+			switch e := e.(type) {
+			case *GCEvent:
+				if e.Forced {
+					gc = e
+				}
+			case *STWEvent:
+				stw = e
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for events: got GC event %v, STW event %v", gc, stw)
+		}
+	}
+
+	if gc.Cycle == 0 || gc.HeapGoal == 0 {
+		t.Errorf("GC event %+v: want non-zero Cycle and HeapGoal", gc)
+	}
+	if gc.End.Before(start.Add(-time.Second)) || gc.Duration < 0 {
+		t.Errorf("GC event %+v: bad End or Duration for cycle started at %v", gc, start)
+	}
+	if stw.Reason == "" || stw.Stopping > stw.Duration {
+		t.Errorf("STW event %+v: want Reason and Stopping <= Duration", stw)
+	}
+}
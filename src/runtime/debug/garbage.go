@@ -164,7 +164,20 @@ func SetPanicOnFault(enabled bool) bool {
 // connected to a pipe or socket whose other end is in the same Go
 // process; instead, use a temporary file or network socket.
 //
-// The heap dump format is defined at https://golang.org/s/go15heapdump.
+// The heap dump format is described in the runtime package source,
+// in runtime/heapdump.go. As of Go 1.24, it differs from the format
+// described at https://golang.org/s/go15heapdump in two ways: the header
+// is "go1.24 heap dump\n" instead of "go1.7 heap dump\n", and each object
+// record has, after the address of the object, the address of its type,
+// which is described by an earlier type record. The type address is 0
+// for small objects whose type the runtime does not record. Readers of
+// the earlier format must be updated to read the new one.
+//
+// The "go tool pprof" command reads heap dumps and reports the in-use
+// heap by the chain of object types that retains each object.
+//
+// See [SetMemoryLimitHeapDump] to write a heap dump automatically when
+// the memory limit is exceeded.
 func WriteHeapDump(fd uintptr)
 
 // SetTraceback sets the amount of detail printed by the runtime in
@@ -0,0 +1,84 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package debug
+
+import (
+	"internal/poll"
+	"os"
+	"runtime"
+	"sync"
+)
+
+// limitDump is the state behind SetMemoryLimitHeapDump.
+var limitDump struct {
+	mu      sync.Mutex
+	f       *os.File // file to write the next dump to, or nil
+	started bool     // whether limitDumper is running
+}
+
+// SetMemoryLimitHeapDump arranges for a heap dump, as written by
+// [WriteHeapDump], to be written to f the first time a garbage
+// collection ends with the total memory mapped by the Go runtime above
+// the memory limit (see [SetMemoryLimit]).
+//
+// At most one dump is written per call. Once the dump has been written,
+// the file is closed, and SetMemoryLimitHeapDump must be called again to
+// request another dump. Calling SetMemoryLimitHeapDump again before the
+// limit is exceeded replaces the earlier request. To cancel a pending
+// request, call SetMemoryLimitHeapDump(nil).
+//
+// SetMemoryLimitHeapDump duplicates f's file descriptor, so the caller may
+// safely close f as soon as SetMemoryLimitHeapDump returns.
+// As with WriteHeapDump, f must not be connected to a pipe or socket
+// whose other end is in the same Go process.
+//
+// The dump is written by a background goroutine shortly after the
+// garbage collection that observed the limit being exceeded, and, like
+// WriteHeapDump, suspends the execution of all goroutines while it runs.
+func SetMemoryLimitHeapDump(f *os.File) error {
+	var dup *os.File
+	if f != nil {
+		// Duplicate the fd so that the caller may close f, and so that
+		// the dump is not written to an fd reissued by the kernel.
+		fd, _, err := poll.DupCloseOnExec(int(f.Fd()))
+		if err != nil {
+			return err
+		}
+		runtime.KeepAlive(f) // prevent finalization before dup
+		dup = os.NewFile(uintptr(fd), f.Name())
+	}
+
+	limitDump.mu.Lock()
+	prev := limitDump.f
+	limitDump.f = dup
+	if dup != nil && !limitDump.started {
+		limitDump.started = true
+		go limitDumper()
+	}
+	setMemoryLimitHeapDump(dup != nil)
+	limitDump.mu.Unlock()
+
+	if prev != nil {
+		prev.Close() // ignore error
+	}
+	return nil
+}
+
+// limitDumper writes the heap dumps requested by SetMemoryLimitHeapDump.
+func limitDumper() {
+	for {
+		waitMemoryLimitHeapDump()
+
+		limitDump.mu.Lock()
+		f := limitDump.f
+		limitDump.f = nil
+		limitDump.mu.Unlock()
+
+		if f != nil {
+			WriteHeapDump(f.Fd())
+			f.Close() // ignore error
+		}
+	}
+}
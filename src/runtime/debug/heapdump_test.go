@@ -9,6 +9,7 @@ import (
 	"runtime"
 	. "runtime/debug"
 	"testing"
+	"time"
 )
 
 func TestWriteHeapDumpNonempty(t *testing.T) {
@@ -93,3 +94,37 @@ func TestWriteHeapDumpTypeName(t *testing.T) {
 	dummy.M()
 	dummy2.M()
 }
+
+func TestSetMemoryLimitHeapDump(t *testing.T) {
+	if runtime.GOOS == "js" || runtime.GOOS == "wasip1" {
+		t.Skipf("WriteHeapDump is not available on %s.", runtime.GOOS)
+	}
+	f, err := os.CreateTemp(t.TempDir(), "heapdumptest")
+	if err != nil {
+		t.Fatalf("TempFile failed: %v", err)
+	}
+	defer f.Close()
+	if err := SetMemoryLimitHeapDump(f); err != nil {
+		t.Fatalf("SetMemoryLimitHeapDump failed: %v", err)
+	}
+	defer SetMemoryLimitHeapDump(nil)
+
+	// Any running program exceeds a 1 byte limit, so the
+	// next GC triggers the dump.
+	defer SetMemoryLimit(SetMemoryLimit(1))
+	runtime.GC()
+
+	// The dump is written asynchronously. Wait for it to finish.
+	const hdr = "go1.24 heap dump\n"
+	for start := time.Now(); time.Since(start) < time.Minute; time.Sleep(10 * time.Millisecond) {
+		b, err := os.ReadFile(f.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		// A complete dump ends with the EOF tag.
+		if len(b) > len(hdr) && string(b[:len(hdr)]) == hdr && b[len(b)-1] == 0 {
+			return
+		}
+	}
+	t.Fatal("heap dump not written")
+}
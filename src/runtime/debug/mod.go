@@ -54,6 +54,14 @@ type BuildInfo struct {
 	// that contributed packages to the build of this binary.
 	Deps []*Module
 
+	// Packages lists the import paths of the packages built into the
+	// binary, other than those of the standard library, in sorted order.
+	// These are the packages in the import graph of the main package,
+	// including any whose code the linker discarded as unreachable.
+	// It is empty unless the binary was built with the -buildpkgs build
+	// flag of Go 1.24 or later.
+	Packages []string
+
 	// Settings describes the build settings used to build the binary.
 	Settings []BuildSetting
 }
@@ -131,6 +139,9 @@ func (bi *BuildInfo) String() string {
 	for _, dep := range bi.Deps {
 		formatMod("dep", *dep)
 	}
+	for _, pkg := range bi.Packages {
+		fmt.Fprintf(buf, "pkg\t%s\n", pkg)
+	}
 	for _, s := range bi.Settings {
 		key := s.Key
 		if quoteKey(key) {
@@ -159,6 +170,7 @@ func ParseBuildInfo(data string) (bi *BuildInfo, err error) {
 		modLine   = "mod\t"
 		depLine   = "dep\t"
 		repLine   = "=>\t"
+		pkgLine   = "pkg\t"
 		buildLine = "build\t"
 		newline   = "\n"
 		tab       = "\t"
@@ -225,6 +237,8 @@ func ParseBuildInfo(data string) (bi *BuildInfo, err error) {
 				Sum:     string(elem[2]),
 			}
 			last = nil
+		case strings.HasPrefix(line, pkgLine):
+			bi.Packages = append(bi.Packages, line[len(pkgLine):])
 		case strings.HasPrefix(line, buildLine):
 			kv := line[len(buildLine):]
 			if len(kv) < 1 {
@@ -38,6 +38,17 @@ func FuzzParseBuildInfoRoundTrip(f *testing.F) {
 		build	-compiler=gc
 		`))
 
+	// Package built with the linked packages recorded.
+	f.Add(strip(`
+		go	1.24
+		path	example.com/m/cmd/m
+		mod	example.com/m	(devel)	
+		dep	golang.org/x/text	v0.14.0	h1:ScX5w1eTa3QqT8oi6+ziP7dTV1S2+ALU0bI+0zXKWiQ=
+		pkg	example.com/m/cmd/m
+		pkg	golang.org/x/text/language
+		build	-compiler=gc
+		`))
+
 	// Package built in GOPATH mode.
 	f.Add(strip(`
 		go	1.18
@@ -0,0 +1,461 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package debug
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WriteSBOM writes to w a software bill of materials (SBOM) describing
+// the binary with the build information bi, in the given format:
+//
+//   - "spdx-json": an SPDX 2.3 document in JSON format
+//   - "cyclonedx-json": a CycloneDX 1.5 document in JSON format
+//
+// The document describes the main module of the binary, its dependency
+// modules and the Go standard library, and, if recorded in bi.Packages,
+// the packages of each module built into the binary. The creation time
+// of the document is the vcs.time build setting, if any, and the current
+// time otherwise. The identifier of the document is derived from bi, so
+// that the documents describing a binary are reproducible.
+func (bi *BuildInfo) WriteSBOM(w io.Writer, format string) error {
+	var doc jsonObject
+	switch format {
+	case "spdx-json":
+		doc = bi.spdx()
+	case "cyclonedx-json":
+		doc = bi.cyclonedx()
+	default:
+		return fmt.Errorf("unknown SBOM format %q", format)
+	}
+	buf := new(strings.Builder)
+	writeJSON(buf, doc, "")
+	buf.WriteByte('\n')
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
+
+// An sbomComponent is a module described by an SBOM.
+type sbomComponent struct {
+	path     string   // module path
+	version  string   // version used in the build, or ""
+	purl     string   // package URL, or "" for a module replaced by a directory
+	sha256   string   // hex-encoded SHA-256 checksum of the module, or ""
+	replace  string   // replacement of the module, or ""
+	packages []string // import paths of the linked packages of the module
+}
+
+// sbomComponents returns the components of the binary with the build
+// information bi: its main module, its dependency modules, and the
+// standard library, in that order.
+func (bi *BuildInfo) sbomComponents() []*sbomComponent {
+	main := bi.Main
+	if main.Path == "" {
+		// Built outside of a module.
+		main.Path = bi.Path
+	}
+	var comps []*sbomComponent
+	for _, m := range append([]*Module{&main}, bi.Deps...) {
+		c := &sbomComponent{path: m.Path}
+		used := m
+		if r := m.Replace; r != nil {
+			used = r
+			c.replace = r.Path
+		}
+		if used.Version != "(devel)" {
+			c.version = used.Version
+		}
+		switch {
+		case m.Replace == nil:
+			c.purl = purl(m.Path, c.version)
+		case c.version != "":
+			c.purl = purl(used.Path, c.version)
+			c.replace += "@" + c.version
+		}
+		if sum, ok := strings.CutPrefix(used.Sum, "h1:"); ok {
+			if h, ok := decodeBase64(sum); ok {
+				c.sha256 = fmt.Sprintf("%x", h)
+			}
+		}
+		comps = append(comps, c)
+	}
+
+	// Assign each package to the module with the longest path
+	// that is a prefix of the package's import path.
+	for _, pkg := range bi.Packages {
+		var best *sbomComponent
+		for _, c := range comps {
+			if (pkg == c.path || strings.HasPrefix(pkg, c.path+"/")) && (best == nil || len(c.path) > len(best.path)) {
+				best = c
+			}
+		}
+		if best != nil {
+			best.packages = append(best.packages, pkg)
+		}
+	}
+
+	if bi.GoVersion != "" {
+		// Omit any suffix, as in "go1.24.0 X:fieldtrack".
+		version, _, _ := strings.Cut(bi.GoVersion, " ")
+		comps = append(comps, &sbomComponent{path: "stdlib", version: version, purl: purl("stdlib", version)})
+	}
+	return comps
+}
+
+// packagePURL returns the package URL of the package pkg of c, or "".
+func (c *sbomComponent) packagePURL(pkg string) string {
+	if c.purl == "" || pkg == c.path {
+		return c.purl
+	}
+	return c.purl + "#" + purlEscape(strings.TrimPrefix(pkg, c.path+"/"), true)
+}
+
+// purl returns the package URL of the Go module with the given path and version.
+func purl(path, version string) string {
+	s := "pkg:golang/" + purlEscape(path, true)
+	if version != "" {
+		s += "@" + purlEscape(version, false)
+	}
+	return s
+}
+
+// purlEscape percent-encodes the characters of s that may not appear
+// unencoded in a component of a package URL. If slash is true, slashes
+// separating path segments are not encoded.
+func purlEscape(s string, slash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+			c == '.' || c == '-' || c == '_' || c == '~' || c == '/' && slash {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// sbomTime returns the creation time of an SBOM for bi.
+func (bi *BuildInfo) sbomTime() string {
+	for _, s := range bi.Settings {
+		if s.Key == "vcs.time" {
+			return s.Value
+		}
+	}
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
+// sbomUUID returns a UUID identifying an SBOM for bi, derived from the
+// hash of the build information: a version 8 (custom) UUID, as defined
+// by RFC 9562.
+func (bi *BuildInfo) sbomUUID() string {
+	s := bi.String()
+	h1 := fnv1a(fnvOffset, s)
+	h2 := fnv1a(h1, s)
+	var u [16]byte
+	for i := range 8 {
+		u[i] = byte(h1 >> (56 - 8*i))
+		u[8+i] = byte(h2 >> (56 - 8*i))
+	}
+	u[6] = u[6]&0x0f | 0x80 // version 8
+	u[8] = u[8]&0x3f | 0x80 // variant 10
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16])
+}
+
+// spdx returns an SPDX 2.3 document describing bi.
+func (bi *BuildInfo) spdx() jsonObject {
+	comps := bi.sbomComponents()
+	var (
+		packages      []jsonObject
+		relationships []jsonObject
+	)
+	relate := func(id, typ, related string) {
+		relationships = append(relationships, jsonObject{
+			{"spdxElementId", id},
+			{"relationshipType", typ},
+			{"relatedSpdxElement", related},
+		})
+	}
+	spdxPackage := func(id, name, version, purl, purpose string) jsonObject {
+		p := jsonObject{{"name", name}, {"SPDXID", id}}
+		if version != "" {
+			p = append(p, jsonField{"versionInfo", version})
+		}
+		p = append(p, jsonField{"downloadLocation", "NOASSERTION"}, jsonField{"filesAnalyzed", false})
+		if purl != "" {
+			p = append(p, jsonField{"externalRefs", []jsonObject{{
+				{"referenceCategory", "PACKAGE-MANAGER"},
+				{"referenceType", "purl"},
+				{"referenceLocator", purl},
+			}}})
+		}
+		return append(p, jsonField{"primaryPackagePurpose", purpose})
+	}
+
+	mainID := "SPDXRef-Module-0"
+	relate("SPDXRef-DOCUMENT", "DESCRIBES", mainID)
+	for i, c := range comps {
+		id := "SPDXRef-Module-" + strconv.Itoa(i)
+		purpose := "LIBRARY"
+		if i == 0 {
+			purpose = "APPLICATION"
+		} else {
+			relate(mainID, "DEPENDS_ON", id)
+		}
+		p := spdxPackage(id, c.path, c.version, c.purl, purpose)
+		if c.sha256 != "" {
+			p = append(p, jsonField{"checksums", []jsonObject{{
+				{"algorithm", "SHA256"},
+				{"checksumValue", c.sha256},
+			}}})
+		}
+		var comment []string
+		if c.replace != "" {
+			comment = append(comment, "replaced by "+c.replace)
+		}
+		if i == 0 {
+			for _, s := range bi.Settings {
+				comment = append(comment, "build "+s.Key+"="+s.Value)
+			}
+		}
+		if len(comment) > 0 {
+			p = append(p, jsonField{"comment", strings.Join(comment, "\n")})
+		}
+		packages = append(packages, p)
+
+		for j, pkg := range c.packages {
+			pkgID := id + "-Package-" + strconv.Itoa(j)
+			packages = append(packages, spdxPackage(pkgID, pkg, c.version, c.packagePURL(pkg), "LIBRARY"))
+			relate(id, "CONTAINS", pkgID)
+		}
+	}
+
+	name := bi.Path
+	if name == "" {
+		name = "unknown"
+	}
+	return jsonObject{
+		{"spdxVersion", "SPDX-2.3"},
+		{"dataLicense", "CC0-1.0"},
+		{"SPDXID", "SPDXRef-DOCUMENT"},
+		{"name", name},
+		{"documentNamespace", "https://go.dev/spdxdocs/" + purlEscape(name, true) + "-" + bi.sbomUUID()},
+		{"creationInfo", jsonObject{
+			{"created", bi.sbomTime()},
+			{"creators", []string{"Tool: go-" + bi.GoVersion}},
+		}},
+		{"packages", packages},
+		{"relationships", relationships},
+	}
+}
+
+// cyclonedx returns a CycloneDX 1.5 document describing bi.
+func (bi *BuildInfo) cyclonedx() jsonObject {
+	comps := bi.sbomComponents()
+	component := func(c *sbomComponent, typ string) jsonObject {
+		o := jsonObject{{"bom-ref", "module:" + c.path}, {"type", typ}, {"name", c.path}}
+		if c.version != "" {
+			o = append(o, jsonField{"version", c.version})
+		}
+		if c.sha256 != "" {
+			o = append(o, jsonField{"hashes", []jsonObject{{{"alg", "SHA-256"}, {"content", c.sha256}}}})
+		}
+		if c.purl != "" {
+			o = append(o, jsonField{"purl", c.purl})
+		}
+		var props []jsonObject
+		if c.replace != "" {
+			props = append(props, jsonObject{{"name", "go:replace"}, {"value", c.replace}})
+		}
+		if typ == "application" {
+			for _, s := range bi.Settings {
+				props = append(props, jsonObject{{"name", "go:build:" + s.Key}, {"value", s.Value}})
+			}
+		}
+		if len(props) > 0 {
+			o = append(o, jsonField{"properties", props})
+		}
+		var pkgs []jsonObject
+		for _, pkg := range c.packages {
+			p := jsonObject{{"bom-ref", "package:" + pkg}, {"type", "library"}, {"name", pkg}}
+			if c.version != "" {
+				p = append(p, jsonField{"version", c.version})
+			}
+			if purl := c.packagePURL(pkg); purl != "" {
+				p = append(p, jsonField{"purl", purl})
+			}
+			pkgs = append(pkgs, p)
+		}
+		if len(pkgs) > 0 {
+			o = append(o, jsonField{"components", pkgs})
+		}
+		return o
+	}
+
+	main := comps[0]
+	var (
+		components []jsonObject
+		dependsOn  []string
+	)
+	for _, c := range comps[1:] {
+		components = append(components, component(c, "library"))
+		dependsOn = append(dependsOn, "module:"+c.path)
+	}
+	return jsonObject{
+		{"bomFormat", "CycloneDX"},
+		{"specVersion", "1.5"},
+		{"serialNumber", "urn:uuid:" + bi.sbomUUID()},
+		{"version", 1},
+		{"metadata", jsonObject{
+			{"timestamp", bi.sbomTime()},
+			{"tools", jsonObject{
+				{"components", []jsonObject{{
+					{"type", "application"},
+					{"name", "go"},
+					{"version", bi.GoVersion},
+				}}},
+			}},
+			{"component", component(main, "application")},
+		}},
+		{"components", components},
+		{"dependencies", []jsonObject{{
+			{"ref", "module:" + main.path},
+			{"dependsOn", dependsOn},
+		}}},
+	}
+}
+
+// A jsonObject is a JSON object, with its fields in order.
+type jsonObject []jsonField
+
+// A jsonField is a field of a JSON object.
+// Its value is a string, an int, a bool, a []string,
+// a jsonObject, or a []jsonObject.
+type jsonField struct {
+	key   string
+	value any
+}
+
+// writeJSON writes the JSON encoding of v to b, indented by two spaces
+// per level, each line after the first starting with indent.
+func writeJSON(b *strings.Builder, v any, indent string) {
+	switch v := v.(type) {
+	case string:
+		writeJSONString(b, v)
+	case int:
+		b.WriteString(strconv.Itoa(v))
+	case bool:
+		b.WriteString(strconv.FormatBool(v))
+	case []string:
+		writeJSONList(b, len(v), indent, func(i int) { writeJSONString(b, v[i]) })
+	case []jsonObject:
+		writeJSONList(b, len(v), indent, func(i int) { writeJSON(b, v[i], indent+"  ") })
+	case jsonObject:
+		if len(v) == 0 {
+			b.WriteString("{}")
+			return
+		}
+		b.WriteString("{\n")
+		for i, f := range v {
+			b.WriteString(indent + "  ")
+			writeJSONString(b, f.key)
+			b.WriteString(": ")
+			writeJSON(b, f.value, indent+"  ")
+			if i < len(v)-1 {
+				b.WriteByte(',')
+			}
+			b.WriteByte('\n')
+		}
+		b.WriteString(indent + "}")
+	default:
+		panic(fmt.Sprintf("unexpected JSON value of type %T", v))
+	}
+}
+
+// writeJSONList writes to b a JSON array of n elements,
+// written by calling elem with the index of each element.
+func writeJSONList(b *strings.Builder, n int, indent string, elem func(int)) {
+	if n == 0 {
+		b.WriteString("[]")
+		return
+	}
+	b.WriteString("[\n")
+	for i := 0; i < n; i++ {
+		b.WriteString(indent + "  ")
+		elem(i)
+		if i < n-1 {
+			b.WriteByte(',')
+		}
+		b.WriteByte('\n')
+	}
+	b.WriteString(indent + "]")
+}
+
+// writeJSONString writes the JSON encoding of the string s to b.
+func writeJSONString(b *strings.Builder, s string) {
+	b.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r < 0x20 || r == '\u2028' || r == '\u2029':
+			fmt.Fprintf(b, `\u%04x`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+}
+
+// This package cannot import encoding/base64 or hash/fnv, which are
+// tested with package testing, itself a user of this package.
+
+const fnvOffset = 14695981039346656037
+
+// fnv1a returns the 64-bit FNV-1a hash of s, starting from the state h.
+func fnv1a(h uint64, s string) uint64 {
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= 1099511628211
+	}
+	return h
+}
+
+// decodeBase64 decodes s, which must be padded standard base64.
+func decodeBase64(s string) ([]byte, bool) {
+	const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+	if len(s)%4 != 0 {
+		return nil, false
+	}
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "="), "=")
+	var (
+		b    []byte
+		acc  uint
+		bits uint
+	)
+	for i := 0; i < len(s); i++ {
+		v := strings.IndexByte(alphabet, s[i])
+		if v < 0 {
+			return nil, false
+		}
+		acc = acc<<6 | uint(v)
+		bits += 6
+		if bits >= 8 {
+			bits -= 8
+			b = append(b, byte(acc>>bits))
+		}
+	}
+	return b, true
+}
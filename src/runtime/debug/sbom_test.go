@@ -0,0 +1,154 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package debug_test
+
+import (
+	"encoding/json"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const sbomBuildInfo = `path	example.com/m/cmd/m
+mod	example.com/m	v1.0.0	h1:ScX5w1eTa3QqT8oi6+ziP7dTV1S2+ALU0bI+0zXKWiQ=
+dep	example.com/dep	v0.1.0+incompatible	h1:47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=
+dep	example.com/local	v1.2.0
+=>	../local	(devel)	
+pkg	example.com/dep
+pkg	example.com/dep/sub
+pkg	example.com/local/x
+pkg	example.com/m/cmd/m
+build	GOOS=linux
+build	vcs.time=2024-01-02T03:04:05Z
+`
+
+func sbomInfo(t *testing.T) *debug.BuildInfo {
+	bi, err := debug.ParseBuildInfo(sbomBuildInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bi.GoVersion = "go1.24.0 X:fieldtrack"
+	return bi
+}
+
+func writeSBOM(t *testing.T, bi *debug.BuildInfo, format string) (string, map[string]any) {
+	var b strings.Builder
+	if err := bi.WriteSBOM(&b, format); err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal([]byte(b.String()), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, b.String())
+	}
+	return b.String(), doc
+}
+
+func TestWriteSBOMSPDX(t *testing.T) {
+	bi := sbomInfo(t)
+	out, doc := writeSBOM(t, bi, "spdx-json")
+	if out2, _ := writeSBOM(t, bi, "spdx-json"); out2 != out {
+		t.Errorf("SPDX document is not reproducible:\n%s\n%s", out, out2)
+	}
+
+	if doc["spdxVersion"] != "SPDX-2.3" || doc["name"] != "example.com/m/cmd/m" {
+		t.Errorf("unexpected document header:\n%s", out)
+	}
+	if created := doc["creationInfo"].(map[string]any)["created"]; created != "2024-01-02T03:04:05Z" {
+		t.Errorf("created = %v, want vcs.time", created)
+	}
+
+	purls := make(map[string]string)
+	for _, p := range doc["packages"].([]any) {
+		p := p.(map[string]any)
+		name := p["name"].(string)
+		if refs, ok := p["externalRefs"].([]any); ok {
+			purls[name] = refs[0].(map[string]any)["referenceLocator"].(string)
+		} else {
+			purls[name] = ""
+		}
+	}
+	want := map[string]string{
+		"example.com/m":       "pkg:golang/example.com/m@v1.0.0",
+		"example.com/m/cmd/m": "pkg:golang/example.com/m@v1.0.0#cmd/m",
+		"example.com/dep":     "pkg:golang/example.com/dep@v0.1.0%2Bincompatible",
+		"example.com/dep/sub": "pkg:golang/example.com/dep@v0.1.0%2Bincompatible#sub",
+		"example.com/local":   "",
+		"example.com/local/x": "",
+		"stdlib":              "pkg:golang/stdlib@go1.24.0",
+	}
+	for name, purl := range want {
+		if got, ok := purls[name]; !ok {
+			t.Errorf("missing package %s", name)
+		} else if got != purl {
+			t.Errorf("package %s: purl %q, want %q", name, got, purl)
+		}
+	}
+
+	var rels []string
+	for _, r := range doc["relationships"].([]any) {
+		r := r.(map[string]any)
+		rels = append(rels, r["spdxElementId"].(string)+" "+r["relationshipType"].(string)+" "+r["relatedSpdxElement"].(string))
+	}
+	for _, rel := range []string{
+		"SPDXRef-DOCUMENT DESCRIBES SPDXRef-Module-0",
+		"SPDXRef-Module-0 DEPENDS_ON SPDXRef-Module-1",
+		"SPDXRef-Module-0 DEPENDS_ON SPDXRef-Module-3",
+		"SPDXRef-Module-0 CONTAINS SPDXRef-Module-0-Package-0",
+		"SPDXRef-Module-1 CONTAINS SPDXRef-Module-1-Package-1",
+	} {
+		if !slices.Contains(rels, rel) {
+			t.Errorf("missing relationship %s", rel)
+		}
+	}
+}
+
+func TestWriteSBOMCycloneDX(t *testing.T) {
+	bi := sbomInfo(t)
+	out, doc := writeSBOM(t, bi, "cyclonedx-json")
+
+	if doc["bomFormat"] != "CycloneDX" || doc["specVersion"] != "1.5" {
+		t.Errorf("unexpected document header:\n%s", out)
+	}
+	serial := doc["serialNumber"].(string)
+	if len(serial) != len("urn:uuid:")+36 || serial[len("urn:uuid:")+14] != '8' {
+		t.Errorf("serialNumber = %s, want version 8 UUID", serial)
+	}
+
+	main := doc["metadata"].(map[string]any)["component"].(map[string]any)
+	if main["type"] != "application" || main["purl"] != "pkg:golang/example.com/m@v1.0.0" {
+		t.Errorf("unexpected main component:\n%s", out)
+	}
+	if main["hashes"].([]any)[0].(map[string]any)["content"] != "49c5f9c357936b742a4fca22ebece23fb7535754b6f802d4d1b23ed335ca5a24" {
+		t.Errorf("unexpected main component hash:\n%s", out)
+	}
+
+	var deps []string
+	for _, c := range doc["components"].([]any) {
+		c := c.(map[string]any)
+		dep := c["name"].(string)
+		if sub, ok := c["components"].([]any); ok {
+			for _, p := range sub {
+				dep += " " + p.(map[string]any)["name"].(string)
+			}
+		}
+		deps = append(deps, dep)
+	}
+	want := []string{
+		"example.com/dep example.com/dep example.com/dep/sub",
+		"example.com/local example.com/local/x",
+		"stdlib",
+	}
+	if !slices.Equal(deps, want) {
+		t.Errorf("components = %q, want %q", deps, want)
+	}
+}
+
+func TestWriteSBOMUnknownFormat(t *testing.T) {
+	bi := sbomInfo(t)
+	if err := bi.WriteSBOM(new(strings.Builder), "spdx-tv"); err == nil {
+		t.Errorf("WriteSBOM with unknown format succeeded")
+	}
+}
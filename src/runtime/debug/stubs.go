@@ -16,3 +16,8 @@ func setGCPercent(int32) int32
 func setPanicOnFault(bool) bool
 func setMaxThreads(int) int
 func setMemoryLimit(int64) int64
+func setMemoryLimitHeapDump(bool)
+func waitMemoryLimitHeapDump()
+func setDebugEvents(bool)
+func readDebugEvents([][8]uint64) (int, uint64)
+func debugEventSTWReason(uint64) string
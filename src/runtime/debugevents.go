@@ -0,0 +1,126 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Runtime event stream for runtime/debug.SubscribeRuntimeEvents.
+//
+// The runtime records GC cycles, stop-the-world pauses, memory limit
+// overruns and background scavenger activity in a fixed-size ring
+// buffer while at least one subscriber exists. A goroutine in
+// runtime/debug drains the buffer and delivers the events.
+//
+// Each event is a [debugEventLen]uint64. The first three words are the
+// same for every event: the kind, the wall-clock time at which the
+// event ended in Unix nanoseconds, and its duration in nanoseconds.
+// The remaining words depend on the kind, and must match the decoding
+// in runtime/debug/events.go.
+
+package runtime
+
+import (
+	"internal/runtime/atomic"
+	_ "unsafe" // for go:linkname
+)
+
+const (
+	debugEventLen    = 8
+	debugEventBufLen = 256
+
+	debugEventGC          = 1 // cycle, heap at start, heap marked, heap goal, forced
+	debugEventSTW         = 2 // stwReason, stopping time
+	debugEventMemoryLimit = 3 // total memory, memory limit
+	debugEventScavenge    = 4 // bytes released
+)
+
+type debugEvent [debugEventLen]uint64
+
+var debugEvents struct {
+	// enabled is 1 while runtime/debug has subscribers.
+	enabled atomic.Uint32
+
+	lock mutex
+	buf  [debugEventBufLen]debugEvent
+	r, w uint64 // read and write positions in buf
+
+	// dropped counts events discarded because buf was full.
+	dropped uint64
+
+	// sleeping is set when the reader is about to sleep on wait.
+	sleeping bool
+	wait     note
+}
+
+// emitDebugEvent records an event of the given kind, which ended now and
+// lasted for duration nanoseconds.
+//
+// It may be called on the system stack and with the world stopped.
+func emitDebugEvent(kind uint64, duration int64, args ...uint64) {
+	if debugEvents.enabled.Load() == 0 {
+		return
+	}
+	systemstack(func() {
+		var ev debugEvent
+		sec, nsec, _ := time_now()
+		ev[0] = kind
+		ev[1] = uint64(sec*1e9 + int64(nsec))
+		ev[2] = uint64(duration)
+		copy(ev[3:], args)
+
+		lock(&debugEvents.lock)
+		if debugEvents.w-debugEvents.r == debugEventBufLen {
+			debugEvents.dropped++
+		} else {
+			debugEvents.buf[debugEvents.w%debugEventBufLen] = ev
+			debugEvents.w++
+		}
+		wake := debugEvents.sleeping
+		debugEvents.sleeping = false
+		unlock(&debugEvents.lock)
+		if wake {
+			notewakeup(&debugEvents.wait)
+		}
+	})
+}
+
+//go:linkname setDebugEvents runtime/debug.setDebugEvents
+func setDebugEvents(enabled bool) {
+	if enabled {
+		debugEvents.enabled.Store(1)
+	} else {
+		debugEvents.enabled.Store(0)
+	}
+}
+
+// readDebugEvents blocks until at least one event is available, then
+// copies as many events as fit into buf. It returns the number of events
+// copied and the number of events dropped since the previous call.
+//
+//go:linkname readDebugEvents runtime/debug.readDebugEvents
+func readDebugEvents(buf [][debugEventLen]uint64) (n int, dropped uint64) {
+	for {
+		lock(&debugEvents.lock)
+		if debugEvents.r != debugEvents.w || debugEvents.dropped != 0 {
+			for n < len(buf) && debugEvents.r != debugEvents.w {
+				buf[n] = debugEvents.buf[debugEvents.r%debugEventBufLen]
+				debugEvents.r++
+				n++
+			}
+			dropped = debugEvents.dropped
+			debugEvents.dropped = 0
+			unlock(&debugEvents.lock)
+			return n, dropped
+		}
+		debugEvents.sleeping = true
+		noteclear(&debugEvents.wait)
+		unlock(&debugEvents.lock)
+		notetsleepg(&debugEvents.wait, -1)
+	}
+}
+
+//go:linkname debugEventSTWReason runtime/debug.debugEventSTWReason
+func debugEventSTWReason(r uint64) string {
+	if r >= uint64(len(stwReasonStrings)) {
+		return "unknown"
+	}
+	return stwReasonStrings[r]
+}
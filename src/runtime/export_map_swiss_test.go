@@ -11,48 +11,109 @@ import (
 	"unsafe"
 )
 
-func MapBucketsCount(m map[int]int) int {
+const MaxTableCapacity = maxTableCapacity
+
+func mapOf(m map[int]int) (*maptype, *hmap) {
+	i := any(m)
+	t := *(**maptype)(unsafe.Pointer(&i))
 	h := *(**hmap)(unsafe.Pointer(&m))
-	return 1 << h.B
+	return t, h
 }
 
-func MapBucketsPointerIsNil(m map[int]int) bool {
-	h := *(**hmap)(unsafe.Pointer(&m))
-	return h.buckets == nil
+// MapTables returns the capacity of each table of m, in directory
+// order, or nil for a small map.
+func MapTables(m map[int]int) []int {
+	_, h := mapOf(m)
+	var caps []int
+	for i := uintptr(0); i < uintptr(h.dirLen); i++ {
+		tab := h.directoryAt(i)
+		if i > 0 && h.directoryAt(i-1) == tab {
+			continue
+		}
+		caps = append(caps, int(tab.capacity))
+	}
+	return caps
 }
 
-func MapTombstoneCheck(m map[int]int) {
-	// Make sure emptyOne and emptyRest are distributed correctly.
-	// We should have a series of filled and emptyOne cells, followed by
-	// a series of emptyRest cells.
-	h := *(**hmap)(unsafe.Pointer(&m))
-	i := any(m)
-	t := *(**maptype)(unsafe.Pointer(&i))
+func MapDirectoryLen(m map[int]int) int {
+	_, h := mapOf(m)
+	return h.dirLen
+}
 
-	for x := 0; x < 1<<h.B; x++ {
-		b0 := (*bmap)(add(h.buckets, uintptr(x)*uintptr(t.BucketSize)))
-		n := 0
-		for b := b0; b != nil; b = b.overflow(t) {
-			for i := 0; i < abi.SwissMapBucketCount; i++ {
-				if b.tophash[i] != emptyRest {
-					n++
-				}
+func MapGroupPointerIsNil(m map[int]int) bool {
+	_, h := mapOf(m)
+	return h.dirPtr == nil
+}
+
+func MapTombstoneCheck(m map[int]int) {
+	// Check the bookkeeping of each table against its control bytes,
+	// and that each table covers an aligned run of directory entries.
+	t, h := mapOf(m)
+	if h.dirLen == 0 {
+		return
+	}
+	count := 0
+	for i := uintptr(0); i < uintptr(h.dirLen); i++ {
+		tab := h.directoryAt(i)
+		if i > 0 && h.directoryAt(i-1) == tab {
+			continue
+		}
+		if tab.retired {
+			panic("retired table in directory")
+		}
+		run := uintptr(1) << (h.globalDepth - tab.localDepth)
+		if i%run != 0 {
+			panic("misaligned table run")
+		}
+		for j := i; j < i+run; j++ {
+			if h.directoryAt(j) != tab {
+				panic("short table run")
 			}
 		}
-		k := 0
-		for b := b0; b != nil; b = b.overflow(t) {
-			for i := 0; i < abi.SwissMapBucketCount; i++ {
-				if k < n && b.tophash[i] == emptyRest {
-					panic("early emptyRest")
+		used, empty := 0, 0
+		for j := uint64(0); j <= tab.groups.lengthMask; j++ {
+			g := tab.groups.group(t, j)
+			for k := uintptr(0); k < abi.SwissMapGroupSlots; k++ {
+				switch c := g.ctrls().get(k); {
+				case c == ctrlEmpty:
+					empty++
+				case c&ctrlEmpty == 0:
+					used++
 				}
-				if k >= n && b.tophash[i] != emptyRest {
-					panic("late non-emptyRest")
-				}
-				if k == n-1 && b.tophash[i] == emptyOne {
-					panic("last non-emptyRest entry is emptyOne")
-				}
-				k++
 			}
 		}
+		if used != int(tab.used) {
+			panic("table used count mismatch")
+		}
+		// Every insert into an empty slot consumes growthLeft, and
+		// one in eight slots is always left empty.
+		if int(tab.growthLeft) != empty-int(tab.capacity)/abi.SwissMapGroupSlots {
+			panic("table growthLeft mismatch")
+		}
+		count += used
+	}
+	if count != h.count {
+		panic("map count mismatch")
 	}
 }
+
+// CtrlGroupMatch returns the slots of a group with the given control
+// word matching h2, and those that are empty, empty or deleted, and
+// full, as computed by the group matching routines.
+func CtrlGroupMatch(ctrls uint64, h2 uintptr) (match, empty, emptyOrDeleted, full []int) {
+	g := ctrlGroup(ctrls)
+	slots := func(b bitset) []int {
+		var s []int
+		for b != 0 {
+			s = append(s, int(b.first()))
+			b = b.removeFirst()
+		}
+		return s
+	}
+	return slots(g.matchH2(h2)), slots(g.matchEmpty()), slots(g.matchEmptyOrDeleted()), slots(g.matchFull())
+}
+
+const (
+	CtrlEmpty   = uint8(ctrlEmpty)
+	CtrlDeleted = uint8(ctrlDeleted)
+)
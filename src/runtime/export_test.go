@@ -483,10 +483,6 @@ func (rw *RWMutex) Unlock() {
 
 const RuntimeHmapSize = unsafe.Sizeof(hmap{})
 
-func OverLoadFactor(count int, B uint8) bool {
-	return overLoadFactor(count, B)
-}
-
 func LockOSCounts() (external, internal uint32) {
 	gp := getg()
 	if gp.m.lockedExt+gp.m.lockedInt == 0 {
@@ -1754,6 +1750,11 @@ func (a *UserArena) Free() {
 	a.arena.free()
 }
 
+func (a *UserArena) Map(out *any, hint int) {
+	i := efaceOf(out)
+	i.data = unsafe.Pointer(a.arena.makemap((*maptype)(unsafe.Pointer(i._type)), hint))
+}
+
 func GlobalWaitingArenaChunks() int {
 	n := 0
 	systemstack(func() {
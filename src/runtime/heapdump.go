@@ -6,14 +6,64 @@
 // objects in the heap plus additional info (roots, threads,
 // finalizers, etc.) to a file.
 
-// The format of the dumped file is described at
-// https://golang.org/s/go15heapdump.
+// Heap dump format
+//
+// A heap dump starts with the header "go1.24 heap dump\n" and is
+// followed by a sequence of records, terminated by an EOF record.
+// Unless otherwise noted, all numbers are unsigned varints as read by
+// encoding/binary.Uvarint. A string or byte slice is a varint length
+// followed by that many bytes. A bool is a varint 0 or 1. Addresses
+// are numbers; 0 means nil or unknown.
+//
+// Several records describe a block of memory together with the offsets
+// of the pointer-typed words within it. Such a field list is a sequence
+// of (kind, offset) pairs, where kind is fieldKindPtr, terminated by a
+// single fieldKindEol.
+//
+// Each record starts with its tag:
+//
+//	tagEOF (0): end of dump.
+//	tagObject (1): address, type address, contents, field list.
+//		The type address refers to an earlier tagType record and is 0
+//		for objects whose type the allocator does not record (small
+//		objects without an allocation header).
+//	tagOtherRoot (2): description string, pointer.
+//	tagType (3): address, size, name string, indirect bool.
+//		A type may be written more than once.
+//	tagGoroutine (4): descriptor address, sp, goroutine id, creation pc,
+//		status, system bool, background bool, wait since, wait reason
+//		string, context pointer, M address, top defer address, top
+//		panic address.
+//	tagStackFrame (5): sp, depth, child sp, contents, entry pc, pc,
+//		continuation pc, function name string, field list.
+//	tagParams (6): big-endian bool, pointer size, heap start, heap end,
+//		GOARCH string, Go version string, number of CPUs.
+//	tagFinalizer (7): object, funcval, code pointer, argument type,
+//		pointer-to-object type.
+//	tagItab (8): itab address, type address.
+//	tagOSThread (9): M address, M id, OS thread id.
+//	tagMemStats (10): the fields of MemStats, in declaration order,
+//		from Alloc through NumGC, with PauseNs written as 256 numbers.
+//	tagQueuedFinalizer (11): same as tagFinalizer.
+//	tagData (12): address, contents, field list.
+//	tagBSS (13): address, contents, field list.
+//	tagDefer (14): defer address, goroutine, sp, pc, funcval, code
+//		pointer, next defer.
+//	tagPanic (15): panic address, goroutine, argument type, argument
+//		data, 0, next panic.
+//	tagMemProf (16): bucket address, allocation size, number of frames,
+//		then for each frame a function name string, file string and
+//		line, then allocation count and free count.
+//	tagAllocSample (17): object address, bucket address.
+//
+// Readers should reject dumps with an unknown header.
 
 package runtime
 
 import (
 	"internal/abi"
 	"internal/goarch"
+	"internal/runtime/atomic"
 	"unsafe"
 )
 
@@ -209,9 +259,11 @@ func dumptype(t *_type) {
 }
 
 // dump an object.
-func dumpobj(obj unsafe.Pointer, size uintptr, bv bitvector) {
+func dumpobj(obj unsafe.Pointer, size uintptr, typ *_type, bv bitvector) {
+	dumptype(typ)
 	dumpint(tagObject)
 	dumpint(uint64(uintptr(obj)))
+	dumpint(uint64(uintptr(unsafe.Pointer(typ))))
 	dumpmemrange(obj, size)
 	dumpfields(bv)
 }
@@ -499,7 +551,7 @@ func dumpobjs() {
 				freemark[j] = false
 				continue
 			}
-			dumpobj(unsafe.Pointer(p), size, makeheapobjbv(p, size))
+			dumpobj(unsafe.Pointer(p), size, heapobjtype(s, p), makeheapobjbv(p, size))
 		}
 	}
 }
@@ -666,7 +718,7 @@ func dumpmemprof() {
 	}
 }
 
-var dumphdr = []byte("go1.7 heap dump\n")
+var dumphdr = []byte("go1.24 heap dump\n")
 
 func mdump(m *MemStats) {
 	assertWorldStopped()
@@ -713,6 +765,25 @@ func writeheapdump_m(fd uintptr, m *MemStats) {
 	casgstatus(gp.m.curg, _Gwaiting, _Grunning)
 }
 
+// heapobjtype returns the type recorded by the allocator for the object
+// in the allocation slot starting at p, or nil if none is recorded.
+func heapobjtype(s *mspan, p uintptr) *_type {
+	if s.spanclass.noscan() || heapBitsInSpan(s.elemsize) {
+		return nil
+	}
+	if s.isUserArenaChunk {
+		// The type recorded for a user arena chunk is synthesized
+		// in the chunk itself and describes the whole chunk. It
+		// is not a real type, and must not outlive the chunk in
+		// typecache.
+		return nil
+	}
+	if s.spanclass.sizeclass() != 0 {
+		return *(**_type)(unsafe.Pointer(p))
+	}
+	return s.largeType
+}
+
 // dumpint() the kind & offset of each field in an object.
 func dumpfields(bv bitvector) {
 	dumpbv(&bv, 0)
@@ -747,3 +818,45 @@ func makeheapobjbv(p uintptr, size uintptr) bitvector {
 	}
 	return bitvector{int32(nptr), &tmpbuf[0]}
 }
+
+// heapDumpOnLimit is the state behind runtime/debug.SetMemoryLimitHeapDump.
+var heapDumpOnLimit struct {
+	// armed is 1 while a heap dump has been requested but
+	// the memory limit has not yet been exceeded.
+	armed atomic.Uint32
+
+	// sema is released each time an armed dump is triggered.
+	// runtime/debug's dump goroutine blocks on it.
+	sema uint32
+}
+
+//go:linkname setMemoryLimitHeapDump runtime/debug.setMemoryLimitHeapDump
+func setMemoryLimitHeapDump(armed bool) {
+	if armed {
+		heapDumpOnLimit.armed.Store(1)
+	} else {
+		heapDumpOnLimit.armed.Store(0)
+	}
+}
+
+//go:linkname waitMemoryLimitHeapDump runtime/debug.waitMemoryLimitHeapDump
+func waitMemoryLimitHeapDump() {
+	semacquire(&heapDumpOnLimit.sema)
+}
+
+// checkMemoryLimitHeapDump triggers an armed memory limit heap dump if
+// the total memory mapped by the runtime exceeds the memory limit.
+//
+// It is called at the end of each GC cycle and must run on a regular
+// goroutine stack.
+func checkMemoryLimitHeapDump() {
+	if heapDumpOnLimit.armed.Load() == 0 {
+		return
+	}
+	if gcController.mappedReady.Load() <= uint64(gcController.memoryLimit.Load()) {
+		return
+	}
+	if heapDumpOnLimit.armed.CompareAndSwap(1, 0) {
+		semrelease(&heapDumpOnLimit.sema)
+	}
+}
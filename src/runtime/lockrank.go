@@ -43,6 +43,7 @@ const (
 	lockRankItab
 	lockRankReflectOffs
 	lockRankUserArenaState
+	lockRankDebugEvents
 	// TRACEGLOBAL
 	lockRankTraceBuf
 	lockRankTraceStrings
@@ -115,6 +116,7 @@ var lockNames = []string{
 	lockRankItab:            "itab",
 	lockRankReflectOffs:     "reflectOffs",
 	lockRankUserArenaState:  "userArenaState",
+	lockRankDebugEvents:     "debugEvents",
 	lockRankTraceBuf:        "traceBuf",
 	lockRankTraceStrings:    "traceStrings",
 	lockRankFin:             "fin",
@@ -194,6 +196,7 @@ var lockPartialOrder [][]lockRank = [][]lockRank{
 	lockRankItab:            {},
 	lockRankReflectOffs:     {lockRankItab},
 	lockRankUserArenaState:  {},
+	lockRankDebugEvents:     {},
 	lockRankTraceBuf:        {lockRankSysmon, lockRankScavenge},
 	lockRankTraceStrings:    {lockRankSysmon, lockRankScavenge, lockRankTraceBuf},
 	lockRankFin:             {lockRankSysmon, lockRankScavenge, lockRankForcegc, lockRankSweepWaiters, lockRankAssistQueue, lockRankSweep, lockRankTestR, lockRankTimerSend, lockRankExecW, lockRankCpuprof, lockRankPollDesc, lockRankWakeableSleep, lockRankHchan, lockRankAllocmR, lockRankExecR, lockRankSched, lockRankAllg, lockRankAllp, lockRankNotifyList, lockRankTimers, lockRankTimer, lockRankItab, lockRankReflectOffs, lockRankUserArenaState, lockRankTraceBuf, lockRankTraceStrings},
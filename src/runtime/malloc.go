@@ -1032,6 +1032,11 @@ func mallocgc(size uintptr, typ *_type, needzero bool) unsafe.Pointer {
 		}
 	}
 
+	// Charge the allocation to the goroutine's label account, if any.
+	if acct := getg().labelAccount; acct != nil {
+		acct.chargeAlloc(userSize)
+	}
+
 	// assistG is the G to charge for this allocation, or nil if
 	// GC is not currently active.
 	assistG := deductAssistCredit(size)
@@ -1,4 +1,4 @@
-// Copyright 2018 The Go Authors. All rights reserved.
+// Copyright 2024 The Go Authors. All rights reserved.
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
@@ -7,29 +7,362 @@
 package runtime
 
 import (
+	"internal/abi"
 	"unsafe"
 )
 
+// getFast64 returns a pointer to the element for key, or nil if key
+// is not present.
+func (h *hmap) getFast64(t *maptype, key uint64) unsafe.Pointer {
+	if h.dirLen == 0 {
+		// Small map. No need to hash, just check every full slot.
+		g := groupReference{data: h.dirPtr}
+		full := g.ctrls().matchFull()
+		slotKey := g.key(t, 0)
+		for full != 0 {
+			if full.lowestSet() && key == *(*uint64)(slotKey) {
+				return add(slotKey, t.ElemOff)
+			}
+			slotKey = add(slotKey, t.SlotSize)
+			full = full.shiftOutLowest()
+		}
+		return nil
+	}
+
+	hash := t.Hasher(noescape(unsafe.Pointer(&key)), h.seed)
+	tab := h.directoryAt(h.directoryIndex(hash))
+	seq := makeProbeSeq(h1(hash), tab.groups.lengthMask)
+	for ; ; seq = seq.next() {
+		g := tab.groups.group(t, seq.offset)
+		match := g.ctrls().matchH2(h2(hash))
+		for match != 0 {
+			i := match.first()
+			slotKey := g.key(t, i)
+			if key == *(*uint64)(slotKey) {
+				return add(slotKey, t.ElemOff)
+			}
+			match = match.removeFirst()
+		}
+		if g.ctrls().matchEmpty() != 0 {
+			return nil
+		}
+	}
+}
+
 func mapaccess1_fast64(t *maptype, h *hmap, key uint64) unsafe.Pointer {
-	throw("mapaccess1_fast64 unimplemented")
-	panic("unreachable")
+	if raceenabled && h != nil {
+		callerpc := getcallerpc()
+		racereadpc(unsafe.Pointer(h), callerpc, abi.FuncPCABIInternal(mapaccess1_fast64))
+	}
+	if h == nil || h.count == 0 {
+		return unsafe.Pointer(&zeroVal[0])
+	}
+	if h.flags&hashWriting != 0 {
+		fatal("concurrent map read and map write")
+	}
+	if elem := h.getFast64(t, key); elem != nil {
+		return elem
+	}
+	return unsafe.Pointer(&zeroVal[0])
 }
 
+// mapaccess2_fast64 should be an internal detail,
+// but widely used packages access it using linkname.
+// Notable members of the hall of shame include:
+//   - github.com/ugorji/go/codec
+//
+// Do not remove or change the type signature.
+// See go.dev/issue/67401.
+//
+//go:linkname mapaccess2_fast64
 func mapaccess2_fast64(t *maptype, h *hmap, key uint64) (unsafe.Pointer, bool) {
-	throw("mapaccess2_fast64 unimplemented")
-	panic("unreachable")
+	if raceenabled && h != nil {
+		callerpc := getcallerpc()
+		racereadpc(unsafe.Pointer(h), callerpc, abi.FuncPCABIInternal(mapaccess2_fast64))
+	}
+	if h == nil || h.count == 0 {
+		return unsafe.Pointer(&zeroVal[0]), false
+	}
+	if h.flags&hashWriting != 0 {
+		fatal("concurrent map read and map write")
+	}
+	if elem := h.getFast64(t, key); elem != nil {
+		return elem, true
+	}
+	return unsafe.Pointer(&zeroVal[0]), false
+}
+
+// putSlotSmallFast64 is putSlotSmall for 64-bit keys without pointers.
+func (h *hmap) putSlotSmallFast64(t *maptype, hash uintptr, key uint64) unsafe.Pointer {
+	g := groupReference{data: h.dirPtr}
+	match := g.ctrls().matchH2(h2(hash))
+	for match != 0 {
+		i := match.first()
+		if key == *(*uint64)(g.key(t, i)) {
+			return g.elem(t, i)
+		}
+		match = match.removeFirst()
+	}
+
+	match = g.ctrls().matchEmptyOrDeleted()
+	if match == 0 {
+		return nil
+	}
+	i := match.first()
+	*(*uint64)(g.key(t, i)) = key
+	g.ctrls().set(i, ctrl(h2(hash)))
+	h.count++
+	return g.elem(t, i)
+}
+
+// putSlotFast64 is putSlot for 64-bit keys without pointers.
+func (tab *table) putSlotFast64(t *maptype, h *hmap, hash uintptr, key uint64) unsafe.Pointer {
+	seq := makeProbeSeq(h1(hash), tab.groups.lengthMask)
+
+	var firstDeletedGroup groupReference
+	var firstDeletedSlot uintptr
+
+	for ; ; seq = seq.next() {
+		g := tab.groups.group(t, seq.offset)
+		match := g.ctrls().matchH2(h2(hash))
+		for match != 0 {
+			i := match.first()
+			if key == *(*uint64)(g.key(t, i)) {
+				return g.elem(t, i)
+			}
+			match = match.removeFirst()
+		}
+
+		match = g.ctrls().matchEmpty()
+		if match == 0 {
+			if firstDeletedGroup.data == nil {
+				if del := g.ctrls().matchEmptyOrDeleted(); del != 0 {
+					firstDeletedGroup = g
+					firstDeletedSlot = del.first()
+				}
+			}
+			continue
+		}
+
+		i := match.first()
+		if firstDeletedGroup.data != nil {
+			g = firstDeletedGroup
+			i = firstDeletedSlot
+			tab.growthLeft++ // undone below
+		}
+		if tab.growthLeft == 0 {
+			tab.rehash(t, h, hash)
+			return nil
+		}
+		*(*uint64)(g.key(t, i)) = key
+		g.ctrls().set(i, ctrl(h2(hash)))
+		tab.growthLeft--
+		tab.used++
+		h.count++
+		return g.elem(t, i)
+	}
 }
 
+// mapassign_fast64 should be an internal detail,
+// but widely used packages access it using linkname.
+// Notable members of the hall of shame include:
+//   - github.com/bytedance/sonic
+//   - github.com/cloudwego/frugal
+//   - github.com/ugorji/go/codec
+//
+// Do not remove or change the type signature.
+// See go.dev/issue/67401.
+//
+//go:linkname mapassign_fast64
 func mapassign_fast64(t *maptype, h *hmap, key uint64) unsafe.Pointer {
-	throw("mapassign_fast64 unimplemented")
-	panic("unreachable")
+	if h == nil {
+		panic(plainError("assignment to entry in nil map"))
+	}
+	if raceenabled {
+		callerpc := getcallerpc()
+		racewritepc(unsafe.Pointer(h), callerpc, abi.FuncPCABIInternal(mapassign_fast64))
+	}
+	if h.flags&hashWriting != 0 {
+		fatal("concurrent map writes")
+	}
+	hash := t.Hasher(noescape(unsafe.Pointer(&key)), h.seed)
+
+	// Set hashWriting after calling t.hasher for consistency with mapassign.
+	h.flags ^= hashWriting
+
+	if h.dirPtr == nil {
+		h.growToSmall(t)
+	}
+
+	var elem unsafe.Pointer
+	if h.dirLen == 0 {
+		elem = h.putSlotSmallFast64(t, hash, key)
+		if elem == nil {
+			h.growToTable(t)
+		}
+	}
+	for elem == nil {
+		elem = h.directoryAt(h.directoryIndex(hash)).putSlotFast64(t, h, hash, key)
+	}
+
+	if h.flags&hashWriting == 0 {
+		fatal("concurrent map writes")
+	}
+	h.flags &^= hashWriting
+	return elem
 }
 
+// putSlotSmallFastPtr is putSlotSmall for pointer-shaped keys.
+func (h *hmap) putSlotSmallFastPtr(t *maptype, hash uintptr, key unsafe.Pointer) unsafe.Pointer {
+	g := groupReference{data: h.dirPtr}
+	match := g.ctrls().matchH2(h2(hash))
+	for match != 0 {
+		i := match.first()
+		if key == *(*unsafe.Pointer)(g.key(t, i)) {
+			return g.elem(t, i)
+		}
+		match = match.removeFirst()
+	}
+
+	match = g.ctrls().matchEmptyOrDeleted()
+	if match == 0 {
+		return nil
+	}
+	i := match.first()
+	*(*unsafe.Pointer)(g.key(t, i)) = key
+	g.ctrls().set(i, ctrl(h2(hash)))
+	h.count++
+	return g.elem(t, i)
+}
+
+// putSlotFastPtr is putSlot for pointer-shaped keys.
+func (tab *table) putSlotFastPtr(t *maptype, h *hmap, hash uintptr, key unsafe.Pointer) unsafe.Pointer {
+	seq := makeProbeSeq(h1(hash), tab.groups.lengthMask)
+
+	var firstDeletedGroup groupReference
+	var firstDeletedSlot uintptr
+
+	for ; ; seq = seq.next() {
+		g := tab.groups.group(t, seq.offset)
+		match := g.ctrls().matchH2(h2(hash))
+		for match != 0 {
+			i := match.first()
+			if key == *(*unsafe.Pointer)(g.key(t, i)) {
+				return g.elem(t, i)
+			}
+			match = match.removeFirst()
+		}
+
+		match = g.ctrls().matchEmpty()
+		if match == 0 {
+			if firstDeletedGroup.data == nil {
+				if del := g.ctrls().matchEmptyOrDeleted(); del != 0 {
+					firstDeletedGroup = g
+					firstDeletedSlot = del.first()
+				}
+			}
+			continue
+		}
+
+		i := match.first()
+		if firstDeletedGroup.data != nil {
+			g = firstDeletedGroup
+			i = firstDeletedSlot
+			tab.growthLeft++ // undone below
+		}
+		if tab.growthLeft == 0 {
+			tab.rehash(t, h, hash)
+			return nil
+		}
+		*(*unsafe.Pointer)(g.key(t, i)) = key
+		g.ctrls().set(i, ctrl(h2(hash)))
+		tab.growthLeft--
+		tab.used++
+		h.count++
+		return g.elem(t, i)
+	}
+}
+
+// mapassignFastPtr implements mapassign_fast32ptr and mapassign_fast64ptr.
+func mapassignFastPtr(t *maptype, h *hmap, key unsafe.Pointer) unsafe.Pointer {
+	if h.flags&hashWriting != 0 {
+		fatal("concurrent map writes")
+	}
+	hash := t.Hasher(noescape(unsafe.Pointer(&key)), h.seed)
+
+	// Set hashWriting after calling t.hasher for consistency with mapassign.
+	h.flags ^= hashWriting
+
+	if h.dirPtr == nil {
+		h.growToSmall(t)
+	}
+
+	var elem unsafe.Pointer
+	if h.dirLen == 0 {
+		elem = h.putSlotSmallFastPtr(t, hash, key)
+		if elem == nil {
+			h.growToTable(t)
+		}
+	}
+	for elem == nil {
+		elem = h.directoryAt(h.directoryIndex(hash)).putSlotFastPtr(t, h, hash, key)
+	}
+
+	if h.flags&hashWriting == 0 {
+		fatal("concurrent map writes")
+	}
+	h.flags &^= hashWriting
+	return elem
+}
+
+// mapassign_fast64ptr should be an internal detail,
+// but widely used packages access it using linkname.
+// Notable members of the hall of shame include:
+//   - github.com/bytedance/sonic
+//   - github.com/cloudwego/frugal
+//   - github.com/ugorji/go/codec
+//
+// Do not remove or change the type signature.
+// See go.dev/issue/67401.
+//
+//go:linkname mapassign_fast64ptr
 func mapassign_fast64ptr(t *maptype, h *hmap, key unsafe.Pointer) unsafe.Pointer {
-	throw("mapassign_fast64ptr unimplemented")
-	panic("unreachable")
+	if h == nil {
+		panic(plainError("assignment to entry in nil map"))
+	}
+	if raceenabled {
+		callerpc := getcallerpc()
+		racewritepc(unsafe.Pointer(h), callerpc, abi.FuncPCABIInternal(mapassign_fast64ptr))
+	}
+	return mapassignFastPtr(t, h, key)
 }
 
 func mapdelete_fast64(t *maptype, h *hmap, key uint64) {
-	throw("mapdelete_fast64 unimplemented")
+	if raceenabled && h != nil {
+		callerpc := getcallerpc()
+		racewritepc(unsafe.Pointer(h), callerpc, abi.FuncPCABIInternal(mapdelete_fast64))
+	}
+	if h == nil || h.count == 0 {
+		return
+	}
+	if h.flags&hashWriting != 0 {
+		fatal("concurrent map writes")
+	}
+
+	hash := t.Hasher(noescape(unsafe.Pointer(&key)), h.seed)
+
+	// Set hashWriting after calling t.hasher for consistency with mapdelete
+	h.flags ^= hashWriting
+
+	h.deleteKey(t, hash, noescape(unsafe.Pointer(&key)))
+	if h.count == 0 {
+		// Reset the hash seed to make it more difficult for attackers to
+		// repeatedly trigger hash collisions. See issue 25237.
+		h.seed = uintptr(rand())
+	}
+
+	if h.flags&hashWriting == 0 {
+		fatal("concurrent map writes")
+	}
+	h.flags &^= hashWriting
 }
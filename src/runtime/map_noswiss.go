@@ -75,6 +75,9 @@ const (
 	loadFactorDen = 2
 	loadFactorNum = loadFactorDen * abi.OldMapBucketCount * 13 / 16
 
+	// exported value for testing
+	hashLoad = float32(loadFactorNum) / float32(loadFactorDen)
+
 	// data offset should be the size of the bmap struct, but needs to be
 	// aligned correctly. For amd64p32 this means 64-bit alignment
 	// even though pointers are 32 bit.
@@ -354,6 +357,17 @@ func makemap(t *maptype, hint int, h *hmap) *hmap {
 	return h
 }
 
+// makemap implements arena.MakeMap. It allocates the map header in
+// the arena, and also the first bucket if the map does not need more
+// than one. Buckets added as the map grows are allocated on the heap.
+func (a *userArena) makemap(t *maptype, hint int) *hmap {
+	h := (*hmap)(a.new(abi.TypeOf(hmap{})))
+	if !overLoadFactor(hint, 0) {
+		h.buckets = a.new(t.Bucket)
+	}
+	return makemap(t, hint, h)
+}
+
 // makeBucketArray initializes a backing array for map buckets.
 // 1<<b is the minimum number of buckets to allocate.
 // dirtyalloc should either be nil or a bucket array previously
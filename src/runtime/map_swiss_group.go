@@ -0,0 +1,256 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build goexperiment.swissmap
+
+package runtime
+
+import "unsafe"
+
+// A control byte describes the state of one slot of a group.
+//
+//	empty:   1 0 0 0 0 0 0 0
+//	deleted: 1 1 1 1 1 1 1 0
+//	full:    0 h h h h h h h  // h represents the H2 hash bits
+//
+// The high bit distinguishes full slots from the rest, so a single
+// sign test on each byte finds all full (or all non-full) slots.
+type ctrl uint8
+
+const (
+	ctrlEmpty   ctrl = 0b10000000
+	ctrlDeleted ctrl = 0b11111110
+
+	bitsetLSB     = 0x0101010101010101
+	bitsetMSB     = 0x8080808080808080
+	bitsetEmpty   = bitsetLSB * uint64(ctrlEmpty)
+	bitsetDeleted = bitsetLSB * uint64(ctrlDeleted)
+)
+
+// A ctrlGroup holds the eight control bytes of a group.
+//
+// It is always manipulated as a uint64 value rather than as bytes in
+// memory: the control byte for slot i is bits 8*i through 8*i+7 of
+// the word. This keeps the matching code independent of byte order.
+type ctrlGroup uint64
+
+// get returns the i-th control byte.
+func (g *ctrlGroup) get(i uintptr) ctrl {
+	return ctrl(*g >> (8 * i))
+}
+
+// set sets the i-th control byte.
+func (g *ctrlGroup) set(i uintptr, c ctrl) {
+	*g = *g&^(0xff<<(8*i)) | ctrlGroup(c)<<(8*i)
+}
+
+// setEmpty sets all the control bytes to empty.
+func (g *ctrlGroup) setEmpty() {
+	*g = ctrlGroup(bitsetEmpty)
+}
+
+// matchH2 returns the set of slots which are full and for which the
+// 7-bit hash matches the given value. May return false positives.
+func (g ctrlGroup) matchH2(h uintptr) bitset {
+	return ctrlGroupMatchH2(g, h)
+}
+
+// matchEmpty returns the set of slots in the group that are empty.
+func (g ctrlGroup) matchEmpty() bitset {
+	return ctrlGroupMatchEmpty(g)
+}
+
+// matchEmptyOrDeleted returns the set of slots in the group that are
+// empty or deleted.
+func (g ctrlGroup) matchEmptyOrDeleted() bitset {
+	return ctrlGroupMatchEmptyOrDeleted(g)
+}
+
+// matchFull returns the set of slots in the group that are full.
+func (g ctrlGroup) matchFull() bitset {
+	return ctrlGroupMatchFull(g)
+}
+
+// Portable implementations of the group matching operations, which
+// work on all eight control bytes at once using ordinary integer
+// arithmetic. They return a bitset with the high bit of each matching
+// byte set.
+
+func ctrlGroupMatchH2Portable(g ctrlGroup, h uintptr) bitset {
+	// NB: This generic matching routine produces false positive matches when
+	// h is 2^N and the control bytes have a seq of 2^N followed by 2^N+1. For
+	// example: if ctrls==0x0302 and h=02, we'll compute v as 0x0100. When we
+	// subtract off 0x0101 the first 2 bytes we'll become 0xffff and both be
+	// considered matches of h. The false positive matches are not a problem,
+	// just a rare inefficiency. Note that they only occur if there is a real
+	// match and never occur on ctrlEmpty, or ctrlDeleted. The subsequent key
+	// comparisons ensure that there is no correctness issue.
+	v := uint64(g) ^ (bitsetLSB * uint64(h))
+	return bitset(((v - bitsetLSB) &^ v) & bitsetMSB)
+}
+
+func ctrlGroupMatchEmptyPortable(g ctrlGroup) bitset {
+	// An empty slot is   1000 0000
+	// A deleted slot is  1111 1110
+	// A full slot is     0??? ????
+	//
+	// A slot is empty iff bit 7 is set and bit 1 is not. We could select any
+	// of the other bits here (e.g. v << 1 would also work).
+	v := uint64(g)
+	return bitset((v &^ (v << 6)) & bitsetMSB)
+}
+
+func ctrlGroupMatchEmptyOrDeletedPortable(g ctrlGroup) bitset {
+	// An empty slot is  1000 0000
+	// A deleted slot is 1111 1110
+	// A full slot is    0??? ????
+	//
+	// A slot is empty or deleted iff bit 7 is set.
+	return bitset(uint64(g) & bitsetMSB)
+}
+
+func ctrlGroupMatchFullPortable(g ctrlGroup) bitset {
+	// A full slot is 0??? ????
+	return bitset(^uint64(g) & bitsetMSB)
+}
+
+// A bitset is a set of slots in a group, as returned by the ctrlGroup
+// match methods. Its representation is architecture dependent; see
+// bitsetShift.
+type bitset uint64
+
+// first returns the index of the first (lowest) slot in the set.
+// The set must be non-empty.
+func (b bitset) first() uintptr {
+	return bitsetFirst(b)
+}
+
+// removeFirst removes the first slot from the set.
+func (b bitset) removeFirst() bitset {
+	return b & (b - 1)
+}
+
+// lowestSet reports whether slot 0 is in the set.
+func (b bitset) lowestSet() bool {
+	return b&(1<<bitsetShift) != 0
+}
+
+// shiftOutLowest shifts slot 0 out of the set, so that slot i becomes
+// slot i-1. Together with lowestSet this lets the fast paths walk
+// the slots of a group in order.
+func (b bitset) shiftOutLowest() bitset {
+	return b >> bitsetStride
+}
+
+// A groupReference refers to a single slot group.
+type groupReference struct {
+	// data points to the group, which is laid out as:
+	//
+	//	type group struct {
+	//		ctrls ctrlGroup
+	//		slots [abi.SwissMapGroupSlots]slot
+	//	}
+	//
+	//	type slot struct {
+	//		key  typ.Key
+	//		elem typ.Elem
+	//	}
+	data unsafe.Pointer // data *typ.Group
+}
+
+const ctrlGroupsSize = unsafe.Sizeof(ctrlGroup(0))
+
+// ctrls returns the group control word.
+func (g *groupReference) ctrls() *ctrlGroup {
+	return (*ctrlGroup)(g.data)
+}
+
+// key returns a pointer to the key at index i.
+func (g *groupReference) key(typ *maptype, i uintptr) unsafe.Pointer {
+	return add(g.data, ctrlGroupsSize+i*typ.SlotSize)
+}
+
+// elem returns a pointer to the element at index i.
+func (g *groupReference) elem(typ *maptype, i uintptr) unsafe.Pointer {
+	return add(g.data, ctrlGroupsSize+i*typ.SlotSize+typ.ElemOff)
+}
+
+// groupsReference refers to an array of groups.
+type groupsReference struct {
+	// data points to an array of groups. See groupReference above for
+	// the definition of group.
+	data unsafe.Pointer // data *[length]typ.Group
+
+	// lengthMask is the number of groups in data minus one (note that
+	// length must be a power of two). This allows computing i%length
+	// quickly using bitwise AND.
+	lengthMask uint64
+}
+
+// newGroups allocates a new array of length groups, with all slots
+// empty.
+func newGroups(typ *maptype, length uint64) groupsReference {
+	g := groupsReference{
+		data:       newarray(typ.Group, int(length)),
+		lengthMask: length - 1,
+	}
+	for i := uint64(0); i < length; i++ {
+		grp := g.group(typ, i)
+		grp.ctrls().setEmpty()
+	}
+	return g
+}
+
+// group returns the group at index i.
+func (g *groupsReference) group(typ *maptype, i uint64) groupReference {
+	// i is at most maxTableCapacity/abi.SwissMapGroupSlots, so the
+	// conversion to uintptr cannot truncate on 32-bit systems.
+	offset := uintptr(i) * typ.GroupSize
+
+	return groupReference{
+		data: add(g.data, offset),
+	}
+}
+
+// probeSeq maintains the state for a probe sequence that iterates
+// through the groups in a table. The sequence is a triangular
+// progression of the form
+//
+//	p(i) := (i^2 + i)/2 + hash (mod mask+1)
+//
+// which visits every group exactly once when the number of groups is
+// a power of two.
+//
+// See https://en.wikipedia.org/wiki/Quadratic_probing.
+type probeSeq struct {
+	mask   uint64
+	offset uint64
+	index  uint64
+}
+
+func makeProbeSeq(hash uintptr, mask uint64) probeSeq {
+	return probeSeq{
+		mask:   mask,
+		offset: uint64(hash) & mask,
+		index:  0,
+	}
+}
+
+func (s probeSeq) next() probeSeq {
+	s.index++
+	s.offset = (s.offset + s.index) & s.mask
+	return s
+}
+
+// h1 extracts the H1 portion of a hash: the upper bits, used to pick
+// the first group to probe.
+func h1(h uintptr) uintptr {
+	return h >> 7
+}
+
+// h2 extracts the H2 portion of a hash: the 7 bits not used for h1,
+// stored in the control byte of a full slot.
+func h2(h uintptr) uintptr {
+	return h & 0x7f
+}
@@ -0,0 +1,49 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build goexperiment.swissmap
+
+package runtime
+
+import (
+	"internal/runtime/sys"
+)
+
+// On amd64 a bitset is packed, with bit i set for slot i, which is the
+// form produced by PMOVMSKB. The compiler replaces the ctrlGroupMatch
+// functions below with SSE2 sequences (see cmd/compile/internal/ssagen);
+// the Go bodies are only used when intrinsics are disabled.
+const (
+	bitsetShift  = 0 // bit position of slot 0
+	bitsetStride = 1 // bits per slot
+)
+
+func bitsetFirst(b bitset) uintptr {
+	return uintptr(sys.TrailingZeros64(uint64(b)))
+}
+
+// bitsetPack converts a bitset in the byte-per-slot form produced by
+// the portable matching routines to the packed form.
+func bitsetPack(b bitset) bitset {
+	// Shift each slot's bit down to the bottom of its byte, then
+	// gather bit 8*i into bit 56+i with a single multiply. No two
+	// partial products overlap, so there are no carries.
+	return bitset(((uint64(b) >> 7) * 0x0102040810204080) >> 56)
+}
+
+func ctrlGroupMatchH2(g ctrlGroup, h uintptr) bitset {
+	return bitsetPack(ctrlGroupMatchH2Portable(g, h))
+}
+
+func ctrlGroupMatchEmpty(g ctrlGroup) bitset {
+	return bitsetPack(ctrlGroupMatchEmptyPortable(g))
+}
+
+func ctrlGroupMatchEmptyOrDeleted(g ctrlGroup) bitset {
+	return bitsetPack(ctrlGroupMatchEmptyOrDeletedPortable(g))
+}
+
+func ctrlGroupMatchFull(g ctrlGroup) bitset {
+	return bitsetPack(ctrlGroupMatchFullPortable(g))
+}
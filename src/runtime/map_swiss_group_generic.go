@@ -0,0 +1,40 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build goexperiment.swissmap && !amd64
+
+package runtime
+
+import (
+	"internal/runtime/sys"
+)
+
+// On architectures without a group matching intrinsic, a bitset has
+// the high bit of byte i set for slot i, exactly as produced by the
+// portable matching routines. This includes arm64, which does not have
+// a NEON implementation yet.
+const (
+	bitsetShift  = 7 // bit position of slot 0
+	bitsetStride = 8 // bits per slot
+)
+
+func bitsetFirst(b bitset) uintptr {
+	return uintptr(sys.TrailingZeros64(uint64(b))) >> 3
+}
+
+func ctrlGroupMatchH2(g ctrlGroup, h uintptr) bitset {
+	return ctrlGroupMatchH2Portable(g, h)
+}
+
+func ctrlGroupMatchEmpty(g ctrlGroup) bitset {
+	return ctrlGroupMatchEmptyPortable(g)
+}
+
+func ctrlGroupMatchEmptyOrDeleted(g ctrlGroup) bitset {
+	return ctrlGroupMatchEmptyOrDeletedPortable(g)
+}
+
+func ctrlGroupMatchFull(g ctrlGroup) bitset {
+	return ctrlGroupMatchFullPortable(g)
+}
@@ -9,15 +9,17 @@ package runtime_test
 import (
 	"internal/abi"
 	"runtime"
+	"runtime/metrics"
 	"slices"
 	"testing"
+	"unsafe"
 )
 
 func TestMapIterOrder(t *testing.T) {
 	sizes := []int{3, 7, 9, 15}
-	if abi.SwissMapBucketCountBits >= 5 {
-		// it gets flaky (often only one iteration order) at size 3 when abi.MapBucketCountBits >=5.
-		t.Fatalf("This test becomes flaky if abi.MapBucketCountBits(=%d) is 5 or larger", abi.SwissMapBucketCountBits)
+	if abi.SwissMapGroupSlotsBits >= 5 {
+		// it gets flaky (often only one iteration order) at size 3 when abi.SwissMapGroupSlotsBits >=5.
+		t.Fatalf("This test becomes flaky if abi.SwissMapGroupSlotsBits(=%d) is 5 or larger", abi.SwissMapGroupSlotsBits)
 	}
 	for _, n := range sizes {
 		for i := 0; i < 1000; i++ {
@@ -50,139 +52,183 @@ func TestMapIterOrder(t *testing.T) {
 	}
 }
 
-const bs = abi.SwissMapBucketCount
-
-// belowOverflow should be a pretty-full pair of buckets;
-// atOverflow is 1/8 bs larger = 13/8 buckets or two buckets
-// that are 13/16 full each, which is the overflow boundary.
-// Adding one to that should ensure overflow to the next higher size.
-const (
-	belowOverflow = bs * 3 / 2           // 1.5 bs = 2 buckets @ 75%
-	atOverflow    = belowOverflow + bs/8 // 2 buckets at 13/16 fill.
-)
-
-var mapBucketTests = [...]struct {
-	n        int // n is the number of map elements
-	noescape int // number of expected buckets for non-escaping map
-	escape   int // number of expected buckets for escaping map
-}{
-	{-(1 << 30), 1, 1},
-	{-1, 1, 1},
-	{0, 1, 1},
-	{1, 1, 1},
-	{bs, 1, 1},
-	{bs + 1, 2, 2},
-	{belowOverflow, 2, 2},  // 1.5 bs = 2 buckets @ 75%
-	{atOverflow + 1, 4, 4}, // 13/8 bs + 1 == overflow to 4
-
-	{2 * belowOverflow, 4, 4}, // 3 bs = 4 buckets @75%
-	{2*atOverflow + 1, 8, 8},  // 13/4 bs + 1 = overflow to 8
-
-	{4 * belowOverflow, 8, 8},  // 6 bs = 8 buckets @ 75%
-	{4*atOverflow + 1, 16, 16}, // 13/2 bs + 1 = overflow to 16
+func TestMapGroupMatch(t *testing.T) {
+	const h2 = 0x12
+	ctrls := []uint8{
+		h2,
+		runtime.CtrlEmpty,
+		runtime.CtrlDeleted,
+		h2,
+		h2 + 1,
+		0x00,
+		runtime.CtrlEmpty,
+		0x7f,
+	}
+	var word uint64
+	for i, c := range ctrls {
+		word |= uint64(c) << (8 * i)
+	}
+	match, empty, emptyOrDeleted, full := runtime.CtrlGroupMatch(word, h2)
+	// The portable matching routine may report false positives
+	// following a real match; key comparisons weed them out.
+	if !slices.Contains(match, 0) || !slices.Contains(match, 3) {
+		t.Errorf("matchH2 = %v, want slots 0 and 3", match)
+	}
+	for _, i := range match {
+		if ctrls[i]&runtime.CtrlEmpty != 0 {
+			t.Errorf("matchH2 = %v, includes non-full slot %d", match, i)
+		}
+	}
+	if want := []int{1, 6}; !slices.Equal(empty, want) {
+		t.Errorf("matchEmpty = %v, want %v", empty, want)
+	}
+	if want := []int{1, 2, 6}; !slices.Equal(emptyOrDeleted, want) {
+		t.Errorf("matchEmptyOrDeleted = %v, want %v", emptyOrDeleted, want)
+	}
+	if want := []int{0, 3, 4, 5, 7}; !slices.Equal(full, want) {
+		t.Errorf("matchFull = %v, want %v", full, want)
+	}
 }
 
-func TestMapBuckets(t *testing.T) {
-	// Test that maps of different sizes have the right number of buckets.
-	// Non-escaping maps with small buckets (like map[int]int) never
-	// have a nil bucket pointer due to starting with preallocated buckets
-	// on the stack. Escaping maps start with a non-nil bucket pointer if
-	// hint size is above bucketCnt and thereby have more than one bucket.
-	// These tests depend on bucketCnt and loadFactor* in map.go.
-	t.Run("mapliteral", func(t *testing.T) {
-		for _, tt := range mapBucketTests {
-			localMap := map[int]int{}
-			if runtime.MapBucketsPointerIsNil(localMap) {
-				t.Errorf("no escape: buckets pointer is nil for non-escaping map")
-			}
-			for i := 0; i < tt.n; i++ {
-				localMap[i] = i
-			}
-			if got := runtime.MapBucketsCount(localMap); got != tt.noescape {
-				t.Errorf("no escape: n=%d want %d buckets, got %d", tt.n, tt.noescape, got)
-			}
-			escapingMap := runtime.Escape(map[int]int{})
-			if count := runtime.MapBucketsCount(escapingMap); count > 1 && runtime.MapBucketsPointerIsNil(escapingMap) {
-				t.Errorf("escape: buckets pointer is nil for n=%d buckets", count)
-			}
-			for i := 0; i < tt.n; i++ {
-				escapingMap[i] = i
-			}
-			if got := runtime.MapBucketsCount(escapingMap); got != tt.escape {
-				t.Errorf("escape n=%d want %d buckets, got %d", tt.n, tt.escape, got)
-			}
+func TestMapTables(t *testing.T) {
+	t.Run("small", func(t *testing.T) {
+		localMap := map[int]int{}
+		if runtime.MapGroupPointerIsNil(localMap) {
+			t.Errorf("no escape: group pointer is nil for non-escaping map")
+		}
+		escapingMap := runtime.Escape(map[int]int{})
+		for i := 0; i < abi.SwissMapGroupSlots; i++ {
+			localMap[i] = i
+			escapingMap[i] = i
+		}
+		if tabs := runtime.MapTables(localMap); tabs != nil {
+			t.Errorf("no escape: full small map has tables %v", tabs)
+		}
+		if tabs := runtime.MapTables(escapingMap); tabs != nil {
+			t.Errorf("escape: full small map has tables %v", tabs)
+		}
+		localMap[abi.SwissMapGroupSlots] = 0
+		if tabs := runtime.MapTables(localMap); !slices.Equal(tabs, []int{2 * abi.SwissMapGroupSlots}) {
+			t.Errorf("small map grew to tables %v, want one table of %d slots", tabs, 2*abi.SwissMapGroupSlots)
 		}
 	})
-	t.Run("nohint", func(t *testing.T) {
-		for _, tt := range mapBucketTests {
-			localMap := make(map[int]int)
-			if runtime.MapBucketsPointerIsNil(localMap) {
-				t.Errorf("no escape: buckets pointer is nil for non-escaping map")
-			}
-			for i := 0; i < tt.n; i++ {
-				localMap[i] = i
+	t.Run("hint", func(t *testing.T) {
+		for _, hint := range []int{9, 100, 1000, 10000} {
+			m := make(map[int]int, hint)
+			before := runtime.MapTables(m)
+			var total int
+			for _, c := range before {
+				total += c
 			}
-			if got := runtime.MapBucketsCount(localMap); got != tt.noescape {
-				t.Errorf("no escape: n=%d want %d buckets, got %d", tt.n, tt.noescape, got)
+			if total*7/8 < hint {
+				t.Errorf("hint %d: tables %v can't hold %d entries", hint, before, hint)
 			}
-			escapingMap := runtime.Escape(make(map[int]int))
-			if count := runtime.MapBucketsCount(escapingMap); count > 1 && runtime.MapBucketsPointerIsNil(escapingMap) {
-				t.Errorf("escape: buckets pointer is nil for n=%d buckets", count)
+			if hint > 1000 {
+				// Keys are not spread evenly enough across
+				// tables to guarantee no table grows.
+				continue
 			}
-			for i := 0; i < tt.n; i++ {
-				escapingMap[i] = i
+			for i := 0; i < hint; i++ {
+				m[i] = i
 			}
-			if got := runtime.MapBucketsCount(escapingMap); got != tt.escape {
-				t.Errorf("escape: n=%d want %d buckets, got %d", tt.n, tt.escape, got)
+			if after := runtime.MapTables(m); !slices.Equal(before, after) {
+				t.Errorf("hint %d: map grew from tables %v to %v", hint, before, after)
 			}
 		}
 	})
-	t.Run("makemap", func(t *testing.T) {
-		for _, tt := range mapBucketTests {
-			localMap := make(map[int]int, tt.n)
-			if runtime.MapBucketsPointerIsNil(localMap) {
-				t.Errorf("no escape: buckets pointer is nil for non-escaping map")
-			}
-			for i := 0; i < tt.n; i++ {
-				localMap[i] = i
-			}
-			if got := runtime.MapBucketsCount(localMap); got != tt.noescape {
-				t.Errorf("no escape: n=%d want %d buckets, got %d", tt.n, tt.noescape, got)
-			}
-			escapingMap := runtime.Escape(make(map[int]int, tt.n))
-			if count := runtime.MapBucketsCount(escapingMap); count > 1 && runtime.MapBucketsPointerIsNil(escapingMap) {
-				t.Errorf("escape: buckets pointer is nil for n=%d buckets", count)
-			}
-			for i := 0; i < tt.n; i++ {
-				escapingMap[i] = i
-			}
-			if got := runtime.MapBucketsCount(escapingMap); got != tt.escape {
-				t.Errorf("escape: n=%d want %d buckets, got %d", tt.n, tt.escape, got)
+	t.Run("split", func(t *testing.T) {
+		m := make(map[int]int)
+		const N = 100000
+		for i := 0; i < N; i++ {
+			m[i] = i
+		}
+		runtime.MapTombstoneCheck(m)
+		tabs := runtime.MapTables(m)
+		for _, c := range tabs {
+			if c > runtime.MaxTableCapacity {
+				t.Fatalf("table capacity %d exceeds maximum %d", c, runtime.MaxTableCapacity)
 			}
 		}
+		if len(tabs) < N/runtime.MaxTableCapacity {
+			t.Errorf("%d entries in only %d tables", N, len(tabs))
+		}
+		if d := runtime.MapDirectoryLen(m); d < len(tabs) {
+			t.Errorf("directory has %d entries for %d tables", d, len(tabs))
+		}
 	})
-	t.Run("makemap64", func(t *testing.T) {
-		for _, tt := range mapBucketTests {
-			localMap := make(map[int]int, int64(tt.n))
-			if runtime.MapBucketsPointerIsNil(localMap) {
-				t.Errorf("no escape: buckets pointer is nil for non-escaping map")
-			}
-			for i := 0; i < tt.n; i++ {
-				localMap[i] = i
+}
+
+func TestMapIterateWhileGrowing(t *testing.T) {
+	for _, n := range []int{5, 8, 100, 5000} {
+		m := make(map[int]int)
+		for i := 0; i < n; i++ {
+			m[i] = i
+		}
+		deleted := make(map[int]bool)
+		updated := make(map[int]bool)
+		seen := make(map[int]int)
+		next := n
+		for k, v := range m {
+			if k < n {
+				seen[k]++
+				if deleted[k] {
+					t.Errorf("n=%d: iteration returned deleted key %d", n, k)
+				}
+				want := k
+				if updated[k] {
+					want = -k
+				}
+				if v != want {
+					t.Errorf("n=%d: m[%d] = %d, want %d", n, k, v, want)
+				}
 			}
-			if got := runtime.MapBucketsCount(localMap); got != tt.noescape {
-				t.Errorf("no escape: n=%d want %d buckets, got %d", tt.n, tt.noescape, got)
+			// Insert enough new keys to keep the map growing.
+			for i := 0; i < 10; i++ {
+				m[next] = next
+				next++
 			}
-			escapingMap := runtime.Escape(make(map[int]int, tt.n))
-			if count := runtime.MapBucketsCount(escapingMap); count > 1 && runtime.MapBucketsPointerIsNil(escapingMap) {
-				t.Errorf("escape: buckets pointer is nil for n=%d buckets", count)
+			if j := (k + 1) % n; k < n && seen[j] == 0 && !deleted[j] {
+				delete(m, j)
+				deleted[j] = true
 			}
-			for i := 0; i < tt.n; i++ {
-				escapingMap[i] = i
+			if j := (k + 2) % n; k < n && !deleted[j] {
+				m[j] = -j
+				updated[j] = true
 			}
-			if got := runtime.MapBucketsCount(escapingMap); got != tt.escape {
-				t.Errorf("escape: n=%d want %d buckets, got %d", tt.n, tt.escape, got)
+		}
+		for i := 0; i < n; i++ {
+			if !deleted[i] && seen[i] != 1 {
+				t.Errorf("n=%d: key %d returned %d times, want 1", n, i, seen[i])
 			}
 		}
-	})
+		runtime.MapTombstoneCheck(m)
+	}
+}
+
+func TestMapMetrics(t *testing.T) {
+	samples := []metrics.Sample{
+		{Name: "/gc/maps/allocs:bytes"},
+		{Name: "/gc/maps/overhead:bytes"},
+	}
+	metrics.Read(samples)
+	allocs, overhead := samples[0].Value.Uint64(), samples[1].Value.Uint64()
+
+	const N = 10000
+	m := runtime.Escape(make(map[int]int))
+	for i := 0; i < N; i++ {
+		m[i] = i
+	}
+
+	metrics.Read(samples)
+	dAllocs := samples[0].Value.Uint64() - allocs
+	dOverhead := samples[1].Value.Uint64() - overhead
+	// Each entry occupies a slot, and was copied into a new table at
+	// least once.
+	const slotSize = uint64(2 * unsafe.Sizeof(int(0)))
+	if dAllocs < 2*N*slotSize {
+		t.Errorf("map allocs grew by %d bytes, want at least %d", dAllocs, 2*N*slotSize)
+	}
+	if dOverhead == 0 || dOverhead >= dAllocs {
+		t.Errorf("map overhead grew by %d bytes, want in (0, %d)", dOverhead, dAllocs)
+	}
 }
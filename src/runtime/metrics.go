@@ -168,6 +168,27 @@ func initMetrics() {
 				out.scalar = float64bits(nsToSec(in.cpuStats.UserTime))
 			},
 		},
+		"/gc/arenas/allocs:bytes": {
+			deps: makeStatDepSet(heapStatsDep),
+			compute: func(in *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = in.heapStats.arenaAlloc
+			},
+		},
+		"/gc/arenas/chunks:bytes": {
+			deps: makeStatDepSet(heapStatsDep),
+			compute: func(in *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = uint64(in.heapStats.arenaChunks)
+			},
+		},
+		"/gc/arenas/frees:bytes": {
+			deps: makeStatDepSet(heapStatsDep),
+			compute: func(in *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = in.heapStats.arenaFree
+			},
+		},
 		"/gc/cycles/automatic:gc-cycles": {
 			deps: makeStatDepSet(sysStatsDep),
 			compute: func(in *statAggregate, out *metricValue) {
@@ -315,6 +336,20 @@ func initMetrics() {
 				out.scalar = uint64(gcCPULimiter.lastEnabledCycle.Load())
 			},
 		},
+		"/gc/maps/allocs:bytes": {
+			deps: makeStatDepSet(heapStatsDep),
+			compute: func(in *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = in.heapStats.mapAlloc
+			},
+		},
+		"/gc/maps/overhead:bytes": {
+			deps: makeStatDepSet(heapStatsDep),
+			compute: func(in *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = in.heapStats.mapOverhead
+			},
+		},
 		"/gc/pauses:seconds": {
 			compute: func(_ *statAggregate, out *metricValue) {
 				// N.B. this is identical to /sched/pauses/total/gc:seconds.
@@ -509,6 +544,91 @@ func godebug_registerMetric(name string, read func() uint64) {
 	metricsUnlock()
 }
 
+// A labelMetric is a metric reporting the usage of a label account.
+type labelMetric struct {
+	acct *labelAccount
+	kind labelMetricKind
+}
+
+type labelMetricKind uint8
+
+const (
+	labelMetricRunning labelMetricKind = iota
+	labelMetricAllocBytes
+	labelMetricAllocObjects
+)
+
+// labelMetrics holds the metrics reporting the usage of label accounts,
+// by name, and labelMetricNames their names in sorted order.
+// Protected by metricsLock.
+var (
+	labelMetrics     map[string]labelMetric
+	labelMetricNames []string
+)
+
+// compute populates out with the value of m.
+func (m labelMetric) compute(out *metricValue) {
+	switch m.kind {
+	case labelMetricRunning:
+		out.kind = metricKindFloat64
+		out.scalar = float64bits(nsToSec(m.acct.runningNanos.Load()))
+	case labelMetricAllocBytes:
+		out.kind = metricKindUint64
+		out.scalar = m.acct.allocBytes.Load()
+	case labelMetricAllocObjects:
+		out.kind = metricKindUint64
+		out.scalar = m.acct.allocObjects.Load()
+	}
+}
+
+// registerLabelMetrics adds the metrics reporting the usage of a,
+// under names starting with prefix.
+func registerLabelMetrics(prefix string, a *labelAccount) {
+	metricsLock()
+	if labelMetrics == nil {
+		labelMetrics = make(map[string]labelMetric)
+	}
+	addLabelMetric(prefix+"/sched/running:seconds", labelMetric{a, labelMetricRunning})
+	addLabelMetric(prefix+"/gc/heap/allocs:bytes", labelMetric{a, labelMetricAllocBytes})
+	addLabelMetric(prefix+"/gc/heap/allocs:objects", labelMetric{a, labelMetricAllocObjects})
+	metricsUnlock()
+}
+
+// addLabelMetric adds a label account metric. metricsLock must be held.
+func addLabelMetric(name string, m labelMetric) {
+	if _, ok := labelMetrics[name]; ok {
+		throw("runtime: duplicate label metric " + name)
+	}
+	labelMetrics[name] = m
+	i := len(labelMetricNames)
+	for i > 0 && labelMetricNames[i-1] > name {
+		i--
+	}
+	labelMetricNames = append(labelMetricNames, "")
+	copy(labelMetricNames[i+1:], labelMetricNames[i:])
+	labelMetricNames[i] = name
+}
+
+// unregisterLabelMetrics removes the metrics of all label accounts.
+func unregisterLabelMetrics() {
+	metricsLock()
+	labelMetrics = nil
+	labelMetricNames = nil
+	metricsUnlock()
+}
+
+// readLabelMetricNames is the implementation of
+// runtime/metrics.runtime_readLabelMetricNames.
+//
+//go:linkname readLabelMetricNames runtime/metrics.runtime_readLabelMetricNames
+func readLabelMetricNames() []string {
+	metricsLock()
+	list := make([]string, len(labelMetricNames))
+	copy(list, labelMetricNames)
+	metricsUnlock()
+	return list
+}
+
 // statDep is a dependency on a group of statistics
 // that a metric might have.
 type statDep uint
@@ -858,7 +978,11 @@ func readMetricsLocked(samplesp unsafe.Pointer, len int, cap int) {
 		sample := &samples[i]
 		data, ok := metrics[sample.name]
 		if !ok {
-			sample.value.kind = metricKindBad
+			if m, ok := labelMetrics[sample.name]; ok {
+				m.compute(&sample.value)
+			} else {
+				sample.value.kind = metricKindBad
+			}
 			continue
 		}
 		// Ensure we have all the stats we need.
@@ -174,6 +174,30 @@ var allDesc = []Description{
 		Kind:       KindFloat64,
 		Cumulative: true,
 	},
+	{
+		Name: "/gc/arenas/allocs:bytes",
+		Description: "Cumulative sum of memory allocated for Go values in arenas " +
+			"created by the arena package. Values too large for an arena chunk " +
+			"are allocated from the heap instead, and are not counted.",
+		Kind:       KindUint64,
+		Cumulative: true,
+	},
+	{
+		Name: "/gc/arenas/chunks:bytes",
+		Description: "Memory held in chunks by arenas that have not yet been freed, " +
+			"including the part of the chunks that is not yet allocated. This " +
+			"memory is also counted by /memory/classes/heap/objects:bytes.",
+		Kind: KindUint64,
+	},
+	{
+		Name: "/gc/arenas/frees:bytes",
+		Description: "Cumulative sum of memory, as counted by /gc/arenas/allocs:bytes, " +
+			"that was released by freeing its arena. The difference between " +
+			"/gc/arenas/allocs:bytes and this metric is the memory allocated in " +
+			"arenas that are still live.",
+		Kind:       KindUint64,
+		Cumulative: true,
+	},
 	{
 		Name:        "/gc/cycles/automatic:gc-cycles",
 		Description: "Count of completed GC cycles generated by the Go runtime.",
@@ -287,6 +311,24 @@ var allDesc = []Description{
 			"The first GC cycle is cycle 1, so a value of 0 indicates that it was never enabled.",
 		Kind: KindUint64,
 	},
+	{
+		Name: "/gc/maps/allocs:bytes",
+		Description: "Cumulative sum of memory allocated for the storage of built-in " +
+			"maps: slot groups, tables and table directories. Always zero when " +
+			"built with GOEXPERIMENT=noswissmap.",
+		Kind:       KindUint64,
+		Cumulative: true,
+	},
+	{
+		Name: "/gc/maps/overhead:bytes",
+		Description: "Cumulative sum of map storage, as counted by /gc/maps/allocs:bytes, " +
+			"that did not hold map entries when it was allocated. The ratio of this " +
+			"metric to /gc/maps/allocs:bytes estimates the fraction of map memory " +
+			"spent on empty slots and table bookkeeping. Always zero when built " +
+			"with GOEXPERIMENT=noswissmap.",
+		Kind:       KindUint64,
+		Cumulative: true,
+	},
 	{
 		Name:        "/gc/pauses:seconds",
 		Description: "Deprecated. Prefer the identical /sched/pauses/total/gc:seconds.",
@@ -471,7 +513,66 @@ func init() {
 	allDesc = append(more, allDesc[i:]...)
 }
 
+// Implemented in the runtime.
+func runtime_readLabelMetricNames() []string
+
 // All returns a slice of containing metric descriptions for all supported metrics.
+//
+// Besides the metrics listed in the package documentation, the slice
+// describes the metrics that report the resource usage of goroutines by
+// profiler label, for the label values seen since accounting was enabled
+// by [runtime/pprof.SetLabelAccounting].
 func All() []Description {
-	return allDesc
+	names := runtime_readLabelMetricNames()
+	if len(names) == 0 {
+		return allDesc
+	}
+	// Insert the label metrics into the table,
+	// preserving the overall sort order.
+	i := 0
+	for i < len(allDesc) && allDesc[i].Name < "/pprof/" {
+		i++
+	}
+	all := make([]Description, i, len(allDesc)+len(names))
+	copy(all, allDesc)
+	for _, name := range names {
+		all = append(all, labelMetricDesc(name))
+	}
+	return append(all, allDesc[i:]...)
+}
+
+// labelMetricDesc returns the description of the label account metric name.
+func labelMetricDesc(name string) Description {
+	sel := "the goroutines with the profiler label values in the metric " +
+		"name, as selected by runtime/pprof.SetLabelAccounting."
+	if hasPrefix(name, "/pprof/labels/other/") {
+		sel = "the goroutines with profiler label values beyond the limit " +
+			"on the number of combinations accounted separately by " +
+			"runtime/pprof.SetLabelAccounting."
+	}
+	d := Description{Name: name, Kind: KindUint64, Cumulative: true}
+	switch {
+	case hasSuffix(name, "/sched/running:seconds"):
+		d.Description = "Wall-clock time spent running Go code by " + sel +
+			" This is not CPU time: it includes time during which the " +
+			"operating system did not schedule the thread running the " +
+			"goroutine, and excludes time spent in system calls and cgo calls."
+		d.Kind = KindFloat64
+	case hasSuffix(name, "/gc/heap/allocs:bytes"):
+		d.Description = "Cumulative sum of memory allocated to the heap by " + sel
+	case hasSuffix(name, "/gc/heap/allocs:objects"):
+		d.Description = "Cumulative count of heap allocations made by " + sel
+	}
+	return d
+}
+
+// hasPrefix reports whether s begins with prefix.
+// The package cannot depend on strings.
+func hasPrefix(s, prefix string) bool {
+	return len(s) >= len(prefix) && s[:len(prefix)] == prefix
+}
+
+// hasSuffix reports whether s ends with suffix.
+func hasSuffix(s, suffix string) bool {
+	return len(s) >= len(suffix) && s[len(s)-len(suffix):] == suffix
 }
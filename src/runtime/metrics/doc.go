@@ -51,6 +51,14 @@ This package supports metrics whose values have a floating-point representation.
 order to improve ease-of-use, this package promises to never produce the following
 classes of floating-point values: NaN, infinity.
 
+# Metrics by profiler label
+
+While accounting by profiler label is enabled by [runtime/pprof.SetLabelAccounting],
+[All] also describes metrics reporting the running time and heap allocations of the
+goroutines with each combination of label values seen so far, whose names start
+with /pprof/labels/. They are not listed below, because their names depend on the
+labels used by the program.
+
 # Supported metrics
 
 Below is the full list of supported metrics, ordered lexicographically.
@@ -137,6 +145,22 @@ Below is the full list of supported metrics, ordered lexicographically.
 		to system CPU time measurements. Compare only with other
 		/cpu/classes metrics.
 
+	/gc/arenas/allocs:bytes
+		Cumulative sum of memory allocated for Go values in arenas
+		created by the arena package. Values too large for an arena
+		chunk are allocated from the heap instead, and are not counted.
+
+	/gc/arenas/chunks:bytes
+		Memory held in chunks by arenas that have not yet been freed,
+		including the part of the chunks that is not yet allocated. This
+		memory is also counted by /memory/classes/heap/objects:bytes.
+
+	/gc/arenas/frees:bytes
+		Cumulative sum of memory, as counted by /gc/arenas/allocs:bytes,
+		that was released by freeing its arena. The difference between
+		/gc/arenas/allocs:bytes and this metric is the memory allocated
+		in arenas that are still live.
+
 	/gc/cycles/automatic:gc-cycles
 		Count of completed GC cycles generated by the Go runtime.
 
@@ -211,6 +235,19 @@ Below is the full list of supported metrics, ordered lexicographically.
 		to occur with use of SetMemoryLimit. The first GC cycle is cycle
 		1, so a value of 0 indicates that it was never enabled.
 
+	/gc/maps/allocs:bytes
+		Cumulative sum of memory allocated for the storage of built-in
+		maps: slot groups, tables and table directories. Always zero
+		when built with GOEXPERIMENT=noswissmap.
+
+	/gc/maps/overhead:bytes
+		Cumulative sum of map storage, as counted by
+		/gc/maps/allocs:bytes, that did not hold map entries when it was
+		allocated. The ratio of this metric to /gc/maps/allocs:bytes
+		estimates the fraction of map memory spent on empty
+		slots and table bookkeeping. Always zero when built with
+		GOEXPERIMENT=noswissmap.
+
 	/gc/pauses:seconds
 		Deprecated. Prefer the identical /sched/pauses/total/gc:seconds.
 
@@ -1138,6 +1138,10 @@ func gcMarkTermination(stw worldStop) {
 	// Free stack spans. This must be done between GC cycles.
 	systemstack(freeStackSpans)
 
+	// Set user arena chunks freed during the cycle to fault, now that
+	// the GC no longer scans them.
+	faultUserArenaChunks()
+
 	// Ensure all mcaches are flushed. Each P will flush its own
 	// mcache before allocating, but idle Ps may not. Since this
 	// is necessary to sweep all spans, we need to ensure all
@@ -1219,6 +1223,20 @@ func gcMarkTermination(stw worldStop) {
 		printunlock()
 	}
 
+	// Report the cycle to runtime/debug.SubscribeRuntimeEvents.
+	if debugEvents.enabled.Load() != 0 {
+		forced := uint64(0)
+		if work.userForced {
+			forced = 1
+		}
+		emitDebugEvent(debugEventGC, work.tEnd-work.tSweepTerm,
+			uint64(memstats.numgc), work.heap0, work.heap2, gcController.lastHeapGoal, forced)
+		total, limit := gcController.mappedReady.Load(), uint64(gcController.memoryLimit.Load())
+		if total > limit {
+			emitDebugEvent(debugEventMemoryLimit, 0, total, limit)
+		}
+	}
+
 	// Set any arena chunks that were deferred to fault.
 	lock(&userArenaState.lock)
 	faultList := userArenaState.fault
@@ -1242,6 +1260,10 @@ func gcMarkTermination(stw worldStop) {
 	releasem(mp)
 	mp = nil
 
+	// Wake the memory limit heap dump goroutine if this cycle
+	// ended above the memory limit.
+	checkMemoryLimitHeapDump()
+
 	// now that gc is done, kick off finalizer thread if needed
 	if !concurrentSweep {
 		// give the queued finalizers, if any, a chance to run
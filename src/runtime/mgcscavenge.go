@@ -652,12 +652,22 @@ func bgscavenge(c chan int) {
 	c <- 1
 	scavenger.park()
 
+	// Memory released and time spent since the scavenger last parked,
+	// for runtime/debug.SubscribeRuntimeEvents.
+	var episodeReleased uintptr
+	var episodeTime float64
 	for {
 		released, workTime := scavenger.run()
 		if released == 0 {
+			if episodeReleased != 0 {
+				emitDebugEvent(debugEventScavenge, int64(episodeTime), uint64(episodeReleased))
+				episodeReleased, episodeTime = 0, 0
+			}
 			scavenger.park()
 			continue
 		}
+		episodeReleased += released
+		episodeTime += workTime
 		mheap_.pages.scav.releasedBg.Add(released)
 		scavenger.sleep(workTime)
 	}
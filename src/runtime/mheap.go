@@ -2077,6 +2077,20 @@ func internal_weak_runtime_makeStrongFromWeak(u unsafe.Pointer) unsafe.Pointer {
 	return ptr
 }
 
+// sync_runtime_lockOrderKey returns a key identifying the lock at p for
+// the lock order checker in sync. For a lock in the heap, the key is the
+// lock's weak handle, which is never reused for another object while the
+// checker references it, even if the lock is freed and its memory reused.
+// Other locks do not move and are never freed, so their key is p.
+//
+//go:linkname sync_runtime_lockOrderKey sync.runtime_lockOrderKey
+func sync_runtime_lockOrderKey(p unsafe.Pointer) unsafe.Pointer {
+	if !inheap(uintptr(p)) {
+		return p
+	}
+	return unsafe.Pointer(getOrAddWeakHandle(p))
+}
+
 // Retrieves or creates a weak pointer handle for the object p.
 func getOrAddWeakHandle(p unsafe.Pointer) *atomic.Uintptr {
 	// First try to retrieve without allocating.
@@ -96,6 +96,9 @@ NONE
 # User arena state
 NONE < userArenaState;
 
+# Runtime event stream for runtime/debug.SubscribeRuntimeEvents
+NONE < debugEvents;
+
 # Tracing without a P uses a global trace buffer.
 scavenge
 # Above TRACEGLOBAL can emit a trace event without a P.
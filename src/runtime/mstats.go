@@ -686,6 +686,15 @@ type heapStatsDelta struct {
 	largeFreeCount  uint64                  // number of frees for large objects (>maxSmallSize)
 	smallFreeCount  [_NumSizeClasses]uint64 // number of frees for small objects (<=maxSmallSize)
 
+	// Map stats.
+	mapAlloc    uint64 // bytes allocated for map storage
+	mapOverhead uint64 // bytes of map storage not holding entries when allocated
+
+	// User arena stats.
+	arenaAlloc  uint64 // bytes allocated into user arena chunks
+	arenaFree   uint64 // bytes allocated into user arena chunks, then freed with their arena
+	arenaChunks int64  // bytes of user arena chunks held by arenas that were not freed
+
 	// NOTE: This struct must be a multiple of 8 bytes in size because it
 	// is stored in an array. If it's not, atomic accesses to the above
 	// fields may be unaligned and fail on 32-bit platforms.
@@ -711,6 +720,11 @@ func (a *heapStatsDelta) merge(b *heapStatsDelta) {
 	for i := range b.smallFreeCount {
 		a.smallFreeCount[i] += b.smallFreeCount[i]
 	}
+	a.mapAlloc += b.mapAlloc
+	a.mapOverhead += b.mapOverhead
+	a.arenaAlloc += b.arenaAlloc
+	a.arenaFree += b.arenaFree
+	a.arenaChunks += b.arenaChunks
 }
 
 // consistentHeapStats represents a set of various memory statistics
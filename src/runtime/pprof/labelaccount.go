@@ -0,0 +1,194 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pprof
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// runtime_newLabelAccount is defined in runtime/proflabel.go.
+func runtime_newLabelAccount(labels string) unsafe.Pointer
+
+// runtime_resetLabelAccounts is defined in runtime/proflabel.go.
+func runtime_resetLabelAccounts()
+
+// runtime_setLabelAccount is defined in runtime/proflabel.go.
+func runtime_setLabelAccount(acct unsafe.Pointer)
+
+// labelAccounting is the current accounting configuration, or nil if
+// accounting is disabled. It is replaced by SetLabelAccounting.
+var labelAccounting atomic.Pointer[labelAccountingState]
+
+// labelAccountingMu serializes the creation of accounts with changes
+// of labelAccounting, so that the runtime registers the metrics of
+// each combination of label values once per configuration.
+var labelAccountingMu sync.Mutex
+
+// maxLabelAccounts is the maximum number of combinations of label values
+// accounted separately. It is a variable for testing.
+var maxLabelAccounts = 1000
+
+// labelAccountOverflow is the name of the account charged for the
+// combinations of label values beyond maxLabelAccounts. It contains no
+// '=', so it cannot be the name of a combination.
+const labelAccountOverflow = "other"
+
+type labelAccountingState struct {
+	keys     []string // sorted
+	accounts sync.Map // labelAccountName -> runtime account
+
+	// The fields below are protected by labelAccountingMu.
+	n        int            // number of accounts in accounts
+	overflow unsafe.Pointer // runtime account for labelAccountOverflow, or nil
+}
+
+// SetLabelAccounting enables always-on accounting of CPU time and heap
+// allocations by the values of the given label keys, and discards any
+// usage accounted so far. Calling SetLabelAccounting with no keys
+// disables accounting.
+//
+// While accounting is enabled, each time a goroutine's labels are set by
+// [Do] or [SetGoroutineLabels], the goroutine's subsequent usage, and
+// that of goroutines it creates, is charged to the combination of values
+// its labels have for keys. Goroutines whose labels have none of the
+// keys are not accounted.
+//
+// The usage of each combination of values is reported by [runtime/metrics]
+// under three metrics, listed by [runtime/metrics.All] once the combination
+// has been seen:
+//
+//	/pprof/labels/{labels}/gc/heap/allocs:bytes
+//	/pprof/labels/{labels}/gc/heap/allocs:objects
+//	/pprof/labels/{labels}/sched/running:seconds
+//
+// {labels} lists the values as key=value pairs in the order of their
+// keys, separated by commas. Keys that were not set on the goroutines
+// are omitted. The characters '%', '/', ':', ',' and '=' in keys and
+// values are escaped as in URLs, for example "%2F" for '/'.
+// For instance, the running time of goroutines labeled with tenant "a"
+// is reported by /pprof/labels/tenant=a/sched/running:seconds.
+//
+// The running time is the wall-clock time goroutines spend running Go
+// code, not CPU time: it includes time during which the operating system
+// did not schedule the thread running the goroutine, so it can exceed
+// the CPU time on an overloaded machine, and it excludes time spent in
+// system calls and cgo calls.
+//
+// At most 1000 combinations of values are accounted separately, to bound
+// the memory used by accounting and the number of metrics. The usage of
+// goroutines with further combinations is reported together under the
+// name "other" in place of {labels}, as in
+// /pprof/labels/other/sched/running:seconds.
+//
+// Unlike the CPU profile, accounting does not sample: it adds a small
+// cost to every scheduling event and heap allocation of an accounted
+// goroutine. Time a goroutine spends running is charged when the
+// goroutine stops running, so the reported running time lags by up to
+// one scheduling quantum for goroutines that are currently running.
+//
+// Goroutines whose labels were set before the call keep being charged
+// to their previous accounts, which are no longer reported, until their
+// labels are set again.
+func SetLabelAccounting(keys ...string) {
+	labelAccountingMu.Lock()
+	defer labelAccountingMu.Unlock()
+	if len(keys) == 0 {
+		labelAccounting.Store(nil)
+	} else {
+		keys = slices.Clone(keys)
+		slices.Sort(keys)
+		keys = slices.Compact(keys)
+		labelAccounting.Store(&labelAccountingState{keys: keys})
+	}
+	runtime_resetLabelAccounts()
+}
+
+// setLabelAccount charges the current goroutine's subsequent resource
+// usage to the account for labels.
+func setLabelAccount(labels *labelMap) {
+	st := labelAccounting.Load()
+	if st == nil {
+		runtime_setLabelAccount(nil)
+		return
+	}
+
+	name, ok := labelAccountName(labels, st.keys)
+	if !ok {
+		runtime_setLabelAccount(nil)
+		return
+	}
+	acct, ok := st.accounts.Load(name)
+	if !ok {
+		acct = newLabelAccount(st, name)
+	}
+	runtime_setLabelAccount(acct.(unsafe.Pointer))
+}
+
+// newLabelAccount returns the account named name in st, creating it
+// if needed, or the overflow account if st has too many accounts.
+func newLabelAccount(st *labelAccountingState, name string) any {
+	labelAccountingMu.Lock()
+	defer labelAccountingMu.Unlock()
+	if acct, ok := st.accounts.Load(name); ok {
+		return acct
+	}
+	if labelAccounting.Load() != st {
+		// SetLabelAccounting was called in the meantime. Charge the
+		// goroutine to an account that is not reported, as for
+		// goroutines whose labels were set before the call.
+		return unsafe.Pointer(nil)
+	}
+	if st.n >= maxLabelAccounts {
+		if st.overflow == nil {
+			st.overflow = runtime_newLabelAccount(labelAccountOverflow)
+		}
+		return st.overflow
+	}
+	acct := runtime_newLabelAccount(name)
+	st.accounts.Store(name, acct)
+	st.n++
+	return acct
+}
+
+// labelAccountName returns the name identifying the values labels has
+// for keys in the names of the account metrics, and whether labels has
+// any of keys at all.
+func labelAccountName(labels *labelMap, keys []string) (string, bool) {
+	if labels == nil {
+		return "", false
+	}
+	var b strings.Builder
+	for _, k := range keys {
+		if v, ok := (*labels)[k]; ok {
+			if b.Len() > 0 {
+				b.WriteByte(',')
+			}
+			writeLabelEscaped(&b, k)
+			b.WriteByte('=')
+			writeLabelEscaped(&b, v)
+		}
+	}
+	return b.String(), b.Len() > 0
+}
+
+// writeLabelEscaped writes s to b, escaping the characters that would
+// make the account name ambiguous or an invalid metric path.
+func writeLabelEscaped(b *strings.Builder, s string) {
+	const hex = "0123456789ABCDEF"
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '%', '/', ':', ',', '=':
+			b.WriteByte('%')
+			b.WriteByte(hex[c>>4])
+			b.WriteByte(hex[c&15])
+		default:
+			b.WriteByte(c)
+		}
+	}
+}
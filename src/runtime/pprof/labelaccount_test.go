@@ -0,0 +1,116 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pprof
+
+import (
+	"context"
+	"runtime/metrics"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var labelAccountSink []byte
+
+// readLabelMetrics returns the values of the label account metrics
+// listed by metrics.All, by name.
+func readLabelMetrics() map[string]metrics.Value {
+	var samples []metrics.Sample
+	for _, d := range metrics.All() {
+		if strings.HasPrefix(d.Name, "/pprof/labels/") {
+			samples = append(samples, metrics.Sample{Name: d.Name})
+		}
+	}
+	if len(samples) == 0 {
+		return nil
+	}
+	metrics.Read(samples)
+	values := make(map[string]metrics.Value)
+	for _, s := range samples {
+		values[s.Name] = s.Value
+	}
+	return values
+}
+
+func TestLabelAccounting(t *testing.T) {
+	SetLabelAccounting("tenant")
+	defer SetLabelAccounting()
+
+	const (
+		allocs = 1000
+		size   = 1024
+	)
+	work := func(ctx context.Context) {
+		var wg sync.WaitGroup
+		wg.Add(1)
+		// Goroutines inherit the account of their creator.
+		go func() {
+			defer wg.Done()
+			for range allocs {
+				labelAccountSink = make([]byte, size)
+			}
+			for start := time.Now(); time.Since(start) < 10*time.Millisecond; {
+			}
+		}()
+		wg.Wait()
+	}
+	ctx := context.Background()
+	Do(ctx, Labels("tenant", "a", "request", "1"), work)
+	Do(ctx, Labels("tenant", "a", "request", "2"), work)
+	Do(ctx, Labels("tenant", "b/c"), work)
+	Do(ctx, Labels("other", "c"), work)
+
+	values := readLabelMetrics()
+	if len(values) != 6 {
+		t.Fatalf("got %d label metrics, want 6: %v", len(values), values)
+	}
+	for name, n := range map[string]uint64{"tenant=a": 2, "tenant=b%2Fc": 1} {
+		prefix := "/pprof/labels/" + name
+		objects, bytes, cpu := values[prefix+"/gc/heap/allocs:objects"], values[prefix+"/gc/heap/allocs:bytes"], values[prefix+"/sched/running:seconds"]
+		if objects.Kind() != metrics.KindUint64 || bytes.Kind() != metrics.KindUint64 || cpu.Kind() != metrics.KindFloat64 {
+			t.Fatalf("%s: missing metrics, got %v", name, values)
+		}
+		if min := n * allocs; objects.Uint64() < min {
+			t.Errorf("%s: got %d objects allocated, want at least %d", name, objects.Uint64(), min)
+		}
+		if min := n * allocs * size; bytes.Uint64() < min {
+			t.Errorf("%s: got %d bytes allocated, want at least %d", name, bytes.Uint64(), min)
+		}
+		if cpu.Float64() <= 0 {
+			t.Errorf("%s: got %v seconds running, want more than 0", name, cpu.Float64())
+		}
+	}
+
+	SetLabelAccounting()
+	if v := readLabelMetrics(); v != nil {
+		t.Errorf("got label metrics %v after disabling accounting, want none", v)
+	}
+}
+
+func TestLabelAccountingOverflow(t *testing.T) {
+	defer func(n int) { maxLabelAccounts = n }(maxLabelAccounts)
+	maxLabelAccounts = 2
+	SetLabelAccounting("tenant")
+	defer SetLabelAccounting()
+
+	work := func(context.Context) {
+		labelAccountSink = make([]byte, 1024)
+	}
+	ctx := context.Background()
+	for _, tenant := range []string{"a", "b", "c", "d", "a"} {
+		Do(ctx, Labels("tenant", tenant), work)
+	}
+
+	values := readLabelMetrics()
+	for _, name := range []string{"tenant=a", "tenant=b", "other"} {
+		if v := values["/pprof/labels/"+name+"/gc/heap/allocs:objects"]; v.Kind() != metrics.KindUint64 || v.Uint64() == 0 {
+			t.Errorf("%s: got %v objects allocated, want more than 0", name, v)
+		}
+	}
+	if len(values) != 9 {
+		t.Errorf("got %d label metrics, want 9: %v", len(values), values)
+	}
+}
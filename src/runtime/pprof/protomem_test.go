@@ -88,6 +88,7 @@ func TestConvertMemProfile(t *testing.T) {
 	}
 }
 
+//go:noinline
 func genericAllocFunc[T interface{ uint32 | uint64 }](n int) []T {
 	return make([]T, n)
 }
@@ -35,6 +35,7 @@ func runtime_getProfLabel() unsafe.Pointer
 func SetGoroutineLabels(ctx context.Context) {
 	ctxLabels, _ := ctx.Value(labelContextKey{}).(*labelMap)
 	runtime_setProfLabel(unsafe.Pointer(ctxLabels))
+	setLabelAccount(ctxLabels)
 }
 
 // Do calls f with a copy of the parent context with the
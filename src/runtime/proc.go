@@ -791,6 +791,7 @@ func schedinit() {
 	lockInit(&reflectOffs.lock, lockRankReflectOffs)
 	lockInit(&finlock, lockRankFin)
 	lockInit(&cpuprof.lock, lockRankCpuprof)
+	lockInit(&debugEvents.lock, lockRankDebugEvents)
 	allocmLock.init(lockRankAllocmR, lockRankAllocmRInternal, lockRankAllocmW)
 	execLock.init(lockRankExecR, lockRankExecRInternal, lockRankExecW)
 	traceLockInit()
@@ -1211,6 +1212,10 @@ func casgstatus(gp *g, oldval, newval uint32) {
 		}
 	}
 
+	if gp.labelAccount != nil {
+		gp.labelAccount.chargeRunning(gp, oldval, newval)
+	}
+
 	if oldval == _Grunning {
 		// Track every gTrackingPeriod time a goroutine transitions out of running.
 		if casgstatusAlwaysTrack || gp.trackingSeq%gTrackingPeriod == 0 {
@@ -1323,6 +1328,9 @@ func casGToPreemptScan(gp *g, old, new uint32) {
 	acquireLockRankAndM(lockRankGscan)
 	for !gp.atomicstatus.CompareAndSwap(_Grunning, _Gscan|_Gpreempted) {
 	}
+	if gp.labelAccount != nil {
+		gp.labelAccount.chargeRunning(gp, _Grunning, _Gpreempted)
+	}
 }
 
 // casGFromPreempted attempts to transition gp from _Gpreempted to
@@ -1709,6 +1717,7 @@ func startTheWorldWithSema(now int64, w worldStop) int64 {
 	} else {
 		sched.stwTotalTimeOther.record(totalTime)
 	}
+	emitDebugEvent(debugEventSTW, totalTime, uint64(w.reason), uint64(w.finishedStopping-w.startedStopping))
 	trace := traceAcquire()
 	if trace.ok() {
 		trace.STWDone()
@@ -4289,6 +4298,7 @@ func gdestroy(gp *g) {
 	gp.waitreason = waitReasonZero
 	gp.param = nil
 	gp.labels = nil
+	gp.labelAccount = nil
 	gp.timer = nil
 
 	if gcBlackenEnabled != 0 && gp.gcAssistBytes > 0 {
@@ -5027,6 +5037,7 @@ func newproc1(fn *funcval, callergp *g, callerpc uintptr, parked bool, waitreaso
 		// Only user goroutines inherit pprof labels.
 		if mp.curg != nil {
 			newg.labels = mp.curg.labels
+			newg.labelAccount = mp.curg.labelAccount
 		}
 		if goroutineProfile.active {
 			// A concurrent goroutine profile is running. It should include
@@ -7165,6 +7176,15 @@ func sync_runtime_doSpin() {
 	procyield(active_spin_cnt)
 }
 
+// sync_runtime_goid returns the ID of the calling goroutine, for the
+// lock order checker in sync.
+//
+//go:linkname sync_runtime_goid sync.runtime_goid
+//go:nosplit
+func sync_runtime_goid() uint64 {
+	return getg().goid
+}
+
 var stealOrder randomOrder
 
 // randomOrder/randomEnum are helper types for randomized work stealing.
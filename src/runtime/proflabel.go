@@ -4,7 +4,10 @@
 
 package runtime
 
-import "unsafe"
+import (
+	"internal/runtime/atomic"
+	"unsafe"
+)
 
 var labelSync uintptr
 
@@ -56,3 +59,76 @@ func runtime_setProfLabel(labels unsafe.Pointer) {
 func runtime_getProfLabel() unsafe.Pointer {
 	return getg().labels
 }
+
+// A labelAccount accumulates the resource usage of all goroutines whose
+// profiler labels runtime/pprof maps to the same account. See
+// runtime/pprof.SetLabelAccounting.
+type labelAccount struct {
+	runningNanos atomic.Int64  // wall time spent in _Grunning
+	allocBytes   atomic.Uint64 // bytes requested from mallocgc
+	allocObjects atomic.Uint64 // calls to mallocgc
+}
+
+// chargeRunning charges the time gp spends running to a, given that gp
+// is transitioning from status oldval to newval.
+//
+//go:nosplit
+func (a *labelAccount) chargeRunning(gp *g, oldval, newval uint32) {
+	if newval == _Grunning {
+		gp.labelRunStart = nanotime()
+	} else if oldval == _Grunning && gp.labelRunStart != 0 {
+		a.runningNanos.Add(nanotime() - gp.labelRunStart)
+		gp.labelRunStart = 0
+	}
+}
+
+// chargeAlloc charges an allocation of size bytes to a.
+//
+//go:nosplit
+func (a *labelAccount) chargeAlloc(size uintptr) {
+	a.allocBytes.Add(int64(size))
+	a.allocObjects.Add(1)
+}
+
+// labelMetricsPrefix is the prefix of the names of the metrics that
+// report the usage of label accounts.
+const labelMetricsPrefix = "/pprof/labels/"
+
+// runtime_newLabelAccount returns a new account, whose usage is reported
+// by metrics whose names start with labelMetricsPrefix followed by labels.
+//
+//go:linkname runtime_newLabelAccount runtime/pprof.runtime_newLabelAccount
+func runtime_newLabelAccount(labels string) unsafe.Pointer {
+	a := new(labelAccount)
+	registerLabelMetrics(labelMetricsPrefix+labels, a)
+	return unsafe.Pointer(a)
+}
+
+// runtime_resetLabelAccounts stops reporting the usage of all the
+// accounts created so far.
+//
+//go:linkname runtime_resetLabelAccounts runtime/pprof.runtime_resetLabelAccounts
+func runtime_resetLabelAccounts() {
+	unregisterLabelMetrics()
+}
+
+// runtime_setLabelAccount sets the account charged for the current
+// goroutine's resource usage. Goroutines created afterwards inherit it.
+//
+//go:linkname runtime_setLabelAccount runtime/pprof.runtime_setLabelAccount
+func runtime_setLabelAccount(acct unsafe.Pointer) {
+	gp := getg()
+	a := (*labelAccount)(acct)
+	if gp.labelAccount == a {
+		return
+	}
+	now := nanotime()
+	if old := gp.labelAccount; old != nil && gp.labelRunStart != 0 {
+		old.runningNanos.Add(now - gp.labelRunStart)
+	}
+	gp.labelRunStart = 0
+	if a != nil {
+		gp.labelRunStart = now
+	}
+	gp.labelAccount = a
+}
@@ -5,7 +5,32 @@
 #include "textflag.h"
 
 TEXT _rt0_amd64_linux(SB),NOSPLIT,$-8
+	// A static PIE is started without a dynamic linker, so it must
+	// apply its own dynamic relocations before it uses any absolute
+	// address. The linker only allows R_X86_64_RELATIVE relocations,
+	// and sets runtime·rela == runtime·erela in other programs.
+	LEAQ	runtime·rela(SB), SI
+	LEAQ	runtime·erela(SB), DI
+	CMPQ	SI, DI
+	JEQ	done
+	// The load bias is the difference between the run-time address
+	// of _rt0_amd64_linux_self<> and its link-time address, which
+	// is what it contains before it is relocated.
+	LEAQ	_rt0_amd64_linux_self<>(SB), AX
+	SUBQ	(AX), AX
+loop:
+	MOVQ	0(SI), BX	// r_offset
+	MOVQ	16(SI), CX	// r_addend
+	ADDQ	AX, CX
+	MOVQ	CX, (AX)(BX*1)
+	ADDQ	$24, SI
+	CMPQ	SI, DI
+	JCS	loop
+done:
 	JMP	_rt0_amd64(SB)
 
+DATA _rt0_amd64_linux_self<>(SB)/8, $_rt0_amd64_linux_self<>(SB)
+GLOBL _rt0_amd64_linux_self<>(SB), NOPTR, $8
+
 TEXT _rt0_amd64_linux_lib(SB),NOSPLIT,$0
 	JMP	_rt0_amd64_lib(SB)
@@ -6,10 +6,36 @@
 #include "cgo/abi_arm64.h"
 
 TEXT _rt0_arm64_linux(SB),NOSPLIT|NOFRAME,$0
+	// A static PIE is started without a dynamic linker, so it must
+	// apply its own dynamic relocations before it uses any absolute
+	// address. The linker only allows R_AARCH64_RELATIVE relocations,
+	// and sets runtime·rela == runtime·erela in other programs.
+	MOVD	$runtime·rela(SB), R2
+	MOVD	$runtime·erela(SB), R3
+	CMP	R2, R3
+	BEQ	done
+	// The load bias is the difference between the run-time address
+	// of _rt0_arm64_linux_self<> and its link-time address, which
+	// is what it contains before it is relocated.
+	MOVD	$_rt0_arm64_linux_self<>(SB), R4
+	MOVD	(R4), R5
+	SUB	R5, R4, R4
+loop:
+	MOVD	0(R2), R5	// r_offset
+	MOVD	16(R2), R6	// r_addend
+	ADD	R4, R6, R6
+	MOVD	R6, (R4)(R5)
+	ADD	$24, R2, R2
+	CMP	R3, R2
+	BLO	loop
+done:
 	MOVD	0(RSP), R0	// argc
 	ADD	$8, RSP, R1	// argv
 	BL	main(SB)
 
+DATA _rt0_arm64_linux_self<>(SB)/8, $_rt0_arm64_linux_self<>(SB)
+GLOBL _rt0_arm64_linux_self<>(SB), NOPTR, $8
+
 // When building with -buildmode=c-shared, this symbol is called when the shared
 // library is loaded.
 TEXT _rt0_arm64_linux_lib(SB),NOSPLIT,$184
@@ -141,7 +141,6 @@ class SliceTypePrinter:
 			yield ('[{0}]'.format(idx), item)
 
 
-# TODO(go.dev/issue/54766): Support swisstable maps.
 class MapTypePrinter:
 	"""Pretty print map[K]V types.
 
@@ -161,6 +160,50 @@ class MapTypePrinter:
 		return str(self.val.type)
 
 	def children(self):
+		fields = [f.name for f in self.val.dereference().type.fields()]
+		if 'dirPtr' in fields:
+			return self.swiss_children()
+		return self.old_children()
+
+	def swiss_children(self):
+		SwissMapGroupSlots = 8 # see internal/abi:SwissMapGroupSlots
+
+		cnt = 0
+		# dirPtr is typed as a pointer to a single group, which is what
+		# it points to for small maps. Otherwise it points to a
+		# directory of dirLen table pointers, several of which may
+		# point to the same table.
+		dirPtr = self.val['dirPtr']
+		dirLen = int(self.val['dirLen'])
+		groups = []
+		if dirLen == 0:
+			if dirPtr:
+				groups.append(dirPtr)
+		else:
+			entries = dirPtr.cast(lookup_type('runtime.table').pointer().pointer())
+			last = None
+			for i in xrange(dirLen):
+				tab = entries[i]
+				if last is not None and tab == last:
+					continue
+				last = tab
+				data = tab['groups']['data'].cast(dirPtr.type)
+				for j in xrange(int(tab['groups']['lengthMask']) + 1):
+					groups.append(data + j)
+
+		for gp in groups:
+			g = gp.dereference()
+			ctrl = int(g['ctrl'])
+			for i in xrange(SwissMapGroupSlots):
+				if (ctrl >> (8 * i)) & 0x80 != 0:
+					continue # empty or deleted
+				# Keys and elements larger than 128 bytes are
+				# stored indirectly, and print as pointers.
+				yield str(cnt), g['slots'][i]['key']
+				yield str(cnt + 1), g['slots'][i]['elem']
+				cnt += 2
+
+	def old_children(self):
 		MapBucketCount = 8 # see internal/abi.go:MapBucketCount
 		B = self.val['B']
 		buckets = self.val['buckets']
@@ -479,6 +479,8 @@ type g struct {
 	waiting       *sudog         // sudog structures this g is waiting on (that have a valid elem ptr); in lock order
 	cgoCtxt       []uintptr      // cgo traceback context
 	labels        unsafe.Pointer // profiler labels
+	labelAccount  *labelAccount  // resource usage account for labels, or nil
+	labelRunStart int64          // nanotime when gp started running, if labelAccount != nil
 	timer         *timer         // cached timer for time.Sleep
 	sleepWhen     int64          // when to sleep until
 	selectDone    atomic.Uint32  // are we participating in a select and did someone win the race?
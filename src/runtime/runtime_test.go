@@ -323,6 +323,28 @@ func TestAppendGrowth(t *testing.T) {
 			want = 2 * i
 		}
 	}
+	Escape(&x) // keep the backing store off the stack
+}
+
+func TestAppendGrowthStack(t *testing.T) {
+	var x []int64
+	check := func(want int) {
+		if cap(x) != want {
+			t.Errorf("len=%d, cap=%d, want cap=%d", len(x), cap(x), want)
+		}
+	}
+
+	// The first append grows x into a 32-byte stack buffer, the default
+	// size set by the compiler's -d=variablemakethreshold flag.
+	check(0)
+	want := 32 / 8
+	for i := 1; i <= 100; i++ {
+		x = append(x, 1)
+		check(want)
+		if i&(i-1) == 0 {
+			want = max(want, 2*i)
+		}
+	}
 }
 
 var One = []int64{1}
@@ -344,6 +366,7 @@ func TestAppendSliceGrowth(t *testing.T) {
 			want = 2 * i
 		}
 	}
+	Escape(&x) // keep the backing store off the stack
 }
 
 func TestGoroutineProfileTrivial(t *testing.T) {
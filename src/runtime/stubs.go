@@ -151,9 +151,6 @@ func reflect_memmove(to, from unsafe.Pointer, n uintptr) {
 	memmove(to, from, n)
 }
 
-// exported value for testing
-const hashLoad = float32(loadFactorNum) / float32(loadFactorDen)
-
 // in internal/bytealg/equal_*.s
 //
 // memequal should be an internal detail,
@@ -0,0 +1,15 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build goexperiment.simd
+
+package simd
+
+import "internal/cpu"
+
+// HasAVX2 reports whether the CPU supports AVX2, which every vector
+// operation in this package requires.
+func HasAVX2() bool {
+	return cpu.X86.HasAVX2
+}
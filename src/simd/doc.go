@@ -0,0 +1,48 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build goexperiment.simd
+
+//go:generate go run mkstubs.go
+
+/*
+Package simd provides access to the vector instructions of the CPU.
+
+This package is experimental and is only available when building with
+GOEXPERIMENT=simd. Its API is not covered by the Go 1 compatibility
+promise and is likely to change.
+
+Each vector type, such as [Int32x8] or [Float64x4], holds a fixed number
+of lanes of one element type. The methods of the vector types are
+compiler intrinsics: a call compiles to one or a few machine
+instructions, and vector values are kept in vector registers across
+calls, without a function call or a trip through memory. Vectors are
+only loaded from or stored to memory by the Load and Store functions
+and methods, when they are passed to or returned from functions that
+are not inlined, and when the register allocator runs out of registers.
+
+Comparisons produce masks, such as [Mask32x8], with one lane per lane
+of the compared vectors. A mask lane is either all ones (true) or all
+zeros (false). Masks select lanes in [Int32x8.Blend] and friends, and
+[Mask32x8.ToBits] packs them into an integer.
+
+Currently only 256-bit vectors on amd64 are supported, and every
+operation requires AVX2. Programs must check [HasAVX2] before using
+any vector operation; on a CPU without AVX2 they fault with an illegal
+instruction.
+
+The package has other known limits:
+
+  - There are no 512-bit vectors, masks held in mask registers, or
+    other AVX-512 operations, even on CPUs that support them.
+  - Vectors are never passed in registers: a vector argument or result
+    of a function that is not inlined goes through memory on the stack.
+  - A function that uses vectors executes VZEROUPPER before each call
+    and return, to avoid the penalty the CPU charges for running SSE
+    instructions with the upper halves of the vector registers in use.
+    The compiler's own floating-point code, which uses SSE instructions,
+    may still pay that penalty when it is mixed with vector operations
+    in the same function.
+*/
+package simd
@@ -0,0 +1,395 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build ignore
+
+// mkstubs generates the vector types of package simd, the declarations
+// of their operations, and the table in cmd/compile/internal/ssagen
+// that maps each operation to the compiler intrinsic implementing it.
+//
+// Run it with "go generate" in this directory.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"strings"
+)
+
+type kind int
+
+const (
+	signed kind = iota
+	unsigned
+	float
+)
+
+// A vec describes a 256-bit vector type.
+type vec struct {
+	name  string // Int32x8
+	elem  string // int32
+	kind  kind
+	bits  int // lane width in bits
+	lanes int
+}
+
+func (v vec) mask() string { return fmt.Sprintf("Mask%dx%d", v.bits, v.lanes) }
+func (v vec) arr() string  { return fmt.Sprintf("[%d]%s", v.lanes, v.elem) }
+
+// suffix is the instruction suffix for integer lanes of v.
+func (v vec) suffix() string {
+	return map[int]string{8: "B", 16: "W", 32: "D", 64: "Q"}[v.bits]
+}
+
+// fsuffix is the instruction suffix for floating point lanes of v.
+func (v vec) fsuffix() string {
+	return map[int]string{32: "PS", 64: "PD"}[v.bits]
+}
+
+func newVec(k kind, bits int) vec {
+	prefix := map[kind]string{signed: "Int", unsigned: "Uint", float: "Float"}[k]
+	v := vec{kind: k, bits: bits, lanes: 256 / bits}
+	v.name = fmt.Sprintf("%s%dx%d", prefix, bits, v.lanes)
+	v.elem = fmt.Sprintf("%s%d", strings.ToLower(prefix), bits)
+	return v
+}
+
+var (
+	ints   []vec // signed and unsigned
+	floats []vec
+	vecs   []vec // all of the above
+	masks  []vec // one per lane width; kind and elem are unused
+)
+
+func init() {
+	for _, bits := range []int{8, 16, 32, 64} {
+		ints = append(ints, newVec(signed, bits), newVec(unsigned, bits))
+		masks = append(masks, vec{name: fmt.Sprintf("Mask%dx%d", bits, 256/bits), bits: bits, lanes: 256 / bits})
+	}
+	floats = []vec{newVec(float, 32), newVec(float, 64)}
+	vecs = append(append(vecs, ints...), floats...)
+}
+
+// A decl is a function or method of package simd.
+type decl struct {
+	doc     string // without the leading name
+	recv    string // receiver type, or "" for a function
+	name    string
+	params  string
+	results string
+	noesc   bool   // //go:noescape
+	builder string // ssagen intrinsic builder
+}
+
+var decls []decl
+
+func (d decl) key() string {
+	if d.recv == "" {
+		return d.name
+	}
+	return d.recv + "." + d.name
+}
+
+func op(name string) string { return "ssa.OpAMD64" + name }
+
+// method adds a method of v with one vector operand (x, the receiver)
+// or two (x and y, both of type v) that is implemented by builder.
+func method(v vec, name string, nargs int, result, doc, builder string) {
+	params := "()"
+	if nargs == 2 {
+		params = "(y " + v.name + ")"
+	}
+	decls = append(decls, decl{doc: doc, recv: v.name, name: name, params: params, results: result, builder: builder})
+}
+
+func binary(v vec, name, ssaop, doc string) {
+	method(v, name, 2, v.name, doc, fmt.Sprintf("simdBinary(%s)", op(ssaop)))
+}
+
+func main() {
+	for _, v := range vecs {
+		decls = append(decls,
+			decl{
+				doc:     fmt.Sprintf("returns the %s at p as a vector.", v.arr()),
+				name:    "Load" + v.name,
+				params:  "(p *" + v.arr() + ")",
+				results: v.name,
+				noesc:   true,
+				builder: "simdLoad()",
+			},
+			decl{
+				doc:     "stores x to p.",
+				recv:    v.name,
+				name:    "Store",
+				params:  "(p *" + v.arr() + ")",
+				noesc:   true,
+				builder: "simdStore()",
+			})
+		move, bcast := "ssa.OpInvalid", "VBROADCASTS"+map[int]string{32: "S", 64: "D"}[v.bits]+"256"
+		if v.kind != float {
+			move, bcast = op("MOVLi2f"), "VPBROADCAST"+v.suffix()+"256"
+			if v.bits == 64 {
+				move = op("MOVQi2f")
+			}
+		}
+		decls = append(decls, decl{
+			doc:     "returns a vector with every lane set to x.",
+			name:    "Broadcast" + v.name,
+			params:  "(x " + v.elem + ")",
+			results: v.name,
+			builder: fmt.Sprintf("simdBroadcast(%s, %s)", move, op(bcast)),
+		})
+	}
+
+	for _, v := range ints {
+		s := v.suffix()
+		binary(v, "Add", "VPADD"+s+"256", "returns x + y in each lane, wrapping around on overflow.")
+		binary(v, "Sub", "VPSUB"+s+"256", "returns x - y in each lane, wrapping around on overflow.")
+		if v.bits == 16 || v.bits == 32 {
+			binary(v, "MulLow", "VPMULL"+s+"256", "returns the low half of x * y in each lane.")
+		}
+		if v.bits <= 16 {
+			u := "S"
+			if v.kind == unsigned {
+				u = "US"
+			}
+			binary(v, "AddSaturated", "VPADD"+u+s+"256", "returns x + y in each lane, clamped to the range of "+v.elem+".")
+			binary(v, "SubSaturated", "VPSUB"+u+s+"256", "returns x - y in each lane, clamped to the range of "+v.elem+".")
+		}
+		if v.bits <= 32 {
+			u := "S"
+			if v.kind == unsigned {
+				u = "U"
+			}
+			binary(v, "Min", "VPMIN"+u+s+"256", "returns the smaller of x and y in each lane.")
+			binary(v, "Max", "VPMAX"+u+s+"256", "returns the larger of x and y in each lane.")
+		}
+		if v.kind == unsigned && v.bits <= 16 {
+			binary(v, "Average", "VPAVG"+s+"256", "returns (x + y + 1) / 2 in each lane, computed without overflow.")
+		}
+		if v.kind == signed && v.bits <= 32 {
+			method(v, "Abs", 1, v.name, "returns the absolute value of each lane. The most negative value is unchanged.",
+				fmt.Sprintf("simdUnary(%s)", op("VPABS"+s+"256")))
+		}
+		if v.bits >= 32 {
+			binary(v, "ShiftLeft", "VPSLLV"+s+"256", "returns x << y in each lane. Shifts by the lane width or more produce zero.")
+			shift := "a logical right shift"
+			if v.kind == signed {
+				shift = "an arithmetic right shift"
+			}
+			var ssaop string
+			switch {
+			case v.kind == unsigned:
+				ssaop = "VPSRLV" + s + "256"
+			case v.bits == 32:
+				ssaop = "VPSRAV" + s + "256"
+			}
+			if ssaop != "" {
+				binary(v, "ShiftRight", ssaop, fmt.Sprintf("returns x >> y in each lane, using %s.\n"+
+					"Shifts by the lane width or more produce %s.", shift,
+					map[kind]string{signed: "0 or -1, according to the sign of x", unsigned: "zero"}[v.kind]))
+			}
+		}
+		binary(v, "And", "VPAND256", "returns x & y.")
+		binary(v, "Or", "VPOR256", "returns x | y.")
+		binary(v, "Xor", "VPXOR256", "returns x ^ y.")
+		method(v, "AndNot", 2, v.name, "returns x &^ y.", fmt.Sprintf("simdBinarySwapped(%s)", op("VPANDN256")))
+		method(v, "Equal", 2, v.mask(), "reports whether x == y in each lane.",
+			fmt.Sprintf("simdBinary(%s)", op("VPCMPEQ"+s+"256")))
+		if v.kind == signed {
+			method(v, "Greater", 2, v.mask(), "reports whether x > y in each lane.",
+				fmt.Sprintf("simdBinary(%s)", op("VPCMPGT"+s+"256")))
+			method(v, "Less", 2, v.mask(), "reports whether x < y in each lane.",
+				fmt.Sprintf("simdBinarySwapped(%s)", op("VPCMPGT"+s+"256")))
+		}
+	}
+
+	for _, v := range floats {
+		s := v.fsuffix()
+		binary(v, "Add", "VADD"+s+"256", "returns x + y in each lane.")
+		binary(v, "Sub", "VSUB"+s+"256", "returns x - y in each lane.")
+		binary(v, "Mul", "VMUL"+s+"256", "returns x * y in each lane.")
+		binary(v, "Div", "VDIV"+s+"256", "returns x / y in each lane.")
+		binary(v, "Min", "VMIN"+s+"256", "returns the smaller of x and y in each lane.\n"+
+			"If either lane is a NaN, or both are zeros, the lane of y is returned.")
+		binary(v, "Max", "VMAX"+s+"256", "returns the larger of x and y in each lane.\n"+
+			"If either lane is a NaN, or both are zeros, the lane of y is returned.")
+		method(v, "Sqrt", 1, v.name, "returns the square root of each lane.",
+			fmt.Sprintf("simdUnary(%s)", op("VSQRT"+s+"256")))
+		for _, c := range []struct {
+			name, op string
+			pred     int
+		}{
+			{"Equal", "==", 0x00},
+			{"NotEqual", "!=", 0x04},
+			{"Less", "<", 0x01},
+			{"LessEqual", "<=", 0x02},
+			{"Greater", ">", 0x0e},
+			{"GreaterEqual", ">=", 0x0d},
+		} {
+			method(v, c.name, 2, v.mask(), fmt.Sprintf("reports whether x %s y in each lane.", c.op),
+				fmt.Sprintf("simdCompare(%s, %#02x)", op("VCMP"+s+"256"), c.pred))
+		}
+	}
+
+	for _, v := range vecs {
+		decls = append(decls, decl{
+			doc:     "returns a vector whose lanes are the lanes of y where m is true, and the lanes of x elsewhere.",
+			recv:    v.name,
+			name:    "Blend",
+			params:  "(y " + v.name + ", m " + v.mask() + ")",
+			results: v.name,
+			builder: fmt.Sprintf("simdBlend(%s)", op("VPBLENDVB256")),
+		})
+	}
+
+	// Conversions that reinterpret the bits of a vector.
+	for _, v := range vecs {
+		for _, w := range vecs {
+			if v.bits != w.bits || v.kind == w.kind {
+				continue
+			}
+			method(v, "As"+w.name, 1, w.name,
+				fmt.Sprintf("returns x reinterpreted as a %s. The bits of x are unchanged.", w.name),
+				"simdReinterpret()")
+		}
+	}
+
+	for _, m := range masks {
+		for _, b := range []struct{ name, op, doc string }{
+			{"And", "VPAND256", "reports whether both x and y are true in each lane."},
+			{"Or", "VPOR256", "reports whether either of x and y is true in each lane."},
+			{"Xor", "VPXOR256", "reports whether exactly one of x and y is true in each lane."},
+		} {
+			binary(m, b.name, b.op, b.doc)
+		}
+		method(m, "AndNot", 2, m.name, "reports whether x is true and y is false in each lane.",
+			fmt.Sprintf("simdBinarySwapped(%s)", op("VPANDN256")))
+		method(m, "Not", 1, m.name, "negates each lane of x.",
+			fmt.Sprintf("simdNot(%s, %s)", op("VPCMPEQB256"), op("VPXOR256")))
+		iv := newVec(signed, m.bits)
+		method(m, "As"+iv.name, 1, iv.name, "returns x as a vector whose lanes are -1 where x is true and 0 elsewhere.",
+			"simdReinterpret()")
+		var msk, bitsType string
+		switch m.bits {
+		case 8:
+			msk, bitsType = "VPMOVMSKB256", "uint32"
+		case 32:
+			msk, bitsType = "VMOVMSKPS256", "uint8"
+		case 64:
+			msk, bitsType = "VMOVMSKPD256", "uint8"
+		}
+		if msk != "" {
+			method(m, "ToBits", 1, bitsType, "returns x as a bit set, with bit i set if lane i is true.",
+				fmt.Sprintf("simdUnary(%s)", op(msk)))
+		}
+	}
+
+	write("types_amd64.go", genTypes())
+	write("stubs_amd64.go", genStubs())
+	write("slice_amd64.go", genSlices())
+	write("../cmd/compile/internal/ssagen/simdintrinsics.go", genIntrinsics())
+}
+
+const header = `// Code generated by mkstubs.go. DO NOT EDIT.
+
+`
+
+const buildTag = "//go:build goexperiment.simd && amd64\n\n"
+
+func genTypes() []byte {
+	var b bytes.Buffer
+	b.WriteString(header + buildTag + "package simd\n\n")
+	b.WriteString(`// v256 tags the vector types of this package. The compiler keeps a
+// struct whose first field is a v256 in a 256-bit vector register.
+// Its func element makes vectors incomparable.
+type v256 struct {
+	_256 [0]func()
+}
+`)
+	for _, v := range vecs {
+		fmt.Fprintf(&b, "\n// %s is a 256-bit vector of %d %s lanes.\n", v.name, v.lanes, v.elem)
+		fmt.Fprintf(&b, "type %s struct {\n\t_ v256\n\t_ %s\n}\n", v.name, v.arr())
+	}
+	for _, m := range masks {
+		fmt.Fprintf(&b, "\n// %s is the mask of %d lanes of %d bits produced by comparing\n", m.name, m.lanes, m.bits)
+		fmt.Fprintf(&b, "// vectors with %d-bit lanes.\n", m.bits)
+		fmt.Fprintf(&b, "type %s struct {\n\t_ v256\n\t_ [%d]int%d\n}\n", m.name, m.lanes, m.bits)
+	}
+	return b.Bytes()
+}
+
+func genStubs() []byte {
+	var b bytes.Buffer
+	b.WriteString(header + buildTag + "package simd\n")
+	for _, d := range decls {
+		b.WriteString("\n")
+		doc := strings.Split(d.doc, "\n")
+		fmt.Fprintf(&b, "// %s %s\n", d.name, doc[0])
+		for _, l := range doc[1:] {
+			fmt.Fprintf(&b, "// %s\n", l)
+		}
+		if d.noesc {
+			b.WriteString("//\n//go:noescape\n")
+		}
+		b.WriteString("func ")
+		if d.recv != "" {
+			fmt.Fprintf(&b, "(x %s) ", d.recv)
+		}
+		fmt.Fprintf(&b, "%s%s %s\n", d.name, d.params, d.results)
+	}
+	return b.Bytes()
+}
+
+func genSlices() []byte {
+	var b bytes.Buffer
+	b.WriteString(header + buildTag + "package simd\n")
+	for _, v := range vecs {
+		fmt.Fprintf(&b, `
+// Load%[1]sSlice returns the first %[2]d elements of s as a vector.
+// It panics if len(s) < %[2]d.
+func Load%[1]sSlice(s []%[3]s) %[1]s {
+	return Load%[1]s((*%[4]s)(s))
+}
+
+// StoreSlice stores x to the first %[2]d elements of s.
+// It panics if len(s) < %[2]d.
+func (x %[1]s) StoreSlice(s []%[3]s) {
+	x.Store((*%[4]s)(s))
+}
+`, v.name, v.lanes, v.elem, v.arr())
+	}
+	return b.Bytes()
+}
+
+func genIntrinsics() []byte {
+	var b bytes.Buffer
+	b.WriteString(header + `package ssagen
+
+import (
+	"cmd/compile/internal/ssa"
+	"cmd/internal/sys"
+)
+
+func simdIntrinsics(addF func(pkg, fn string, b intrinsicBuilder, archFamilies ...sys.ArchFamily)) {
+`)
+	for _, d := range decls {
+		fmt.Fprintf(&b, "\taddF(%q, %q, %s, sys.AMD64)\n", "simd", d.key(), d.builder)
+	}
+	b.WriteString("}\n")
+	return b.Bytes()
+}
+
+func write(file string, src []byte) {
+	out, err := format.Source(src)
+	if err != nil {
+		log.Fatalf("%s: %v\n%s", file, err, src)
+	}
+	if err := os.WriteFile(file, out, 0666); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,271 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build goexperiment.simd && amd64
+
+package simd_test
+
+import (
+	"math"
+	"simd"
+	"testing"
+)
+
+func requireAVX2(t testing.TB) {
+	if !simd.HasAVX2() {
+		t.Skip("CPU does not support AVX2")
+	}
+}
+
+var int32s = [...]int32{0, 1, -1, 2, -2, 7, math.MaxInt32, math.MinInt32, 100, -100, 12345, -54321, 3, 1 << 20, -(1 << 20), 42}
+
+func TestInt32x8(t *testing.T) {
+	requireAVX2(t)
+	for i := 0; i+8 <= len(int32s); i++ {
+		for j := 0; j+8 <= len(int32s); j++ {
+			var a, b [8]int32
+			copy(a[:], int32s[i:])
+			copy(b[:], int32s[j:])
+			x, y := simd.LoadInt32x8(&a), simd.LoadInt32x8(&b)
+
+			check := func(name string, v simd.Int32x8, f func(a, b int32) int32) {
+				t.Helper()
+				var got [8]int32
+				v.Store(&got)
+				for k := range got {
+					if want := f(a[k], b[k]); got[k] != want {
+						t.Errorf("%v.%s(%v): lane %d = %d, want %d", a, name, b, k, got[k], want)
+					}
+				}
+			}
+			check("Add", x.Add(y), func(a, b int32) int32 { return a + b })
+			check("Sub", x.Sub(y), func(a, b int32) int32 { return a - b })
+			check("MulLow", x.MulLow(y), func(a, b int32) int32 { return a * b })
+			check("Min", x.Min(y), func(a, b int32) int32 { return min(a, b) })
+			check("Max", x.Max(y), func(a, b int32) int32 { return max(a, b) })
+			check("And", x.And(y), func(a, b int32) int32 { return a & b })
+			check("Or", x.Or(y), func(a, b int32) int32 { return a | b })
+			check("Xor", x.Xor(y), func(a, b int32) int32 { return a ^ b })
+			check("AndNot", x.AndNot(y), func(a, b int32) int32 { return a &^ b })
+			check("Abs", x.Abs(), func(a, _ int32) int32 {
+				if a < 0 {
+					return -a
+				}
+				return a
+			})
+			check("ShiftLeft", x.ShiftLeft(y), func(a, b int32) int32 {
+				if uint32(b) >= 32 {
+					return 0
+				}
+				return a << b
+			})
+			check("ShiftRight", x.ShiftRight(y), func(a, b int32) int32 {
+				return a >> min(uint32(b), 31)
+			})
+			check("Blend", x.Blend(y, x.Greater(y)), func(a, b int32) int32 { return min(a, b) })
+
+			checkMask := func(name string, m simd.Mask32x8, f func(a, b int32) bool) {
+				t.Helper()
+				bits := m.ToBits()
+				var lanes [8]int32
+				m.AsInt32x8().Store(&lanes)
+				for k := range lanes {
+					want := f(a[k], b[k])
+					if got := bits>>k&1 != 0; got != want {
+						t.Errorf("%v.%s(%v).ToBits(): bit %d = %v, want %v", a, name, b, k, got, want)
+					}
+					if want && lanes[k] != -1 || !want && lanes[k] != 0 {
+						t.Errorf("%v.%s(%v): lane %d = %d, want %v", a, name, b, k, lanes[k], want)
+					}
+				}
+			}
+			checkMask("Equal", x.Equal(y), func(a, b int32) bool { return a == b })
+			checkMask("Greater", x.Greater(y), func(a, b int32) bool { return a > b })
+			checkMask("Less", x.Less(y), func(a, b int32) bool { return a < b })
+			checkMask("Less.Not", x.Less(y).Not(), func(a, b int32) bool { return a >= b })
+			checkMask("Less.Or", x.Less(y).Or(x.Equal(y)), func(a, b int32) bool { return a <= b })
+			checkMask("Less.AndNot", x.Less(y).AndNot(x.Greater(y)), func(a, b int32) bool { return a < b })
+		}
+	}
+}
+
+func TestUint8x32(t *testing.T) {
+	requireAVX2(t)
+	var a, b [32]uint8
+	for i := range a {
+		a[i] = uint8(i * 37)
+		b[i] = uint8(255 - i*11)
+	}
+	x, y := simd.LoadUint8x32Slice(a[:]), simd.LoadUint8x32Slice(b[:])
+
+	check := func(name string, v simd.Uint8x32, f func(a, b uint8) uint8) {
+		t.Helper()
+		got := make([]uint8, 32)
+		v.StoreSlice(got)
+		for k := range got {
+			if want := f(a[k], b[k]); got[k] != want {
+				t.Errorf("%s: lane %d = %d, want %d", name, k, got[k], want)
+			}
+		}
+	}
+	check("Add", x.Add(y), func(a, b uint8) uint8 { return a + b })
+	check("AddSaturated", x.AddSaturated(y), func(a, b uint8) uint8 { return uint8(min(int(a)+int(b), 255)) })
+	check("SubSaturated", x.SubSaturated(y), func(a, b uint8) uint8 { return uint8(max(int(a)-int(b), 0)) })
+	check("Average", x.Average(y), func(a, b uint8) uint8 { return uint8((int(a) + int(b) + 1) / 2) })
+	check("Min", x.Min(y), func(a, b uint8) uint8 { return min(a, b) })
+	check("Broadcast", simd.BroadcastUint8x32(b[3]), func(_, _ uint8) uint8 { return b[3] })
+
+	eq := x.Equal(simd.BroadcastUint8x32(a[5])).ToBits()
+	if eq != 1<<5 {
+		t.Errorf("Equal(Broadcast(a[5])).ToBits() = %#x, want %#x", eq, 1<<5)
+	}
+}
+
+func TestFloat64x4(t *testing.T) {
+	requireAVX2(t)
+	a := [4]float64{1.5, -2, 0, math.Inf(1)}
+	b := [4]float64{0.5, -2, 3, 2}
+	x, y := simd.LoadFloat64x4(&a), simd.LoadFloat64x4(&b)
+
+	check := func(name string, v simd.Float64x4, f func(a, b float64) float64) {
+		t.Helper()
+		var got [4]float64
+		v.Store(&got)
+		for k := range got {
+			if want := f(a[k], b[k]); got[k] != want && !(math.IsNaN(got[k]) && math.IsNaN(want)) {
+				t.Errorf("%s: lane %d = %v, want %v", name, k, got[k], want)
+			}
+		}
+	}
+	check("Add", x.Add(y), func(a, b float64) float64 { return a + b })
+	check("Sub", x.Sub(y), func(a, b float64) float64 { return a - b })
+	check("Mul", x.Mul(y), func(a, b float64) float64 { return a * b })
+	check("Div", x.Div(y), func(a, b float64) float64 { return a / b })
+	check("Max", x.Max(y), func(a, b float64) float64 { return max(a, b) })
+	check("Sqrt", y.Sqrt(), func(_, b float64) float64 { return math.Sqrt(b) })
+
+	for _, c := range []struct {
+		name string
+		m    simd.Mask64x4
+		f    func(a, b float64) bool
+	}{
+		{"Equal", x.Equal(y), func(a, b float64) bool { return a == b }},
+		{"NotEqual", x.NotEqual(y), func(a, b float64) bool { return a != b }},
+		{"Less", x.Less(y), func(a, b float64) bool { return a < b }},
+		{"LessEqual", x.LessEqual(y), func(a, b float64) bool { return a <= b }},
+		{"Greater", x.Greater(y), func(a, b float64) bool { return a > b }},
+		{"GreaterEqual", x.GreaterEqual(y), func(a, b float64) bool { return a >= b }},
+	} {
+		bits := c.m.ToBits()
+		for k := range a {
+			if got, want := bits>>k&1 != 0, c.f(a[k], b[k]); got != want {
+				t.Errorf("%s: lane %d = %v, want %v", c.name, k, got, want)
+			}
+		}
+	}
+
+	nan := simd.BroadcastFloat64x4(math.NaN())
+	if bits := nan.NotEqual(nan).ToBits(); bits != 0xf {
+		t.Errorf("NaN.NotEqual(NaN).ToBits() = %#x, want 0xf", bits)
+	}
+	if bits := nan.Equal(nan).ToBits(); bits != 0 {
+		t.Errorf("NaN.Equal(NaN).ToBits() = %#x, want 0", bits)
+	}
+}
+
+func TestConversions(t *testing.T) {
+	requireAVX2(t)
+	f := simd.BroadcastFloat32x8(-1)
+	var got [8]uint32
+	f.AsUint32x8().Store(&got)
+	for k, v := range got {
+		if v != math.Float32bits(-1) {
+			t.Errorf("lane %d = %#x, want %#x", k, v, math.Float32bits(-1))
+		}
+	}
+	var back [8]float32
+	simd.BroadcastUint32x8(math.Float32bits(2.5)).AsFloat32x8().Store(&back)
+	for k, v := range back {
+		if v != 2.5 {
+			t.Errorf("lane %d = %v, want 2.5", k, v)
+		}
+	}
+	var i64 [4]int64
+	simd.BroadcastInt64x4(math.MinInt64 + 3).Store(&i64)
+	for k, v := range i64 {
+		if v != math.MinInt64+3 {
+			t.Errorf("lane %d = %d, want %d", k, v, int64(math.MinInt64+3))
+		}
+	}
+}
+
+// sum adds the elements of s in a loop that keeps the accumulator in
+// a vector register.
+func sum(s []int32) int32 {
+	var acc simd.Int32x8
+	for len(s) >= 8 {
+		acc = acc.Add(simd.LoadInt32x8Slice(s))
+		s = s[8:]
+	}
+	var lanes [8]int32
+	acc.Store(&lanes)
+	var total int32
+	for _, v := range lanes {
+		total += v
+	}
+	for _, v := range s {
+		total += v
+	}
+	return total
+}
+
+func TestLoop(t *testing.T) {
+	requireAVX2(t)
+	s := make([]int32, 1003)
+	var want int32
+	for i := range s {
+		s[i] = int32(i*i - 500)
+		want += s[i]
+	}
+	if got := sum(s); got != want {
+		t.Errorf("sum = %d, want %d", got, want)
+	}
+}
+
+//go:noinline
+func addNoinline(x, y simd.Float32x8) simd.Float32x8 {
+	return x.Add(y)
+}
+
+func TestIndirect(t *testing.T) {
+	requireAVX2(t)
+	x, y := simd.BroadcastFloat32x8(1), simd.BroadcastFloat32x8(2)
+	for name, add := range map[string]func(simd.Float32x8) simd.Float32x8{
+		"method value":       x.Add,
+		"method expression":  func(y simd.Float32x8) simd.Float32x8 { return simd.Float32x8.Add(x, y) },
+		"noinline function":  func(y simd.Float32x8) simd.Float32x8 { return addNoinline(x, y) },
+		"interface method":   any(x).(interface{ Add(simd.Float32x8) simd.Float32x8 }).Add,
+		"generated function": simd.Float32x8.Add(x, simd.Float32x8{}).Add,
+	} {
+		var got [8]float32
+		add(y).Store(&got)
+		for k, v := range got {
+			if v != 3 {
+				t.Errorf("%s: lane %d = %v, want 3", name, k, v)
+			}
+		}
+	}
+}
+
+func BenchmarkSum(b *testing.B) {
+	requireAVX2(b)
+	s := make([]int32, 4096)
+	for i := range s {
+		s[i] = int32(i)
+	}
+	b.SetBytes(int64(4 * len(s)))
+	for range b.N {
+		sum(s)
+	}
+}
@@ -0,0 +1,125 @@
+// Code generated by mkstubs.go. DO NOT EDIT.
+
+//go:build goexperiment.simd && amd64
+
+package simd
+
+// LoadInt8x32Slice returns the first 32 elements of s as a vector.
+// It panics if len(s) < 32.
+func LoadInt8x32Slice(s []int8) Int8x32 {
+	return LoadInt8x32((*[32]int8)(s))
+}
+
+// StoreSlice stores x to the first 32 elements of s.
+// It panics if len(s) < 32.
+func (x Int8x32) StoreSlice(s []int8) {
+	x.Store((*[32]int8)(s))
+}
+
+// LoadUint8x32Slice returns the first 32 elements of s as a vector.
+// It panics if len(s) < 32.
+func LoadUint8x32Slice(s []uint8) Uint8x32 {
+	return LoadUint8x32((*[32]uint8)(s))
+}
+
+// StoreSlice stores x to the first 32 elements of s.
+// It panics if len(s) < 32.
+func (x Uint8x32) StoreSlice(s []uint8) {
+	x.Store((*[32]uint8)(s))
+}
+
+// LoadInt16x16Slice returns the first 16 elements of s as a vector.
+// It panics if len(s) < 16.
+func LoadInt16x16Slice(s []int16) Int16x16 {
+	return LoadInt16x16((*[16]int16)(s))
+}
+
+// StoreSlice stores x to the first 16 elements of s.
+// It panics if len(s) < 16.
+func (x Int16x16) StoreSlice(s []int16) {
+	x.Store((*[16]int16)(s))
+}
+
+// LoadUint16x16Slice returns the first 16 elements of s as a vector.
+// It panics if len(s) < 16.
+func LoadUint16x16Slice(s []uint16) Uint16x16 {
+	return LoadUint16x16((*[16]uint16)(s))
+}
+
+// StoreSlice stores x to the first 16 elements of s.
+// It panics if len(s) < 16.
+func (x Uint16x16) StoreSlice(s []uint16) {
+	x.Store((*[16]uint16)(s))
+}
+
+// LoadInt32x8Slice returns the first 8 elements of s as a vector.
+// It panics if len(s) < 8.
+func LoadInt32x8Slice(s []int32) Int32x8 {
+	return LoadInt32x8((*[8]int32)(s))
+}
+
+// StoreSlice stores x to the first 8 elements of s.
+// It panics if len(s) < 8.
+func (x Int32x8) StoreSlice(s []int32) {
+	x.Store((*[8]int32)(s))
+}
+
+// LoadUint32x8Slice returns the first 8 elements of s as a vector.
+// It panics if len(s) < 8.
+func LoadUint32x8Slice(s []uint32) Uint32x8 {
+	return LoadUint32x8((*[8]uint32)(s))
+}
+
+// StoreSlice stores x to the first 8 elements of s.
+// It panics if len(s) < 8.
+func (x Uint32x8) StoreSlice(s []uint32) {
+	x.Store((*[8]uint32)(s))
+}
+
+// LoadInt64x4Slice returns the first 4 elements of s as a vector.
+// It panics if len(s) < 4.
+func LoadInt64x4Slice(s []int64) Int64x4 {
+	return LoadInt64x4((*[4]int64)(s))
+}
+
+// StoreSlice stores x to the first 4 elements of s.
+// It panics if len(s) < 4.
+func (x Int64x4) StoreSlice(s []int64) {
+	x.Store((*[4]int64)(s))
+}
+
+// LoadUint64x4Slice returns the first 4 elements of s as a vector.
+// It panics if len(s) < 4.
+func LoadUint64x4Slice(s []uint64) Uint64x4 {
+	return LoadUint64x4((*[4]uint64)(s))
+}
+
+// StoreSlice stores x to the first 4 elements of s.
+// It panics if len(s) < 4.
+func (x Uint64x4) StoreSlice(s []uint64) {
+	x.Store((*[4]uint64)(s))
+}
+
+// LoadFloat32x8Slice returns the first 8 elements of s as a vector.
+// It panics if len(s) < 8.
+func LoadFloat32x8Slice(s []float32) Float32x8 {
+	return LoadFloat32x8((*[8]float32)(s))
+}
+
+// StoreSlice stores x to the first 8 elements of s.
+// It panics if len(s) < 8.
+func (x Float32x8) StoreSlice(s []float32) {
+	x.Store((*[8]float32)(s))
+}
+
+// LoadFloat64x4Slice returns the first 4 elements of s as a vector.
+// It panics if len(s) < 4.
+func LoadFloat64x4Slice(s []float64) Float64x4 {
+	return LoadFloat64x4((*[4]float64)(s))
+}
+
+// StoreSlice stores x to the first 4 elements of s.
+// It panics if len(s) < 4.
+func (x Float64x4) StoreSlice(s []float64) {
+	x.Store((*[4]float64)(s))
+}
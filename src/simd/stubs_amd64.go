@@ -0,0 +1,679 @@
+// Code generated by mkstubs.go. DO NOT EDIT.
+
+//go:build goexperiment.simd && amd64
+
+package simd
+
+// LoadInt8x32 returns the [32]int8 at p as a vector.
+//
+//go:noescape
+func LoadInt8x32(p *[32]int8) Int8x32
+
+// Store stores x to p.
+//
+//go:noescape
+func (x Int8x32) Store(p *[32]int8)
+
+// BroadcastInt8x32 returns a vector with every lane set to x.
+func BroadcastInt8x32(x int8) Int8x32
+
+// LoadUint8x32 returns the [32]uint8 at p as a vector.
+//
+//go:noescape
+func LoadUint8x32(p *[32]uint8) Uint8x32
+
+// Store stores x to p.
+//
+//go:noescape
+func (x Uint8x32) Store(p *[32]uint8)
+
+// BroadcastUint8x32 returns a vector with every lane set to x.
+func BroadcastUint8x32(x uint8) Uint8x32
+
+// LoadInt16x16 returns the [16]int16 at p as a vector.
+//
+//go:noescape
+func LoadInt16x16(p *[16]int16) Int16x16
+
+// Store stores x to p.
+//
+//go:noescape
+func (x Int16x16) Store(p *[16]int16)
+
+// BroadcastInt16x16 returns a vector with every lane set to x.
+func BroadcastInt16x16(x int16) Int16x16
+
+// LoadUint16x16 returns the [16]uint16 at p as a vector.
+//
+//go:noescape
+func LoadUint16x16(p *[16]uint16) Uint16x16
+
+// Store stores x to p.
+//
+//go:noescape
+func (x Uint16x16) Store(p *[16]uint16)
+
+// BroadcastUint16x16 returns a vector with every lane set to x.
+func BroadcastUint16x16(x uint16) Uint16x16
+
+// LoadInt32x8 returns the [8]int32 at p as a vector.
+//
+//go:noescape
+func LoadInt32x8(p *[8]int32) Int32x8
+
+// Store stores x to p.
+//
+//go:noescape
+func (x Int32x8) Store(p *[8]int32)
+
+// BroadcastInt32x8 returns a vector with every lane set to x.
+func BroadcastInt32x8(x int32) Int32x8
+
+// LoadUint32x8 returns the [8]uint32 at p as a vector.
+//
+//go:noescape
+func LoadUint32x8(p *[8]uint32) Uint32x8
+
+// Store stores x to p.
+//
+//go:noescape
+func (x Uint32x8) Store(p *[8]uint32)
+
+// BroadcastUint32x8 returns a vector with every lane set to x.
+func BroadcastUint32x8(x uint32) Uint32x8
+
+// LoadInt64x4 returns the [4]int64 at p as a vector.
+//
+//go:noescape
+func LoadInt64x4(p *[4]int64) Int64x4
+
+// Store stores x to p.
+//
+//go:noescape
+func (x Int64x4) Store(p *[4]int64)
+
+// BroadcastInt64x4 returns a vector with every lane set to x.
+func BroadcastInt64x4(x int64) Int64x4
+
+// LoadUint64x4 returns the [4]uint64 at p as a vector.
+//
+//go:noescape
+func LoadUint64x4(p *[4]uint64) Uint64x4
+
+// Store stores x to p.
+//
+//go:noescape
+func (x Uint64x4) Store(p *[4]uint64)
+
+// BroadcastUint64x4 returns a vector with every lane set to x.
+func BroadcastUint64x4(x uint64) Uint64x4
+
+// LoadFloat32x8 returns the [8]float32 at p as a vector.
+//
+//go:noescape
+func LoadFloat32x8(p *[8]float32) Float32x8
+
+// Store stores x to p.
+//
+//go:noescape
+func (x Float32x8) Store(p *[8]float32)
+
+// BroadcastFloat32x8 returns a vector with every lane set to x.
+func BroadcastFloat32x8(x float32) Float32x8
+
+// LoadFloat64x4 returns the [4]float64 at p as a vector.
+//
+//go:noescape
+func LoadFloat64x4(p *[4]float64) Float64x4
+
+// Store stores x to p.
+//
+//go:noescape
+func (x Float64x4) Store(p *[4]float64)
+
+// BroadcastFloat64x4 returns a vector with every lane set to x.
+func BroadcastFloat64x4(x float64) Float64x4
+
+// Add returns x + y in each lane, wrapping around on overflow.
+func (x Int8x32) Add(y Int8x32) Int8x32
+
+// Sub returns x - y in each lane, wrapping around on overflow.
+func (x Int8x32) Sub(y Int8x32) Int8x32
+
+// AddSaturated returns x + y in each lane, clamped to the range of int8.
+func (x Int8x32) AddSaturated(y Int8x32) Int8x32
+
+// SubSaturated returns x - y in each lane, clamped to the range of int8.
+func (x Int8x32) SubSaturated(y Int8x32) Int8x32
+
+// Min returns the smaller of x and y in each lane.
+func (x Int8x32) Min(y Int8x32) Int8x32
+
+// Max returns the larger of x and y in each lane.
+func (x Int8x32) Max(y Int8x32) Int8x32
+
+// Abs returns the absolute value of each lane. The most negative value is unchanged.
+func (x Int8x32) Abs() Int8x32
+
+// And returns x & y.
+func (x Int8x32) And(y Int8x32) Int8x32
+
+// Or returns x | y.
+func (x Int8x32) Or(y Int8x32) Int8x32
+
+// Xor returns x ^ y.
+func (x Int8x32) Xor(y Int8x32) Int8x32
+
+// AndNot returns x &^ y.
+func (x Int8x32) AndNot(y Int8x32) Int8x32
+
+// Equal reports whether x == y in each lane.
+func (x Int8x32) Equal(y Int8x32) Mask8x32
+
+// Greater reports whether x > y in each lane.
+func (x Int8x32) Greater(y Int8x32) Mask8x32
+
+// Less reports whether x < y in each lane.
+func (x Int8x32) Less(y Int8x32) Mask8x32
+
+// Add returns x + y in each lane, wrapping around on overflow.
+func (x Uint8x32) Add(y Uint8x32) Uint8x32
+
+// Sub returns x - y in each lane, wrapping around on overflow.
+func (x Uint8x32) Sub(y Uint8x32) Uint8x32
+
+// AddSaturated returns x + y in each lane, clamped to the range of uint8.
+func (x Uint8x32) AddSaturated(y Uint8x32) Uint8x32
+
+// SubSaturated returns x - y in each lane, clamped to the range of uint8.
+func (x Uint8x32) SubSaturated(y Uint8x32) Uint8x32
+
+// Min returns the smaller of x and y in each lane.
+func (x Uint8x32) Min(y Uint8x32) Uint8x32
+
+// Max returns the larger of x and y in each lane.
+func (x Uint8x32) Max(y Uint8x32) Uint8x32
+
+// Average returns (x + y + 1) / 2 in each lane, computed without overflow.
+func (x Uint8x32) Average(y Uint8x32) Uint8x32
+
+// And returns x & y.
+func (x Uint8x32) And(y Uint8x32) Uint8x32
+
+// Or returns x | y.
+func (x Uint8x32) Or(y Uint8x32) Uint8x32
+
+// Xor returns x ^ y.
+func (x Uint8x32) Xor(y Uint8x32) Uint8x32
+
+// AndNot returns x &^ y.
+func (x Uint8x32) AndNot(y Uint8x32) Uint8x32
+
+// Equal reports whether x == y in each lane.
+func (x Uint8x32) Equal(y Uint8x32) Mask8x32
+
+// Add returns x + y in each lane, wrapping around on overflow.
+func (x Int16x16) Add(y Int16x16) Int16x16
+
+// Sub returns x - y in each lane, wrapping around on overflow.
+func (x Int16x16) Sub(y Int16x16) Int16x16
+
+// MulLow returns the low half of x * y in each lane.
+func (x Int16x16) MulLow(y Int16x16) Int16x16
+
+// AddSaturated returns x + y in each lane, clamped to the range of int16.
+func (x Int16x16) AddSaturated(y Int16x16) Int16x16
+
+// SubSaturated returns x - y in each lane, clamped to the range of int16.
+func (x Int16x16) SubSaturated(y Int16x16) Int16x16
+
+// Min returns the smaller of x and y in each lane.
+func (x Int16x16) Min(y Int16x16) Int16x16
+
+// Max returns the larger of x and y in each lane.
+func (x Int16x16) Max(y Int16x16) Int16x16
+
+// Abs returns the absolute value of each lane. The most negative value is unchanged.
+func (x Int16x16) Abs() Int16x16
+
+// And returns x & y.
+func (x Int16x16) And(y Int16x16) Int16x16
+
+// Or returns x | y.
+func (x Int16x16) Or(y Int16x16) Int16x16
+
+// Xor returns x ^ y.
+func (x Int16x16) Xor(y Int16x16) Int16x16
+
+// AndNot returns x &^ y.
+func (x Int16x16) AndNot(y Int16x16) Int16x16
+
+// Equal reports whether x == y in each lane.
+func (x Int16x16) Equal(y Int16x16) Mask16x16
+
+// Greater reports whether x > y in each lane.
+func (x Int16x16) Greater(y Int16x16) Mask16x16
+
+// Less reports whether x < y in each lane.
+func (x Int16x16) Less(y Int16x16) Mask16x16
+
+// Add returns x + y in each lane, wrapping around on overflow.
+func (x Uint16x16) Add(y Uint16x16) Uint16x16
+
+// Sub returns x - y in each lane, wrapping around on overflow.
+func (x Uint16x16) Sub(y Uint16x16) Uint16x16
+
+// MulLow returns the low half of x * y in each lane.
+func (x Uint16x16) MulLow(y Uint16x16) Uint16x16
+
+// AddSaturated returns x + y in each lane, clamped to the range of uint16.
+func (x Uint16x16) AddSaturated(y Uint16x16) Uint16x16
+
+// SubSaturated returns x - y in each lane, clamped to the range of uint16.
+func (x Uint16x16) SubSaturated(y Uint16x16) Uint16x16
+
+// Min returns the smaller of x and y in each lane.
+func (x Uint16x16) Min(y Uint16x16) Uint16x16
+
+// Max returns the larger of x and y in each lane.
+func (x Uint16x16) Max(y Uint16x16) Uint16x16
+
+// Average returns (x + y + 1) / 2 in each lane, computed without overflow.
+func (x Uint16x16) Average(y Uint16x16) Uint16x16
+
+// And returns x & y.
+func (x Uint16x16) And(y Uint16x16) Uint16x16
+
+// Or returns x | y.
+func (x Uint16x16) Or(y Uint16x16) Uint16x16
+
+// Xor returns x ^ y.
+func (x Uint16x16) Xor(y Uint16x16) Uint16x16
+
+// AndNot returns x &^ y.
+func (x Uint16x16) AndNot(y Uint16x16) Uint16x16
+
+// Equal reports whether x == y in each lane.
+func (x Uint16x16) Equal(y Uint16x16) Mask16x16
+
+// Add returns x + y in each lane, wrapping around on overflow.
+func (x Int32x8) Add(y Int32x8) Int32x8
+
+// Sub returns x - y in each lane, wrapping around on overflow.
+func (x Int32x8) Sub(y Int32x8) Int32x8
+
+// MulLow returns the low half of x * y in each lane.
+func (x Int32x8) MulLow(y Int32x8) Int32x8
+
+// Min returns the smaller of x and y in each lane.
+func (x Int32x8) Min(y Int32x8) Int32x8
+
+// Max returns the larger of x and y in each lane.
+func (x Int32x8) Max(y Int32x8) Int32x8
+
+// Abs returns the absolute value of each lane. The most negative value is unchanged.
+func (x Int32x8) Abs() Int32x8
+
+// ShiftLeft returns x << y in each lane. Shifts by the lane width or more produce zero.
+func (x Int32x8) ShiftLeft(y Int32x8) Int32x8
+
+// ShiftRight returns x >> y in each lane, using an arithmetic right shift.
+// Shifts by the lane width or more produce 0 or -1, according to the sign of x.
+func (x Int32x8) ShiftRight(y Int32x8) Int32x8
+
+// And returns x & y.
+func (x Int32x8) And(y Int32x8) Int32x8
+
+// Or returns x | y.
+func (x Int32x8) Or(y Int32x8) Int32x8
+
+// Xor returns x ^ y.
+func (x Int32x8) Xor(y Int32x8) Int32x8
+
+// AndNot returns x &^ y.
+func (x Int32x8) AndNot(y Int32x8) Int32x8
+
+// Equal reports whether x == y in each lane.
+func (x Int32x8) Equal(y Int32x8) Mask32x8
+
+// Greater reports whether x > y in each lane.
+func (x Int32x8) Greater(y Int32x8) Mask32x8
+
+// Less reports whether x < y in each lane.
+func (x Int32x8) Less(y Int32x8) Mask32x8
+
+// Add returns x + y in each lane, wrapping around on overflow.
+func (x Uint32x8) Add(y Uint32x8) Uint32x8
+
+// Sub returns x - y in each lane, wrapping around on overflow.
+func (x Uint32x8) Sub(y Uint32x8) Uint32x8
+
+// MulLow returns the low half of x * y in each lane.
+func (x Uint32x8) MulLow(y Uint32x8) Uint32x8
+
+// Min returns the smaller of x and y in each lane.
+func (x Uint32x8) Min(y Uint32x8) Uint32x8
+
+// Max returns the larger of x and y in each lane.
+func (x Uint32x8) Max(y Uint32x8) Uint32x8
+
+// ShiftLeft returns x << y in each lane. Shifts by the lane width or more produce zero.
+func (x Uint32x8) ShiftLeft(y Uint32x8) Uint32x8
+
+// ShiftRight returns x >> y in each lane, using a logical right shift.
+// Shifts by the lane width or more produce zero.
+func (x Uint32x8) ShiftRight(y Uint32x8) Uint32x8
+
+// And returns x & y.
+func (x Uint32x8) And(y Uint32x8) Uint32x8
+
+// Or returns x | y.
+func (x Uint32x8) Or(y Uint32x8) Uint32x8
+
+// Xor returns x ^ y.
+func (x Uint32x8) Xor(y Uint32x8) Uint32x8
+
+// AndNot returns x &^ y.
+func (x Uint32x8) AndNot(y Uint32x8) Uint32x8
+
+// Equal reports whether x == y in each lane.
+func (x Uint32x8) Equal(y Uint32x8) Mask32x8
+
+// Add returns x + y in each lane, wrapping around on overflow.
+func (x Int64x4) Add(y Int64x4) Int64x4
+
+// Sub returns x - y in each lane, wrapping around on overflow.
+func (x Int64x4) Sub(y Int64x4) Int64x4
+
+// ShiftLeft returns x << y in each lane. Shifts by the lane width or more produce zero.
+func (x Int64x4) ShiftLeft(y Int64x4) Int64x4
+
+// And returns x & y.
+func (x Int64x4) And(y Int64x4) Int64x4
+
+// Or returns x | y.
+func (x Int64x4) Or(y Int64x4) Int64x4
+
+// Xor returns x ^ y.
+func (x Int64x4) Xor(y Int64x4) Int64x4
+
+// AndNot returns x &^ y.
+func (x Int64x4) AndNot(y Int64x4) Int64x4
+
+// Equal reports whether x == y in each lane.
+func (x Int64x4) Equal(y Int64x4) Mask64x4
+
+// Greater reports whether x > y in each lane.
+func (x Int64x4) Greater(y Int64x4) Mask64x4
+
+// Less reports whether x < y in each lane.
+func (x Int64x4) Less(y Int64x4) Mask64x4
+
+// Add returns x + y in each lane, wrapping around on overflow.
+func (x Uint64x4) Add(y Uint64x4) Uint64x4
+
+// Sub returns x - y in each lane, wrapping around on overflow.
+func (x Uint64x4) Sub(y Uint64x4) Uint64x4
+
+// ShiftLeft returns x << y in each lane. Shifts by the lane width or more produce zero.
+func (x Uint64x4) ShiftLeft(y Uint64x4) Uint64x4
+
+// ShiftRight returns x >> y in each lane, using a logical right shift.
+// Shifts by the lane width or more produce zero.
+func (x Uint64x4) ShiftRight(y Uint64x4) Uint64x4
+
+// And returns x & y.
+func (x Uint64x4) And(y Uint64x4) Uint64x4
+
+// Or returns x | y.
+func (x Uint64x4) Or(y Uint64x4) Uint64x4
+
+// Xor returns x ^ y.
+func (x Uint64x4) Xor(y Uint64x4) Uint64x4
+
+// AndNot returns x &^ y.
+func (x Uint64x4) AndNot(y Uint64x4) Uint64x4
+
+// Equal reports whether x == y in each lane.
+func (x Uint64x4) Equal(y Uint64x4) Mask64x4
+
+// Add returns x + y in each lane.
+func (x Float32x8) Add(y Float32x8) Float32x8
+
+// Sub returns x - y in each lane.
+func (x Float32x8) Sub(y Float32x8) Float32x8
+
+// Mul returns x * y in each lane.
+func (x Float32x8) Mul(y Float32x8) Float32x8
+
+// Div returns x / y in each lane.
+func (x Float32x8) Div(y Float32x8) Float32x8
+
+// Min returns the smaller of x and y in each lane.
+// If either lane is a NaN, or both are zeros, the lane of y is returned.
+func (x Float32x8) Min(y Float32x8) Float32x8
+
+// Max returns the larger of x and y in each lane.
+// If either lane is a NaN, or both are zeros, the lane of y is returned.
+func (x Float32x8) Max(y Float32x8) Float32x8
+
+// Sqrt returns the square root of each lane.
+func (x Float32x8) Sqrt() Float32x8
+
+// Equal reports whether x == y in each lane.
+func (x Float32x8) Equal(y Float32x8) Mask32x8
+
+// NotEqual reports whether x != y in each lane.
+func (x Float32x8) NotEqual(y Float32x8) Mask32x8
+
+// Less reports whether x < y in each lane.
+func (x Float32x8) Less(y Float32x8) Mask32x8
+
+// LessEqual reports whether x <= y in each lane.
+func (x Float32x8) LessEqual(y Float32x8) Mask32x8
+
+// Greater reports whether x > y in each lane.
+func (x Float32x8) Greater(y Float32x8) Mask32x8
+
+// GreaterEqual reports whether x >= y in each lane.
+func (x Float32x8) GreaterEqual(y Float32x8) Mask32x8
+
+// Add returns x + y in each lane.
+func (x Float64x4) Add(y Float64x4) Float64x4
+
+// Sub returns x - y in each lane.
+func (x Float64x4) Sub(y Float64x4) Float64x4
+
+// Mul returns x * y in each lane.
+func (x Float64x4) Mul(y Float64x4) Float64x4
+
+// Div returns x / y in each lane.
+func (x Float64x4) Div(y Float64x4) Float64x4
+
+// Min returns the smaller of x and y in each lane.
+// If either lane is a NaN, or both are zeros, the lane of y is returned.
+func (x Float64x4) Min(y Float64x4) Float64x4
+
+// Max returns the larger of x and y in each lane.
+// If either lane is a NaN, or both are zeros, the lane of y is returned.
+func (x Float64x4) Max(y Float64x4) Float64x4
+
+// Sqrt returns the square root of each lane.
+func (x Float64x4) Sqrt() Float64x4
+
+// Equal reports whether x == y in each lane.
+func (x Float64x4) Equal(y Float64x4) Mask64x4
+
+// NotEqual reports whether x != y in each lane.
+func (x Float64x4) NotEqual(y Float64x4) Mask64x4
+
+// Less reports whether x < y in each lane.
+func (x Float64x4) Less(y Float64x4) Mask64x4
+
+// LessEqual reports whether x <= y in each lane.
+func (x Float64x4) LessEqual(y Float64x4) Mask64x4
+
+// Greater reports whether x > y in each lane.
+func (x Float64x4) Greater(y Float64x4) Mask64x4
+
+// GreaterEqual reports whether x >= y in each lane.
+func (x Float64x4) GreaterEqual(y Float64x4) Mask64x4
+
+// Blend returns a vector whose lanes are the lanes of y where m is true, and the lanes of x elsewhere.
+func (x Int8x32) Blend(y Int8x32, m Mask8x32) Int8x32
+
+// Blend returns a vector whose lanes are the lanes of y where m is true, and the lanes of x elsewhere.
+func (x Uint8x32) Blend(y Uint8x32, m Mask8x32) Uint8x32
+
+// Blend returns a vector whose lanes are the lanes of y where m is true, and the lanes of x elsewhere.
+func (x Int16x16) Blend(y Int16x16, m Mask16x16) Int16x16
+
+// Blend returns a vector whose lanes are the lanes of y where m is true, and the lanes of x elsewhere.
+func (x Uint16x16) Blend(y Uint16x16, m Mask16x16) Uint16x16
+
+// Blend returns a vector whose lanes are the lanes of y where m is true, and the lanes of x elsewhere.
+func (x Int32x8) Blend(y Int32x8, m Mask32x8) Int32x8
+
+// Blend returns a vector whose lanes are the lanes of y where m is true, and the lanes of x elsewhere.
+func (x Uint32x8) Blend(y Uint32x8, m Mask32x8) Uint32x8
+
+// Blend returns a vector whose lanes are the lanes of y where m is true, and the lanes of x elsewhere.
+func (x Int64x4) Blend(y Int64x4, m Mask64x4) Int64x4
+
+// Blend returns a vector whose lanes are the lanes of y where m is true, and the lanes of x elsewhere.
+func (x Uint64x4) Blend(y Uint64x4, m Mask64x4) Uint64x4
+
+// Blend returns a vector whose lanes are the lanes of y where m is true, and the lanes of x elsewhere.
+func (x Float32x8) Blend(y Float32x8, m Mask32x8) Float32x8
+
+// Blend returns a vector whose lanes are the lanes of y where m is true, and the lanes of x elsewhere.
+func (x Float64x4) Blend(y Float64x4, m Mask64x4) Float64x4
+
+// AsUint8x32 returns x reinterpreted as a Uint8x32. The bits of x are unchanged.
+func (x Int8x32) AsUint8x32() Uint8x32
+
+// AsInt8x32 returns x reinterpreted as a Int8x32. The bits of x are unchanged.
+func (x Uint8x32) AsInt8x32() Int8x32
+
+// AsUint16x16 returns x reinterpreted as a Uint16x16. The bits of x are unchanged.
+func (x Int16x16) AsUint16x16() Uint16x16
+
+// AsInt16x16 returns x reinterpreted as a Int16x16. The bits of x are unchanged.
+func (x Uint16x16) AsInt16x16() Int16x16
+
+// AsUint32x8 returns x reinterpreted as a Uint32x8. The bits of x are unchanged.
+func (x Int32x8) AsUint32x8() Uint32x8
+
+// AsFloat32x8 returns x reinterpreted as a Float32x8. The bits of x are unchanged.
+func (x Int32x8) AsFloat32x8() Float32x8
+
+// AsInt32x8 returns x reinterpreted as a Int32x8. The bits of x are unchanged.
+func (x Uint32x8) AsInt32x8() Int32x8
+
+// AsFloat32x8 returns x reinterpreted as a Float32x8. The bits of x are unchanged.
+func (x Uint32x8) AsFloat32x8() Float32x8
+
+// AsUint64x4 returns x reinterpreted as a Uint64x4. The bits of x are unchanged.
+func (x Int64x4) AsUint64x4() Uint64x4
+
+// AsFloat64x4 returns x reinterpreted as a Float64x4. The bits of x are unchanged.
+func (x Int64x4) AsFloat64x4() Float64x4
+
+// AsInt64x4 returns x reinterpreted as a Int64x4. The bits of x are unchanged.
+func (x Uint64x4) AsInt64x4() Int64x4
+
+// AsFloat64x4 returns x reinterpreted as a Float64x4. The bits of x are unchanged.
+func (x Uint64x4) AsFloat64x4() Float64x4
+
+// AsInt32x8 returns x reinterpreted as a Int32x8. The bits of x are unchanged.
+func (x Float32x8) AsInt32x8() Int32x8
+
+// AsUint32x8 returns x reinterpreted as a Uint32x8. The bits of x are unchanged.
+func (x Float32x8) AsUint32x8() Uint32x8
+
+// AsInt64x4 returns x reinterpreted as a Int64x4. The bits of x are unchanged.
+func (x Float64x4) AsInt64x4() Int64x4
+
+// AsUint64x4 returns x reinterpreted as a Uint64x4. The bits of x are unchanged.
+func (x Float64x4) AsUint64x4() Uint64x4
+
+// And reports whether both x and y are true in each lane.
+func (x Mask8x32) And(y Mask8x32) Mask8x32
+
+// Or reports whether either of x and y is true in each lane.
+func (x Mask8x32) Or(y Mask8x32) Mask8x32
+
+// Xor reports whether exactly one of x and y is true in each lane.
+func (x Mask8x32) Xor(y Mask8x32) Mask8x32
+
+// AndNot reports whether x is true and y is false in each lane.
+func (x Mask8x32) AndNot(y Mask8x32) Mask8x32
+
+// Not negates each lane of x.
+func (x Mask8x32) Not() Mask8x32
+
+// AsInt8x32 returns x as a vector whose lanes are -1 where x is true and 0 elsewhere.
+func (x Mask8x32) AsInt8x32() Int8x32
+
+// ToBits returns x as a bit set, with bit i set if lane i is true.
+func (x Mask8x32) ToBits() uint32
+
+// And reports whether both x and y are true in each lane.
+func (x Mask16x16) And(y Mask16x16) Mask16x16
+
+// Or reports whether either of x and y is true in each lane.
+func (x Mask16x16) Or(y Mask16x16) Mask16x16
+
+// Xor reports whether exactly one of x and y is true in each lane.
+func (x Mask16x16) Xor(y Mask16x16) Mask16x16
+
+// AndNot reports whether x is true and y is false in each lane.
+func (x Mask16x16) AndNot(y Mask16x16) Mask16x16
+
+// Not negates each lane of x.
+func (x Mask16x16) Not() Mask16x16
+
+// AsInt16x16 returns x as a vector whose lanes are -1 where x is true and 0 elsewhere.
+func (x Mask16x16) AsInt16x16() Int16x16
+
+// And reports whether both x and y are true in each lane.
+func (x Mask32x8) And(y Mask32x8) Mask32x8
+
+// Or reports whether either of x and y is true in each lane.
+func (x Mask32x8) Or(y Mask32x8) Mask32x8
+
+// Xor reports whether exactly one of x and y is true in each lane.
+func (x Mask32x8) Xor(y Mask32x8) Mask32x8
+
+// AndNot reports whether x is true and y is false in each lane.
+func (x Mask32x8) AndNot(y Mask32x8) Mask32x8
+
+// Not negates each lane of x.
+func (x Mask32x8) Not() Mask32x8
+
+// AsInt32x8 returns x as a vector whose lanes are -1 where x is true and 0 elsewhere.
+func (x Mask32x8) AsInt32x8() Int32x8
+
+// ToBits returns x as a bit set, with bit i set if lane i is true.
+func (x Mask32x8) ToBits() uint8
+
+// And reports whether both x and y are true in each lane.
+func (x Mask64x4) And(y Mask64x4) Mask64x4
+
+// Or reports whether either of x and y is true in each lane.
+func (x Mask64x4) Or(y Mask64x4) Mask64x4
+
+// Xor reports whether exactly one of x and y is true in each lane.
+func (x Mask64x4) Xor(y Mask64x4) Mask64x4
+
+// AndNot reports whether x is true and y is false in each lane.
+func (x Mask64x4) AndNot(y Mask64x4) Mask64x4
+
+// Not negates each lane of x.
+func (x Mask64x4) Not() Mask64x4
+
+// AsInt64x4 returns x as a vector whose lanes are -1 where x is true and 0 elsewhere.
+func (x Mask64x4) AsInt64x4() Int64x4
+
+// ToBits returns x as a bit set, with bit i set if lane i is true.
+func (x Mask64x4) ToBits() uint8
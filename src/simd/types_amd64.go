@@ -0,0 +1,100 @@
+// Code generated by mkstubs.go. DO NOT EDIT.
+
+//go:build goexperiment.simd && amd64
+
+package simd
+
+// v256 tags the vector types of this package. The compiler keeps a
+// struct whose first field is a v256 in a 256-bit vector register.
+// Its func element makes vectors incomparable.
+type v256 struct {
+	_256 [0]func()
+}
+
+// Int8x32 is a 256-bit vector of 32 int8 lanes.
+type Int8x32 struct {
+	_ v256
+	_ [32]int8
+}
+
+// Uint8x32 is a 256-bit vector of 32 uint8 lanes.
+type Uint8x32 struct {
+	_ v256
+	_ [32]uint8
+}
+
+// Int16x16 is a 256-bit vector of 16 int16 lanes.
+type Int16x16 struct {
+	_ v256
+	_ [16]int16
+}
+
+// Uint16x16 is a 256-bit vector of 16 uint16 lanes.
+type Uint16x16 struct {
+	_ v256
+	_ [16]uint16
+}
+
+// Int32x8 is a 256-bit vector of 8 int32 lanes.
+type Int32x8 struct {
+	_ v256
+	_ [8]int32
+}
+
+// Uint32x8 is a 256-bit vector of 8 uint32 lanes.
+type Uint32x8 struct {
+	_ v256
+	_ [8]uint32
+}
+
+// Int64x4 is a 256-bit vector of 4 int64 lanes.
+type Int64x4 struct {
+	_ v256
+	_ [4]int64
+}
+
+// Uint64x4 is a 256-bit vector of 4 uint64 lanes.
+type Uint64x4 struct {
+	_ v256
+	_ [4]uint64
+}
+
+// Float32x8 is a 256-bit vector of 8 float32 lanes.
+type Float32x8 struct {
+	_ v256
+	_ [8]float32
+}
+
+// Float64x4 is a 256-bit vector of 4 float64 lanes.
+type Float64x4 struct {
+	_ v256
+	_ [4]float64
+}
+
+// Mask8x32 is the mask of 32 lanes of 8 bits produced by comparing
+// vectors with 8-bit lanes.
+type Mask8x32 struct {
+	_ v256
+	_ [32]int8
+}
+
+// Mask16x16 is the mask of 16 lanes of 16 bits produced by comparing
+// vectors with 16-bit lanes.
+type Mask16x16 struct {
+	_ v256
+	_ [16]int16
+}
+
+// Mask32x8 is the mask of 8 lanes of 32 bits produced by comparing
+// vectors with 32-bit lanes.
+type Mask32x8 struct {
+	_ v256
+	_ [8]int32
+}
+
+// Mask64x4 is the mask of 4 lanes of 64 bits produced by comparing
+// vectors with 64-bit lanes.
+type Mask64x4 struct {
+	_ v256
+	_ [4]int64
+}
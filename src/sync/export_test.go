@@ -4,6 +4,8 @@
 
 package sync
 
+import "unsafe"
+
 // Export for testing.
 var Runtime_Semacquire = runtime_Semacquire
 var Runtime_Semrelease = runtime_Semrelease
@@ -55,3 +57,23 @@ func (c *poolChain) PopHead() (any, bool) {
 func (c *poolChain) PopTail() (any, bool) {
 	return c.popTail()
 }
+
+// LockOrderCheck runs f with an empty lock order graph and returns the
+// reports of the lock order checker. The Lock methods only call the
+// checker when GOEXPERIMENT=lockorder is set, so f should drive it with
+// the LockOrder functions below.
+func LockOrderCheck(f func()) []string {
+	var reports []string
+	defer func(report func(string)) {
+		lockOrderReport = report
+		lockOrder.held, lockOrder.edges, lockOrder.reported = nil, nil, nil
+	}(lockOrderReport)
+	lockOrderReport = func(s string) { reports = append(reports, s) }
+	lockOrder.held, lockOrder.edges, lockOrder.reported = nil, nil, nil
+	f()
+	return reports
+}
+
+func LockOrderAcquire(l *Mutex)    { lockOrderAcquire(unsafe.Pointer(l)) }
+func LockOrderTryAcquire(l *Mutex) { lockOrderTryAcquire(unsafe.Pointer(l)) }
+func LockOrderRelease(l *Mutex)    { lockOrderRelease(unsafe.Pointer(l)) }
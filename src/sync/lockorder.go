@@ -0,0 +1,287 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sync
+
+import (
+	"internal/goexperiment"
+	"internal/itoa"
+	"runtime"
+	"unsafe"
+)
+
+// Lock order checking, enabled by GOEXPERIMENT=lockorder.
+//
+// For each goroutine, the checker records the Mutex and RWMutex locks it
+// holds, in the order it acquired them. Whenever a goroutine acquires a
+// lock B while holding a lock A, the checker adds the edge A -> B to a
+// global lock order graph, remembering the stacks at which A and B were
+// acquired. If B already reaches A in the graph, some other goroutine
+// may be holding B while waiting for A, and the two can deadlock: the
+// checker reports the inversion with the stacks of every acquisition
+// involved, once per new edge, and lets the program continue.
+//
+// Read locks are treated like write locks: a pending writer blocks new
+// readers, so inversions involving read locks can deadlock too.
+// Acquisitions by TryLock and TryRLock are recorded as held but do not
+// add edges, since they cannot block.
+//
+// Locks in the heap are identified by their weak handle rather than
+// their address, so that a lock whose memory is freed and reused by
+// another lock does not inherit the edges of the old one.
+
+const lockOrderEnabled = goexperiment.LockOrder
+
+// runtime_goid returns the ID of the calling goroutine.
+// Provided by runtime via linkname.
+func runtime_goid() uint64
+
+// runtime_lockOrderKey returns a key that identifies the lock at l for
+// as long as the key is reachable, even if the lock is freed and its
+// memory reused. Provided by runtime via linkname.
+func runtime_lockOrderKey(l unsafe.Pointer) unsafe.Pointer
+
+// lockOrderStackDepth is the maximum number of frames recorded for an
+// acquisition.
+const lockOrderStackDepth = 32
+
+// A lockOrderEdge is an edge of the lock order graph, between the keys
+// of two locks.
+type lockOrderEdge struct {
+	from, to unsafe.Pointer
+}
+
+// A lockOrderWitness records where an edge of the lock order graph was
+// first observed: the acquisitions of the held lock and of the new lock.
+type lockOrderWitness struct {
+	goid                uint64
+	heldAddr, takenAddr uintptr
+	held, taken         []uintptr
+}
+
+type heldLock struct {
+	// lock is the lock itself, rather than its address, so that
+	// locks on the stack escape to the heap and do not move.
+	lock  unsafe.Pointer
+	key   unsafe.Pointer
+	stack []uintptr
+}
+
+// lockOrderSema is a binary semaphore guarding lockOrder.
+// It is not a Mutex so that the checker does not check itself.
+var lockOrderSema uint32 = 1
+
+var lockOrder struct {
+	held     map[uint64][]heldLock // goroutine ID -> locks held
+	edges    map[unsafe.Pointer]map[unsafe.Pointer]*lockOrderWitness
+	reported map[unsafe.Pointer]bool // keys of locks reported as locked recursively
+}
+
+// lockOrderReport prints a lock order report.
+// It is replaced by tests.
+var lockOrderReport = func(s string) { print(s) }
+
+// lockOrderAcquire records that the calling goroutine is about to
+// acquire the lock l, and checks that doing so cannot deadlock.
+// The lock is recorded as held before it is acquired, so that an
+// acquisition that blocks forever is still reported.
+func lockOrderAcquire(l unsafe.Pointer) {
+	lockOrderRecord(l, true)
+}
+
+// lockOrderTryAcquire records that the calling goroutine acquired the
+// lock l without blocking.
+func lockOrderTryAcquire(l unsafe.Pointer) {
+	lockOrderRecord(l, false)
+}
+
+func lockOrderRecord(l unsafe.Pointer, check bool) {
+	goid := runtime_goid()
+	key := runtime_lockOrderKey(l)
+	stack := make([]uintptr, lockOrderStackDepth)
+	// Skip runtime.Callers, lockOrderRecord and lockOrderAcquire,
+	// so that the stack starts at the lock method.
+	stack = stack[:runtime.Callers(3, stack)]
+
+	runtime_Semacquire(&lockOrderSema)
+	if lockOrder.held == nil {
+		lockOrder.held = make(map[uint64][]heldLock)
+		lockOrder.edges = make(map[unsafe.Pointer]map[unsafe.Pointer]*lockOrderWitness)
+		lockOrder.reported = make(map[unsafe.Pointer]bool)
+	}
+	var report []byte
+	inverted := false
+	held := lockOrder.held[goid]
+	for _, h := range held {
+		if !check {
+			break
+		}
+		if h.key == key {
+			if !lockOrder.reported[key] {
+				lockOrder.reported[key] = true
+				report = appendRecursiveLockReport(report, goid, h, stack)
+			}
+			continue
+		}
+		if lockOrder.edges[h.key][key] != nil {
+			continue
+		}
+		w := &lockOrderWitness{
+			goid:      goid,
+			heldAddr:  uintptr(h.lock),
+			takenAddr: uintptr(l),
+			held:      h.stack,
+			taken:     stack,
+		}
+		// Report at most one inversion per acquisition: the others
+		// are usually the same cycle extended through other held locks.
+		if !inverted {
+			if path := lockOrderPath(key, h.key); path != nil {
+				report = appendInversionReport(report, w, path)
+				inverted = true
+			}
+		}
+		if lockOrder.edges[h.key] == nil {
+			lockOrder.edges[h.key] = make(map[unsafe.Pointer]*lockOrderWitness)
+		}
+		lockOrder.edges[h.key][key] = w
+	}
+	lockOrder.held[goid] = append(held, heldLock{l, key, stack})
+	runtime_Semrelease(&lockOrderSema, false, 0)
+
+	if report != nil {
+		lockOrderReport(string(report))
+	}
+}
+
+// lockOrderRelease records that the lock l is being released.
+func lockOrderRelease(l unsafe.Pointer) {
+	goid := runtime_goid()
+	runtime_Semacquire(&lockOrderSema)
+	if !lockOrderRemove(goid, l) {
+		// Locks may be released by a goroutine other than the one
+		// that acquired them.
+		for id := range lockOrder.held {
+			if lockOrderRemove(id, l) {
+				break
+			}
+		}
+	}
+	runtime_Semrelease(&lockOrderSema, false, 0)
+}
+
+// lockOrderRemove removes the most recent acquisition of the lock l
+// from the locks held by goroutine goid, and reports whether there was
+// one. lockOrderSema must be held.
+func lockOrderRemove(goid uint64, l unsafe.Pointer) bool {
+	held := lockOrder.held[goid]
+	for i := len(held) - 1; i >= 0; i-- {
+		if held[i].lock == l {
+			held = append(held[:i], held[i+1:]...)
+			if len(held) == 0 {
+				delete(lockOrder.held, goid)
+			} else {
+				lockOrder.held[goid] = held
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// lockOrderPath returns a path of edges from the lock with key from to
+// the lock with key to in the lock order graph, or nil if there is none.
+// lockOrderSema must be held.
+func lockOrderPath(from, to unsafe.Pointer) []lockOrderEdge {
+	parent := map[unsafe.Pointer]unsafe.Pointer{from: from}
+	queue := []unsafe.Pointer{from}
+	for len(queue) > 0 {
+		a := queue[0]
+		queue = queue[1:]
+		for b := range lockOrder.edges[a] {
+			if _, ok := parent[b]; ok {
+				continue
+			}
+			parent[b] = a
+			if b == to {
+				var path []lockOrderEdge
+				for b != from {
+					path = append(path, lockOrderEdge{parent[b], b})
+					b = parent[b]
+				}
+				for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+					path[i], path[j] = path[j], path[i]
+				}
+				return path
+			}
+			queue = append(queue, b)
+		}
+	}
+	return nil
+}
+
+const lockOrderSeparator = "==================\n"
+
+func appendInversionReport(b []byte, w *lockOrderWitness, path []lockOrderEdge) []byte {
+	b = append(b, lockOrderSeparator...)
+	b = append(b, "WARNING: POTENTIAL DEADLOCK: lock order inversion\n"...)
+	b = appendWitnessReport(b, w)
+	for _, e := range path {
+		b = append(b, "\nwhich inverts the order in which earlier\n"...)
+		b = appendWitnessReport(b, lockOrder.edges[e.from][e.to])
+	}
+	return append(b, lockOrderSeparator...)
+}
+
+func appendWitnessReport(b []byte, w *lockOrderWitness) []byte {
+	b = append(b, "goroutine "...)
+	b = append(b, itoa.Uitoa(uint(w.goid))...)
+	b = append(b, " acquired lock "...)
+	b = appendLockAddr(b, w.takenAddr)
+	b = append(b, " at:\n"...)
+	b = appendLockStack(b, w.taken)
+	b = append(b, "while holding lock "...)
+	b = appendLockAddr(b, w.heldAddr)
+	b = append(b, ", acquired at:\n"...)
+	return appendLockStack(b, w.held)
+}
+
+func appendRecursiveLockReport(b []byte, goid uint64, h heldLock, stack []uintptr) []byte {
+	b = append(b, lockOrderSeparator...)
+	b = append(b, "WARNING: POTENTIAL DEADLOCK: recursive locking\n"...)
+	b = append(b, "goroutine "...)
+	b = append(b, itoa.Uitoa(uint(goid))...)
+	b = append(b, " acquired lock "...)
+	b = appendLockAddr(b, uintptr(h.lock))
+	b = append(b, " at:\n"...)
+	b = appendLockStack(b, stack)
+	b = append(b, "while already holding it, acquired at:\n"...)
+	b = appendLockStack(b, h.stack)
+	return append(b, lockOrderSeparator...)
+}
+
+func appendLockAddr(b []byte, addr uintptr) []byte {
+	return append(b, itoa.Uitox(uint(addr))...)
+}
+
+func appendLockStack(b []byte, stack []uintptr) []byte {
+	if len(stack) == 0 {
+		return append(b, "  (unknown)\n"...)
+	}
+	frames := runtime.CallersFrames(stack)
+	for {
+		f, more := frames.Next()
+		b = append(b, "  "...)
+		b = append(b, f.Function...)
+		b = append(b, "()\n      "...)
+		b = append(b, f.File...)
+		b = append(b, ':')
+		b = append(b, itoa.Itoa(f.Line)...)
+		b = append(b, '\n')
+		if !more {
+			break
+		}
+	}
+	return b
+}
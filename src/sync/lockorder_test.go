@@ -0,0 +1,125 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sync_test
+
+import (
+	"strings"
+	. "sync"
+	"testing"
+)
+
+// inGoroutine runs f in a new goroutine and waits for it.
+func inGoroutine(f func()) {
+	done := make(chan bool)
+	go func() {
+		f()
+		done <- true
+	}()
+	<-done
+}
+
+func TestLockOrderInversion(t *testing.T) {
+	var a, b Mutex
+	reports := LockOrderCheck(func() {
+		LockOrderAcquire(&a)
+		LockOrderAcquire(&b)
+		LockOrderRelease(&b)
+		LockOrderRelease(&a)
+
+		inGoroutine(func() {
+			LockOrderAcquire(&b)
+			LockOrderAcquire(&a)
+			LockOrderRelease(&a)
+			LockOrderRelease(&b)
+		})
+
+		// The inversion is reported only the first time.
+		inGoroutine(func() {
+			LockOrderAcquire(&b)
+			LockOrderAcquire(&a)
+			LockOrderRelease(&a)
+			LockOrderRelease(&b)
+		})
+	})
+	if len(reports) != 1 {
+		t.Fatalf("got %d reports, want 1:\n%s", len(reports), strings.Join(reports, ""))
+	}
+	r := reports[0]
+	if !strings.Contains(r, "lock order inversion") {
+		t.Errorf("report does not mention lock order inversion:\n%s", r)
+	}
+	// The report shows both goroutines' acquisitions of both locks.
+	if n := strings.Count(r, "sync.LockOrderAcquire()"); n != 4 {
+		t.Errorf("report shows %d acquisitions, want 4:\n%s", n, r)
+	}
+}
+
+func TestLockOrderCycle(t *testing.T) {
+	var a, b, c Mutex
+	reports := LockOrderCheck(func() {
+		for _, pair := range [][2]*Mutex{{&a, &b}, {&b, &c}, {&c, &a}} {
+			inGoroutine(func() {
+				LockOrderAcquire(pair[0])
+				LockOrderAcquire(pair[1])
+				LockOrderRelease(pair[1])
+				LockOrderRelease(pair[0])
+			})
+		}
+	})
+	if len(reports) != 1 {
+		t.Fatalf("got %d reports, want 1:\n%s", len(reports), strings.Join(reports, ""))
+	}
+	if n := strings.Count(reports[0], "while holding lock"); n != 3 {
+		t.Errorf("report shows %d edges, want 3:\n%s", n, reports[0])
+	}
+}
+
+func TestLockOrderConsistent(t *testing.T) {
+	var a, b, c Mutex
+	reports := LockOrderCheck(func() {
+		for range 2 {
+			inGoroutine(func() {
+				LockOrderAcquire(&a)
+				LockOrderAcquire(&b)
+				LockOrderAcquire(&c)
+				LockOrderRelease(&c)
+				LockOrderRelease(&b)
+				LockOrderRelease(&a)
+			})
+		}
+		// Locks released in a different order, or by another
+		// goroutine, are no longer held.
+		LockOrderAcquire(&b)
+		LockOrderAcquire(&c)
+		LockOrderRelease(&b)
+		inGoroutine(func() {
+			LockOrderRelease(&c)
+		})
+		LockOrderAcquire(&a)
+		LockOrderRelease(&a)
+
+		// Locks acquired without blocking cannot deadlock.
+		LockOrderAcquire(&b)
+		LockOrderTryAcquire(&a)
+		LockOrderRelease(&a)
+		LockOrderRelease(&b)
+	})
+	if len(reports) != 0 {
+		t.Errorf("got unexpected reports:\n%s", strings.Join(reports, ""))
+	}
+}
+
+func TestLockOrderRecursive(t *testing.T) {
+	var a Mutex
+	reports := LockOrderCheck(func() {
+		LockOrderAcquire(&a)
+		LockOrderAcquire(&a)
+		LockOrderRelease(&a)
+		LockOrderRelease(&a)
+	})
+	if len(reports) != 1 || !strings.Contains(reports[0], "recursive locking") {
+		t.Errorf("got reports:\n%s\nwant one recursive locking report", strings.Join(reports, ""))
+	}
+}
@@ -2,9 +2,12 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
+//go:build !goexperiment.synchashtriemap
+
 package sync
 
 import (
+	"iter"
 	"sync/atomic"
 )
 
@@ -14,7 +17,9 @@ import (
 //
 // The Map type is specialized. Most code should use a plain Go map instead,
 // with separate locking or coordination, for better type safety and to make it
-// easier to maintain other invariants along with the map content.
+// easier to maintain other invariants along with the map content. Code that
+// needs a concurrent map with keys and values of known types should use
+// [MapOf], which does not convert them to interfaces.
 //
 // The Map type is optimized for two common use cases: (1) when the entry for a given
 // key is only ever written once but read many times, as in caches that only grow,
@@ -504,6 +509,14 @@ func (m *Map) Range(f func(key, value any) bool) {
 	}
 }
 
+// All returns an iterator over the keys and values present in the map.
+// It provides the same guarantees as [Map.Range].
+func (m *Map) All() iter.Seq2[any, any] {
+	return func(yield func(key, value any) bool) {
+		m.Range(yield)
+	}
+}
+
 func (m *Map) missLocked() {
 	m.misses++
 	if m.misses < len(m.dirty) {
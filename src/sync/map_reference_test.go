@@ -28,6 +28,7 @@ type mapInterface interface {
 var (
 	_ mapInterface = &RWMutexMap{}
 	_ mapInterface = &DeepCopyMap{}
+	_ mapInterface = &sync.MapOf[any, any]{}
 )
 
 // RWMutexMap is an implementation of mapInterface using a sync.RWMutex.
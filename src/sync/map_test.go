@@ -125,6 +125,10 @@ func applyMap(calls []mapCall) ([]mapResult, map[any]any) {
 	return applyCalls(new(sync.Map), calls)
 }
 
+func applyMapOf(calls []mapCall) ([]mapResult, map[any]any) {
+	return applyCalls(new(sync.MapOf[any, any]), calls)
+}
+
 func applyRWMutexMap(calls []mapCall) ([]mapResult, map[any]any) {
 	return applyCalls(new(RWMutexMap), calls)
 }
@@ -145,6 +149,12 @@ func TestMapMatchesDeepCopy(t *testing.T) {
 	}
 }
 
+func TestMapOfMatchesRWMutex(t *testing.T) {
+	if err := quick.CheckEqual(applyMapOf, applyRWMutexMap, nil); err != nil {
+		t.Error(err)
+	}
+}
+
 func TestConcurrentRange(t *testing.T) {
 	const mapSize = 1 << 10
 
@@ -347,13 +357,95 @@ func TestConcurrentClear(t *testing.T) {
 	})
 }
 
-func TestMapClearNoAllocations(t *testing.T) {
+func TestMapClearOneAllocation(t *testing.T) {
 	testenv.SkipIfOptimizationOff(t)
 	var m sync.Map
 	allocs := testing.AllocsPerRun(10, func() {
 		m.Clear()
 	})
+	if allocs > 1 {
+		t.Errorf("AllocsPerRun of m.Clear = %v; want 1", allocs)
+	}
+}
+
+func TestMapAll(t *testing.T) {
+	var m sync.Map
+	want := make(map[any]any)
+	for i := range 100 {
+		m.Store(i, i*10)
+		want[i] = i * 10
+	}
+	got := make(map[any]any)
+	for k, v := range m.All() {
+		if _, ok := got[k]; ok {
+			t.Errorf("All visited key %v more than once", k)
+		}
+		got[k] = v
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("All = %v; want %v", got, want)
+	}
+	n := 0
+	for range m.All() {
+		n++
+		break
+	}
+	if n != 1 {
+		t.Errorf("All continued after break")
+	}
+}
+
+func TestMapOf(t *testing.T) {
+	var m sync.MapOf[string, []int]
+	if v, ok := m.Load("a"); ok || v != nil {
+		t.Errorf("Load(a) on empty MapOf = %v, %v; want nil, false", v, ok)
+	}
+	m.Store("a", []int{1})
+	if v, loaded := m.LoadOrStore("a", []int{2}); !loaded || !reflect.DeepEqual(v, []int{1}) {
+		t.Errorf("LoadOrStore(a) = %v, %v; want [1], true", v, loaded)
+	}
+	if v, loaded := m.Swap("b", []int{3}); loaded || v != nil {
+		t.Errorf("Swap(b) = %v, %v; want nil, false", v, loaded)
+	}
+	got := make(map[string][]int)
+	for k, v := range m.All() {
+		got[k] = v
+	}
+	if want := map[string][]int{"a": {1}, "b": {3}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("All = %v; want %v", got, want)
+	}
+	if v, loaded := m.LoadAndDelete("a"); !loaded || !reflect.DeepEqual(v, []int{1}) {
+		t.Errorf("LoadAndDelete(a) = %v, %v; want [1], true", v, loaded)
+	}
+	m.Clear()
+	m.Range(func(k string, v []int) bool {
+		t.Errorf("after Clear, MapOf contains (%v, %v); expected to be empty", k, v)
+		return true
+	})
+
+	// Values of an uncomparable type cannot be compared.
+	defer func() {
+		if recover() == nil {
+			t.Errorf("CompareAndSwap of uncomparable values did not panic")
+		}
+	}()
+	m.CompareAndSwap("a", nil, nil)
+}
+
+func TestMapOfStoreNoAllocations(t *testing.T) {
+	testenv.SkipIfOptimizationOff(t)
+	var m sync.MapOf[int, int]
+	for i := range 100 {
+		m.Store(i, i)
+	}
+	allocs := testing.AllocsPerRun(10, func() {
+		for i := range 100 {
+			if v, ok := m.Load(i); !ok || v != i {
+				t.Fatalf("Load(%d) = %v, %v; want %d, true", i, v, ok, i)
+			}
+		}
+	})
 	if allocs > 0 {
-		t.Errorf("AllocsPerRun of m.Clear = %v; want 0", allocs)
+		t.Errorf("AllocsPerRun of m.Load = %v; want 0", allocs)
 	}
 }
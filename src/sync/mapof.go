@@ -0,0 +1,106 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sync
+
+import (
+	"internal/concurrent"
+	"iter"
+)
+
+// MapOf is like a Go map[K]V but is safe for concurrent use
+// by multiple goroutines without additional locking or coordination.
+// Loads, stores, and deletes run in amortized constant time.
+//
+// MapOf is a type-safe version of [Map]: it has the same methods and
+// the same performance characteristics, but stores its keys and values
+// without converting them to interfaces, so that storing a value does
+// not allocate to box it.
+//
+// [MapOf.CompareAndSwap] and [MapOf.CompareAndDelete] compare values
+// of type V, which must be comparable: they panic if V is not a
+// comparable type, or if V is an interface type and the dynamic types
+// of the values compared are not comparable.
+//
+// The zero MapOf is empty and ready for use. A MapOf must not be copied
+// after first use.
+//
+// MapOf provides the same synchronization guarantees as [Map], where
+// the read and write operations are the methods of the same names.
+type MapOf[K comparable, V any] struct {
+	_ noCopy
+
+	m concurrent.HashTrieMap[K, V]
+}
+
+// Load returns the value stored in the map for a key, or the zero value
+// of V if no value is present.
+// The ok result indicates whether value was found in the map.
+func (m *MapOf[K, V]) Load(key K) (value V, ok bool) {
+	return m.m.Load(key)
+}
+
+// Store sets the value for a key.
+func (m *MapOf[K, V]) Store(key K, value V) {
+	m.m.Store(key, value)
+}
+
+// Clear deletes all the entries, resulting in an empty MapOf.
+func (m *MapOf[K, V]) Clear() {
+	m.m.Clear()
+}
+
+// LoadOrStore returns the existing value for the key if present.
+// Otherwise, it stores and returns the given value.
+// The loaded result is true if the value was loaded, false if stored.
+func (m *MapOf[K, V]) LoadOrStore(key K, value V) (actual V, loaded bool) {
+	return m.m.LoadOrStore(key, value)
+}
+
+// LoadAndDelete deletes the value for a key, returning the previous value if any.
+// The loaded result reports whether the key was present.
+func (m *MapOf[K, V]) LoadAndDelete(key K) (value V, loaded bool) {
+	return m.m.LoadAndDelete(key)
+}
+
+// Delete deletes the value for a key.
+func (m *MapOf[K, V]) Delete(key K) {
+	m.m.Delete(key)
+}
+
+// Swap swaps the value for a key and returns the previous value if any.
+// The loaded result reports whether the key was present.
+func (m *MapOf[K, V]) Swap(key K, value V) (previous V, loaded bool) {
+	return m.m.Swap(key, value)
+}
+
+// CompareAndSwap swaps the old and new values for key
+// if the value stored in the map is equal to old.
+// V must be a comparable type.
+func (m *MapOf[K, V]) CompareAndSwap(key K, old, new V) (swapped bool) {
+	return m.m.CompareAndSwap(key, old, new)
+}
+
+// CompareAndDelete deletes the entry for key if its value is equal to old.
+// V must be a comparable type.
+//
+// If there is no current value for key in the map, CompareAndDelete
+// returns false (even if the old value is the zero value of V).
+func (m *MapOf[K, V]) CompareAndDelete(key K, old V) (deleted bool) {
+	return m.m.CompareAndDelete(key, old)
+}
+
+// Range calls f sequentially for each key and value present in the map.
+// If f returns false, range stops the iteration.
+//
+// Range provides the same guarantees as [Map.Range].
+func (m *MapOf[K, V]) Range(f func(key K, value V) bool) {
+	m.m.Range(f)
+}
+
+// All returns an iterator over the keys and values present in the map.
+// It provides the same guarantees as [Map.Range].
+func (m *MapOf[K, V]) All() iter.Seq2[K, V] {
+	return m.m.All()
+}
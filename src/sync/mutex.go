@@ -81,6 +81,9 @@ const (
 // If the lock is already in use, the calling goroutine
 // blocks until the mutex is available.
 func (m *Mutex) Lock() {
+	if lockOrderEnabled {
+		lockOrderAcquire(unsafe.Pointer(m))
+	}
 	// Fast path: grab unlocked mutex.
 	if atomic.CompareAndSwapInt32(&m.state, 0, mutexLocked) {
 		if race.Enabled {
@@ -110,6 +113,9 @@ func (m *Mutex) TryLock() bool {
 		return false
 	}
 
+	if lockOrderEnabled {
+		lockOrderTryAcquire(unsafe.Pointer(m))
+	}
 	if race.Enabled {
 		race.Acquire(unsafe.Pointer(m))
 	}
@@ -212,6 +218,9 @@ func (m *Mutex) lockSlow() {
 // It is allowed for one goroutine to lock a Mutex and then
 // arrange for another goroutine to unlock it.
 func (m *Mutex) Unlock() {
+	if lockOrderEnabled {
+		lockOrderRelease(unsafe.Pointer(m))
+	}
 	if race.Enabled {
 		_ = m.state
 		race.Release(unsafe.Pointer(m))
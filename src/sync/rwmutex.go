@@ -63,6 +63,10 @@ const rwmutexMaxReaders = 1 << 30
 // call excludes new readers from acquiring the lock. See the
 // documentation on the [RWMutex] type.
 func (rw *RWMutex) RLock() {
+	if lockOrderEnabled {
+		// The write lock is checked by rw.w, which has the same address.
+		lockOrderAcquire(unsafe.Pointer(rw))
+	}
 	if race.Enabled {
 		_ = rw.w.state
 		race.Disable()
@@ -96,6 +100,9 @@ func (rw *RWMutex) TryRLock() bool {
 			return false
 		}
 		if rw.readerCount.CompareAndSwap(c, c+1) {
+			if lockOrderEnabled {
+				lockOrderTryAcquire(unsafe.Pointer(rw))
+			}
 			if race.Enabled {
 				race.Enable()
 				race.Acquire(unsafe.Pointer(&rw.readerSem))
@@ -110,6 +117,9 @@ func (rw *RWMutex) TryRLock() bool {
 // It is a run-time error if rw is not locked for reading
 // on entry to RUnlock.
 func (rw *RWMutex) RUnlock() {
+	if lockOrderEnabled {
+		lockOrderRelease(unsafe.Pointer(rw))
+	}
 	if race.Enabled {
 		_ = rw.w.state
 		race.ReleaseMerge(unsafe.Pointer(&rw.writerSem))
@@ -233,6 +243,20 @@ func syscall_hasWaitingReaders(rw *RWMutex) bool {
 	return r < 0 && r+rwmutexMaxReaders > 0
 }
 
+// syscall_lockOrderDisown tells the lock order checker that the calling
+// goroutine, which has just locked rw for writing, does not own the lock.
+// The syscall package holds the write lock on ForkLock on behalf of all
+// goroutines that are forking, and releases it from whichever goroutine
+// finishes last, so ForkLock does not take part in lock ordering while
+// it is locked for writing. See GOEXPERIMENT=lockorder in lockorder.go.
+//
+//go:linkname syscall_lockOrderDisown syscall.lockOrderDisown
+func syscall_lockOrderDisown(rw *RWMutex) {
+	if lockOrderEnabled {
+		lockOrderRelease(unsafe.Pointer(rw))
+	}
+}
+
 // RLocker returns a [Locker] interface that implements
 // the [Locker.Lock] and [Locker.Unlock] methods by calling rw.RLock and rw.RUnlock.
 func (rw *RWMutex) RLocker() Locker {
@@ -27,6 +27,11 @@ var (
 // to acquire a read lock on rw. It is defined in the sync package.
 func hasWaitingReaders(rw *sync.RWMutex) bool
 
+// lockOrderDisown tells the sync package's lock order checker that the
+// calling goroutine does not own the write lock it has just acquired on
+// rw. It is defined in the sync package.
+func lockOrderDisown(rw *sync.RWMutex)
+
 // acquireForkLock acquires a write lock on ForkLock.
 // ForkLock is exported and we've promised that during a fork
 // we will call ForkLock.Lock, so that no other threads create
@@ -43,6 +48,7 @@ func acquireForkLock() {
 	if forking == 0 {
 		// There is no current write lock on ForkLock.
 		ForkLock.Lock()
+		lockOrderDisown(&ForkLock)
 		forking++
 		return
 	}
@@ -73,6 +79,7 @@ func acquireForkLock() {
 
 		if forking == 0 {
 			ForkLock.Lock()
+			lockOrderDisown(&ForkLock)
 		}
 	}
 
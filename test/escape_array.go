@@ -123,7 +123,9 @@ func doesMakeSlice(x *string, y *string) { // ERROR "leaking param: x" "leaking
 
 func nonconstArray() {
 	n := 32
-	s1 := make([]int, n)    // ERROR "make\(\[\]int, n\) escapes to heap"
-	s2 := make([]int, 0, n) // ERROR "make\(\[\]int, 0, n\) escapes to heap"
-	_, _ = s1, s2
+	s1 := make([]int, n)      // ERROR "make\(\[\]int, n\) does not escape"
+	s2 := make([]int, 0, n)   // ERROR "make\(\[\]int, 0, n\) does not escape"
+	s3 := make([][64]byte, n) // ERROR "make\(\[\]\[64\]byte, n\) escapes to heap"
+	s4 := make([]struct{}, n) // ERROR "make\(\[\]struct {}, n\) escapes to heap"
+	_, _, _, _ = s1, s2, s3, s4
 }
@@ -9,7 +9,6 @@
 // license that can be found in the LICENSE file.
 
 // non-swissmap-specific tests for live.go
-// TODO(#54766): temporary while fast variants are disabled.
 
 package main
 
@@ -7,7 +7,6 @@
 // license that can be found in the LICENSE file.
 
 // non-swissmap-specific tests for live_regabi.go
-// TODO(#54766): temporary while fast variants are disabled.
 
 package main
 
@@ -495,14 +495,14 @@ func f18(b []int, x int, y uint) {
 func f19() (e int64, err error) {
 	// Issue 29502: slice[:0] is incorrectly disproved.
 	var stack []int64
-	stack = append(stack, 123)
+	stack = append(stack, 123) // ERROR "Disproved Less64U$"
 	if len(stack) > 1 {
 		panic("too many elements")
 	}
 	last := len(stack) - 1
 	e = stack[last]
 	// Buggy compiler prints "Disproved Leq64" for the next line.
-	stack = stack[:last]
+	stack = stack[:last] // ERROR "Proved IsSliceInBounds$"
 	return e, nil
 }
 